@@ -0,0 +1,705 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Dacode45/OldGoldEngine/goldcore"
+)
+
+//buildVersion : Changes whenever outputs of the same inputs change, so
+//upgrading goldpack rebuilds everything
+const buildVersion = 1
+
+const (
+	configFile   = "goldpack.json"
+	manifestFile = "manifest.json"
+	cacheFile    = ".goldpack-cache.json"
+)
+
+//defaultChars : Printable ASCII, what fonts are baked with by default
+const defaultChars = " !\"#$%&'()*+,-./0123456789:;<=>?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[\\]^_`abcdefghijklmnopqrstuvwxyz{|}~"
+
+/////////////////////////////////////
+///		CONFIG
+/////////////////////////////////////
+
+//config : goldpack.json at the root of the sources, optional
+type config struct {
+	//Atlases : Images to pack together, each only goes in the first atlas
+	//including it
+	Atlases []atlasConfig `json:"atlases"`
+	//Fonts : TrueType fonts to bake into bitmap fonts
+	Fonts []fontConfig `json:"fonts"`
+	//Schemas : Schema file validating the JSON files matching each pattern
+	Schemas map[string]string `json:"schemas"`
+}
+
+//atlasConfig : An atlas, written as Name.atlas and its Name.N.gtex pages
+type atlasConfig struct {
+	Name     string   `json:"name"`
+	Include  []string `json:"include"` //Patterns of the images to pack, see matchGlob
+	PageSize int      `json:"pageSize"`
+	Padding  *int     `json:"padding"`
+}
+
+//fontConfig : A font baked at one size, written as Name.fnt and its
+//Name.N.gtex pages
+type fontConfig struct {
+	Name     string `json:"name"`
+	Source   string `json:"source"`
+	Size     int    `json:"size"`
+	Chars    string `json:"chars"` //Printable ASCII if empty
+	PageSize int    `json:"pageSize"`
+}
+
+//loadConfig : The config of the sources in src, empty if there is none
+func loadConfig(src string) (*config, error) {
+	cfg := &config{}
+	data, err := os.ReadFile(filepath.Join(src, configFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("%s: %v", configFile, err)
+	}
+	for _, a := range cfg.Atlases {
+		if a.Name == "" || len(a.Include) == 0 {
+			return nil, fmt.Errorf("%s: atlases need a name and include patterns", configFile)
+		}
+	}
+	for _, f := range cfg.Fonts {
+		if f.Name == "" || f.Source == "" || f.Size <= 0 {
+			return nil, fmt.Errorf("%s: fonts need a name, a source and a size", configFile)
+		}
+	}
+	return cfg, nil
+}
+
+//matchGlob : Checks whether the slash separated name matches pattern, in
+//which ** matches any number of directories and the rest is path.Match
+func matchGlob(pattern, name string) bool {
+	return matchParts(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchParts(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			if matchParts(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(name) == 0 {
+		return false
+	}
+	ok, err := path.Match(pattern[0], name[0])
+	return err == nil && ok && matchParts(pattern[1:], name[1:])
+}
+
+/////////////////////////////////////
+///		BUILDER
+/////////////////////////////////////
+
+//builder : Builds the sources in src into out. Every output comes from a
+//step, steps whose inputs and settings didn't change since the last build
+//are skipped
+type builder struct {
+	src, out string
+	force    bool      //Rebuilds every step
+	log      io.Writer //Where built steps are listed, nil for nowhere
+
+	config  *config
+	hashes  map[string]string //Hash of every source, by path
+	steps   []*step
+	old     *buildCache
+	cache   *buildCache
+	written map[string]string //Step writing each output
+
+	built, skipped, removed int
+}
+
+//step : Outputs built from some inputs
+type step struct {
+	id     string
+	inputs []string    //Sources read
+	params interface{} //Settings, rebuilds when they change
+	run    func(o *stepOutput) error
+}
+
+//buildCache : What the last build did, kept in the output
+type buildCache struct {
+	Version int                    `json:"version"`
+	Steps   map[string]*cachedStep `json:"steps"`
+}
+
+//cachedStep : A step of the last build
+type cachedStep struct {
+	Key     string                            `json:"key"`     //Hash of the inputs and settings
+	Outputs map[string]string                 `json:"outputs"` //Hash of every output, by path
+	Assets  map[string]goldcore.ManifestAsset `json:"assets"`
+}
+
+//stepOutput : Where a step writes its files and assets
+type stepOutput struct {
+	b    *builder
+	id   string
+	done *cachedStep
+}
+
+//newBuilder : Builder from src to out
+func newBuilder(src, out string) *builder {
+	return &builder{src: src, out: out}
+}
+
+//build : Builds everything, returns every problem found. The manifest is
+//only written when there are none
+func (b *builder) build() error {
+	cfg, err := loadConfig(b.src)
+	if err != nil {
+		return err
+	}
+	b.config = cfg
+	b.built, b.skipped, b.removed = 0, 0, 0
+	b.written = make(map[string]string)
+	b.cache = &buildCache{Version: buildVersion, Steps: make(map[string]*cachedStep)}
+	b.old = b.loadCache()
+	if err := b.scan(); err != nil {
+		return err
+	}
+	if err := b.plan(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(b.out, 0755); err != nil {
+		return err
+	}
+
+	var problems []string
+	for _, s := range b.steps {
+		if err := b.runStep(s); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if len(problems) == 0 {
+		b.removeStale()
+	} else {
+		//Keep what was there for the steps that failed, so they aren't
+		//mistaken for stale next time
+		for id, cached := range b.old.Steps {
+			if _, ok := b.cache.Steps[id]; !ok {
+				cached.Key = ""
+				b.cache.Steps[id] = cached
+			}
+		}
+	}
+	if err := b.saveCache(); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, "\n"))
+	}
+	return b.writeManifest()
+}
+
+//scan : Hashes every source. The config, dot files and the output are
+//left out
+func (b *builder) scan() error {
+	b.hashes = make(map[string]string)
+	out, _ := filepath.Abs(b.out)
+	return filepath.WalkDir(b.src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(b.src, p)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if abs, _ := filepath.Abs(p); abs == out || (name != "." && strings.HasPrefix(d.Name(), ".")) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || name == configFile {
+			return nil
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		b.hashes[name] = goldcore.HashBytes(data)
+		return nil
+	})
+}
+
+//plan : Picks a step for every source and every atlas and font of the
+//config
+func (b *builder) plan() error {
+	b.steps = nil
+	claimed := make(map[string]bool)
+	for _, a := range b.config.Atlases {
+		var inputs []string
+		for _, name := range b.sources() {
+			if claimed[name] || !isImage(name) {
+				continue
+			}
+			for _, pattern := range a.Include {
+				if matchGlob(pattern, name) {
+					inputs = append(inputs, name)
+					claimed[name] = true
+					break
+				}
+			}
+		}
+		b.steps = append(b.steps, b.atlasStep(a, inputs))
+	}
+	for _, f := range b.config.Fonts {
+		if _, ok := b.hashes[f.Source]; !ok {
+			return fmt.Errorf("font %s: no source %s", f.Name, f.Source)
+		}
+		b.steps = append(b.steps, b.fontStep(f))
+	}
+	for _, name := range b.sources() {
+		if claimed[name] {
+			continue
+		}
+		switch ext := strings.ToLower(path.Ext(name)); {
+		case isImage(name):
+			b.steps = append(b.steps, b.imageStep(name))
+		case ext == ".fnt":
+			b.steps = append(b.steps, b.fntStep(name))
+		case ext == ".json":
+			s, err := b.jsonStep(name)
+			if err != nil {
+				return err
+			}
+			b.steps = append(b.steps, s)
+		default:
+			b.steps = append(b.steps, b.copyStep(name))
+		}
+	}
+	return nil
+}
+
+//sources : Paths of the sources, sorted
+func (b *builder) sources() []string {
+	names := make([]string, 0, len(b.hashes))
+	for name := range b.hashes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+//key : Hash of what goes into s
+func (b *builder) key(s *step) string {
+	h := sha256.New()
+	params, _ := json.Marshal(s.params)
+	fmt.Fprintf(h, "%d\n%s\n%s\n", buildVersion, s.id, params)
+	for _, name := range s.inputs {
+		fmt.Fprintf(h, "%s %s\n", name, b.hashes[name])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+//runStep : Runs s unless the last build already did with the same inputs
+//and its outputs are untouched
+func (b *builder) runStep(s *step) error {
+	key := b.key(s)
+	if cached, ok := b.old.Steps[s.id]; ok && !b.force && cached.Key == key && b.intact(cached) {
+		for name := range cached.Outputs {
+			b.written[name] = s.id
+		}
+		b.cache.Steps[s.id] = cached
+		b.skipped++
+		return nil
+	}
+	o := &stepOutput{b: b, id: s.id, done: &cachedStep{
+		Key:     key,
+		Outputs: make(map[string]string),
+		Assets:  make(map[string]goldcore.ManifestAsset),
+	}}
+	if err := s.run(o); err != nil {
+		return err
+	}
+	b.cache.Steps[s.id] = o.done
+	b.built++
+	if b.log != nil {
+		fmt.Fprintf(b.log, "built %s\n", s.id)
+	}
+	return nil
+}
+
+//intact : Checks whether the outputs of a step are still what it wrote
+func (b *builder) intact(cached *cachedStep) bool {
+	for name, hash := range cached.Outputs {
+		data, err := os.ReadFile(filepath.Join(b.out, filepath.FromSlash(name)))
+		if err != nil || goldcore.HashBytes(data) != hash {
+			return false
+		}
+	}
+	return true
+}
+
+//removeStale : Deletes the outputs of the last build nothing wrote this
+//time
+func (b *builder) removeStale() {
+	for _, cached := range b.old.Steps {
+		for name := range cached.Outputs {
+			if _, ok := b.written[name]; ok {
+				continue
+			}
+			if err := os.Remove(filepath.Join(b.out, filepath.FromSlash(name))); err == nil {
+				b.removed++
+			}
+		}
+	}
+}
+
+//loadCache : The cache of the last build, empty if there is none or it
+//came from another version
+func (b *builder) loadCache() *buildCache {
+	cache := &buildCache{}
+	data, err := os.ReadFile(filepath.Join(b.out, cacheFile))
+	if err != nil || json.Unmarshal(data, cache) != nil || cache.Version != buildVersion || cache.Steps == nil {
+		return &buildCache{Version: buildVersion, Steps: make(map[string]*cachedStep)}
+	}
+	return cache
+}
+
+func (b *builder) saveCache() error {
+	data, err := json.MarshalIndent(b.cache, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(b.out, cacheFile), data, 0644)
+}
+
+//writeManifest : Writes the assets of every step
+func (b *builder) writeManifest() error {
+	man := goldcore.NewManifest()
+	for _, cached := range b.cache.Steps {
+		for name, asset := range cached.Assets {
+			man.Assets[name] = asset
+		}
+	}
+	var buf bytes.Buffer
+	if err := goldcore.EncodeManifest(&buf, man); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(b.out, manifestFile), buf.Bytes(), 0644)
+}
+
+/////////////////////////////////////
+///		OUTPUT
+/////////////////////////////////////
+
+//read : Contents of the source name
+func (o *stepOutput) read(name string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(o.b.src, filepath.FromSlash(name)))
+}
+
+//write : Writes the output name. Fails if another step wrote it
+func (o *stepOutput) write(name string, data []byte) error {
+	if name == manifestFile || name == cacheFile {
+		return fmt.Errorf("%s: %s is reserved", o.id, name)
+	}
+	if other, ok := o.b.written[name]; ok && other != o.id {
+		return fmt.Errorf("%s: %s is also written by %s", o.id, name, other)
+	}
+	p := filepath.Join(o.b.out, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(p, data, 0644); err != nil {
+		return err
+	}
+	o.b.written[name] = o.id
+	o.done.Outputs[name] = goldcore.HashBytes(data)
+	return nil
+}
+
+//asset : Lists an asset in the manifest, loaded from file and built as
+//files
+func (o *stepOutput) asset(name string, kind goldcore.ResourceKind, file string, files ...string) {
+	hashes := make(map[string]string, len(files))
+	for _, f := range files {
+		hashes[f] = o.done.Outputs[f]
+	}
+	o.done.Assets[name] = goldcore.ManifestAsset{Kind: kind, File: file, Hashes: hashes}
+}
+
+//writePages : Writes textures as base.N.gtex and gives their names
+func (o *stepOutput) writePages(base string, pages []*goldcore.Texture) ([]string, error) {
+	names := make([]string, len(pages))
+	for i, page := range pages {
+		var buf bytes.Buffer
+		if err := goldcore.EncodeGTEX(&buf, page.Image()); err != nil {
+			return nil, err
+		}
+		names[i] = base + "." + strconv.Itoa(i) + ".gtex"
+		if err := o.write(names[i], buf.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+	return names, nil
+}
+
+/////////////////////////////////////
+///		STEPS
+/////////////////////////////////////
+
+//isImage : Checks whether name is an image converted to GTEX
+func isImage(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".png", ".jpg", ".jpeg":
+		return true
+	}
+	return false
+}
+
+//gtexName : Where the image name is converted to
+func gtexName(name string) string {
+	return strings.TrimSuffix(name, path.Ext(name)) + ".gtex"
+}
+
+//decodeImage : The image at the source name
+func (o *stepOutput) decodeImage(name string) (image.Image, error) {
+	data, err := o.read(name)
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	return img, nil
+}
+
+//imageStep : Converts an image to GTEX
+func (b *builder) imageStep(name string) *step {
+	return &step{id: "image:" + name, inputs: []string{name}, run: func(o *stepOutput) error {
+		img, err := o.decodeImage(name)
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		if err := goldcore.EncodeGTEX(&buf, img); err != nil {
+			return err
+		}
+		file := gtexName(name)
+		if err := o.write(file, buf.Bytes()); err != nil {
+			return err
+		}
+		o.asset(name, goldcore.ResourceTexture, file, file)
+		return nil
+	}}
+}
+
+//atlasStep : Packs images into an atlas. Its regions are named after the
+//images' source paths
+func (b *builder) atlasStep(cfg atlasConfig, inputs []string) *step {
+	return &step{id: "atlas:" + cfg.Name, inputs: inputs, params: cfg, run: func(o *stepOutput) error {
+		builder := goldcore.NewAtlasBuilder()
+		if cfg.PageSize > 0 {
+			builder.PageSize = cfg.PageSize
+		}
+		if cfg.Padding != nil {
+			builder.Padding = *cfg.Padding
+		}
+		for _, name := range inputs {
+			img, err := o.decodeImage(name)
+			if err != nil {
+				return err
+			}
+			builder.Add(name, img)
+		}
+		atlas, err := builder.Build()
+		if err != nil {
+			return fmt.Errorf("atlas %s: %v", cfg.Name, err)
+		}
+		pages, err := o.writePages(cfg.Name, atlas.Pages)
+		if err != nil {
+			return err
+		}
+		bases := make([]string, len(pages))
+		for i, page := range pages {
+			bases[i] = path.Base(page)
+		}
+		var buf bytes.Buffer
+		if err := goldcore.EncodeAtlas(&buf, atlas, bases); err != nil {
+			return err
+		}
+		file := cfg.Name + ".atlas"
+		if err := o.write(file, buf.Bytes()); err != nil {
+			return err
+		}
+		o.asset(cfg.Name, goldcore.ResourceAtlas, file, append(pages, file)...)
+		for _, name := range inputs {
+			o.done.Assets[name] = goldcore.ManifestAsset{Kind: goldcore.ResourceTexture, File: file, Atlas: cfg.Name}
+		}
+		return nil
+	}}
+}
+
+//fontStep : Bakes a TrueType font into a BMFont
+func (b *builder) fontStep(cfg fontConfig) *step {
+	return &step{id: "font:" + cfg.Name, inputs: []string{cfg.Source}, params: cfg, run: func(o *stepOutput) error {
+		data, err := o.read(cfg.Source)
+		if err != nil {
+			return err
+		}
+		ttf, err := goldcore.ParseTrueTypeFont(data)
+		if err != nil {
+			return fmt.Errorf("font %s: %v", cfg.Name, err)
+		}
+		chars := cfg.Chars
+		if chars == "" {
+			chars = defaultChars
+		}
+		bm, err := goldcore.BakeBMFont(ttf, cfg.Size, []rune(chars), cfg.PageSize)
+		if err != nil {
+			return fmt.Errorf("font %s: %v", cfg.Name, err)
+		}
+		pages, err := o.writePages(cfg.Name, bm.Pages())
+		if err != nil {
+			return err
+		}
+		bases := make([]string, len(pages))
+		for i, page := range pages {
+			bases[i] = path.Base(page)
+		}
+		var buf bytes.Buffer
+		if err := goldcore.EncodeBMFont(&buf, bm, bases); err != nil {
+			return err
+		}
+		file := cfg.Name + ".fnt"
+		if err := o.write(file, buf.Bytes()); err != nil {
+			return err
+		}
+		o.asset(cfg.Name, goldcore.ResourceFont, file, append(pages, file)...)
+		return nil
+	}}
+}
+
+//fntPage : file attribute of the page lines of a .fnt
+var fntPage = regexp.MustCompile(`(?m)^(page\b.*\bfile=)"([^"]*)"`)
+
+//fntStep : Copies a BMFont, pointing it at its pages converted to GTEX
+func (b *builder) fntStep(name string) *step {
+	return &step{id: "fnt:" + name, inputs: []string{name}, run: func(o *stepOutput) error {
+		data, err := o.read(name)
+		if err != nil {
+			return err
+		}
+		data = fntPage.ReplaceAllFunc(data, func(line []byte) []byte {
+			m := fntPage.FindSubmatch(line)
+			page := string(m[2])
+			if isImage(page) {
+				page = gtexName(page)
+			}
+			return []byte(string(m[1]) + `"` + page + `"`)
+		})
+		if err := o.write(name, data); err != nil {
+			return err
+		}
+		o.asset(name, goldcore.ResourceFont, name, name)
+		return nil
+	}}
+}
+
+//jsonStep : Checks a JSON file, against its schema if a pattern of the
+//config matches it, and copies it
+func (b *builder) jsonStep(name string) (*step, error) {
+	inputs := []string{name}
+	schemaName := ""
+	patterns := make([]string, 0, len(b.config.Schemas))
+	for pattern := range b.config.Schemas {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		if matchGlob(pattern, name) {
+			schemaName = b.config.Schemas[pattern]
+			if _, ok := b.hashes[schemaName]; !ok {
+				return nil, fmt.Errorf("%s: no schema %s", name, schemaName)
+			}
+			inputs = append(inputs, schemaName)
+			break
+		}
+	}
+	return &step{id: "json:" + name, inputs: inputs, run: func(o *stepOutput) error {
+		data, err := o.read(name)
+		if err != nil {
+			return err
+		}
+		var doc interface{}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		if schemaName != "" {
+			raw, err := o.read(schemaName)
+			if err != nil {
+				return err
+			}
+			s, err := parseSchema(raw)
+			if err != nil {
+				return fmt.Errorf("%s: %v", schemaName, err)
+			}
+			if problems := s.validate(doc); len(problems) > 0 {
+				return fmt.Errorf("%s: does not match %s:\n\t%s", name, schemaName, strings.Join(problems, "\n\t"))
+			}
+		}
+		if err := o.write(name, data); err != nil {
+			return err
+		}
+		o.asset(name, goldcore.ResourceData, name, name)
+		return nil
+	}}, nil
+}
+
+//copyStep : Copies a file as it is
+func (b *builder) copyStep(name string) *step {
+	kind := goldcore.ResourceData
+	switch strings.ToLower(path.Ext(name)) {
+	case ".ttf", ".otf":
+		kind = goldcore.ResourceFont
+	case ".wav", ".ogg", ".flac":
+		kind = goldcore.ResourceSound
+	case ".vert", ".frag":
+		kind = goldcore.ResourceShader
+	}
+	return &step{id: "copy:" + name, inputs: []string{name}, run: func(o *stepOutput) error {
+		data, err := o.read(name)
+		if err != nil {
+			return err
+		}
+		if err := o.write(name, data); err != nil {
+			return err
+		}
+		o.asset(name, kind, name, name)
+		return nil
+	}}
+}
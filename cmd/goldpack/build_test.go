@@ -0,0 +1,286 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dacode45/OldGoldEngine/goldcore"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+//writeFiles : Writes files under dir, by slash separated name
+func writeFiles(t *testing.T, dir string, files map[string][]byte) {
+	t.Helper()
+	for name, data := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+//pngBytes : PNG file of a w x h image of c
+func pngBytes(t *testing.T, w, h int, c color.Color) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+const testConfig = `{
+	"atlases": [{"name": "atlases/sprites", "include": ["sprites/**"], "pageSize": 64}],
+	"fonts": [{"name": "fonts/ui", "source": "fonts/go.ttf", "size": 16, "chars": "AB "}],
+	"schemas": {"levels/*.json": "schemas/level.json"}
+}`
+
+const testSchema = `{
+	"type": "object",
+	"required": ["name", "enemies"],
+	"properties": {
+		"name": {"type": "string", "minLength": 1},
+		"enemies": {"type": "array", "items": {"$ref": "#/$defs/enemy"}}
+	},
+	"$defs": {
+		"enemy": {"type": "object", "properties": {"hp": {"type": "integer", "minimum": 1}}, "required": ["hp"]}
+	}
+}`
+
+//testFNT : BMFont with a PNG page
+const testFNT = `info face="Test" size=8
+common lineHeight=8 base=6 scaleW=4 scaleH=4 pages=1
+page id=0 file="small.png"
+chars count=1
+char id=65 x=0 y=0 width=4 height=4 xoffset=0 yoffset=2 xadvance=5 page=0 chnl=15
+`
+
+func testSources(t *testing.T) string {
+	t.Helper()
+	src := t.TempDir()
+	writeFiles(t, src, map[string][]byte{
+		"goldpack.json":       []byte(testConfig),
+		"hero.png":            pngBytes(t, 5, 3, color.NRGBA{R: 255, A: 128}),
+		"sprites/a.png":       pngBytes(t, 4, 4, color.White),
+		"sprites/deep/b.png":  pngBytes(t, 6, 2, color.Black),
+		"fonts/go.ttf":        goregular.TTF,
+		"fonts/small.fnt":     []byte(testFNT),
+		"fonts/small.png":     pngBytes(t, 4, 4, color.White),
+		"schemas/level.json":  []byte(testSchema),
+		"levels/one.json":     []byte(`{"name": "one", "enemies": [{"hp": 3}]}`),
+		"settings.json":       []byte(`{"volume": 1}`),
+		"shaders/flash.frag":  []byte("void main() { gl_FragColor = vec4(1.0); }"),
+		"sounds/jump.wav":     []byte("RIFF\x24\x00\x00\x00WAVEfmt "),
+		".git/HEAD":           []byte("ignored"),
+		"notes/readme.txt":    []byte("copied"),
+		"sprites/notes.txt":   []byte("not an image"),
+		"sprites/.hidden.png": []byte("ignored"),
+	})
+	return src
+}
+
+func TestBuild(t *testing.T) {
+	src := testSources(t)
+	out := filepath.Join(t.TempDir(), "out")
+	b := newBuilder(src, out)
+	var report bytes.Buffer
+	if err := run(b, &report); err != nil {
+		t.Fatal(err)
+	}
+	//2 images, atlas, baked font, fnt, TTF, 3 JSON files, shader, sound and 2 texts
+	if s := report.String(); s != "goldpack: 13 built, 0 up to date, 0 removed\n" {
+		t.Errorf("Unexpected report %q", s)
+	}
+	if _, err := os.Stat(filepath.Join(out, ".git")); err == nil {
+		t.Error("Dot files are left out")
+	}
+
+	fsys := os.DirFS(out)
+	man, err := goldcore.LoadManifestFS(fsys, manifestFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := man.Verify(fsys); err != nil {
+		t.Error(err)
+	}
+	for name, expected := range map[string]goldcore.ManifestAsset{
+		"hero.png":           {Kind: goldcore.ResourceTexture, File: "hero.gtex"},
+		"sprites/a.png":      {Kind: goldcore.ResourceTexture, File: "atlases/sprites.atlas", Atlas: "atlases/sprites"},
+		"atlases/sprites":    {Kind: goldcore.ResourceAtlas, File: "atlases/sprites.atlas"},
+		"fonts/ui":           {Kind: goldcore.ResourceFont, File: "fonts/ui.fnt"},
+		"fonts/go.ttf":       {Kind: goldcore.ResourceFont, File: "fonts/go.ttf"},
+		"fonts/small.png":    {Kind: goldcore.ResourceTexture, File: "fonts/small.gtex"},
+		"levels/one.json":    {Kind: goldcore.ResourceData, File: "levels/one.json"},
+		"shaders/flash.frag": {Kind: goldcore.ResourceShader, File: "shaders/flash.frag"},
+		"sounds/jump.wav":    {Kind: goldcore.ResourceSound, File: "sounds/jump.wav"},
+		"sprites/notes.txt":  {Kind: goldcore.ResourceData, File: "sprites/notes.txt"},
+	} {
+		asset, ok := man.Assets[name]
+		if !ok || asset.Kind != expected.Kind || asset.File != expected.File || asset.Atlas != expected.Atlas {
+			t.Errorf("%s: expected %+v got %+v", name, expected, asset)
+		}
+	}
+	if hashes := man.Assets["atlases/sprites"].Hashes; len(hashes) != 2 || hashes["atlases/sprites.0.gtex"] == "" {
+		t.Errorf("The atlas and its page should be hashed, got %v", hashes)
+	}
+
+	//The engine loads the build through the manifest
+	m := goldcore.NewResourceManager(nil, 2)
+	defer m.Close()
+	m.SetFileSystem(fsys)
+	m.SetManifest(man)
+	hero := m.Texture("hero.png")
+	atlas := m.Atlas("atlases/sprites")
+	ui := m.Font("fonts/ui")
+	small := m.Font("fonts/small.fnt")
+	flash := m.Shader("shaders/flash")
+	for _, h := range []interface{ Wait() error }{hero, atlas, ui, small, flash} {
+		if err := h.Wait(); err != nil {
+			t.Error(err)
+		}
+	}
+	if hero.Texture().Size() != (goldcore.Vector2u{X: 5, Y: 3}) {
+		t.Errorf("Expected a 5x3 texture got %v", hero.Texture().Size())
+	}
+	if c := hero.Texture().Image().RGBAAt(0, 0); c != (color.RGBA{R: 128, A: 128}) {
+		t.Errorf("Pixels should be premultiplied, got %v", c)
+	}
+	if names := strings.Join(atlas.Atlas().Names(), " "); names != "sprites/a.png sprites/deep/b.png" {
+		t.Errorf("Unexpected regions %s", names)
+	}
+	if g, ok := ui.Font().Glyph('A', 16); !ok || g.Texture == nil {
+		t.Error("The baked font should have A")
+	}
+	if ui.Font().HasGlyph('C') {
+		t.Error("Only the chars asked are baked")
+	}
+	if g, ok := small.Font().Glyph('A', 8); !ok || g.Texture.Size() != (goldcore.Vector2u{X: 4, Y: 4}) {
+		t.Error("BMFont pages should be found as GTEX")
+	}
+}
+
+func TestIncrementalBuild(t *testing.T) {
+	src := testSources(t)
+	out := t.TempDir()
+	b := newBuilder(src, out)
+	build := func(built, skipped, removed int) {
+		t.Helper()
+		if err := b.build(); err != nil {
+			t.Fatal(err)
+		}
+		if b.built != built || b.skipped != skipped || b.removed != removed {
+			t.Errorf("Expected %d built, %d skipped and %d removed got %d, %d and %d", built, skipped, removed, b.built, b.skipped, b.removed)
+		}
+	}
+	build(13, 0, 0)
+	build(0, 13, 0)
+
+	//Only what reads a changed file is built
+	writeFiles(t, src, map[string][]byte{"sprites/a.png": pngBytes(t, 8, 8, color.White)})
+	build(1, 12, 0)
+	//Settings count too
+	writeFiles(t, src, map[string][]byte{"goldpack.json": []byte(strings.Replace(testConfig, `"size": 16`, `"size": 20`, 1))})
+	build(1, 12, 0)
+	//So do outputs that were touched
+	if err := os.WriteFile(filepath.Join(out, "hero.gtex"), []byte("broken"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	build(1, 12, 0)
+	//Timestamps alone change nothing
+	later := time.Now().Add(time.Hour)
+	os.Chtimes(filepath.Join(src, "settings.json"), later, later)
+	build(0, 13, 0)
+
+	//Outputs of removed sources go
+	os.Remove(filepath.Join(src, "hero.png"))
+	build(0, 12, 1)
+	if _, err := os.Stat(filepath.Join(out, "hero.gtex")); err == nil {
+		t.Error("hero.gtex should be removed")
+	}
+	man, err := goldcore.LoadManifestFS(os.DirFS(out), manifestFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := man.Assets["hero.png"]; ok {
+		t.Error("hero.png should leave the manifest")
+	}
+
+	//Invalid data fails the build, listing every problem, and keeps the manifest
+	writeFiles(t, src, map[string][]byte{
+		"levels/one.json": []byte(`{"name": "", "enemies": [{"hp": 0}, {}]}`),
+		"settings.json":   []byte(`{"volume": `),
+	})
+	err = b.build()
+	if err == nil {
+		t.Fatal("Expected the build to fail")
+	}
+	for _, problem := range []string{"$.name: shorter than 1 characters", "$.enemies[0].hp: 0 is less than 1", "$.enemies[1]: missing hp", "settings.json: unexpected end of JSON input"} {
+		if !strings.Contains(err.Error(), problem) {
+			t.Errorf("Expected %q in\n%v", problem, err)
+		}
+	}
+	if _, ok := man.Assets["levels/one.json"]; !ok {
+		t.Error("The last manifest should stay")
+	}
+	writeFiles(t, src, map[string][]byte{
+		"levels/one.json": []byte(`{"name": "one", "enemies": []}`),
+		"settings.json":   []byte(`{"volume": 2}`),
+	})
+	build(2, 10, 0)
+
+	b.force = true
+	build(12, 0, 0)
+}
+
+func TestBuildErrors(t *testing.T) {
+	for name, files := range map[string]map[string][]byte{
+		"bad config":     {"goldpack.json": []byte(`{"fonts": [{"name": "x"}]}`)},
+		"missing font":   {"goldpack.json": []byte(`{"fonts": [{"name": "x", "source": "x.ttf", "size": 8}]}`)},
+		"missing schema": {"goldpack.json": []byte(`{"schemas": {"*.json": "schema.json"}}`), "a.json": []byte("{}")},
+		"bad image":      {"a.png": []byte("not a png")},
+		"same output":    {"a.png": pngBytes(t, 1, 1, color.White), "a.gtex": []byte("?")},
+		"reserved":       {"manifest.json": []byte("{}")},
+	} {
+		src := t.TempDir()
+		writeFiles(t, src, files)
+		if err := newBuilder(src, t.TempDir()).build(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	for _, c := range []struct {
+		pattern, name string
+		match         bool
+	}{
+		{"*.png", "a.png", true},
+		{"*.png", "dir/a.png", false},
+		{"sprites/**", "sprites/a.png", true},
+		{"sprites/**", "sprites/deep/er/a.png", true},
+		{"sprites/**/*.png", "sprites/a.png", true},
+		{"sprites/**/*.png", "sprites/x/a.jpg", false},
+		{"**/*.json", "levels/one.json", true},
+		{"levels/[ab].json", "levels/c.json", false},
+	} {
+		if matchGlob(c.pattern, c.name) != c.match {
+			t.Errorf("%s on %s: expected %v", c.pattern, c.name, c.match)
+		}
+	}
+}
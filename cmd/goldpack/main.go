@@ -0,0 +1,60 @@
+//Command goldpack : Builds game ready assets from a source directory.
+//
+//	goldpack [-force] [-v] src out
+//
+//Images are converted to GTEX, the engine's own compressed format. Images
+//matching an atlas of src/goldpack.json are packed into it instead, and
+//TrueType fonts it lists are baked into bitmap fonts at the sizes asked.
+//JSON files are checked, against a JSON Schema if a pattern of the config
+//matches them. Everything else is copied. The output gets a manifest.json
+//listing every asset with the hashes of its files, for
+//goldcore.ResourceManager.SetManifest:
+//
+//	{
+//		"atlases": [{"name": "atlases/sprites", "include": ["sprites/**"]}],
+//		"fonts": [{"name": "fonts/ui", "source": "fonts/go.ttf", "size": 16}],
+//		"schemas": {"levels/*.json": "schemas/level.json"}
+//	}
+//
+//Builds are incremental: an output is only built again when its sources or
+//settings changed, or when it was touched. -force builds everything
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	force := flag.Bool("force", false, "build everything, even what is up to date")
+	verbose := flag.Bool("v", false, "list what is built")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: goldpack [-force] [-v] src out")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	b := newBuilder(flag.Arg(0), flag.Arg(1))
+	b.force = *force
+	if *verbose {
+		b.log = os.Stdout
+	}
+	if err := run(b, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "goldpack: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+//run : Builds and reports what was done to w
+func run(b *builder, w io.Writer) error {
+	if err := b.build(); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "goldpack: %d built, %d up to date, %d removed\n", b.built, b.skipped, b.removed)
+	return nil
+}
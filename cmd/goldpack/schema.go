@@ -0,0 +1,341 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+//schema : A JSON Schema. The keywords checked are type, enum, const,
+//properties, required, additionalProperties, items, minItems, maxItems,
+//uniqueItems, minimum, maximum, exclusiveMinimum, exclusiveMaximum,
+//multipleOf, minLength, maxLength, pattern, allOf, anyOf, oneOf, not and
+//$ref to the same file. Others, like format, are ignored
+type schema struct {
+	root     interface{}
+	patterns map[string]*regexp.Regexp
+}
+
+//parseSchema : Schema from JSON, checking its patterns compile
+func parseSchema(data []byte) (*schema, error) {
+	s := &schema{patterns: make(map[string]*regexp.Regexp)}
+	if err := json.Unmarshal(data, &s.root); err != nil {
+		return nil, err
+	}
+	var compile func(node interface{}) error
+	compile = func(node interface{}) error {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			if p, ok := n["pattern"].(string); ok {
+				re, err := regexp.Compile(p)
+				if err != nil {
+					return fmt.Errorf("pattern %q: %v", p, err)
+				}
+				s.patterns[p] = re
+			}
+			for _, child := range n {
+				if err := compile(child); err != nil {
+					return err
+				}
+			}
+		case []interface{}:
+			for _, child := range n {
+				if err := compile(child); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if err := compile(s.root); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+//validate : Problems of doc, as decoded by encoding/json, each starting
+//with where it is like $.enemies[2].hp. Empty if doc matches
+func (s *schema) validate(doc interface{}) []string {
+	var problems []string
+	s.check(s.root, doc, "$", &problems, 0)
+	return problems
+}
+
+//maxRefDepth : How many $ref deep validation goes, stops recursive schemas
+//from looping
+const maxRefDepth = 64
+
+//check : Adds the problems of v against node to problems
+func (s *schema) check(node, v interface{}, at string, problems *[]string, depth int) {
+	fail := func(format string, args ...interface{}) {
+		*problems = append(*problems, at+": "+fmt.Sprintf(format, args...))
+	}
+	if allowed, ok := node.(bool); ok {
+		if !allowed {
+			fail("not allowed")
+		}
+		return
+	}
+	n, ok := node.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	if ref, ok := n["$ref"].(string); ok {
+		target, err := s.resolve(ref)
+		if err != nil || depth >= maxRefDepth {
+			fail("bad $ref %s", ref)
+		} else {
+			s.check(target, v, at, problems, depth+1)
+		}
+	}
+	if t, ok := n["type"]; ok && !matchesType(t, v) {
+		fail("expected %s, got %s", typeNames(t), jsonType(v))
+		return
+	}
+	if enum, ok := n["enum"].([]interface{}); ok {
+		found := false
+		for _, e := range enum {
+			if reflect.DeepEqual(e, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("%s is not one of %s", compact(v), compact(enum))
+		}
+	}
+	if c, ok := n["const"]; ok && !reflect.DeepEqual(c, v) {
+		fail("expected %s, got %s", compact(c), compact(v))
+	}
+
+	switch value := v.(type) {
+	case map[string]interface{}:
+		s.checkObject(n, value, at, problems, depth)
+	case []interface{}:
+		s.checkArray(n, value, at, problems, depth)
+	case float64:
+		checkNumber(n, value, fail)
+	case string:
+		length := float64(utf8.RuneCountInString(value))
+		if min, ok := n["minLength"].(float64); ok && length < min {
+			fail("shorter than %v characters", min)
+		}
+		if max, ok := n["maxLength"].(float64); ok && length > max {
+			fail("longer than %v characters", max)
+		}
+		if p, ok := n["pattern"].(string); ok && !s.patterns[p].MatchString(value) {
+			fail("%q does not match %s", value, p)
+		}
+	}
+
+	if all, ok := n["allOf"].([]interface{}); ok {
+		for _, sub := range all {
+			s.check(sub, v, at, problems, depth)
+		}
+	}
+	if anyOf, ok := n["anyOf"].([]interface{}); ok {
+		matched := false
+		for _, sub := range anyOf {
+			if s.matches(sub, v, at, depth) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			fail("matches none of anyOf")
+		}
+	}
+	if oneOf, ok := n["oneOf"].([]interface{}); ok {
+		count := 0
+		for _, sub := range oneOf {
+			if s.matches(sub, v, at, depth) {
+				count++
+			}
+		}
+		if count != 1 {
+			fail("matches %d of oneOf, expected 1", count)
+		}
+	}
+	if not, ok := n["not"]; ok && s.matches(not, v, at, depth) {
+		fail("matches not")
+	}
+}
+
+//matches : Checks whether v matches node without reporting why
+func (s *schema) matches(node, v interface{}, at string, depth int) bool {
+	var problems []string
+	s.check(node, v, at, &problems, depth)
+	return len(problems) == 0
+}
+
+func (s *schema) checkObject(n, value map[string]interface{}, at string, problems *[]string, depth int) {
+	if required, ok := n["required"].([]interface{}); ok {
+		for _, r := range required {
+			if name, ok := r.(string); ok {
+				if _, ok := value[name]; !ok {
+					*problems = append(*problems, fmt.Sprintf("%s: missing %s", at, name))
+				}
+			}
+		}
+	}
+	properties, _ := n["properties"].(map[string]interface{})
+	additional, hasAdditional := n["additionalProperties"]
+	names := make([]string, 0, len(value))
+	for name := range value {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		where := at + "." + name
+		if sub, ok := properties[name]; ok {
+			s.check(sub, value[name], where, problems, depth)
+		} else if hasAdditional {
+			if allowed, ok := additional.(bool); ok && !allowed {
+				*problems = append(*problems, fmt.Sprintf("%s: unexpected property", where))
+			} else {
+				s.check(additional, value[name], where, problems, depth)
+			}
+		}
+	}
+}
+
+func (s *schema) checkArray(n map[string]interface{}, value []interface{}, at string, problems *[]string, depth int) {
+	count := float64(len(value))
+	if min, ok := n["minItems"].(float64); ok && count < min {
+		*problems = append(*problems, fmt.Sprintf("%s: fewer than %v items", at, min))
+	}
+	if max, ok := n["maxItems"].(float64); ok && count > max {
+		*problems = append(*problems, fmt.Sprintf("%s: more than %v items", at, max))
+	}
+	if unique, ok := n["uniqueItems"].(bool); ok && unique {
+		for i := range value {
+			for j := 0; j < i; j++ {
+				if reflect.DeepEqual(value[i], value[j]) {
+					*problems = append(*problems, fmt.Sprintf("%s: items %d and %d are the same", at, j, i))
+				}
+			}
+		}
+	}
+	if items, ok := n["items"]; ok {
+		for i, item := range value {
+			s.check(items, item, at+"["+strconv.Itoa(i)+"]", problems, depth)
+		}
+	}
+}
+
+func checkNumber(n map[string]interface{}, value float64, fail func(string, ...interface{})) {
+	if min, ok := n["minimum"].(float64); ok && value < min {
+		fail("%v is less than %v", value, min)
+	}
+	if max, ok := n["maximum"].(float64); ok && value > max {
+		fail("%v is more than %v", value, max)
+	}
+	if min, ok := n["exclusiveMinimum"].(float64); ok && value <= min {
+		fail("%v is not more than %v", value, min)
+	}
+	if max, ok := n["exclusiveMaximum"].(float64); ok && value >= max {
+		fail("%v is not less than %v", value, max)
+	}
+	if m, ok := n["multipleOf"].(float64); ok && m > 0 {
+		if q := value / m; math.Abs(q-math.Round(q)) > 1e-9 {
+			fail("%v is not a multiple of %v", value, m)
+		}
+	}
+}
+
+//resolve : Part of the schema a local $ref like #/$defs/enemy points at
+func (s *schema) resolve(ref string) (interface{}, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("only local references are supported")
+	}
+	node := s.root
+	pointer := strings.TrimPrefix(ref, "#")
+	if pointer == "" {
+		return node, nil
+	}
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch n := node.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if node, ok = n[token]; !ok {
+				return nil, fmt.Errorf("no %s", token)
+			}
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(n) {
+				return nil, fmt.Errorf("no %s", token)
+			}
+			node = n[i]
+		default:
+			return nil, fmt.Errorf("no %s", token)
+		}
+	}
+	return node, nil
+}
+
+//matchesType : Checks v against a type keyword, a name or a list of names
+func matchesType(t, v interface{}) bool {
+	switch t := t.(type) {
+	case string:
+		actual := jsonType(v)
+		return actual == t || (t == "number" && actual == "integer")
+	case []interface{}:
+		for _, name := range t {
+			if matchesType(name, v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+//jsonType : JSON type of v, integer for whole numbers
+func jsonType(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+//typeNames : Readable type keyword
+func typeNames(t interface{}) string {
+	if list, ok := t.([]interface{}); ok {
+		names := make([]string, len(list))
+		for i, name := range list {
+			names[i] = fmt.Sprint(name)
+		}
+		return strings.Join(names, " or ")
+	}
+	return fmt.Sprint(t)
+}
+
+//compact : v as short JSON for messages
+func compact(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSchema(t *testing.T) {
+	for _, c := range []struct {
+		schema, doc string
+		problems    []string
+	}{
+		{`true`, `{"anything": 1}`, nil},
+		{`false`, `1`, []string{"$: not allowed"}},
+		{`{"type": "integer"}`, `2.0`, nil},
+		{`{"type": "integer"}`, `2.5`, []string{"$: expected integer, got number"}},
+		{`{"type": "number"}`, `2`, nil},
+		{`{"type": ["string", "null"]}`, `true`, []string{"$: expected string or null, got boolean"}},
+		{`{"enum": ["red", 2]}`, `"blue"`, []string{`$: "blue" is not one of ["red",2]`}},
+		{`{"const": {"a": [1]}}`, `{"a": [1]}`, nil},
+		{`{"minimum": 0, "exclusiveMaximum": 10, "multipleOf": 0.5}`, `10`, []string{"$: 10 is not less than 10"}},
+		{`{"multipleOf": 0.1}`, `0.3`, nil},
+		{`{"multipleOf": 2}`, `3`, []string{"$: 3 is not a multiple of 2"}},
+		{`{"maxLength": 2, "pattern": "^[a-z]+$"}`, `"héé"`, []string{"$: longer than 2 characters", `$: "héé" does not match ^[a-z]+$`}},
+		{`{"minLength": 3}`, `"héé"`, nil},
+		{`{"minItems": 1, "uniqueItems": true, "items": {"type": "string"}}`, `["a", 1, "a"]`, []string{"$: items 0 and 2 are the same", "$[1]: expected string, got integer"}},
+		{`{"maxItems": 1}`, `[1, 2]`, []string{"$: more than 1 items"}},
+		{
+			`{"properties": {"a": {"type": "string"}}, "additionalProperties": false, "required": ["a", "b"]}`,
+			`{"a": 1, "c": 2}`,
+			[]string{"$: missing b", "$.a: expected string, got integer", "$.c: unexpected property"},
+		},
+		{`{"additionalProperties": {"type": "integer"}}`, `{"x": 1, "y": "no"}`, []string{"$.y: expected integer, got string"}},
+		{`{"anyOf": [{"type": "string"}, {"minimum": 5}]}`, `3`, []string{"$: matches none of anyOf"}},
+		{`{"oneOf": [{"type": "integer"}, {"minimum": 5}]}`, `7`, []string{"$: matches 2 of oneOf, expected 1"}},
+		{`{"allOf": [{"type": "integer"}, {"minimum": 5}]}`, `3`, []string{"$: 3 is less than 5"}},
+		{`{"not": {"type": "null"}}`, `null`, []string{"$: matches not"}},
+		{
+			`{"$defs": {"node": {"type": "object", "properties": {"next": {"$ref": "#/$defs/node"}, "v": {"type": "integer"}}}}, "$ref": "#/$defs/node"}`,
+			`{"v": 1, "next": {"v": 2, "next": {"v": "three"}}}`,
+			[]string{"$.next.next.v: expected integer, got string"},
+		},
+		{`{"$ref": "#/missing"}`, `1`, []string{"$: bad $ref #/missing"}},
+		{`{"$ref": "#"}`, `1`, []string{"$: bad $ref #"}},
+		{`{"format": "email", "unknown": 1}`, `"not checked"`, nil},
+	} {
+		s, err := parseSchema([]byte(c.schema))
+		if err != nil {
+			t.Errorf("%s: %v", c.schema, err)
+			continue
+		}
+		var doc interface{}
+		if err := json.Unmarshal([]byte(c.doc), &doc); err != nil {
+			t.Fatal(err)
+		}
+		problems := s.validate(doc)
+		if strings.Join(problems, "\n") != strings.Join(c.problems, "\n") {
+			t.Errorf("%s on %s: expected %q got %q", c.schema, c.doc, c.problems, problems)
+		}
+	}
+	for _, bad := range []string{`{"pattern": "("}`, `{`} {
+		if _, err := parseSchema([]byte(bad)); err == nil {
+			t.Errorf("%s should fail", bad)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package goldframework
+
+import "github.com/Dacode45/OldGoldEngine/goldcore"
+
+//Main : Call from main(). Runs run on its own goroutine while the main
+//thread, locked by init, executes everything queued on goldcore.MainThread.
+//Render commands (goldcore.Do and goldcore.DoSync) are left to the render
+//loop, which owns the GL context. Returns once run does
+func Main(run func()) {
+	done := make(chan struct{})
+	if err := goldcore.MainThread.Attach(); err != nil {
+		panic(err)
+	}
+	defer goldcore.MainThread.Detach()
+	go func() {
+		defer close(done)
+		run()
+	}()
+	for {
+		select {
+		case <-goldcore.MainThread.Ready():
+			goldcore.MainThread.Drain()
+		case <-done:
+			return
+		}
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/sadlil/gologger"
 )
 
+//init : Keeps the main goroutine on the main OS thread. Windowing and GL
+//calls have to come from it on some platforms, Main hands it the work
 func init() {
 	runtime.LockOSThread()
 }
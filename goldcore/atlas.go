@@ -0,0 +1,354 @@
+package goldcore
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/draw"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+)
+
+//RectPacker : Places rectangles in a bin without overlaps, using MaxRects
+//with the best short side fit. Packing the biggest rectangles first gives
+//the tightest results
+type RectPacker struct {
+	width, height int
+	free          []RectI //Maximal free rectangles, they may overlap
+}
+
+//NewRectPacker : Empty bin of the given size
+func NewRectPacker(width, height int) *RectPacker {
+	return &RectPacker{width: width, height: height, free: []RectI{{Width: width, Height: height}}}
+}
+
+//Size : Size of the bin
+func (p *RectPacker) Size() Vector2i {
+	return Vector2i{X: p.width, Y: p.height}
+}
+
+//Pack : Finds room for a width by height rectangle and reserves it. False
+//if it doesn't fit anymore
+func (p *RectPacker) Pack(width, height int) (RectI, bool) {
+	if width <= 0 || height <= 0 {
+		return RectI{}, false
+	}
+	best, bestShort, bestLong := -1, 0, 0
+	for i, f := range p.free {
+		if f.Width < width || f.Height < height {
+			continue
+		}
+		//Leftover along the tighter and the looser side
+		short := minInt(f.Width-width, f.Height-height)
+		long := maxInt(f.Width-width, f.Height-height)
+		if best < 0 || short < bestShort || (short == bestShort && long < bestLong) {
+			best, bestShort, bestLong = i, short, long
+		}
+	}
+	if best < 0 {
+		return RectI{}, false
+	}
+	placed := RectI{Left: p.free[best].Left, Top: p.free[best].Top, Width: width, Height: height}
+	p.split(placed)
+	return placed, true
+}
+
+//split : Carves placed out of every free rectangle it overlaps, then drops
+//free rectangles contained in others
+func (p *RectPacker) split(placed RectI) {
+	free := p.free[:0:0]
+	for _, f := range p.free {
+		if !f.Intersects(placed) {
+			free = append(free, f)
+			continue
+		}
+		//Up to 4 maximal pieces around placed
+		if placed.Left > f.Left {
+			free = append(free, RectI{Left: f.Left, Top: f.Top, Width: placed.Left - f.Left, Height: f.Height})
+		}
+		if placed.Right() < f.Right() {
+			free = append(free, RectI{Left: placed.Right(), Top: f.Top, Width: f.Right() - placed.Right(), Height: f.Height})
+		}
+		if placed.Top > f.Top {
+			free = append(free, RectI{Left: f.Left, Top: f.Top, Width: f.Width, Height: placed.Top - f.Top})
+		}
+		if placed.Bottom() < f.Bottom() {
+			free = append(free, RectI{Left: f.Left, Top: placed.Bottom(), Width: f.Width, Height: f.Bottom() - placed.Bottom()})
+		}
+	}
+	//Prune
+	pruned := free[:0:0]
+	for i, f := range free {
+		contained := false
+		for j, o := range free {
+			if i != j && o.ContainsRect(f) && (o != f || j < i) {
+				contained = true
+				break
+			}
+		}
+		if !contained {
+			pruned = append(pruned, f)
+		}
+	}
+	p.free = pruned
+}
+
+/////////////////////////////////////
+///		ATLAS
+/////////////////////////////////////
+
+//AtlasRegion : Where an image ended up in an atlas
+type AtlasRegion struct {
+	Texture *Texture
+	Rect    RectI
+}
+
+//Sprite : Sprite showing the region
+func (region AtlasRegion) Sprite() *Sprite {
+	sprite := NewSprite(region.Texture)
+	sprite.TextureRect = region.Rect
+	return sprite
+}
+
+//Atlas : Small images packed into a few big textures, so sprites using
+//them can be drawn together by a SpriteBatch
+type Atlas struct {
+	Pages   []*Texture
+	regions map[string]AtlasRegion
+}
+
+//Region : Where the image added as name is. False if there is none
+func (atlas *Atlas) Region(name string) (AtlasRegion, bool) {
+	region, ok := atlas.regions[name]
+	return region, ok
+}
+
+//Sprite : Sprite showing the image added as name, nil if there is none
+func (atlas *Atlas) Sprite(name string) *Sprite {
+	region, ok := atlas.regions[name]
+	if !ok {
+		return nil
+	}
+	return region.Sprite()
+}
+
+//Names : Names of the images in the atlas, sorted
+func (atlas *Atlas) Names() []string {
+	names := make([]string, 0, len(atlas.regions))
+	for name := range atlas.regions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+//AtlasBuilder : Collects images at load time and packs them into an Atlas
+type AtlasBuilder struct {
+	//PageSize : Biggest width and height of a page. 2048 by default
+	PageSize int
+	//Padding : Pixels between images, filled by repeating their edges so
+	//smooth textures don't bleed into their neighbours. 1 by default
+	Padding int
+	images  map[string]image.Image
+}
+
+//NewAtlasBuilder : Builder with 2048 pixel pages and 1 pixel of padding
+func NewAtlasBuilder() *AtlasBuilder {
+	return &AtlasBuilder{PageSize: 2048, Padding: 1, images: make(map[string]image.Image)}
+}
+
+//Add : Adds img to the atlas as name, replacing any image with that name
+func (b *AtlasBuilder) Add(name string, img image.Image) {
+	b.images[name] = img
+}
+
+//Len : Number of images added
+func (b *AtlasBuilder) Len() int {
+	return len(b.images)
+}
+
+//Build : Packs every image, tallest first, into as few pages as needed.
+//Each page is cropped to what it uses. Fails if an image is bigger than a
+//page
+func (b *AtlasBuilder) Build() (*Atlas, error) {
+	type entry struct {
+		name string
+		img  image.Image
+		size image.Point
+	}
+	entries := make([]entry, 0, len(b.images))
+	for name, img := range b.images {
+		size := img.Bounds().Size()
+		if size.X+2*b.Padding > b.PageSize || size.Y+2*b.Padding > b.PageSize {
+			return nil, fmt.Errorf("goldcore: atlas image %s is %dx%d, bigger than a %d pixel page", name, size.X, size.Y, b.PageSize)
+		}
+		entries = append(entries, entry{name: name, img: img, size: size})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, c := entries[i], entries[j]
+		if a.size.Y != c.size.Y {
+			return a.size.Y > c.size.Y
+		}
+		if a.size.X != c.size.X {
+			return a.size.X > c.size.X
+		}
+		return a.name < c.name
+	})
+
+	type page struct {
+		packer *RectPacker
+		pixels *image.RGBA
+		used   image.Point
+		names  []string
+	}
+	var pages []*page
+	rects := make(map[string]RectI, len(entries))
+	for _, e := range entries {
+		w, h := e.size.X+2*b.Padding, e.size.Y+2*b.Padding
+		var target *page
+		var cell RectI
+		for _, p := range pages {
+			if r, ok := p.packer.Pack(w, h); ok {
+				target, cell = p, r
+				break
+			}
+		}
+		if target == nil {
+			target = &page{
+				packer: NewRectPacker(b.PageSize, b.PageSize),
+				pixels: image.NewRGBA(image.Rect(0, 0, b.PageSize, b.PageSize)),
+			}
+			pages = append(pages, target)
+			cell, _ = target.packer.Pack(w, h)
+		}
+		rect := RectI{Left: cell.Left + b.Padding, Top: cell.Top + b.Padding, Width: e.size.X, Height: e.size.Y}
+		blitPadded(target.pixels, rect, e.img, b.Padding)
+		target.used.X = maxInt(target.used.X, cell.Right())
+		target.used.Y = maxInt(target.used.Y, cell.Bottom())
+		target.names = append(target.names, e.name)
+		rects[e.name] = rect
+	}
+
+	atlas := &Atlas{regions: make(map[string]AtlasRegion, len(entries))}
+	for _, p := range pages {
+		tex := NewTexture(p.pixels.SubImage(image.Rectangle{Max: p.used}))
+		atlas.Pages = append(atlas.Pages, tex)
+		for _, name := range p.names {
+			atlas.regions[name] = AtlasRegion{Texture: tex, Rect: rects[name]}
+		}
+	}
+	return atlas, nil
+}
+
+//blitPadded : Draws img into rect of dst and repeats its edge pixels over
+//padding pixels around it
+func blitPadded(dst *image.RGBA, rect RectI, img image.Image, padding int) {
+	bounds := img.Bounds()
+	r := image.Rect(rect.Left, rect.Top, rect.Right(), rect.Bottom())
+	draw.Draw(dst, r, img, bounds.Min, draw.Src)
+	if padding <= 0 || r.Empty() {
+		return
+	}
+	for y := r.Min.Y - padding; y < r.Max.Y+padding; y++ {
+		for x := r.Min.X - padding; x < r.Max.X+padding; x++ {
+			if (image.Point{X: x, Y: y}).In(r) {
+				continue
+			}
+			sx, sy := clampInt(x, r.Min.X, r.Max.X-1), clampInt(y, r.Min.Y, r.Max.Y-1)
+			dst.SetRGBA(x, y, dst.RGBAAt(sx, sy))
+		}
+	}
+}
+
+/////////////////////////////////////
+///		ATLAS FILES
+/////////////////////////////////////
+
+//atlasFile : JSON layout of an atlas file. Pages are image files next to it,
+//rects are left, top, width and height
+type atlasFile struct {
+	Pages   []string                   `json:"pages"`
+	Regions map[string]atlasFileRegion `json:"regions"`
+}
+
+type atlasFileRegion struct {
+	Page int    `json:"page"`
+	Rect [4]int `json:"rect"`
+}
+
+//EncodeAtlas : Writes where the regions of atlas are as JSON, pages[i]
+//being the file name the page atlas.Pages[i] is saved under
+func EncodeAtlas(w io.Writer, atlas *Atlas, pages []string) error {
+	if len(pages) != len(atlas.Pages) {
+		return fmt.Errorf("goldcore: atlas has %d pages, got %d names", len(atlas.Pages), len(pages))
+	}
+	file := atlasFile{Pages: pages, Regions: make(map[string]atlasFileRegion, len(atlas.regions))}
+	for name, region := range atlas.regions {
+		page := -1
+		for i, tex := range atlas.Pages {
+			if tex == region.Texture {
+				page = i
+			}
+		}
+		if page < 0 {
+			return fmt.Errorf("goldcore: atlas region %s is not on a page", name)
+		}
+		r := region.Rect
+		file.Regions[name] = atlasFileRegion{Page: page, Rect: [4]int{r.Left, r.Top, r.Width, r.Height}}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(file)
+}
+
+//ParseAtlas : Atlas from the JSON written by EncodeAtlas. loadPage is
+//called with the file name of every page
+func ParseAtlas(r io.Reader, loadPage func(name string) (*Texture, error)) (*Atlas, error) {
+	var file atlasFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, err
+	}
+	atlas := &Atlas{regions: make(map[string]AtlasRegion, len(file.Regions))}
+	for _, name := range file.Pages {
+		tex, err := loadPage(name)
+		if err != nil {
+			return nil, fmt.Errorf("page %s: %v", name, err)
+		}
+		atlas.Pages = append(atlas.Pages, tex)
+	}
+	for name, region := range file.Regions {
+		if region.Page < 0 || region.Page >= len(atlas.Pages) {
+			return nil, fmt.Errorf("region %s: page %d out of range", name, region.Page)
+		}
+		r := RectI{Left: region.Rect[0], Top: region.Rect[1], Width: region.Rect[2], Height: region.Rect[3]}
+		atlas.regions[name] = AtlasRegion{Texture: atlas.Pages[region.Page], Rect: r}
+	}
+	return atlas, nil
+}
+
+//LoadAtlas : Atlas from a file written by EncodeAtlas, its pages are loaded
+//from the same directory
+func LoadAtlas(path string) (*Atlas, error) {
+	return LoadAtlasFS(osFS{}, filepath.ToSlash(path))
+}
+
+//LoadAtlasFS : Atlas from a file of fsys written by EncodeAtlas, its pages
+//are loaded from the same directory of fsys
+func LoadAtlasFS(fsys fs.FS, name string) (*Atlas, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	dir := path.Dir(name)
+	atlas, err := ParseAtlas(file, func(page string) (*Texture, error) {
+		return LoadTextureFS(fsys, path.Join(dir, page))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("goldcore: loading atlas %s: %v", name, err)
+	}
+	return atlas, nil
+}
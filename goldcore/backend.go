@@ -0,0 +1,105 @@
+package goldcore
+
+import (
+	"image"
+
+	sf "github.com/manyminds/gosfml"
+)
+
+//windowBackend : What a GameWindow actually talks to. The SFML backend opens
+//a real window, the headless backend keeps everything in memory so the engine
+//can run without a display or a GPU.
+type windowBackend interface {
+	PollEvent() sf.Event
+	IsOpen() bool
+	Close()
+	SetActive(active bool) bool
+	Display()
+	//Present : Displays the frame through the post processing chain.
+	//beforeDisplay, if not nil, is called once the final frame is drawn and
+	//right before it is shown, while Capture still reads it
+	Present(chain *PostChain, beforeDisplay func())
+	GetSize() sf.Vector2u
+	SetSize(size sf.Vector2u)
+	GetPosition() sf.Vector2i
+	SetPosition(pos sf.Vector2i)
+	SetTitle(title string)
+	Clear(c Color)
+	DrawImage(img image.Image, pos Vector2i) error
+	DrawVertices(vertices []Vertex, primitive PrimitiveType, states RenderStates)
+	//Capture : Copy of the window's pixels, see RenderTarget.Capture for what
+	//each backend returns
+	Capture() *image.RGBA
+}
+
+//sfmlWindow : windowBackend backed by an sf.RenderWindow
+type sfmlWindow struct {
+	*sf.RenderWindow
+	view   *sf.View             //Set by the camera, nil for the default one
+	scene  *sf.RenderTexture    //What frames are drawn to while post processing
+	passes [2]*sf.RenderTexture //Results of the post processing passes
+	images imageUpload
+}
+
+//newSFMLWindow : Opens a new SFML window
+func newSFMLWindow(width, height uint, name string) *sfmlWindow {
+	return &sfmlWindow{
+		RenderWindow: sf.NewRenderWindow(sf.VideoMode{Width: width, Height: height, BitsPerPixel: 32}, name, sf.StyleDefault, sf.DefaultContextSettings()),
+	}
+}
+
+//target : Where frames are drawn, off screen while post processing
+func (w *sfmlWindow) target() sf.RenderTarget {
+	if w.scene != nil {
+		return w.scene
+	}
+	return w.RenderWindow
+}
+
+//SetView : Draws through view from now on
+func (w *sfmlWindow) SetView(view *sf.View) {
+	w.view = view
+	w.RenderWindow.SetView(view)
+	if w.scene != nil {
+		w.scene.SetView(view)
+	}
+}
+
+//Clear : Fills the window with c
+func (w *sfmlWindow) Clear(c Color) {
+	w.target().Clear(c.ToSFML())
+}
+
+//DrawImage : Uploads img and draws it at pos
+func (w *sfmlWindow) DrawImage(img image.Image, pos Vector2i) error {
+	return w.images.draw(w.target(), img, pos)
+}
+
+//DrawVertices : Draws vertices through SFML
+func (w *sfmlWindow) DrawVertices(vertices []Vertex, primitive PrimitiveType, states RenderStates) {
+	drawVerticesSFML(w.target(), vertices, primitive, states)
+}
+
+//Capture : Reads the window's buffer back from the GPU as it is now. Between
+//frames that is the frame being drawn, not the one on screen, and after
+//Display its contents are undefined. Must be called from the thread owning
+//the GL context
+func (w *sfmlWindow) Capture() *image.RGBA {
+	return SFImageToRGBA(w.RenderWindow.Capture())
+}
+
+//SFImageToRGBA : SFML Image to image.RGBA. SFML stores non premultiplied
+//RGBA8 rows, image.RGBA is premultiplied
+func SFImageToRGBA(img *sf.Image) *image.RGBA {
+	size := img.GetSize()
+	rgba := image.NewRGBA(image.Rect(0, 0, int(size.X), int(size.Y)))
+	copy(rgba.Pix, img.GetPixelData())
+	for i := 0; i < len(rgba.Pix); i += 4 {
+		if a := uint32(rgba.Pix[i+3]); a != 0xff {
+			rgba.Pix[i] = uint8(uint32(rgba.Pix[i]) * a / 0xff)
+			rgba.Pix[i+1] = uint8(uint32(rgba.Pix[i+1]) * a / 0xff)
+			rgba.Pix[i+2] = uint8(uint32(rgba.Pix[i+2]) * a / 0xff)
+		}
+	}
+	return rgba
+}
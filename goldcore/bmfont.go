@@ -0,0 +1,359 @@
+package goldcore
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//BMFont : Bitmap font in the AngelCode BMFont text format (.fnt), as
+//exported by BMFont, Hiero or Glyph Designer. Glyphs are drawn at the size
+//they were exported at and scaled for other sizes
+type BMFont struct {
+	size       float32 //Size the glyphs were drawn at
+	lineHeight float32
+	base       float32 //Top of the line to the baseline
+	pages      []*Texture
+	chars      map[rune]bmChar
+	kernings   map[[2]rune]float32
+}
+
+//bmChar : A glyph as stored in the font, at its size
+type bmChar struct {
+	rect    RectI
+	offset  Vector2f //Top of the line to the top left of the image
+	advance float32
+	page    int
+}
+
+//LoadBMFont : BMFont from a .fnt file, its pages are loaded from the same
+//directory
+func LoadBMFont(path string) (*BMFont, error) {
+	return LoadBMFontFS(osFS{}, filepath.ToSlash(path))
+}
+
+//LoadBMFontFS : BMFont from a .fnt file of fsys, its pages are loaded from
+//the same directory of fsys
+func LoadBMFontFS(fsys fs.FS, name string) (*BMFont, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	dir := path.Dir(name)
+	bm, err := ParseBMFont(file, func(page string) (*Texture, error) {
+		return LoadTextureFS(fsys, path.Join(dir, page))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("goldcore: loading font %s: %v", name, err)
+	}
+	return bm, nil
+}
+
+//ParseBMFont : BMFont from .fnt text. loadPage is called with the file name
+//of every page
+func ParseBMFont(r io.Reader, loadPage func(name string) (*Texture, error)) (*BMFont, error) {
+	bm := &BMFont{chars: make(map[rune]bmChar), kernings: make(map[[2]rune]float32)}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		tag, attrs := parseBMFontLine(scanner.Text())
+		var err error
+		switch tag {
+		case "info":
+			var size int
+			size, err = attrs.int("size")
+			//Negative sizes mean the size is the character height
+			bm.size = absFloat(float32(size))
+		case "common":
+			var lineHeight, base int
+			if lineHeight, err = attrs.int("lineHeight"); err == nil {
+				base, err = attrs.int("base")
+			}
+			bm.lineHeight, bm.base = float32(lineHeight), float32(base)
+		case "page":
+			var id int
+			if id, err = attrs.int("id"); err != nil {
+				break
+			}
+			if id < 0 || id > 255 {
+				err = fmt.Errorf("page id %d out of range", id)
+				break
+			}
+			for len(bm.pages) <= id {
+				bm.pages = append(bm.pages, nil)
+			}
+			bm.pages[id], err = loadPage(attrs["file"])
+		case "char":
+			err = bm.parseChar(attrs)
+		case "kerning":
+			var first, second, amount int
+			if first, err = attrs.int("first"); err == nil {
+				if second, err = attrs.int("second"); err == nil {
+					amount, err = attrs.int("amount")
+				}
+			}
+			bm.kernings[[2]rune{rune(first), rune(second)}] = float32(amount)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", n, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if bm.size == 0 {
+		bm.size = bm.lineHeight
+	}
+	if bm.size == 0 {
+		return nil, fmt.Errorf("no info or common line")
+	}
+	return bm, nil
+}
+
+func (bm *BMFont) parseChar(attrs bmAttributes) error {
+	var v [8]int
+	for i, name := range []string{"id", "x", "y", "width", "height", "xoffset", "yoffset", "xadvance"} {
+		var err error
+		if v[i], err = attrs.int(name); err != nil {
+			return err
+		}
+	}
+	page, err := attrs.int("page")
+	if err != nil {
+		page = 0
+	}
+	bm.chars[rune(v[0])] = bmChar{
+		rect:    RectI{Left: v[1], Top: v[2], Width: v[3], Height: v[4]},
+		offset:  Vector2f{X: float32(v[5]), Y: float32(v[6])},
+		advance: float32(v[7]),
+		page:    page,
+	}
+	return nil
+}
+
+//Size : Size the glyphs were drawn at. Other sizes are scaled
+func (bm *BMFont) Size() float32 {
+	return bm.size
+}
+
+//Pages : Textures holding the glyphs
+func (bm *BMFont) Pages() []*Texture {
+	return bm.pages
+}
+
+//Glyph : Glyph of r scaled to size pixels. Runes the font lacks get the
+//glyph of '?' if it has one, and false
+func (bm *BMFont) Glyph(r rune, size float32) (Glyph, bool) {
+	c, ok := bm.chars[r]
+	if !ok {
+		c = bm.chars['?']
+	}
+	scale := size / bm.size
+	g := Glyph{
+		Advance: c.advance * scale,
+		Bounds: RectF{
+			Left: c.offset.X * scale, Top: (c.offset.Y - bm.base) * scale,
+			Width: float32(c.rect.Width) * scale, Height: float32(c.rect.Height) * scale,
+		},
+		TextureRect: c.rect,
+	}
+	if c.page >= 0 && c.page < len(bm.pages) && !c.rect.IsEmpty() {
+		g.Texture = bm.pages[c.page]
+	}
+	return g, ok
+}
+
+//HasGlyph : Checks whether the font has a glyph for r
+func (bm *BMFont) HasGlyph(r rune) bool {
+	_, ok := bm.chars[r]
+	return ok
+}
+
+//Kerning : Kerning of the pair scaled to size
+func (bm *BMFont) Kerning(a, b rune, size float32) float32 {
+	return bm.kernings[[2]rune{a, b}] * size / bm.size
+}
+
+//Metrics : Metrics scaled to size
+func (bm *BMFont) Metrics(size float32) FontMetrics {
+	scale := size / bm.size
+	return FontMetrics{Ascent: bm.base * scale, Descent: (bm.lineHeight - bm.base) * scale, LineHeight: bm.lineHeight * scale}
+}
+
+//BakeBMFont : Draws the glyphs of chars at size pixels from f into atlas
+//pages of at most pageSize pixels, 2048 if 0, so the font needs no
+//rasterizing at run time. Runes f lacks are left out. Kerning between every
+//pair of chars is kept
+func BakeBMFont(f Font, size int, chars []rune, pageSize int) (*BMFont, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("goldcore: baking a font at %d pixels", size)
+	}
+	px := float32(size)
+	metrics := f.Metrics(px)
+	bm := &BMFont{
+		size:       px,
+		lineHeight: roundFloat(metrics.LineHeight),
+		base:       roundFloat(metrics.Ascent),
+		chars:      make(map[rune]bmChar),
+		kernings:   make(map[[2]rune]float32),
+	}
+	builder := NewAtlasBuilder()
+	if pageSize > 0 {
+		builder.PageSize = pageSize
+	}
+	glyphs := make(map[rune]Glyph)
+	var order []rune
+	for _, r := range chars {
+		if _, ok := glyphs[r]; ok || !f.HasGlyph(r) {
+			continue
+		}
+		g, _ := f.Glyph(r, px)
+		glyphs[r] = g
+		order = append(order, r)
+	}
+	//Image is a copy, pages are read once every glyph is on them
+	pages := make(map[*Texture]*image.RGBA)
+	for _, r := range order {
+		g := glyphs[r]
+		if g.Texture == nil {
+			continue
+		}
+		img, ok := pages[g.Texture]
+		if !ok {
+			img = g.Texture.Image()
+			pages[g.Texture] = img
+		}
+		rect := g.TextureRect
+		builder.Add(strconv.Itoa(int(r)), img.SubImage(image.Rect(rect.Left, rect.Top, rect.Right(), rect.Bottom())))
+	}
+	atlas, err := builder.Build()
+	if err != nil {
+		return nil, err
+	}
+	bm.pages = atlas.Pages
+	for r, g := range glyphs {
+		c := bmChar{
+			offset:  Vector2f{X: g.Bounds.Left, Y: g.Bounds.Top + bm.base},
+			advance: roundFloat(g.Advance),
+		}
+		if region, ok := atlas.Region(strconv.Itoa(int(r))); ok {
+			c.rect = region.Rect
+			for i, page := range atlas.Pages {
+				if page == region.Texture {
+					c.page = i
+				}
+			}
+		}
+		bm.chars[r] = c
+	}
+	for a := range glyphs {
+		for b := range glyphs {
+			if k := roundFloat(f.Kerning(a, b, px)); k != 0 {
+				bm.kernings[[2]rune{a, b}] = k
+			}
+		}
+	}
+	return bm, nil
+}
+
+//EncodeBMFont : Writes bm in the BMFont text format, pages[i] being the
+//file name the page bm.Pages()[i] is saved under
+func EncodeBMFont(w io.Writer, bm *BMFont, pages []string) error {
+	if len(pages) != len(bm.pages) {
+		return fmt.Errorf("goldcore: font has %d pages, got %d names", len(bm.pages), len(pages))
+	}
+	var scale Vector2u
+	if len(bm.pages) > 0 {
+		scale = bm.pages[0].Size()
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "info face=\"\" size=%d\n", int(bm.size))
+	fmt.Fprintf(bw, "common lineHeight=%d base=%d scaleW=%d scaleH=%d pages=%d\n", int(bm.lineHeight), int(bm.base), scale.X, scale.Y, len(pages))
+	for i, name := range pages {
+		fmt.Fprintf(bw, "page id=%d file=\"%s\"\n", i, name)
+	}
+	runes := make([]rune, 0, len(bm.chars))
+	for r := range bm.chars {
+		runes = append(runes, r)
+	}
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+	fmt.Fprintf(bw, "chars count=%d\n", len(runes))
+	for _, r := range runes {
+		c := bm.chars[r]
+		fmt.Fprintf(bw, "char id=%d x=%d y=%d width=%d height=%d xoffset=%d yoffset=%d xadvance=%d page=%d chnl=15\n",
+			r, c.rect.Left, c.rect.Top, c.rect.Width, c.rect.Height, int(c.offset.X), int(c.offset.Y), int(c.advance), c.page)
+	}
+	pairs := make([][2]rune, 0, len(bm.kernings))
+	for pair := range bm.kernings {
+		pairs = append(pairs, pair)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i][0] != pairs[j][0] {
+			return pairs[i][0] < pairs[j][0]
+		}
+		return pairs[i][1] < pairs[j][1]
+	})
+	fmt.Fprintf(bw, "kernings count=%d\n", len(pairs))
+	for _, pair := range pairs {
+		fmt.Fprintf(bw, "kerning first=%d second=%d amount=%d\n", pair[0], pair[1], int(bm.kernings[pair]))
+	}
+	return bw.Flush()
+}
+
+//bmAttributes : key=value pairs of a line
+type bmAttributes map[string]string
+
+func (attrs bmAttributes) int(name string) (int, error) {
+	v, ok := attrs[name]
+	if !ok {
+		return 0, fmt.Errorf("missing %s", name)
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %v", name, err)
+	}
+	return i, nil
+}
+
+//parseBMFontLine : Tag and attributes of a line like
+//	char id=65 x=0 y=0 ... or info face="Some Font" size=32
+func parseBMFontLine(line string) (string, bmAttributes) {
+	line = strings.TrimSpace(line)
+	tag := line
+	if i := strings.IndexAny(line, " \t"); i >= 0 {
+		tag, line = line[:i], line[i+1:]
+	} else {
+		line = ""
+	}
+	attrs := make(bmAttributes)
+	for {
+		line = strings.TrimLeft(line, " \t")
+		eq := strings.IndexByte(line, '=')
+		if eq < 0 {
+			return tag, attrs
+		}
+		key := strings.TrimSpace(line[:eq])
+		line = line[eq+1:]
+		var value string
+		if strings.HasPrefix(line, `"`) {
+			end := strings.IndexByte(line[1:], '"')
+			if end < 0 {
+				value, line = line[1:], ""
+			} else {
+				value, line = line[1:end+1], line[end+2:]
+			}
+		} else if end := strings.IndexAny(line, " \t"); end >= 0 {
+			value, line = line[:end], line[end:]
+		} else {
+			value, line = line, ""
+		}
+		attrs[key] = value
+	}
+}
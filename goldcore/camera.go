@@ -0,0 +1,244 @@
+package goldcore
+
+import (
+	"math"
+
+	sf "github.com/manyminds/gosfml"
+)
+
+//Camera2D : What part of the world is shown, and where on the target.
+//With the default camera one world unit is one pixel and the world origin is
+//the top left corner of the target
+type Camera2D struct {
+	Center   Vector2f //World position in the middle of the viewport
+	Zoom     float32  //Magnification. 2 shows everything twice as big
+	Rotation float32  //Degrees, clockwise. Rotates the view, not the world
+	//Viewport : Part of the target the camera draws to, as fractions of its
+	//size. {0, 0, 1, 1} is the whole target, {0, 0, 0.5, 1} the left half
+	Viewport RectF
+
+	bounds     RectF
+	hasBounds  bool
+	targetSize Vector2u
+}
+
+//NewCamera2D : Camera showing targetSize pixels of the world, one unit per
+//pixel, with the world origin in the top left corner
+func NewCamera2D(targetSize Vector2u) *Camera2D {
+	return &Camera2D{
+		Center:     Vector2f{X: float32(targetSize.X) / 2, Y: float32(targetSize.Y) / 2},
+		Zoom:       1,
+		Viewport:   RectF{Width: 1, Height: 1},
+		targetSize: targetSize,
+	}
+}
+
+//SetTargetSize : Size in pixels of what the camera draws to. GameWindow keeps
+//it up to date for its camera
+func (cam *Camera2D) SetTargetSize(size Vector2u) {
+	cam.targetSize = size
+}
+
+//TargetSize : Size in pixels of what the camera draws to
+func (cam *Camera2D) TargetSize() Vector2u {
+	return cam.targetSize
+}
+
+//SetBounds : Keeps the view inside bounds. If the view is bigger than bounds
+//it is centered on them instead
+func (cam *Camera2D) SetBounds(bounds RectF) {
+	cam.bounds = bounds
+	cam.hasBounds = true
+	cam.clamp()
+}
+
+//ClearBounds : Lets the camera go anywhere
+func (cam *Camera2D) ClearBounds() {
+	cam.hasBounds = false
+}
+
+//Bounds : The bounds, and whether the camera has any
+func (cam *Camera2D) Bounds() (RectF, bool) {
+	return cam.bounds, cam.hasBounds
+}
+
+//ViewportPixels : Viewport in target pixels
+func (cam *Camera2D) ViewportPixels() RectF {
+	w, h := float32(cam.targetSize.X), float32(cam.targetSize.Y)
+	return RectF{Left: cam.Viewport.Left * w, Top: cam.Viewport.Top * h, Width: cam.Viewport.Width * w, Height: cam.Viewport.Height * h}
+}
+
+//ViewSize : Size of the visible part of the world, in world units
+func (cam *Camera2D) ViewSize() Vector2f {
+	vp := cam.ViewportPixels()
+	return Vector2f{X: vp.Width, Y: vp.Height}.Div(cam.Zoom)
+}
+
+//WindowToWorld : World position shown at pixel p of the target
+func (cam *Camera2D) WindowToWorld(p Vector2i) Vector2f {
+	return cam.WindowToWorldf(p.ToVector2f())
+}
+
+//WindowToWorldf : WindowToWorld for sub pixel positions
+func (cam *Camera2D) WindowToWorldf(p Vector2f) Vector2f {
+	d := p.Minus(cam.ViewportPixels().Center()).Div(cam.Zoom)
+	return rotateDegrees(d, cam.Rotation).Plus(cam.Center)
+}
+
+//WorldToWindowf : Target position, in pixels, where world position p is shown
+func (cam *Camera2D) WorldToWindowf(p Vector2f) Vector2f {
+	d := rotateDegrees(p.Minus(cam.Center), -cam.Rotation).Scale(cam.Zoom)
+	return d.Plus(cam.ViewportPixels().Center())
+}
+
+//Transform : World to target pixels, the transform WorldToWindowf applies
+func (cam *Camera2D) Transform() Transform2D {
+	return TranslationTransform(cam.ViewportPixels().Center()).
+		Scale(Vector2f{X: cam.Zoom, Y: cam.Zoom}).
+		Rotate(-DegreesToRadians(cam.Rotation)).
+		Translate(cam.Center.Neg())
+}
+
+//WorldToWindow : Pixel containing WorldToWindowf(p)
+func (cam *Camera2D) WorldToWindow(p Vector2f) Vector2i {
+	return pixelAt(cam.WorldToWindowf(p))
+}
+
+//pixelTolerance : Distance under which a position counts as on the next
+//pixel already. Absorbs float error so WindowToWorld round trips exactly
+const pixelTolerance = 1e-3
+
+//pixelAt : Pixel containing p, the same rule with or without a camera
+func pixelAt(p Vector2f) Vector2i {
+	return p.Plus(Vector2f{X: pixelTolerance, Y: pixelTolerance}).Floor()
+}
+
+//VisibleBounds : World rectangle containing everything the camera shows,
+//rotation included. Anything outside can be culled
+func (cam *Camera2D) VisibleBounds() RectF {
+	vp := cam.ViewportPixels()
+	corners := vp.Corners()
+	for i := range corners {
+		corners[i] = cam.WindowToWorldf(corners[i])
+	}
+	return RectFromPoints(corners[:]...)
+}
+
+//Move : Moves the camera by offset world units
+func (cam *Camera2D) Move(offset Vector2f) {
+	cam.Center = cam.Center.Plus(offset)
+	cam.clamp()
+}
+
+//Follow : Moves the center towards target. smoothing is how fast it catches
+//up, per second: the distance left shrinks by a factor e every 1/smoothing
+//seconds. 0 or less snaps straight to target. dt is the frame time in seconds
+func (cam *Camera2D) Follow(target Vector2f, smoothing, dt float32) {
+	if smoothing <= 0 {
+		cam.Center = target
+	} else {
+		t := 1 - float32(math.Exp(-float64(smoothing*dt)))
+		cam.Center = cam.Center.Lerp(target, t)
+	}
+	cam.clamp()
+}
+
+//clamp : Keeps the view inside the bounds. Rotation is ignored
+func (cam *Camera2D) clamp() {
+	if !cam.hasBounds {
+		return
+	}
+	half := cam.ViewSize().Scale(0.5)
+	cam.Center.X = clampAxis(cam.Center.X, half.X, cam.bounds.Left, cam.bounds.Width)
+	cam.Center.Y = clampAxis(cam.Center.Y, half.Y, cam.bounds.Top, cam.bounds.Height)
+}
+
+func clampAxis(center, half, min, size float32) float32 {
+	if 2*half >= size {
+		return min + size/2
+	}
+	if center-half < min {
+		return min + half
+	}
+	if center+half > min+size {
+		return min + size - half
+	}
+	return center
+}
+
+//rotateDegrees : v turned clockwise (y pointing down) by degrees
+func rotateDegrees(v Vector2f, degrees float32) Vector2f {
+	if degrees == 0 {
+		return v
+	}
+	return v.Rotate(DegreesToRadians(degrees))
+}
+
+//ToSFML : Allows for SFML compatability
+func (cam *Camera2D) ToSFML() *sf.View {
+	view := sf.NewView()
+	view.SetCenter(cam.Center.ToSFML())
+	view.SetSize(cam.ViewSize().ToSFML())
+	view.SetRotation(cam.Rotation)
+	view.SetViewport(cam.Viewport.ToSFML())
+	return view
+}
+
+//SetCamera : Makes cam the camera of the window. Mouse events report world
+//positions through it. nil goes back to the default camera
+func (gW *GameWindow) SetCamera(cam *Camera2D) {
+	if cam != nil {
+		cam.SetTargetSize(gW.size)
+	}
+	gW.camera = cam
+	gW.InputSystem.SetCamera(cam)
+	gW.ApplyCamera()
+}
+
+//Camera : Camera of the window, nil if it uses the default one
+func (gW *GameWindow) Camera() *Camera2D {
+	return gW.camera
+}
+
+//ApplyCamera : Call after changing the camera so the window draws through it
+func (gW *GameWindow) ApplyCamera() {
+	//Copied, draws queued before the change use the old view
+	var cam *Camera2D
+	if gW.camera != nil {
+		snapshot := *gW.camera
+		cam = &snapshot
+	}
+	if w, ok := gW.renderWindow.(*headlessWindow); ok {
+		gW.do(func() {
+			w.setView(cam)
+		})
+		return
+	}
+	w, ok := gW.renderWindow.(*sfmlWindow)
+	if !ok {
+		return
+	}
+	gW.do(func() {
+		if cam == nil {
+			w.SetView(w.GetDefaultView())
+		} else {
+			w.SetView(cam.ToSFML())
+		}
+	})
+}
+
+//WindowToWorld : World position under pixel p of the window
+func (gW *GameWindow) WindowToWorld(p Vector2i) Vector2f {
+	if gW.camera == nil {
+		return p.ToVector2f()
+	}
+	return gW.camera.WindowToWorld(p)
+}
+
+//WorldToWindow : Pixel of the window showing world position p
+func (gW *GameWindow) WorldToWindow(p Vector2f) Vector2i {
+	if gW.camera == nil {
+		return pixelAt(p)
+	}
+	return gW.camera.WorldToWindow(p)
+}
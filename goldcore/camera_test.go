@@ -0,0 +1,163 @@
+package goldcore
+
+import (
+	"math"
+	"testing"
+
+	sf "github.com/manyminds/gosfml"
+)
+
+func closeTo(a, b Vector2f) bool {
+	return math.Abs(float64(a.X-b.X)) < 1e-3 && math.Abs(float64(a.Y-b.Y)) < 1e-3
+}
+
+func TestDefaultCamera(t *testing.T) {
+	cam := NewCamera2D(Vector2u{800, 600})
+	for _, p := range []Vector2i{{0, 0}, {400, 300}, {799, 10}} {
+		world := cam.WindowToWorld(p)
+		if !closeTo(world, Vector2f{float32(p.X), float32(p.Y)}) {
+			t.Errorf("Default camera should map pixels to themselves. %v became %v", p, world)
+		}
+		if back := cam.WorldToWindow(world); !back.Equals(p) {
+			t.Errorf("Round trip of %v gave %v", p, back)
+		}
+	}
+}
+
+func TestWorldToWindowRounding(t *testing.T) {
+	gW := NewHeadlessGameWindow(800, 600, "Rounding")
+	points := []Vector2f{{10.7, 3.2}, {0.5, 0.5}, {-0.5, -1.2}, {42, 17}, {99.9999, 5}}
+	without := make([]Vector2i, len(points))
+	for i, p := range points {
+		without[i] = gW.WorldToWindow(p)
+	}
+	gW.SetCamera(NewCamera2D(Vector2u{800, 600}))
+	for i, p := range points {
+		if with := gW.WorldToWindow(p); !with.Equals(without[i]) {
+			t.Errorf("%v maps to %v without a camera and %v with the default one", p, without[i], with)
+		}
+	}
+	if got := gW.WorldToWindow(Vector2f{10.7, 3.2}); !got.Equals(Vector2i{10, 3}) {
+		t.Errorf("Expected the pixel containing the point got %v", got)
+	}
+}
+
+func TestCameraTransforms(t *testing.T) {
+	cam := NewCamera2D(Vector2u{800, 600})
+	cam.Center = Vector2f{100, 100}
+	cam.Zoom = 2
+	if got := cam.WindowToWorld(Vector2i{400, 300}); !closeTo(got, Vector2f{100, 100}) {
+		t.Errorf("Viewport center should show the camera center, got %v", got)
+	}
+	if got := cam.WindowToWorld(Vector2i{600, 300}); !closeTo(got, Vector2f{200, 100}) {
+		t.Errorf("Zoomed in camera. Expected %v got %v", Vector2f{200, 100}, got)
+	}
+	if size := cam.ViewSize(); !closeTo(size, Vector2f{400, 300}) {
+		t.Errorf("Zoomed in view size. Expected %v got %v", Vector2f{400, 300}, size)
+	}
+
+	//A view rotated clockwise shows the world rotated counter clockwise, so
+	//what is right of the center appears above it
+	cam.Zoom = 1
+	cam.Rotation = 90
+	if got := cam.WorldToWindowf(Vector2f{110, 100}); !closeTo(got, Vector2f{400, 290}) {
+		t.Errorf("Rotated camera. Expected %v got %v", Vector2f{400, 290}, got)
+	}
+	if got := cam.WindowToWorldf(Vector2f{400, 290}); !closeTo(got, Vector2f{110, 100}) {
+		t.Errorf("Rotated camera inverse. Expected %v got %v", Vector2f{110, 100}, got)
+	}
+
+	//Split screen, right half
+	cam.Rotation = 0
+	cam.Viewport = RectF{Left: 0.5, Width: 0.5, Height: 1}
+	if got := cam.WindowToWorld(Vector2i{600, 300}); !closeTo(got, Vector2f{100, 100}) {
+		t.Errorf("Viewport center should show the camera center, got %v", got)
+	}
+}
+
+func TestCameraFollow(t *testing.T) {
+	cam := NewCamera2D(Vector2u{100, 100})
+	cam.Follow(Vector2f{500, 500}, 0, 1.0/60)
+	if !closeTo(cam.Center, Vector2f{500, 500}) {
+		t.Errorf("Follow without smoothing should snap, got %v", cam.Center)
+	}
+
+	cam.Center = Vector2f{0, 0}
+	cam.Follow(Vector2f{100, 0}, 1, 1)
+	if want := float32(100 * (1 - math.Exp(-1))); !closeTo(cam.Center, Vector2f{want, 0}) {
+		t.Errorf("Smoothed follow. Expected %v got %v", Vector2f{want, 0}, cam.Center)
+	}
+	for i := 0; i < 600; i++ {
+		cam.Follow(Vector2f{100, 0}, 5, 1.0/60)
+	}
+	if !closeTo(cam.Center, Vector2f{100, 0}) {
+		t.Errorf("Smoothed follow should catch up, got %v", cam.Center)
+	}
+
+	cam.SetBounds(RectF{Left: 0, Top: 0, Width: 1000, Height: 200})
+	cam.Follow(Vector2f{-100, -100}, 0, 0)
+	if !closeTo(cam.Center, Vector2f{50, 50}) {
+		t.Errorf("Bounded follow top left. Expected %v got %v", Vector2f{50, 50}, cam.Center)
+	}
+	cam.Follow(Vector2f{2000, 2000}, 0, 0)
+	if !closeTo(cam.Center, Vector2f{950, 150}) {
+		t.Errorf("Bounded follow bottom right. Expected %v got %v", Vector2f{950, 150}, cam.Center)
+	}
+	//View taller than the bounds gets centered on them vertically
+	cam.Zoom = 0.25
+	cam.Move(Vector2f{0, 0})
+	if !closeTo(cam.Center, Vector2f{800, 100}) {
+		t.Errorf("View bigger than bounds. Expected %v got %v", Vector2f{800, 100}, cam.Center)
+	}
+}
+
+func TestMouseEventsInWorldCoordinates(t *testing.T) {
+	gW := NewHeadlessGameWindow(200, 100, "Camera")
+	gW.PollEvent()
+	recorder := &MessageRecorder{}
+	gW.AddObserver(recorder)
+
+	gW.PushEvent(sf.EventMouseMoved{X: 10, Y: 20})
+	gW.PollEvent()
+	if moved := recorder.Messages[0].Payload.(EventMouseMoved); !closeTo(moved.World, Vector2f{10, 20}) {
+		t.Errorf("Without a camera world is pixels. Got %v", moved.World)
+	}
+
+	cam := NewCamera2D(Vector2u{})
+	cam.Center = Vector2f{0, 0}
+	cam.Zoom = 2
+	gW.SetCamera(cam)
+	gW.PushEvent(sf.EventMouseMoved{X: 150, Y: 50})
+	gW.PushEvent(sf.EventMouseButtonPressed{Button: MouseLeft, X: 100, Y: 75})
+	gW.PushEvent(sf.EventMouseWheelMoved{Delta: 1, X: 0, Y: 0})
+	gW.PollEvent()
+	if moved := recorder.Messages[1].Payload.(EventMouseMoved); moved.X != 150 || !closeTo(moved.World, Vector2f{25, 0}) {
+		t.Errorf("Mouse moved. Expected pixel 150 world %v got %#v", Vector2f{25, 0}, moved)
+	}
+	if pressed := recorder.Messages[2].Payload.(EventMouseButtonWrapper); !closeTo(pressed.World, Vector2f{0, 12.5}) {
+		t.Errorf("Mouse pressed. Expected world %v got %v", Vector2f{0, 12.5}, pressed.World)
+	}
+	if wheel := recorder.Messages[3].Payload.(EventMouseWheelMoved); !closeTo(wheel.World, Vector2f{-50, -25}) {
+		t.Errorf("Mouse wheel. Expected world %v got %v", Vector2f{-50, -25}, wheel.World)
+	}
+
+	//Camera follows the window size
+	gW.SetSize(Vector2u{400, 100})
+	gW.PollEvent()
+	if got := gW.WindowToWorld(Vector2i{200, 50}); !closeTo(got, Vector2f{0, 0}) {
+		t.Errorf("After resize the window center should show the camera center, got %v", got)
+	}
+}
+
+func TestCameraTransform(t *testing.T) {
+	cam := NewCamera2D(Vector2u{X: 200, Y: 100})
+	cam.Center = Vector2f{X: 30, Y: -20}
+	cam.Zoom = 2
+	cam.Rotation = 30
+	cam.Viewport = RectF{Left: 0.5, Width: 0.5, Height: 1}
+	for _, p := range []Vector2f{{}, {X: 30, Y: -20}, {X: 45, Y: 10}, {X: -7, Y: 3}} {
+		if got, want := cam.Transform().TransformPoint(p), cam.WorldToWindowf(p); !got.ApproxEquals(want, 1e-3) {
+			t.Errorf("Point %v. Expected %v got %v", p, want, got)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package goldcore
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+//ScreenshotTimeFormat : Layout of the timestamp in screenshot file names.
+//No colons so the names are valid on every OS
+const ScreenshotTimeFormat = "20060102_150405.000"
+
+//ErrFrameCaptureRunning : StartFrameCapture was called twice
+var ErrFrameCaptureRunning = errors.New("goldcore: frame capture already running")
+
+//Capture : Returns a copy of the window's pixels. On the headless backend
+//this is the last presented frame. A GPU window is read back as it is now,
+//which is the frame drawn so far, so capture after drawing and before
+//NextFrame. Use StartFrameCapture to get frames exactly as presented. Nil if
+//the read back failed
+func (gW *GameWindow) Capture() *image.RGBA {
+	frame, _ := gW.captureFrame()
+	return frame
+}
+
+//captureFrame : Capture, also returning why it failed. A headless
+//framebuffer can be read from any goroutine, a GPU window is read on the
+//render thread so don't call it from a render thread command
+func (gW *GameWindow) captureFrame() (*image.RGBA, error) {
+	if _, headless := gW.renderWindow.(*headlessWindow); headless {
+		return gW.renderWindow.Capture(), nil
+	}
+	var frame *image.RGBA
+	err := DoSync(func() {
+		frame = gW.renderWindow.Capture()
+	})
+	return frame, err
+}
+
+//Screenshot : Captures the window and saves it as a timestamped PNG in dir,
+//created if needed. Screenshots taken the same millisecond get a _1, _2...
+//suffix instead of overwriting each other. Returns the path of the file written
+func (gW *GameWindow) Screenshot(dir string) (string, error) {
+	frame, err := gW.captureFrame()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	file, path, err := createUnique(dir, "screenshot_"+time.Now().Format(ScreenshotTimeFormat), ".png")
+	if err != nil {
+		return "", err
+	}
+	if err := encodePNG(file, frame); err != nil {
+		os.Remove(path)
+		return "", err
+	}
+	return path, nil
+}
+
+//createUnique : Creates dir/name+ext, or dir/name_1+ext, dir/name_2+ext...
+//for the first one that doesn't exist yet
+func createUnique(dir, name, ext string) (*os.File, string, error) {
+	for i := 0; ; i++ {
+		path := filepath.Join(dir, name+ext)
+		if i > 0 {
+			path = filepath.Join(dir, fmt.Sprintf("%s_%d%s", name, i, ext))
+		}
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			return file, path, nil
+		}
+		if !os.IsExist(err) {
+			return nil, "", err
+		}
+	}
+}
+
+//ScreenshotCommand : KeyCommand that saves a screenshot into dir. Bind it to
+//a key with KeyboardHandler.AddEventKey. The outcome is announced with
+//WindowScreenshot or WindowScreenshotFailed
+func (gW *GameWindow) ScreenshotCommand(dir string) KeyCommand {
+	return func() {
+		gW.screenshot(dir)
+	}
+}
+
+//screenshot : Saves a screenshot and notifies observers of the result
+func (gW *GameWindow) screenshot(dir string) {
+	path, err := gW.Screenshot(dir)
+	if err != nil {
+		gW.notify(NewGameMessage(WindowScreenshotFailed, err))
+		return
+	}
+	gW.notify(NewGameMessage(WindowScreenshot, path))
+}
+
+//StartFrameCapture : Saves every displayed frame into dir as
+//frame_00000.png, frame_00001.png... until StopFrameCapture is called.
+//Good for turning into GIFs or videos. Encoding happens on its own goroutine
+//so the render loop is only slowed down by the read back
+func (gW *GameWindow) StartFrameCapture(dir string) error {
+	gW.capture.mutex.Lock()
+	defer gW.capture.mutex.Unlock()
+	if gW.capture.recorder != nil {
+		return ErrFrameCaptureRunning
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	gW.capture.recorder = newFrameRecorder(dir)
+	return nil
+}
+
+//StopFrameCapture : Stops capturing and waits for the frames to be written.
+//Returns the number of frames saved and the first error hit, if any
+func (gW *GameWindow) StopFrameCapture() (int, error) {
+	gW.capture.mutex.Lock()
+	recorder := gW.capture.recorder
+	gW.capture.recorder = nil
+	gW.capture.mutex.Unlock()
+	if recorder == nil {
+		return 0, nil
+	}
+	return recorder.stop()
+}
+
+//IsCapturingFrames : Checks whether a frame capture is running
+func (gW *GameWindow) IsCapturingFrames() bool {
+	gW.capture.mutex.Lock()
+	defer gW.capture.mutex.Unlock()
+	return gW.capture.recorder != nil
+}
+
+//recordFrame : Called by the render loop once a frame is drawn, before it
+//is displayed
+func (gW *GameWindow) recordFrame() {
+	gW.capture.mutex.Lock()
+	defer gW.capture.mutex.Unlock()
+	if gW.capture.recorder != nil {
+		gW.capture.recorder.record(gW.renderWindow.Capture())
+	}
+}
+
+//windowCapture : Frame capture state of a GameWindow. Shared by pointer since
+//GameWindows get passed around by value
+type windowCapture struct {
+	mutex    sync.Mutex
+	recorder *frameRecorder
+}
+
+//frameRecorderBufferSize : Frames waiting to be encoded before the render loop blocks
+const frameRecorderBufferSize = 8
+
+//frameRecorder : Writes captured frames to disk in order
+type frameRecorder struct {
+	dir    string
+	frames chan *image.RGBA
+	done   sync.WaitGroup
+	count  int
+	err    error
+}
+
+func newFrameRecorder(dir string) *frameRecorder {
+	fR := &frameRecorder{dir: dir, frames: make(chan *image.RGBA, frameRecorderBufferSize)}
+	fR.done.Add(1)
+	go fR.run()
+	return fR
+}
+
+func (fR *frameRecorder) record(frame *image.RGBA) {
+	fR.frames <- frame
+}
+
+func (fR *frameRecorder) run() {
+	defer fR.done.Done()
+	for frame := range fR.frames {
+		if fR.err != nil {
+			continue
+		}
+		path := filepath.Join(fR.dir, fmt.Sprintf("frame_%05d.png", fR.count))
+		if fR.err = writePNG(path, frame); fR.err == nil {
+			fR.count++
+		}
+	}
+}
+
+func (fR *frameRecorder) stop() (int, error) {
+	close(fR.frames)
+	fR.done.Wait()
+	return fR.count, fR.err
+}
+
+//writePNG : Encodes img into a new file at path
+func writePNG(path string, img image.Image) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	return encodePNG(file, img)
+}
+
+//encodePNG : Writes img to file as a PNG and closes it
+func encodePNG(file *os.File, img image.Image) error {
+	if err := png.Encode(file, img); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
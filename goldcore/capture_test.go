@@ -0,0 +1,186 @@
+package goldcore
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+//MessageRecorder : WindowObserver that remembers every message it sees
+type MessageRecorder struct {
+	Messages []GameMessage
+}
+
+func (mR *MessageRecorder) OnWindowNotify(gM *GameMessage) {
+	mR.Messages = append(mR.Messages, *gM)
+}
+
+//compareImages : Golden image comparison. Returns a description of the first
+//mismatching pixel, or "" if the images are identical
+func compareImages(got, want *image.RGBA) string {
+	if !got.Rect.Eq(want.Rect) {
+		return fmt.Sprintf("bounds differ: got %v want %v", got.Rect, want.Rect)
+	}
+	for y := want.Rect.Min.Y; y < want.Rect.Max.Y; y++ {
+		for x := want.Rect.Min.X; x < want.Rect.Max.X; x++ {
+			if got.RGBAAt(x, y) != want.RGBAAt(x, y) {
+				return fmt.Sprintf("pixel (%d, %d) differs: got %v want %v", x, y, got.RGBAAt(x, y), want.RGBAAt(x, y))
+			}
+		}
+	}
+	return ""
+}
+
+func TestHeadlessCapture(t *testing.T) {
+	gW := NewHeadlessGameWindow(32, 16, "Capture")
+	frame := gW.Capture()
+	if diff := compareImages(frame, newFramebuffer(32, 16)); diff != "" {
+		t.Errorf("Fresh headless window should capture black: %s", diff)
+	}
+
+	//Capture returns a copy, scribbling on it must not touch the framebuffer
+	frame.Pix[0] = 0xff
+	if diff := compareImages(gW.Capture(), newFramebuffer(32, 16)); diff != "" {
+		t.Errorf("Capture leaked the framebuffer: %s", diff)
+	}
+
+	gW.SetSize(Vector2u{8, 4})
+	if size := gW.Capture().Rect.Size(); size != image.Pt(8, 4) {
+		t.Errorf("Capture after resize. Expected %v got %v", image.Pt(8, 4), size)
+	}
+}
+
+func TestScreenshot(t *testing.T) {
+	dir := t.TempDir()
+	gW := NewHeadlessGameWindow(4, 4, "Screenshot")
+	path, err := gW.Screenshot(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	img, err := png.Decode(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := image.NewRGBA(img.Bounds())
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			saved.Set(x, y, img.At(x, y))
+		}
+	}
+	if diff := compareImages(saved, gW.Capture()); diff != "" {
+		t.Errorf("Saved screenshot does not match capture: %s", diff)
+	}
+
+	//Missing directories are created, shots in the same millisecond don't
+	//overwrite each other
+	nested := filepath.Join(dir, "new", "shots")
+	paths := map[string]bool{}
+	for i := 0; i < 5; i++ {
+		path, err := gW.Screenshot(nested)
+		if err != nil {
+			t.Fatal(err)
+		}
+		paths[path] = true
+	}
+	if entries, err := os.ReadDir(nested); err != nil || len(entries) != 5 || len(paths) != 5 {
+		t.Errorf("Expected 5 screenshots got %d files, %v", len(entries), err)
+	}
+
+	notDir := filepath.Join(dir, "file")
+	if err := os.WriteFile(notDir, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	recorder := &MessageRecorder{}
+	gW.AddObserver(recorder)
+	gW.ScreenshotCommand(dir)()
+	gW.ScreenshotCommand(notDir)()
+	if len(recorder.Messages) != 2 {
+		t.Fatalf("Expected 2 screenshot messages got %d", len(recorder.Messages))
+	}
+	if recorder.Messages[0].Message != WindowScreenshot {
+		t.Errorf("Expected WindowScreenshot got %s", recorder.Messages[0].String())
+	}
+	if recorder.Messages[1].Message != WindowScreenshotFailed {
+		t.Errorf("Expected WindowScreenshotFailed got %s", recorder.Messages[1].String())
+	}
+}
+
+func TestFrameCapture(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "frames")
+	gW := NewHeadlessGameWindow(4, 4, "Frames")
+	//Not capturing, nothing happens
+	gW.recordFrame()
+	if err := gW.StartFrameCapture(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := gW.StartFrameCapture(dir); err != ErrFrameCaptureRunning {
+		t.Errorf("Expected ErrFrameCaptureRunning got %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		gW.renderWindow.Display()
+		gW.recordFrame()
+	}
+	count, err := gW.StopFrameCapture()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Errorf("Expected 3 frames got %d", count)
+	}
+	if gW.IsCapturingFrames() {
+		t.Errorf("Still capturing after StopFrameCapture")
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := os.Stat(filepath.Join(dir, fmt.Sprintf("frame_%05d.png", i))); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestFrameCaptureWhileRunning(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "frames")
+	gW := NewHeadlessGameWindow(4, 4, "Frames")
+	if err := gW.StartFrameCapture(dir); err != nil {
+		t.Fatal(err)
+	}
+	gW.Start()
+	colors := []Color{ColorRed, ColorGreen, ColorBlue}
+	for i, c := range colors {
+		gW.Clear(c)
+		gW.NextFrame(i)
+	}
+	if got := gW.Capture().RGBAAt(1, 1); got != blue {
+		t.Errorf("Capture after NextFrame. Expected %v got %v", blue, got)
+	}
+	gW.Stop()
+	count, err := gW.StopFrameCapture()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != len(colors) {
+		t.Fatalf("Expected %d frames got %d", len(colors), count)
+	}
+	for i, want := range []color.RGBA{red, green, blue} {
+		file, err := os.Open(filepath.Join(dir, fmt.Sprintf("frame_%05d.png", i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		img, err := png.Decode(file)
+		file.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := color.RGBAModel.Convert(img.At(1, 1)).(color.RGBA); got != want {
+			t.Errorf("Frame %d. Expected %v got %v", i, want, got)
+		}
+	}
+}
@@ -0,0 +1,324 @@
+package goldcore
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	sf "github.com/manyminds/gosfml"
+)
+
+/////////////////////////////////////
+///		STRUCTS
+/////////////////////////////////////
+
+//Color : 8 bit sRGB color with straight, not premultiplied, alpha. It is a
+//color.Color, so it can be used with image and image/draw
+type Color struct {
+	R, G, B, A uint8
+}
+
+//ColorF : Float color, channels from 0 to 1 with straight alpha. For
+//computations that would lose precision in 8 bits, like blending in
+//linear space
+type ColorF struct {
+	R, G, B, A float32
+}
+
+/////////////////////////////////////
+///		CONSTS
+/////////////////////////////////////
+
+var (
+	//ColorBlack : Opaque black
+	ColorBlack = Color{A: 0xff}
+	//ColorWhite : Opaque white, leaves textures untouched as a tint
+	ColorWhite = Color{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	//ColorRed : Opaque red
+	ColorRed = Color{R: 0xff, A: 0xff}
+	//ColorGreen : Opaque green
+	ColorGreen = Color{G: 0xff, A: 0xff}
+	//ColorBlue : Opaque blue
+	ColorBlue = Color{B: 0xff, A: 0xff}
+	//ColorYellow : Opaque yellow
+	ColorYellow = Color{R: 0xff, G: 0xff, A: 0xff}
+	//ColorMagenta : Opaque magenta
+	ColorMagenta = Color{R: 0xff, B: 0xff, A: 0xff}
+	//ColorCyan : Opaque cyan
+	ColorCyan = Color{G: 0xff, B: 0xff, A: 0xff}
+	//ColorTransparent : Transparent black
+	ColorTransparent = Color{}
+)
+
+//ColorModel : Converts any color.Color to a Color
+var ColorModel = color.ModelFunc(func(c color.Color) color.Color {
+	return ColorFromColor(c)
+})
+
+/////////////////////////////////////
+///		COLOR
+/////////////////////////////////////
+
+//NewColor : Color from its channels
+func NewColor(r, g, b, a uint8) Color {
+	return Color{R: r, G: g, B: b, A: a}
+}
+
+//ColorFromColor : Any color.Color as a Color
+func ColorFromColor(c color.Color) Color {
+	if col, ok := c.(Color); ok {
+		return col
+	}
+	return Color(color.NRGBAModel.Convert(c).(color.NRGBA))
+}
+
+//ParseHexColor : Color from "#rgb", "#rgba", "#rrggbb" or "#rrggbbaa", the
+//# being optional. Colors without alpha are opaque
+func ParseHexColor(s string) (Color, error) {
+	hex := strings.TrimPrefix(s, "#")
+	switch len(hex) {
+	case 3, 4:
+		//Every digit is doubled, #f80 is #ff8800
+		var long strings.Builder
+		for _, d := range hex {
+			long.WriteRune(d)
+			long.WriteRune(d)
+		}
+		hex = long.String()
+	case 6, 8:
+	default:
+		return Color{}, fmt.Errorf("goldcore: invalid hex color %q", s)
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return Color{}, fmt.Errorf("goldcore: invalid hex color %q", s)
+	}
+	return Color{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
+
+//ColorFromHSV : Opaque color from hue in degrees, saturation and value
+//from 0 to 1
+func ColorFromHSV(h, s, v float32) Color {
+	c := v * s
+	return fromHueChroma(h, c, v-c)
+}
+
+//ColorFromHSL : Opaque color from hue in degrees, saturation and lightness
+//from 0 to 1
+func ColorFromHSL(h, s, l float32) Color {
+	c := (1 - absFloat(2*l-1)) * s
+	return fromHueChroma(h, c, l-c/2)
+}
+
+//fromHueChroma : Color of hue with the chroma c, lifted by m
+func fromHueChroma(h, c, m float32) Color {
+	h = float32(math.Mod(float64(h), 360))
+	if h < 0 {
+		h += 360
+	}
+	x := c * (1 - absFloat(float32(math.Mod(float64(h/60), 2))-1))
+	var r, g, b float32
+	switch {
+	case h < 60:
+		r, g = c, x
+	case h < 120:
+		r, g = x, c
+	case h < 180:
+		g, b = c, x
+	case h < 240:
+		g, b = x, c
+	case h < 300:
+		r, b = x, c
+	default:
+		r, b = c, x
+	}
+	return ColorF{R: r + m, G: g + m, B: b + m, A: 1}.Color()
+}
+
+//RGBA : Alpha premultiplied 16 bit channels, for color.Color
+func (c Color) RGBA() (r, g, b, a uint32) {
+	return color.NRGBA(c).RGBA()
+}
+
+//Equals : Checks if two colors are the same
+func (c Color) Equals(other Color) bool {
+	return c == other
+}
+
+//Hex : "#rrggbb", or "#rrggbbaa" if the color isn't opaque
+func (c Color) Hex() string {
+	if c.A == 0xff {
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
+}
+
+//String : Hex form of the color
+func (c Color) String() string {
+	return c.Hex()
+}
+
+//HSV : Hue in degrees, saturation and value from 0 to 1. Grays have a hue
+//of 0
+func (c Color) HSV() (h, s, v float32) {
+	f := c.Float()
+	hi, lo := maxFloat(f.R, maxFloat(f.G, f.B)), minFloat(f.R, minFloat(f.G, f.B))
+	if hi > 0 {
+		s = (hi - lo) / hi
+	}
+	return f.hue(hi, lo), s, hi
+}
+
+//HSL : Hue in degrees, saturation and lightness from 0 to 1. Grays have a
+//hue of 0
+func (c Color) HSL() (h, s, l float32) {
+	f := c.Float()
+	hi, lo := maxFloat(f.R, maxFloat(f.G, f.B)), minFloat(f.R, minFloat(f.G, f.B))
+	l = (hi + lo) / 2
+	if hi > lo {
+		s = (hi - lo) / (1 - absFloat(2*l-1))
+	}
+	return f.hue(hi, lo), s, l
+}
+
+//hue : Hue in degrees of the color with hi and lo as its largest and
+//smallest channel
+func (c ColorF) hue(hi, lo float32) float32 {
+	d := hi - lo
+	if d == 0 {
+		return 0
+	}
+	var h float32
+	switch hi {
+	case c.R:
+		h = (c.G - c.B) / d
+		if h < 0 {
+			h += 6
+		}
+	case c.G:
+		h = (c.B-c.R)/d + 2
+	default:
+		h = (c.R-c.G)/d + 4
+	}
+	return h * 60
+}
+
+//WithAlpha : The color with its alpha replaced
+func (c Color) WithAlpha(a uint8) Color {
+	c.A = a
+	return c
+}
+
+//Mul : Channels multiplied together, what tinting a texture does
+func (c Color) Mul(other Color) Color {
+	return Color{R: mul8(c.R, other.R), G: mul8(c.G, other.G), B: mul8(c.B, other.B), A: mul8(c.A, other.A)}
+}
+
+//Premultiply : The color with its alpha multiplied into its channels
+func (c Color) Premultiply() Color {
+	return Color{R: mul8(c.R, c.A), G: mul8(c.G, c.A), B: mul8(c.B, c.A), A: c.A}
+}
+
+//Unpremultiply : Straight alpha color from a premultiplied one
+func (c Color) Unpremultiply() Color {
+	if c.A == 0 {
+		return Color{}
+	}
+	div := func(x uint8) uint8 {
+		return uint8(minInt((int(x)*0xff+int(c.A)/2)/int(c.A), 0xff))
+	}
+	return Color{R: div(c.R), G: div(c.G), B: div(c.B), A: c.A}
+}
+
+//Lerp : Color a fraction t of the way to other, channel by channel
+func (c Color) Lerp(other Color, t float32) Color {
+	return c.Float().Lerp(other.Float(), t).Color()
+}
+
+//Float : The color as floats
+func (c Color) Float() ColorF {
+	return ColorF{R: float32(c.R) / 0xff, G: float32(c.G) / 0xff, B: float32(c.B) / 0xff, A: float32(c.A) / 0xff}
+}
+
+//NRGBA : The color as an image/color NRGBA
+func (c Color) NRGBA() color.NRGBA {
+	return color.NRGBA(c)
+}
+
+//ToSFML : Allows for SFML compatability
+func (c Color) ToSFML() sf.Color {
+	return sf.Color{R: c.R, G: c.G, B: c.B, A: c.A}
+}
+
+//SFColorToColor : Converts an sf.Color
+func SFColorToColor(other sf.Color) Color {
+	return Color{R: other.R, G: other.G, B: other.B, A: other.A}
+}
+
+//mul8 : a*b with 0xff as 1, rounded
+func mul8(a, b uint8) uint8 {
+	x := uint32(a)*uint32(b) + 0x80
+	return uint8((x + x>>8) >> 8)
+}
+
+/////////////////////////////////////
+///		COLORF
+/////////////////////////////////////
+
+//RGBA : Alpha premultiplied 16 bit channels, for color.Color
+func (c ColorF) RGBA() (r, g, b, a uint32) {
+	return c.Color().RGBA()
+}
+
+//Color : The color in 8 bits, channels clamped to 0 to 1
+func (c ColorF) Color() Color {
+	return Color{R: unitToByte(c.R), G: unitToByte(c.G), B: unitToByte(c.B), A: unitToByte(c.A)}
+}
+
+//ApproxEquals : Checks if two colors are within epsilon channel by channel
+func (c ColorF) ApproxEquals(other ColorF, epsilon float32) bool {
+	return ApproxEqual(c.R, other.R, epsilon) && ApproxEqual(c.G, other.G, epsilon) &&
+		ApproxEqual(c.B, other.B, epsilon) && ApproxEqual(c.A, other.A, epsilon)
+}
+
+//Lerp : Color a fraction t of the way to other, channel by channel
+func (c ColorF) Lerp(other ColorF, t float32) ColorF {
+	return ColorF{R: Lerp(c.R, other.R, t), G: Lerp(c.G, other.G, t), B: Lerp(c.B, other.B, t), A: Lerp(c.A, other.A, t)}
+}
+
+//Premultiply : The color with its alpha multiplied into its channels
+func (c ColorF) Premultiply() ColorF {
+	return ColorF{R: c.R * c.A, G: c.G * c.A, B: c.B * c.A, A: c.A}
+}
+
+//ToLinear : sRGB color in linear light, where it can be blended and lit
+//correctly. Alpha is already linear
+func (c ColorF) ToLinear() ColorF {
+	return ColorF{R: SRGBToLinear(c.R), G: SRGBToLinear(c.G), B: SRGBToLinear(c.B), A: c.A}
+}
+
+//ToSRGB : Linear color back in sRGB, for display
+func (c ColorF) ToSRGB() ColorF {
+	return ColorF{R: LinearToSRGB(c.R), G: LinearToSRGB(c.G), B: LinearToSRGB(c.B), A: c.A}
+}
+
+//SRGBToLinear : sRGB channel from 0 to 1 to linear light
+func SRGBToLinear(x float32) float32 {
+	if x <= 0.04045 {
+		return x / 12.92
+	}
+	return float32(math.Pow((float64(x)+0.055)/1.055, 2.4))
+}
+
+//LinearToSRGB : Linear light channel from 0 to 1 to sRGB
+func LinearToSRGB(x float32) float32 {
+	if x <= 0.0031308 {
+		return x * 12.92
+	}
+	return float32(1.055*math.Pow(float64(x), 1/2.4) - 0.055)
+}
@@ -0,0 +1,152 @@
+package goldcore
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+var (
+	_ color.Color = Color{}
+	_ color.Color = ColorF{}
+)
+
+func TestParseHexColor(t *testing.T) {
+	for s, expected := range map[string]Color{
+		"#ff8000":   {R: 0xff, G: 0x80, A: 0xff},
+		"ff800080":  {R: 0xff, G: 0x80, A: 0x80},
+		"#f80":      {R: 0xff, G: 0x88, A: 0xff},
+		"#F808":     {R: 0xff, G: 0x88, A: 0x88},
+		"#00000000": ColorTransparent,
+	} {
+		c, err := ParseHexColor(s)
+		if err != nil || c != expected {
+			t.Errorf("%s: expected %v got %v %v", s, expected, c, err)
+		}
+	}
+	for _, s := range []string{"", "#", "#12345", "#ggg", "#+12"} {
+		if _, err := ParseHexColor(s); err == nil {
+			t.Errorf("Expected an error parsing %q", s)
+		}
+	}
+	if hex := (Color{R: 0x12, G: 0xab, B: 0x0, A: 0xff}).Hex(); hex != "#12ab00" {
+		t.Errorf("Expected #12ab00 got %s", hex)
+	}
+	if hex := (Color{R: 0x12, G: 0xab, B: 0x0, A: 0x40}).String(); hex != "#12ab0040" {
+		t.Errorf("Expected #12ab0040 got %s", hex)
+	}
+}
+
+func TestColorHSVHSL(t *testing.T) {
+	for _, test := range []struct {
+		c             Color
+		h, s, v, l, p float32 //p is the HSL saturation
+	}{
+		{ColorRed, 0, 1, 1, 0.5, 1},
+		{ColorGreen, 120, 1, 1, 0.5, 1},
+		{ColorBlue, 240, 1, 1, 0.5, 1},
+		{ColorYellow, 60, 1, 1, 0.5, 1},
+		{ColorMagenta, 300, 1, 1, 0.5, 1},
+		{ColorWhite, 0, 0, 1, 1, 0},
+		{ColorBlack, 0, 0, 0, 0, 0},
+		{Color{R: 0x80, G: 0x40, A: 0xff}, 30, 1, 0.502, 0.251, 1},
+	} {
+		h, s, v := test.c.HSV()
+		if !ApproxEqual(h, test.h, 0.01) || !ApproxEqual(s, test.s, 0.01) || !ApproxEqual(v, test.v, 0.01) {
+			t.Errorf("%v: expected HSV %v %v %v got %v %v %v", test.c, test.h, test.s, test.v, h, s, v)
+		}
+		if back := ColorFromHSV(h, s, v); back != test.c {
+			t.Errorf("%v: HSV round trip gave %v", test.c, back)
+		}
+		h, s, l := test.c.HSL()
+		if !ApproxEqual(h, test.h, 0.01) || !ApproxEqual(s, test.p, 0.01) || !ApproxEqual(l, test.l, 0.01) {
+			t.Errorf("%v: expected HSL %v %v %v got %v %v %v", test.c, test.h, test.p, test.l, h, s, l)
+		}
+		if back := ColorFromHSL(h, s, l); back != test.c {
+			t.Errorf("%v: HSL round trip gave %v", test.c, back)
+		}
+	}
+	if ColorFromHSV(-240, 1, 1) != ColorGreen || ColorFromHSV(480, 1, 1) != ColorGreen {
+		t.Error("Hues should wrap around")
+	}
+}
+
+func TestColorSpaces(t *testing.T) {
+	for i := 0; i <= 0xff; i++ {
+		x := float32(i) / 0xff
+		if back := LinearToSRGB(SRGBToLinear(x)); !ApproxEqual(back, x, 1e-5) {
+			t.Fatalf("%v: sRGB round trip gave %v", x, back)
+		}
+	}
+	//Middle gray in sRGB is about a fifth of the light
+	gray := Color{R: 0x80, G: 0x80, B: 0x80, A: 0x80}.Float().ToLinear()
+	if !gray.ApproxEquals(ColorF{R: 0.2158, G: 0.2158, B: 0.2158, A: 0.502}, 1e-3) {
+		t.Errorf("Unexpected linear gray %v", gray)
+	}
+	if c := gray.ToSRGB().Color(); c != (Color{R: 0x80, G: 0x80, B: 0x80, A: 0x80}) {
+		t.Errorf("Expected the gray back got %v", c)
+	}
+
+	half := Color{R: 0xff, G: 0x80, A: 0x80}
+	if p := half.Premultiply(); p != (Color{R: 0x80, G: 0x40, A: 0x80}) {
+		t.Errorf("Unexpected premultiplied %v", p)
+	}
+	if c := half.Premultiply().Unpremultiply(); c != (Color{R: 0xff, G: 0x80, A: 0x80}) {
+		t.Errorf("Unpremultiply should undo Premultiply, got %v", c)
+	}
+	if p := half.Float().Premultiply(); !p.ApproxEquals(ColorF{R: 0.502, G: 0.2519, A: 0.502}, 1e-3) {
+		t.Errorf("Unexpected premultiplied %v", p)
+	}
+	if (Color{R: 10, A: 0}).Unpremultiply() != ColorTransparent {
+		t.Error("Transparent colors unpremultiply to transparent")
+	}
+}
+
+func TestColorConversions(t *testing.T) {
+	if c := ColorBlack.Lerp(ColorWhite, 0.5); c != (Color{R: 0x80, G: 0x80, B: 0x80, A: 0xff}) {
+		t.Errorf("Unexpected lerp %v", c)
+	}
+	if c := ColorRed.Lerp(ColorBlue, 2); c != ColorBlue.Lerp(ColorRed, -1) {
+		t.Error("Lerp past the ends should clamp the same both ways")
+	}
+	if c := (Color{R: 0xff, G: 0x80, B: 0x40, A: 0xff}).Mul(Color{R: 0x80, G: 0xff, A: 0x80}); c != (Color{R: 0x80, G: 0x80, A: 0x80}) {
+		t.Errorf("Unexpected product %v", c)
+	}
+	if ColorCyan.WithAlpha(0) != (Color{G: 0xff, B: 0xff}) {
+		t.Error("WithAlpha should only change alpha")
+	}
+
+	half := Color{R: 0xff, A: 0x80}
+	if r, g, b, a := half.RGBA(); r != 0x8080 || g != 0 || b != 0 || a != 0x8080 {
+		t.Errorf("RGBA should be premultiplied, got %x %x %x %x", r, g, b, a)
+	}
+	if c := ColorFromColor(color.RGBA{R: 0x80, A: 0x80}); c != half {
+		t.Errorf("Expected %v got %v", half, c)
+	}
+	if c := ColorModel.Convert(color.Gray{Y: 0x40}); c != (Color{R: 0x40, G: 0x40, B: 0x40, A: 0xff}) {
+		t.Errorf("Unexpected converted gray %v", c)
+	}
+	if half.NRGBA() != (color.NRGBA{R: 0xff, A: 0x80}) {
+		t.Error("NRGBA should keep the channels")
+	}
+	if SFColorToColor(half.ToSFML()) != half || SFColorToColor(ColorToSFML(color.RGBA{R: 0x80, A: 0x80})) != half {
+		t.Error("SFML colors should round trip")
+	}
+
+	//Colors work with image/draw
+	img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	draw.Draw(img, img.Rect, image.NewUniform(ColorF{G: 1, A: 1}), image.Point{}, draw.Src)
+	if img.RGBAAt(0, 0) != (color.RGBA{G: 0xff, A: 0xff}) {
+		t.Errorf("Unexpected drawn color %v", img.RGBAAt(0, 0))
+	}
+}
+
+func TestClearColor(t *testing.T) {
+	rt := NewSoftwareRenderTexture(2, 2)
+	rt.Clear(Color{R: 0xff, A: 0x80})
+	rt.Display()
+	if c := rt.Capture().RGBAAt(1, 1); c != (color.RGBA{R: 0x80, A: 0x80}) {
+		t.Errorf("Expected premultiplied half red got %v", c)
+	}
+}
@@ -0,0 +1,158 @@
+package goldcore
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+//ErrNoRenderThread : DoSync was called while nothing drains the queue
+var ErrNoRenderThread = errors.New("goldcore: no render thread is draining the command queue")
+
+//ErrQueueAttached : Attach was called while another thread drains the queue
+var ErrQueueAttached = errors.New("goldcore: another thread already drains the command queue")
+
+//CommandQueue : Functions waiting to run on a particular thread. The thread
+//attaches itself with Attach, then calls Drain regularly (every frame for the
+//render loop). A queue has one owner at a time, so commands run one at a
+//time, on that thread, in the order they were queued
+type CommandQueue struct {
+	mutex     sync.Mutex
+	commands  []queuedCommand
+	attached  bool
+	ready     chan struct{}
+	execMutex sync.Mutex
+}
+
+//queuedCommand : A command and who to tell once it ran. done is nil for Do
+type queuedCommand struct {
+	command func()
+	done    chan error
+}
+
+//NewCommandQueue : Creates an empty queue with no thread attached
+func NewCommandQueue() *CommandQueue {
+	return &CommandQueue{ready: make(chan struct{}, 1)}
+}
+
+//RenderThread : The engine's render thread queue. Owned and drained by the
+//render loop of the running GameWindow, the thread holding the GL context
+var RenderThread = NewCommandQueue()
+
+//MainThread : Work that has to happen on the main OS thread, like creating
+//windows on some platforms. Drained by goldframework.Main
+var MainThread = NewCommandQueue()
+
+//Do : Queues f to run on the render thread and returns right away. Safe to
+//call from anywhere, render thread commands included
+func Do(f func()) {
+	RenderThread.Do(f)
+}
+
+//DoSync : Runs f on the render thread and waits for it. Never call it from
+//the render thread itself (from a command), it would wait for itself forever.
+//Use Do there
+func DoSync(f func()) error {
+	return RenderThread.DoSync(f)
+}
+
+//Do : Queues f and returns right away. If no thread is attached f waits for
+//the next one
+func (q *CommandQueue) Do(f func()) {
+	q.push(queuedCommand{command: f})
+}
+
+//DoSync : Queues f and waits until it ran. Returns ErrNoRenderThread, without
+//running f, if no thread is attached. If f panics the panic is returned as
+//an error. Must not be called from a command of q
+func (q *CommandQueue) DoSync(f func()) error {
+	done := make(chan error, 1)
+	q.mutex.Lock()
+	if !q.attached {
+		q.mutex.Unlock()
+		return ErrNoRenderThread
+	}
+	q.commands = append(q.commands, queuedCommand{command: f, done: done})
+	q.mutex.Unlock()
+	q.signal()
+	return <-done
+}
+
+func (q *CommandQueue) push(cmd queuedCommand) {
+	q.mutex.Lock()
+	q.commands = append(q.commands, cmd)
+	q.mutex.Unlock()
+	q.signal()
+}
+
+//signal : Wakes up a thread waiting on Ready
+func (q *CommandQueue) signal() {
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+//Ready : Receives when commands were queued. Lets a thread sleep until there
+//is work instead of polling Drain
+func (q *CommandQueue) Ready() <-chan struct{} {
+	return q.ready
+}
+
+//Attach : Makes the calling thread the one draining the queue from now on.
+//Fails with ErrQueueAttached if another thread already does
+func (q *CommandQueue) Attach() error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if q.attached {
+		return ErrQueueAttached
+	}
+	q.attached = true
+	return nil
+}
+
+//Detach : Called by the thread draining the queue when it stops. Runs
+//whatever is left, so no DoSync is left waiting
+func (q *CommandQueue) Detach() {
+	q.mutex.Lock()
+	q.attached = false
+	q.mutex.Unlock()
+	q.Drain()
+}
+
+//IsAttached : Checks whether some thread is draining the queue
+func (q *CommandQueue) IsAttached() bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.attached
+}
+
+//Drain : Runs every queued command on the calling thread. Returns how many ran
+func (q *CommandQueue) Drain() int {
+	q.execMutex.Lock()
+	defer q.execMutex.Unlock()
+	q.mutex.Lock()
+	commands := q.commands
+	q.commands = nil
+	q.mutex.Unlock()
+
+	for _, cmd := range commands {
+		if cmd.done == nil {
+			cmd.command()
+			continue
+		}
+		cmd.done <- runCommand(cmd.command)
+	}
+	return len(commands)
+}
+
+//runCommand : Runs f turning a panic into an error
+func runCommand(f func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("goldcore: render thread command panicked: %v", r)
+		}
+	}()
+	f()
+	return nil
+}
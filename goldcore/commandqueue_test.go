@@ -0,0 +1,189 @@
+package goldcore
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestCommandQueueWithoutThread(t *testing.T) {
+	q := NewCommandQueue()
+	ran := false
+	if err := q.DoSync(func() { ran = true }); err != ErrNoRenderThread {
+		t.Errorf("Expected ErrNoRenderThread got %v", err)
+	}
+	if ran {
+		t.Errorf("DoSync ran the command without a thread")
+	}
+
+	//Do waits for a thread to come along
+	q.Do(func() { ran = true })
+	if ran {
+		t.Errorf("Do ran the command without a thread")
+	}
+	q.Attach()
+	if n := q.Drain(); n != 1 || !ran {
+		t.Errorf("Expected the queued command to run on Drain, ran %d", n)
+	}
+	q.Detach()
+}
+
+func TestCommandQueueOrder(t *testing.T) {
+	q := NewCommandQueue()
+	q.Attach()
+	stop := make(chan struct{})
+	var drained sync.WaitGroup
+	drained.Add(1)
+	go func() {
+		defer drained.Done()
+		for {
+			select {
+			case <-q.Ready():
+				q.Drain()
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	var order []int
+	for i := 0; i < 10; i++ {
+		i := i
+		if i%2 == 0 {
+			q.Do(func() { order = append(order, i) })
+		} else if err := q.DoSync(func() { order = append(order, i) }); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := q.DoSync(func() { panic("boom") }); err == nil {
+		t.Errorf("Panicking command should return an error")
+	}
+	close(stop)
+	drained.Wait()
+	q.Detach()
+
+	if len(order) != 10 {
+		t.Fatalf("Expected 10 commands to run got %v", order)
+	}
+	for i, v := range order {
+		if v != i {
+			t.Errorf("Commands ran out of order: %v", order)
+			break
+		}
+	}
+}
+
+func TestCommandQueueDetachRunsLeftovers(t *testing.T) {
+	q := NewCommandQueue()
+	q.Attach()
+	ran := 0
+	q.Do(func() { ran++ })
+	q.Do(func() { ran++ })
+	q.Detach()
+	if ran != 2 {
+		t.Errorf("Last Detach should run pending commands, ran %d", ran)
+	}
+	if q.IsAttached() {
+		t.Errorf("Queue still attached")
+	}
+}
+
+func TestWindowMutationsUseRenderThread(t *testing.T) {
+	gW := NewHeadlessGameWindow(64, 64, "Queue")
+	gW.Start()
+	if !RenderThread.IsAttached() {
+		t.Fatalf("Running window should drain the render thread queue")
+	}
+	gW.SetTitle("Renamed")
+	gW.SetPosition(Vector2i{10, 20})
+	gW.SetSize(Vector2u{32, 32})
+	if err := DoSync(func() {}); err != nil {
+		t.Errorf("DoSync on a running window: %v", err)
+	}
+	gW.Stop()
+	if RenderThread.IsAttached() {
+		t.Errorf("Stopped window should detach from the render thread queue")
+	}
+
+	w := gW.renderWindow.(*headlessWindow)
+	if w.title != "Renamed" {
+		t.Errorf("Expected title %q got %q", "Renamed", w.title)
+	}
+	if pos := gW.GetPosition(); !pos.Equals(Vector2i{10, 20}) {
+		t.Errorf("Expected position %v got %v", Vector2i{10, 20}, pos)
+	}
+	if size := gW.GetSize(); !size.Equals(Vector2u{32, 32}) {
+		t.Errorf("Expected size %v got %v", Vector2u{32, 32}, size)
+	}
+}
+
+func TestCommandQueueSingleOwner(t *testing.T) {
+	q := NewCommandQueue()
+	if err := q.Attach(); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Attach(); err != ErrQueueAttached {
+		t.Errorf("Expected ErrQueueAttached for a second owner got %v", err)
+	}
+	q.Detach()
+	if err := q.Attach(); err != nil {
+		t.Errorf("A detached queue can be taken again: %v", err)
+	}
+	q.Detach()
+}
+
+type commandFailedObserver struct {
+	errs []error
+}
+
+func (o *commandFailedObserver) OnWindowNotify(gM *GameMessage) {
+	if gM.Message == WindowCommandFailed {
+		o.errs = append(o.errs, gM.Payload.(error))
+	}
+}
+
+//reactingObserver : Changes the window when told a command failed
+type reactingObserver struct {
+	gW       *GameWindow
+	captured bool
+}
+
+func (o *reactingObserver) OnWindowNotify(gM *GameMessage) {
+	if gM.Message == WindowCommandFailed {
+		o.gW.SetTitle("Reacted")
+		o.gW.Clear(ColorRed)
+		o.captured = o.gW.Capture() != nil
+	}
+}
+
+func TestWindowMutationFromRenderThread(t *testing.T) {
+	gW := NewHeadlessGameWindow(64, 64, "Queue")
+	failed := &commandFailedObserver{}
+	reacting := &reactingObserver{gW: gW}
+	gW.AddObserver(failed)
+	gW.AddObserver(reacting)
+	gW.Start()
+	//Window calls from a command are queued instead of waiting for themselves
+	if err := DoSync(func() { gW.SetTitle("Nested") }); err != nil {
+		t.Errorf("Setter called from the render thread: %v", err)
+	}
+	gW.do(func() { panic("boom") })
+	if err := DoSync(func() {}); err != nil {
+		t.Fatal(err)
+	}
+	if len(failed.errs) != 0 {
+		t.Errorf("The render loop shouldn't notify observers itself")
+	}
+	//Observers hear about it on this goroutine, and can use the window
+	gW.PollEvent()
+	if err := DoSync(func() {}); err != nil {
+		t.Fatal(err)
+	}
+	gW.Stop()
+
+	if w := gW.renderWindow.(*headlessWindow); w.title != "Reacted" {
+		t.Errorf("Expected title %q got %q", "Reacted", w.title)
+	}
+	if len(failed.errs) != 1 || !reacting.captured {
+		t.Errorf("Expected one WindowCommandFailed got %v", failed.errs)
+	}
+}
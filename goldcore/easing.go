@@ -0,0 +1,135 @@
+package goldcore
+
+import "math"
+
+//EasingFunc : Shapes the progress of a tween. Takes the linear progress, 0 at
+//the start and 1 at the end, and returns the eased one. Elastic and back
+//curves overshoot below 0 or above 1 on purpose
+type EasingFunc func(t float32) float32
+
+//EaseLinear : Constant speed
+func EaseLinear(t float32) float32 {
+	return t
+}
+
+//EaseInQuad : Starts slow, speeds up
+func EaseInQuad(t float32) float32 {
+	return t * t
+}
+
+//EaseOutQuad : Starts fast, slows down
+func EaseOutQuad(t float32) float32 {
+	return 1 - (1-t)*(1-t)
+}
+
+//EaseInOutQuad : Slow at both ends
+func EaseInOutQuad(t float32) float32 {
+	return easeInOut(t, EaseInQuad)
+}
+
+//EaseInCubic : Starts slower than quad, speeds up
+func EaseInCubic(t float32) float32 {
+	return t * t * t
+}
+
+//EaseOutCubic : Starts fast, slows down more than quad
+func EaseOutCubic(t float32) float32 {
+	return easeOut(t, EaseInCubic)
+}
+
+//EaseInOutCubic : Slow at both ends
+func EaseInOutCubic(t float32) float32 {
+	return easeInOut(t, EaseInCubic)
+}
+
+//EaseInSine : Gentle start
+func EaseInSine(t float32) float32 {
+	return 1 - float32(math.Cos(float64(t)*math.Pi/2))
+}
+
+//EaseOutSine : Gentle stop
+func EaseOutSine(t float32) float32 {
+	return float32(math.Sin(float64(t) * math.Pi / 2))
+}
+
+//EaseInOutSine : Gentle at both ends
+func EaseInOutSine(t float32) float32 {
+	return easeInOut(t, EaseInSine)
+}
+
+//backOvershoot : How far back curves pull back, about 10%
+const backOvershoot = 1.70158
+
+//EaseInBack : Pulls back a little before going
+func EaseInBack(t float32) float32 {
+	return t * t * ((backOvershoot+1)*t - backOvershoot)
+}
+
+//EaseOutBack : Overshoots the end a little, then settles
+func EaseOutBack(t float32) float32 {
+	return easeOut(t, EaseInBack)
+}
+
+//EaseInOutBack : Pulls back at the start and overshoots at the end
+func EaseInOutBack(t float32) float32 {
+	return easeInOut(t, EaseInBack)
+}
+
+//EaseInElastic : Winds up like a spring before going
+func EaseInElastic(t float32) float32 {
+	if t <= 0 || t >= 1 {
+		return t
+	}
+	return -float32(math.Pow(2, 10*float64(t)-10) * math.Sin((float64(t)*10-10.75)*2*math.Pi/3))
+}
+
+//EaseOutElastic : Springs past the end and wobbles into place
+func EaseOutElastic(t float32) float32 {
+	return easeOut(t, EaseInElastic)
+}
+
+//EaseInOutElastic : Winds up at the start and wobbles at the end
+func EaseInOutElastic(t float32) float32 {
+	return easeInOut(t, EaseInElastic)
+}
+
+//EaseOutBounce : Drops onto the end and bounces off it a few times
+func EaseOutBounce(t float32) float32 {
+	const n, d = 7.5625, 2.75
+	switch {
+	case t < 1/d:
+		return n * t * t
+	case t < 2/d:
+		t -= 1.5 / d
+		return n*t*t + 0.75
+	case t < 2.5/d:
+		t -= 2.25 / d
+		return n*t*t + 0.9375
+	default:
+		t -= 2.625 / d
+		return n*t*t + 0.984375
+	}
+}
+
+//EaseInBounce : Bounces off the start a few times before leaving
+func EaseInBounce(t float32) float32 {
+	return 1 - EaseOutBounce(1-t)
+}
+
+//EaseInOutBounce : Bounces at both ends
+func EaseInOutBounce(t float32) float32 {
+	return easeInOut(t, EaseInBounce)
+}
+
+//easeOut : Out version of an in curve, played backwards
+func easeOut(t float32, in EasingFunc) float32 {
+	return 1 - in(1-t)
+}
+
+//easeInOut : First half of in, then the mirrored second half
+func easeInOut(t float32, in EasingFunc) float32 {
+	if t < 0.5 {
+		return in(2*t) / 2
+	}
+	return 1 - in(2-2*t)/2
+}
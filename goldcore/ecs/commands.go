@@ -0,0 +1,98 @@
+package ecs
+
+import "sync"
+
+//Component : A component value to give an entity, see With
+type Component interface {
+	set(w *World, e Entity)
+}
+
+type component[T any] struct {
+	value T
+}
+
+func (c component[T]) set(w *World, e Entity) {
+	Components[T](w).Set(e, c.value)
+}
+
+//With : value as a Component, for World.Create and Commands
+func With[T any](value T) Component {
+	return component[T]{value: value}
+}
+
+//Commands : Structural changes recorded to be applied later, in the order
+//they were made. Entities are created when applied, so ids are handed out
+//in the same order every run. Commands on entities that are no longer
+//alive by then are dropped. Safe for concurrent use
+type Commands struct {
+	world *World
+	mutex sync.Mutex
+	ops   []func(w *World)
+}
+
+func newCommands(w *World) *Commands {
+	return &Commands{world: w}
+}
+
+//Do : Calls f with the world when the commands are applied
+func (c *Commands) Do(f func(w *World)) {
+	c.mutex.Lock()
+	c.ops = append(c.ops, f)
+	c.mutex.Unlock()
+}
+
+//Create : Creates an entity with components
+func (c *Commands) Create(components ...Component) {
+	c.Do(func(w *World) {
+		w.Create(components...)
+	})
+}
+
+//Destroy : Destroys e
+func (c *Commands) Destroy(e Entity) {
+	c.Do(func(w *World) {
+		w.Destroy(e)
+	})
+}
+
+//Set : Gives e components, replacing those it has
+func (c *Commands) Set(e Entity, components ...Component) {
+	c.Do(func(w *World) {
+		if !w.Alive(e) {
+			return
+		}
+		for _, component := range components {
+			component.set(w, e)
+		}
+	})
+}
+
+//RemoveLater : Drops the T of e when c is applied
+func RemoveLater[T any](c *Commands, e Entity) {
+	c.Do(func(w *World) {
+		Remove[T](w, e)
+	})
+}
+
+//Len : Number of commands waiting
+func (c *Commands) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.ops)
+}
+
+//apply : Runs the commands, including those they make
+func (c *Commands) apply() {
+	for {
+		c.mutex.Lock()
+		ops := c.ops
+		c.ops = nil
+		c.mutex.Unlock()
+		if len(ops) == 0 {
+			return
+		}
+		for _, op := range ops {
+			op(c.world)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+//Package ecs : Entity component system. Entities are ids, their data lives
+//in components stored densely per type, and systems run every update over
+//the entities having the components they query. A World is a
+//goldcore.Updater:
+//	world := ecs.NewWorld()
+//	world.AddSystem(ecs.System{
+//		Name:   "movement",
+//		Access: []ecs.Access{ecs.Read[Velocity](), ecs.Write[Position]()},
+//		Run: func(ctx *ecs.Context) {
+//			ecs.NewQuery2[Position, Velocity](ctx.World).Each(func(e ecs.Entity, p *Position, v *Velocity) {
+//				p.X += v.X * ctx.DT
+//			})
+//		},
+//	})
+//	game.AddUpdater(world)
+//Systems run one after the other unless SetWorkers says otherwise
+package ecs
+
+import "fmt"
+
+//Entity : Handle to an entity, the index of its slot and the generation of
+//the slot. Destroying an entity bumps the generation, so handles to it stop
+//matching the entity that reuses the slot. The zero Entity is never alive
+type Entity uint64
+
+//newEntity : Entity of the slot index at generation
+func newEntity(index, generation uint32) Entity {
+	return Entity(uint64(generation)<<32 | uint64(index))
+}
+
+//Index : Slot of the entity, reused once it is destroyed
+func (e Entity) Index() uint32 {
+	return uint32(e)
+}
+
+//Generation : How many entities used the slot before, plus one
+func (e Entity) Generation() uint32 {
+	return uint32(e >> 32)
+}
+
+func (e Entity) String() string {
+	return fmt.Sprintf("Entity(%dv%d)", e.Index(), e.Generation())
+}
+
+//entitySlot : State of an entity index
+type entitySlot struct {
+	generation uint32 //Of the entity using or next using the slot
+	alive      bool
+}
+
+//entities : Allocates entity slots, reusing destroyed ones first
+type entities struct {
+	slots []entitySlot
+	free  []uint32 //Oldest first, so generations wrap as late as possible
+	alive int
+}
+
+//create : A new alive entity
+func (es *entities) create() Entity {
+	var index uint32
+	if len(es.free) > 0 {
+		index = es.free[0]
+		es.free = es.free[1:]
+	} else {
+		index = uint32(len(es.slots))
+		es.slots = append(es.slots, entitySlot{generation: 1})
+	}
+	es.slots[index].alive = true
+	es.alive++
+	return newEntity(index, es.slots[index].generation)
+}
+
+//release : Frees the slot of e. False if it isn't alive
+func (es *entities) release(e Entity) bool {
+	if !es.isAlive(e) {
+		return false
+	}
+	slot := &es.slots[e.Index()]
+	slot.alive = false
+	slot.generation++
+	if slot.generation == 0 {
+		slot.generation = 1
+	}
+	es.free = append(es.free, e.Index())
+	es.alive--
+	return true
+}
+
+func (es *entities) isAlive(e Entity) bool {
+	i := e.Index()
+	return int(i) < len(es.slots) && es.slots[i].alive && es.slots[i].generation == e.Generation()
+}
@@ -0,0 +1,223 @@
+package ecs
+
+//Queries walk the smallest storage they involve and look the entities up
+//in the others. They lock the world while iterating, see World. Building
+//one is cheap, systems can build theirs on every run
+
+//filter : Storages entities must, or must not, have a component in
+type filter struct {
+	with, without []ComponentStorage
+}
+
+//match : Checks e against the filter
+func (f *filter) match(e Entity) bool {
+	for _, s := range f.with {
+		if !s.Has(e) {
+			return false
+		}
+	}
+	for _, s := range f.without {
+		if s.Has(e) {
+			return false
+		}
+	}
+	return true
+}
+
+//driver : Entities of the smallest of storages and the with filter
+func (f *filter) driver(storages ...ComponentStorage) []Entity {
+	smallest := storages[0]
+	for _, s := range append(storages[1:], f.with...) {
+		if s.Len() < smallest.Len() {
+			smallest = s
+		}
+	}
+	return smallest.Entities()
+}
+
+//each : Calls f with the entities having a component in every storage
+//and passing the filter
+func (f *filter) each(w *World, storages []ComponentStorage, fn func(e Entity)) {
+	w.lock()
+	defer w.unlock()
+	for _, e := range f.driver(storages...) {
+		found := true
+		for _, s := range storages {
+			if !s.Has(e) {
+				found = false
+				break
+			}
+		}
+		if found && f.match(e) {
+			fn(e)
+		}
+	}
+}
+
+//Query1 : Entities having an A
+type Query1[A any] struct {
+	filter
+	world *World
+	a     *Storage[A]
+}
+
+//NewQuery1 : Query of the entities of w having an A
+func NewQuery1[A any](w *World) *Query1[A] {
+	return &Query1[A]{world: w, a: Components[A](w)}
+}
+
+//With : Only keeps entities having a component in every storage
+func (q *Query1[A]) With(storages ...ComponentStorage) *Query1[A] {
+	q.with = append(q.with, storages...)
+	return q
+}
+
+//Without : Leaves out entities having a component in any storage
+func (q *Query1[A]) Without(storages ...ComponentStorage) *Query1[A] {
+	q.without = append(q.without, storages...)
+	return q
+}
+
+//Each : Calls f with every entity and its components. The pointers are
+//only valid during the call
+func (q *Query1[A]) Each(f func(e Entity, a *A)) {
+	if len(q.with) == 0 && len(q.without) == 0 {
+		//Straight down the dense array
+		q.world.lock()
+		defer q.world.unlock()
+		for i, e := range q.a.entities {
+			f(e, &q.a.dense[i])
+		}
+		return
+	}
+	q.each(q.world, []ComponentStorage{q.a}, func(e Entity) {
+		f(e, &q.a.dense[q.a.position(e)])
+	})
+}
+
+//Count : Number of entities matching
+func (q *Query1[A]) Count() int {
+	n := 0
+	q.each(q.world, []ComponentStorage{q.a}, func(Entity) { n++ })
+	return n
+}
+
+//Query2 : Entities having an A and a B
+type Query2[A, B any] struct {
+	filter
+	world *World
+	a     *Storage[A]
+	b     *Storage[B]
+}
+
+//NewQuery2 : Query of the entities of w having an A and a B
+func NewQuery2[A, B any](w *World) *Query2[A, B] {
+	return &Query2[A, B]{world: w, a: Components[A](w), b: Components[B](w)}
+}
+
+//With : See Query1.With
+func (q *Query2[A, B]) With(storages ...ComponentStorage) *Query2[A, B] {
+	q.with = append(q.with, storages...)
+	return q
+}
+
+//Without : See Query1.Without
+func (q *Query2[A, B]) Without(storages ...ComponentStorage) *Query2[A, B] {
+	q.without = append(q.without, storages...)
+	return q
+}
+
+//Each : See Query1.Each
+func (q *Query2[A, B]) Each(f func(e Entity, a *A, b *B)) {
+	q.each(q.world, []ComponentStorage{q.a, q.b}, func(e Entity) {
+		f(e, &q.a.dense[q.a.position(e)], &q.b.dense[q.b.position(e)])
+	})
+}
+
+//Count : Number of entities matching
+func (q *Query2[A, B]) Count() int {
+	n := 0
+	q.each(q.world, []ComponentStorage{q.a, q.b}, func(Entity) { n++ })
+	return n
+}
+
+//Query3 : Entities having an A, a B and a C
+type Query3[A, B, C any] struct {
+	filter
+	world *World
+	a     *Storage[A]
+	b     *Storage[B]
+	c     *Storage[C]
+}
+
+//NewQuery3 : Query of the entities of w having an A, a B and a C
+func NewQuery3[A, B, C any](w *World) *Query3[A, B, C] {
+	return &Query3[A, B, C]{world: w, a: Components[A](w), b: Components[B](w), c: Components[C](w)}
+}
+
+//With : See Query1.With
+func (q *Query3[A, B, C]) With(storages ...ComponentStorage) *Query3[A, B, C] {
+	q.with = append(q.with, storages...)
+	return q
+}
+
+//Without : See Query1.Without
+func (q *Query3[A, B, C]) Without(storages ...ComponentStorage) *Query3[A, B, C] {
+	q.without = append(q.without, storages...)
+	return q
+}
+
+//Each : See Query1.Each
+func (q *Query3[A, B, C]) Each(f func(e Entity, a *A, b *B, c *C)) {
+	q.each(q.world, []ComponentStorage{q.a, q.b, q.c}, func(e Entity) {
+		f(e, &q.a.dense[q.a.position(e)], &q.b.dense[q.b.position(e)], &q.c.dense[q.c.position(e)])
+	})
+}
+
+//Count : Number of entities matching
+func (q *Query3[A, B, C]) Count() int {
+	n := 0
+	q.each(q.world, []ComponentStorage{q.a, q.b, q.c}, func(Entity) { n++ })
+	return n
+}
+
+//Query4 : Entities having an A, a B, a C and a D
+type Query4[A, B, C, D any] struct {
+	filter
+	world *World
+	a     *Storage[A]
+	b     *Storage[B]
+	c     *Storage[C]
+	d     *Storage[D]
+}
+
+//NewQuery4 : Query of the entities of w having an A, a B, a C and a D
+func NewQuery4[A, B, C, D any](w *World) *Query4[A, B, C, D] {
+	return &Query4[A, B, C, D]{world: w, a: Components[A](w), b: Components[B](w), c: Components[C](w), d: Components[D](w)}
+}
+
+//With : See Query1.With
+func (q *Query4[A, B, C, D]) With(storages ...ComponentStorage) *Query4[A, B, C, D] {
+	q.with = append(q.with, storages...)
+	return q
+}
+
+//Without : See Query1.Without
+func (q *Query4[A, B, C, D]) Without(storages ...ComponentStorage) *Query4[A, B, C, D] {
+	q.without = append(q.without, storages...)
+	return q
+}
+
+//Each : See Query1.Each
+func (q *Query4[A, B, C, D]) Each(f func(e Entity, a *A, b *B, c *C, d *D)) {
+	q.each(q.world, []ComponentStorage{q.a, q.b, q.c, q.d}, func(e Entity) {
+		f(e, &q.a.dense[q.a.position(e)], &q.b.dense[q.b.position(e)], &q.c.dense[q.c.position(e)], &q.d.dense[q.d.position(e)])
+	})
+}
+
+//Count : Number of entities matching
+func (q *Query4[A, B, C, D]) Count() int {
+	n := 0
+	q.each(q.world, []ComponentStorage{q.a, q.b, q.c, q.d}, func(Entity) { n++ })
+	return n
+}
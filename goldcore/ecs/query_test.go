@@ -0,0 +1,109 @@
+package ecs
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestQueries(t *testing.T) {
+	w := NewWorld()
+	var all []Entity
+	for i := 0; i < 12; i++ {
+		e := w.Create(With(position{float32(i), 0}))
+		if i%2 == 0 {
+			Set(w, e, velocity{1, 2})
+		}
+		if i%3 == 0 {
+			Set(w, e, health(i))
+		}
+		if i%4 == 0 {
+			Set(w, e, frozen{})
+		}
+		all = append(all, e)
+	}
+	visit := func(each func(f func(e Entity))) []int {
+		var seen []int
+		each(func(e Entity) {
+			seen = append(seen, int(e.Index()))
+		})
+		sort.Ints(seen)
+		return seen
+	}
+	equal := func(a, b []int) bool {
+		if len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if a[i] != b[i] {
+				return false
+			}
+		}
+		return true
+	}
+
+	q1 := NewQuery1[position](w)
+	if q1.Count() != 12 {
+		t.Errorf("expected 12 positions, got %d", q1.Count())
+	}
+	q2 := NewQuery2[position, velocity](w)
+	q2.Each(func(e Entity, p *position, v *velocity) {
+		p.X += v.X
+	})
+	if p, _ := Get[position](w, all[2]); p.X != 3 {
+		t.Errorf("changes through query pointers should stick, got %v", p)
+	}
+	if p, _ := Get[position](w, all[3]); p.X != 3 {
+		t.Errorf("entities without a velocity shouldn't move, got %v", p)
+	}
+	seen := visit(func(f func(Entity)) { q2.Each(func(e Entity, _ *position, _ *velocity) { f(e) }) })
+	if !equal(seen, []int{0, 2, 4, 6, 8, 10}) {
+		t.Errorf("Query2 visited %v", seen)
+	}
+	q3 := NewQuery3[position, velocity, health](w)
+	seen = visit(func(f func(Entity)) { q3.Each(func(e Entity, _ *position, _ *velocity, h *health) { f(e) }) })
+	if !equal(seen, []int{0, 6}) || q3.Count() != 2 {
+		t.Errorf("Query3 visited %v", seen)
+	}
+	q4 := NewQuery4[position, velocity, health, frozen](w)
+	if q4.Count() != 1 {
+		t.Errorf("Query4 expected only entity 0, got %d", q4.Count())
+	}
+	//Filters
+	frozens := Components[frozen](w)
+	without := NewQuery2[position, velocity](w).Without(frozens)
+	seen = visit(func(f func(Entity)) { without.Each(func(e Entity, _ *position, _ *velocity) { f(e) }) })
+	if !equal(seen, []int{2, 6, 10}) {
+		t.Errorf("Without visited %v", seen)
+	}
+	with := NewQuery1[health](w).With(frozens)
+	seen = visit(func(f func(Entity)) { with.Each(func(e Entity, _ *health) { f(e) }) })
+	if !equal(seen, []int{0}) || with.Count() != 1 {
+		t.Errorf("With visited %v", seen)
+	}
+	if NewQuery1[frozen](w).Without(Components[velocity](w)).Count() != 0 {
+		t.Error("Every frozen entity has a velocity")
+	}
+}
+
+func BenchmarkQuery(b *testing.B) {
+	w := benchmarkWorld(50000)
+	q := NewQuery2[position, velocity](w)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q.Each(func(e Entity, p *position, v *velocity) {
+			p.X += v.X
+			p.Y += v.Y
+		})
+	}
+}
+
+func BenchmarkQuery1(b *testing.B) {
+	w := benchmarkWorld(50000)
+	q := NewQuery1[position](w)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q.Each(func(e Entity, p *position) {
+			p.X++
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package ecs
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+//Systems conflict when one writes a component type the other reads or
+//writes, or when After or Before order them. A system without Access
+//conflicts with every other. With more than one worker, Update runs each
+//system once the earlier ones it conflicts with are done, so systems
+//declaring their access right get the same results as one after the
+//other, and their commands are applied in run order either way
+
+//schedule : Systems in run order and what each waits for
+type schedule struct {
+	systems []*system
+	waits   [][]int //Positions of the earlier systems each conflicts with
+	unlocks [][]int //Positions of the later systems waiting for each
+}
+
+//newSchedule : Schedule of systems, sorted in run order
+func newSchedule(systems []*system) *schedule {
+	sc := &schedule{
+		systems: systems,
+		waits:   make([][]int, len(systems)),
+		unlocks: make([][]int, len(systems)),
+	}
+	for i, s := range systems {
+		for j, o := range systems[:i] {
+			if conflicts(s, o) {
+				sc.waits[i] = append(sc.waits[i], j)
+				sc.unlocks[j] = append(sc.unlocks[j], i)
+			}
+		}
+	}
+	return sc
+}
+
+//conflicts : Checks whether a and b must not run at the same time
+func conflicts(a, b *system) bool {
+	if len(a.Access) == 0 || len(b.Access) == 0 {
+		return true
+	}
+	if orders(a, b) || orders(b, a) {
+		return true
+	}
+	for _, x := range a.Access {
+		for _, y := range b.Access {
+			if x.Type == y.Type && (x.Write || y.Write) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+//orders : Checks whether a puts itself after b
+func orders(a, b *system) bool {
+	for _, name := range a.After {
+		if name == b.Name {
+			return true
+		}
+	}
+	for _, name := range b.Before {
+		if name == a.Name {
+			return true
+		}
+	}
+	return false
+}
+
+//run : Runs the systems on up to workers goroutines. A panicking system
+//stops those not started yet, and the panic is raised again once the
+//running ones are done
+func (sc *schedule) run(w *World, dt float32, workers int) {
+	n := len(sc.systems)
+	if workers > n {
+		workers = n
+	}
+	pending := make([]int32, n)
+	ready := make(chan int, n)
+	for i, waits := range sc.waits {
+		pending[i] = int32(len(waits))
+		if len(waits) == 0 {
+			ready <- i
+		}
+	}
+	var (
+		done    sync.WaitGroup
+		failed  int32
+		mutex   sync.Mutex
+		failure interface{}
+		first   = n //Position of the system that panicked first in run order
+	)
+	done.Add(n)
+	runSystem := func(i int) {
+		defer func() {
+			if r := recover(); r != nil {
+				atomic.StoreInt32(&failed, 1)
+				mutex.Lock()
+				if i < first {
+					first, failure = i, r
+				}
+				mutex.Unlock()
+			}
+		}()
+		if atomic.LoadInt32(&failed) == 0 {
+			sc.systems[i].run(w, dt)
+		}
+	}
+	for k := 0; k < workers; k++ {
+		go func() {
+			for i := range ready {
+				runSystem(i)
+				for _, j := range sc.unlocks[i] {
+					if atomic.AddInt32(&pending[j], -1) == 0 {
+						ready <- j
+					}
+				}
+				done.Done()
+			}
+		}()
+	}
+	done.Wait()
+	close(ready)
+	if failure != nil {
+		panic(failure)
+	}
+}
+
+//SetWorkers : Runs systems on up to n goroutines, one per CPU if n < 1.
+//One, the default, runs them one after the other on the goroutine calling
+//Update, in the order of Systems
+func (w *World) SetWorkers(n int) {
+	if n < 1 {
+		n = runtime.NumCPU()
+	}
+	w.workers = n
+}
+
+//Workers : Goroutines systems run on, see SetWorkers
+func (w *World) Workers() int {
+	return w.workers
+}
+
+//Dependencies : Systems name waits for when running on several workers,
+//in run order. Fails if there is no system name
+func (w *World) Dependencies(name string) ([]string, error) {
+	for i, s := range w.schedule.systems {
+		if s.Name == name {
+			names := make([]string, len(w.schedule.waits[i]))
+			for k, j := range w.schedule.waits[i] {
+				names[k] = w.schedule.systems[j].Name
+			}
+			return names, nil
+		}
+	}
+	return nil, fmt.Errorf("ecs: there is no system %s", name)
+}
@@ -0,0 +1,223 @@
+package ecs
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDependencies(t *testing.T) {
+	w := NewWorld()
+	noop := func(*Context) {}
+	systems := []System{
+		{Name: "movement", Run: noop, Access: []Access{Write[position](), Read[velocity]()}},
+		{Name: "regen", Run: noop, Access: []Access{Write[health]()}},
+		{Name: "steering", Run: noop, Access: []Access{Write[velocity]()}},
+		{Name: "camera", Run: noop, Access: []Access{Read[position]()}},
+		{Name: "radar", Run: noop, Access: []Access{Read[position](), Read[velocity]()}},
+		{Name: "log", Run: noop, Access: []Access{Read[frozen]()}, After: []string{"regen"}},
+		{Name: "save", Run: noop},
+	}
+	for _, s := range systems {
+		if err := w.AddSystem(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := map[string]string{
+		"movement": "",
+		"regen":    "",
+		"steering": "movement",
+		"camera":   "movement",
+		"radar":    "movement steering",
+		"log":      "regen",
+		"save":     "movement regen steering camera radar log",
+	}
+	for name, deps := range want {
+		got, err := w.Dependencies(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Join(got, " ") != deps {
+			t.Errorf("expected %s to wait for %q, got %v", name, deps, got)
+		}
+	}
+	if _, err := w.Dependencies("missing"); err == nil {
+		t.Error("Unknown systems have no dependencies")
+	}
+	w.RemoveSystem("movement")
+	if got, _ := w.Dependencies("camera"); len(got) != 0 {
+		t.Errorf("removing a system should drop it from dependencies, got %v", got)
+	}
+}
+
+func TestParallelSystems(t *testing.T) {
+	w := NewWorld()
+	w.SetWorkers(2)
+	meet := make(chan struct{})
+	var met [2]bool
+	w.AddSystem(System{
+		Name:   "a",
+		Access: []Access{Read[position]()},
+		Run: func(*Context) {
+			select {
+			case meet <- struct{}{}:
+				met[0] = true
+			case <-time.After(5 * time.Second):
+			}
+		},
+	})
+	w.AddSystem(System{
+		Name:   "b",
+		Access: []Access{Read[position]()},
+		Run: func(*Context) {
+			select {
+			case <-meet:
+				met[1] = true
+			case <-time.After(5 * time.Second):
+			}
+		},
+	})
+	w.Update(0)
+	if !met[0] || !met[1] {
+		t.Error("Systems only reading should run at the same time")
+	}
+	if w.Workers() != 2 {
+		t.Errorf("expected 2 workers, got %d", w.Workers())
+	}
+	w.SetWorkers(0)
+	if w.Workers() < 1 {
+		t.Error("Workers should default to the CPUs")
+	}
+}
+
+//simulation : World with systems moving, steering, damaging and spawning
+//entities, some at the same time when workers > 1
+func simulation(workers int) *World {
+	w := NewWorld()
+	w.SetWorkers(workers)
+	for i := 0; i < 500; i++ {
+		w.Create(With(position{float32(i), 0}), With(velocity{1, float32(i % 7)}), With(health(i%50+1)))
+	}
+	w.AddSystem(System{
+		Name:   "steering",
+		Access: []Access{Write[velocity](), Read[position]()},
+		Run: func(ctx *Context) {
+			positions := Use[position](ctx)
+			NewQuery1[velocity](ctx.World).Each(func(e Entity, v *velocity) {
+				if p, _ := positions.Get(e); p.Y > 100 {
+					v.Y = -v.Y
+				}
+			})
+		},
+	})
+	w.AddSystem(System{
+		Name:   "movement",
+		Access: []Access{Write[position](), Read[velocity]()},
+		Run: func(ctx *Context) {
+			NewQuery2[position, velocity](ctx.World).Each(func(_ Entity, p *position, v *velocity) {
+				p.X += v.X * ctx.DT
+				p.Y += v.Y * ctx.DT
+			})
+		},
+	})
+	w.AddSystem(System{
+		Name:   "damage",
+		Access: []Access{Write[health]()},
+		Run: func(ctx *Context) {
+			NewQuery1[health](ctx.World).Each(func(e Entity, h *health) {
+				*h--
+				if *h <= 0 {
+					ctx.Commands.Destroy(e)
+					ctx.Commands.Create(With(position{}), With(velocity{2, 1}), With(health(30)))
+				}
+			})
+		},
+	})
+	w.AddSystem(System{
+		Name:   "freeze",
+		Access: []Access{Read[health](), Read[position]()},
+		After:  []string{"damage"},
+		Run: func(ctx *Context) {
+			NewQuery2[health, position](ctx.World).Without(Components[frozen](ctx.World)).Each(func(e Entity, h *health, p *position) {
+				if *h == 10 {
+					ctx.Commands.Set(e, With(frozen{}))
+				}
+			})
+		},
+	})
+	return w
+}
+
+//snapshot : Every entity of w with its components
+func snapshot(w *World) map[Entity][4]interface{} {
+	entities := make(map[Entity][4]interface{})
+	NewQuery1[position](w).Each(func(e Entity, p *position) {
+		v, _ := Get[velocity](w, e)
+		h, _ := Get[health](w, e)
+		entities[e] = [4]interface{}{*p, *v, *h, Has[frozen](w, e)}
+	})
+	return entities
+}
+
+func TestParallelDeterminism(t *testing.T) {
+	serial, parallel := simulation(1), simulation(4)
+	for i := 0; i < 60; i++ {
+		serial.Update(0.5)
+		parallel.Update(0.5)
+	}
+	a, b := snapshot(serial), snapshot(parallel)
+	if len(a) != 500 || Components[frozen](serial).Len() == 0 {
+		t.Fatalf("expected the simulation to respawn and freeze, %d entities", len(a))
+	}
+	if !reflect.DeepEqual(a, b) {
+		t.Error("Running on several workers should give the same world, entity ids included")
+	}
+}
+
+func TestParallelPanic(t *testing.T) {
+	for _, workers := range []int{1, 3} {
+		w := NewWorld()
+		w.SetWorkers(workers)
+		ran := make(chan string, 3)
+		w.AddSystem(System{Name: "fine", Access: []Access{Read[position]()}, Run: func(*Context) { ran <- "fine" }})
+		w.AddSystem(System{Name: "broken", Access: []Access{Write[health]()}, Run: func(*Context) { panic("broken") }})
+		w.AddSystem(System{Name: "later", Access: []Access{Write[health]()}, Run: func(*Context) { ran <- "later" }})
+		if r := func() (r interface{}) {
+			defer func() { r = recover() }()
+			w.Update(0)
+			return nil
+		}(); r != "broken" {
+			t.Errorf("%d workers: expected the system's panic, got %v", workers, r)
+		}
+		close(ran)
+		for name := range ran {
+			if name == "later" {
+				t.Errorf("%d workers: systems waiting for a panicking one shouldn't run", workers)
+			}
+		}
+		//The world is usable again
+		w.Create()
+		if w.Len() != 1 {
+			t.Errorf("%d workers: expected the entity created, got %d", workers, w.Len())
+		}
+	}
+}
+
+//BenchmarkSimulation : The simulation over 50k entities, one after the
+//other and on every CPU
+func BenchmarkSimulation(b *testing.B) {
+	for name, workers := range map[string]int{"serial": 1, "parallel": 0} {
+		w := simulation(workers)
+		for i := 0; i < 100; i++ {
+			for j := 0; j < 500; j++ {
+				w.Create(With(position{float32(j), 0}), With(velocity{1, 1}), With(health(1000)))
+			}
+		}
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				w.Update(1.0 / 60)
+			}
+		})
+	}
+}
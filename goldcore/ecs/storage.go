@@ -0,0 +1,146 @@
+package ecs
+
+import (
+	"fmt"
+	"reflect"
+)
+
+//ComponentStorage : Storage of some component type, see Storage
+type ComponentStorage interface {
+	//Type : The component type
+	Type() reflect.Type
+	//Has : Checks whether e has the component
+	Has(e Entity) bool
+	//Len : Number of entities having the component
+	Len() int
+	//Entities : Entities having the component, in storage order. Owned by
+	//the storage, don't change it
+	Entities() []Entity
+	//remove : Drops the component of e. Only Storage implements it
+	remove(e Entity) bool
+}
+
+//Storage : Components of type T, packed in a dense array in no particular
+//order, with a sparse array from entity index to position. Lookups are two
+//array reads, iterating is walking an array. Pointers to components are
+//only valid until a component of the type is added or removed
+type Storage[T any] struct {
+	world    *World
+	typ      reflect.Type
+	sparse   []int32 //Position in dense by entity index, -1 for none
+	dense    []T
+	entities []Entity //Entity owning dense[i]
+}
+
+//Components : Storage of the components of type T of w, created the first
+//time it is asked for
+func Components[T any](w *World) *Storage[T] {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if s, ok := w.storages[typ]; ok {
+		return s.(*Storage[T])
+	}
+	s := &Storage[T]{world: w, typ: typ}
+	w.storages[typ] = s
+	w.storageOrder = append(w.storageOrder, s)
+	return s
+}
+
+//Type : The component type
+func (s *Storage[T]) Type() reflect.Type {
+	return s.typ
+}
+
+//Len : Number of entities having a T
+func (s *Storage[T]) Len() int {
+	return len(s.dense)
+}
+
+//Entities : Entities having a T, in the order of Values
+func (s *Storage[T]) Entities() []Entity {
+	return s.entities
+}
+
+//Values : Every T, in the order of Entities. Owned by the storage
+func (s *Storage[T]) Values() []T {
+	return s.dense
+}
+
+//position : Where the T of e is in dense, -1 if it has none
+func (s *Storage[T]) position(e Entity) int {
+	i := e.Index()
+	if int(i) >= len(s.sparse) {
+		return -1
+	}
+	p := s.sparse[i]
+	if p < 0 || s.entities[p] != e {
+		return -1
+	}
+	return int(p)
+}
+
+//Has : Checks whether e has a T
+func (s *Storage[T]) Has(e Entity) bool {
+	return s.position(e) >= 0
+}
+
+//Get : The T of e, false if it has none
+func (s *Storage[T]) Get(e Entity) (*T, bool) {
+	p := s.position(e)
+	if p < 0 {
+		return nil, false
+	}
+	return &s.dense[p], true
+}
+
+//Set : Gives e the component value, replacing the one it has. Adding a
+//component to an entity is a structural change, see World
+func (s *Storage[T]) Set(e Entity, value T) {
+	if p := s.position(e); p >= 0 {
+		s.dense[p] = value
+		return
+	}
+	if !s.world.Alive(e) {
+		panic(fmt.Sprintf("ecs: setting a %v on %v, which isn't alive", s.typ, e))
+	}
+	s.world.checkStructural()
+	s.insert(e, value)
+}
+
+//insert : Appends the T of e, which has none
+func (s *Storage[T]) insert(e Entity, value T) {
+	i := int(e.Index())
+	for len(s.sparse) <= i {
+		s.sparse = append(s.sparse, -1)
+	}
+	s.sparse[i] = int32(len(s.dense))
+	s.dense = append(s.dense, value)
+	s.entities = append(s.entities, e)
+}
+
+//Remove : Drops the T of e. False if it has none. Structural, see World
+func (s *Storage[T]) Remove(e Entity) bool {
+	if !s.Has(e) {
+		return false
+	}
+	s.world.checkStructural()
+	return s.remove(e)
+}
+
+//remove : Moves the last T into the place of the T of e
+func (s *Storage[T]) remove(e Entity) bool {
+	p := s.position(e)
+	if p < 0 {
+		return false
+	}
+	last := len(s.dense) - 1
+	moved := s.entities[last]
+	s.dense[p], s.entities[p] = s.dense[last], moved
+	s.sparse[moved.Index()] = int32(p)
+	s.sparse[e.Index()] = -1
+	var zero T
+	s.dense[last] = zero //Let go of what it points to
+	s.dense, s.entities = s.dense[:last], s.entities[:last]
+	return true
+}
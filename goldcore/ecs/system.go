@@ -0,0 +1,177 @@
+package ecs
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+//System : Logic run over the world every update
+type System struct {
+	//Name : Unique, other systems order themselves against it
+	Name string
+	//Run : Called once per update with the systems' context. Must only
+	//touch the components declared in Access, and make structural changes
+	//through ctx.Commands
+	Run func(ctx *Context)
+	//Access : Component types Run reads and writes. Systems sharing none,
+	//or only reading, may run at the same time, see SetWorkers. Without
+	//any, the system runs alone
+	Access []Access
+	//After : Systems this one runs after. Names of systems that aren't
+	//there are ignored
+	After []string
+	//Before : Systems this one runs before
+	Before []string
+}
+
+//Access : A component type a system reads or writes
+type Access struct {
+	Type  reflect.Type
+	Write bool
+}
+
+//Read : Access reading T
+func Read[T any]() Access {
+	return Access{Type: reflect.TypeOf((*T)(nil)).Elem()}
+}
+
+//Write : Access writing T, reading included
+func Write[T any]() Access {
+	return Access{Type: reflect.TypeOf((*T)(nil)).Elem(), Write: true}
+}
+
+//Context : What a system runs with
+type Context struct {
+	//World : The world being updated
+	World *World
+	//DT : Seconds since the last update
+	DT float32
+	//Commands : Structural changes to apply once every system ran
+	Commands *Commands
+	//System : Name of the system running
+	System string
+	system *system
+}
+
+//Use : Storage of T for the system running, which must declare T in its
+//Access. Catches systems touching what they didn't declare, which the
+//order of systems and the workers running them don't account for
+func Use[T any](ctx *Context) *Storage[T] {
+	s := Components[T](ctx.World)
+	for _, a := range ctx.system.Access {
+		if a.Type == s.typ {
+			return s
+		}
+	}
+	panic(fmt.Sprintf("ecs: system %s uses %v without declaring it", ctx.System, s.typ))
+}
+
+//system : A system added to a world
+type system struct {
+	System
+	commands *Commands
+}
+
+func (s *system) run(w *World, dt float32) {
+	s.Run(&Context{World: w, DT: dt, Commands: s.commands, System: s.Name, system: s})
+}
+
+//AddSystem : Adds s, run after the systems already there unless its After
+//and Before say otherwise. Fails if the name is taken or the order has a
+//cycle
+func (w *World) AddSystem(s System) error {
+	if s.Name == "" || s.Run == nil {
+		return fmt.Errorf("ecs: systems need a name and a Run")
+	}
+	for _, o := range w.systems {
+		if o.Name == s.Name {
+			return fmt.Errorf("ecs: there is already a system %s", s.Name)
+		}
+	}
+	systems := append(w.systems[:len(w.systems):len(w.systems)], &system{System: s, commands: newCommands(w)})
+	order, err := sortSystems(systems)
+	if err != nil {
+		return err
+	}
+	w.systems, w.schedule = systems, newSchedule(order)
+	return nil
+}
+
+//RemoveSystem : Removes the system name, after applying its commands.
+//False if there is none
+func (w *World) RemoveSystem(name string) bool {
+	for i, s := range w.systems {
+		if s.Name == name {
+			s.commands.apply()
+			w.systems = append(w.systems[:i:i], w.systems[i+1:]...)
+			order, _ := sortSystems(w.systems)
+			w.schedule = newSchedule(order)
+			return true
+		}
+	}
+	return false
+}
+
+//Systems : Names of the systems in run order
+func (w *World) Systems() []string {
+	names := make([]string, len(w.schedule.systems))
+	for i, s := range w.schedule.systems {
+		names[i] = s.Name
+	}
+	return names
+}
+
+//sortSystems : Orders systems by their After and Before, then by the order
+//they were added
+func sortSystems(systems []*system) ([]*system, error) {
+	byName := make(map[string]*system, len(systems))
+	for _, s := range systems {
+		byName[s.Name] = s
+	}
+	//after[s] : Systems s must wait for
+	after := make(map[*system][]*system, len(systems))
+	for _, s := range systems {
+		for _, name := range s.After {
+			if o, ok := byName[name]; ok {
+				after[s] = append(after[s], o)
+			}
+		}
+		for _, name := range s.Before {
+			if o, ok := byName[name]; ok {
+				after[o] = append(after[o], s)
+			}
+		}
+	}
+	order := make([]*system, 0, len(systems))
+	placed := make(map[*system]bool, len(systems))
+	for len(order) < len(systems) {
+		//Earliest added system whose dependencies are placed
+		var next *system
+		for _, s := range systems {
+			if placed[s] {
+				continue
+			}
+			ready := true
+			for _, o := range after[s] {
+				ready = ready && placed[o]
+			}
+			if ready {
+				next = s
+				break
+			}
+		}
+		if next == nil {
+			var stuck []string
+			for _, s := range systems {
+				if !placed[s] {
+					stuck = append(stuck, s.Name)
+				}
+			}
+			return nil, fmt.Errorf("ecs: systems %s order each other in a cycle", strings.Join(stuck, ", "))
+		}
+		placed[next] = true
+		order = append(order, next)
+	}
+	return order, nil
+}
@@ -0,0 +1,185 @@
+package ecs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Dacode45/OldGoldEngine/goldcore"
+)
+
+var _ goldcore.Updater = (*World)(nil)
+
+func TestSystemOrder(t *testing.T) {
+	w := NewWorld()
+	var ran []string
+	add := func(name string, after, before []string) error {
+		return w.AddSystem(System{Name: name, After: after, Before: before, Run: func(ctx *Context) {
+			ran = append(ran, ctx.System)
+		}})
+	}
+	if err := add("render", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := add("physics", nil, []string{"render"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := add("input", nil, []string{"physics", "missing"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := add("audio", []string{"render"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	want := "input physics render audio"
+	if got := strings.Join(w.Systems(), " "); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+	w.Update(0)
+	if got := strings.Join(ran, " "); got != want {
+		t.Errorf("expected systems to run as %s, got %s", want, got)
+	}
+
+	if err := add("render", nil, nil); err == nil {
+		t.Error("Names should be unique")
+	}
+	if err := add("loop", []string{"render"}, []string{"input"}); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("expected a cycle error, got %v", err)
+	}
+	if strings.Join(w.Systems(), " ") != want {
+		t.Error("A failed AddSystem shouldn't change the systems")
+	}
+	if !w.RemoveSystem("physics") || w.RemoveSystem("physics") {
+		t.Error("Removing once should work, twice not")
+	}
+	//Nothing puts input before render any more, they go by when they were added
+	if got := strings.Join(w.Systems(), " "); got != "render input audio" {
+		t.Errorf("unexpected systems after removal %s", got)
+	}
+}
+
+func TestSystemAccess(t *testing.T) {
+	w := NewWorld()
+	w.Create(With(position{}), With(velocity{1, 0}))
+	var undeclared bool
+	err := w.AddSystem(System{
+		Name:   "movement",
+		Access: []Access{Write[position](), Read[velocity]()},
+		Run: func(ctx *Context) {
+			positions, velocities := Use[position](ctx), Use[velocity](ctx)
+			for i, e := range positions.Entities() {
+				if v, ok := velocities.Get(e); ok {
+					positions.Values()[i].X += v.X * ctx.DT
+				}
+			}
+			undeclared = panics(func() { Use[health](ctx) })
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Update(2)
+	if p := Components[position](w).Values()[0]; p.X != 2 {
+		t.Errorf("expected the entity moved to 2, got %v", p)
+	}
+	if !undeclared {
+		t.Error("Using an undeclared component should panic")
+	}
+	if a := Write[position](); !a.Write || a.Type != Components[position](w).Type() {
+		t.Errorf("unexpected access %v", a)
+	}
+}
+
+func TestSystemCommands(t *testing.T) {
+	w := NewWorld()
+	w.AddSystem(System{
+		Name:   "spawner",
+		Access: []Access{Read[health]()},
+		Run: func(ctx *Context) {
+			ctx.Commands.Create(With(health(10)))
+		},
+	})
+	w.AddSystem(System{
+		Name:   "reaper",
+		Access: []Access{Write[health]()},
+		Run: func(ctx *Context) {
+			NewQuery1[health](ctx.World).Each(func(e Entity, h *health) {
+				*h -= 5
+				if *h <= 0 {
+					ctx.Commands.Destroy(e)
+				}
+			})
+		},
+	})
+	counts := []int{}
+	for i := 0; i < 4; i++ {
+		w.Update(1)
+		counts = append(counts, w.Len())
+	}
+	//Each entity lives through two updates after the one creating it
+	if counts[0] != 1 || counts[1] != 2 || counts[2] != 2 || counts[3] != 2 {
+		t.Errorf("unexpected entity counts %v", counts)
+	}
+}
+
+func TestGameIntegration(t *testing.T) {
+	game := goldcore.NewGame()
+	w := NewWorld()
+	e := w.Create(With(position{}), With(velocity{3, 0}))
+	w.AddSystem(System{
+		Name:   "movement",
+		Access: []Access{Write[position](), Read[velocity]()},
+		Run: func(ctx *Context) {
+			NewQuery2[position, velocity](ctx.World).Each(func(_ Entity, p *position, v *velocity) {
+				p.X += v.X * ctx.DT
+			})
+		},
+	})
+	game.AddUpdater(w)
+	game.Update(1)
+	game.Play()
+	game.Update(0.5)
+	game.Update(0.5)
+	if p, _ := Get[position](w, e); p.X != 3 {
+		t.Errorf("expected the entity moved only while playing, at 3, got %v", p)
+	}
+}
+
+func BenchmarkWorldUpdate(b *testing.B) {
+	w := benchmarkWorld(50000)
+	w.AddSystem(System{
+		Name:   "movement",
+		Access: []Access{Write[position](), Read[velocity]()},
+		Run: func(ctx *Context) {
+			NewQuery2[position, velocity](ctx.World).Each(func(_ Entity, p *position, v *velocity) {
+				p.X += v.X * ctx.DT
+				p.Y += v.Y * ctx.DT
+			})
+		},
+	})
+	w.AddSystem(System{
+		Name:   "regen",
+		Access: []Access{Write[health]()},
+		Run: func(ctx *Context) {
+			NewQuery1[health](ctx.World).Each(func(_ Entity, h *health) {
+				if *h < 100 {
+					*h++
+				}
+			})
+		},
+	})
+	w.AddSystem(System{
+		Name:   "bounds",
+		After:  []string{"movement"},
+		Access: []Access{Write[position]()},
+		Run: func(ctx *Context) {
+			NewQuery1[position](ctx.World).Each(func(_ Entity, p *position) {
+				if p.X > 1000 {
+					p.X = 0
+				}
+			})
+		},
+	})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w.Update(1.0 / 60)
+	}
+}
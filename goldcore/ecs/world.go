@@ -0,0 +1,139 @@
+package ecs
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+//World : Entities, their components and the systems updating them.
+//Creating or destroying entities and adding or removing components are
+//structural changes: they move components around, so they panic while
+//systems run or a query iterates. Systems make them through
+//Context.Commands, applied once every system ran. Changing the value of a
+//component is always fine
+type World struct {
+	mutex        sync.Mutex //Guards storages and commands
+	entities     entities
+	storages     map[reflect.Type]ComponentStorage
+	storageOrder []ComponentStorage //Creation order, to destroy the same way every run
+	locks        int32              //Running systems and iterating queries, atomic
+	commands     *Commands          //Made outside systems, applied after theirs
+
+	systems  []*system
+	schedule *schedule //systems in run order
+	workers  int
+}
+
+//NewWorld : World without entities or systems
+func NewWorld() *World {
+	w := &World{storages: make(map[reflect.Type]ComponentStorage), schedule: newSchedule(nil), workers: 1}
+	w.commands = newCommands(w)
+	return w
+}
+
+//lock : Forbids structural changes until unlock
+func (w *World) lock() {
+	atomic.AddInt32(&w.locks, 1)
+}
+
+func (w *World) unlock() {
+	atomic.AddInt32(&w.locks, -1)
+}
+
+//checkStructural : Panics if structural changes are forbidden
+func (w *World) checkStructural() {
+	if atomic.LoadInt32(&w.locks) > 0 {
+		panic("ecs: structural change while systems run or a query iterates, use Commands")
+	}
+}
+
+//Create : New entity with components
+func (w *World) Create(components ...Component) Entity {
+	w.checkStructural()
+	e := w.entities.create()
+	for _, c := range components {
+		c.set(w, e)
+	}
+	return e
+}
+
+//Destroy : Destroys e and its components. False if it wasn't alive
+func (w *World) Destroy(e Entity) bool {
+	if !w.Alive(e) {
+		return false
+	}
+	w.checkStructural()
+	w.mutex.Lock()
+	storages := w.storageOrder
+	w.mutex.Unlock()
+	for _, s := range storages {
+		s.remove(e)
+	}
+	return w.entities.release(e)
+}
+
+//Alive : Checks whether e was created and not destroyed since
+func (w *World) Alive(e Entity) bool {
+	return w.entities.isAlive(e)
+}
+
+//Len : Number of alive entities
+func (w *World) Len() int {
+	return w.entities.alive
+}
+
+//Commands : Changes to apply after the systems of the next Update, or on
+//Flush. For code running beside systems, like input handlers
+func (w *World) Commands() *Commands {
+	return w.commands
+}
+
+//Flush : Applies the commands of every system, in run order, then those
+//of Commands. Update calls it after running the systems
+func (w *World) Flush() {
+	for _, s := range w.schedule.systems {
+		s.commands.apply()
+	}
+	w.commands.apply()
+}
+
+//Update : Runs every system, on the workers set by SetWorkers, then
+//applies the commands they made. Makes the World a goldcore.Updater
+func (w *World) Update(dt float32) {
+	w.runSystems(dt)
+	w.Flush()
+}
+
+//runSystems : Runs every system with structural changes forbidden
+func (w *World) runSystems(dt float32) {
+	w.lock()
+	defer w.unlock()
+	if w.workers > 1 && len(w.schedule.systems) > 1 {
+		w.schedule.run(w, dt, w.workers)
+		return
+	}
+	for _, s := range w.schedule.systems {
+		s.run(w, dt)
+	}
+}
+
+//Set : Gives e the component value, see Storage.Set
+func Set[T any](w *World, e Entity, value T) {
+	Components[T](w).Set(e, value)
+}
+
+//Get : The T of e, false if it has none
+func Get[T any](w *World, e Entity) (*T, bool) {
+	return Components[T](w).Get(e)
+}
+
+//Has : Checks whether e has a T
+func Has[T any](w *World, e Entity) bool {
+	return Components[T](w).Has(e)
+}
+
+//Remove : Drops the T of e, see Storage.Remove
+func Remove[T any](w *World, e Entity) bool {
+	return Components[T](w).Remove(e)
+}
@@ -0,0 +1,161 @@
+package ecs
+
+import "testing"
+
+type position struct{ X, Y float32 }
+type velocity struct{ X, Y float32 }
+type health int
+type frozen struct{}
+
+//panics : Checks f panics
+func panics(f func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	f()
+	return false
+}
+
+func TestEntities(t *testing.T) {
+	w := NewWorld()
+	a := w.Create()
+	b := w.Create()
+	if a == 0 || a == b || !w.Alive(a) || !w.Alive(b) || w.Len() != 2 {
+		t.Fatalf("expected two distinct alive entities, got %v %v", a, b)
+	}
+	if w.Alive(0) {
+		t.Error("The zero Entity is never alive")
+	}
+	if !w.Destroy(a) || w.Destroy(a) || w.Alive(a) || w.Len() != 1 {
+		t.Error("Destroying once should work, twice not")
+	}
+	//The slot is reused with a new generation
+	c := w.Create()
+	if c.Index() != a.Index() || c.Generation() != a.Generation()+1 {
+		t.Errorf("expected slot %d reused, got %v after %v", a.Index(), c, a)
+	}
+	if w.Alive(a) || !w.Alive(c) {
+		t.Error("Stale handles shouldn't match the entity reusing their slot")
+	}
+	if c.String() != "Entity(0v2)" {
+		t.Errorf("unexpected string %s", c)
+	}
+}
+
+func TestStorage(t *testing.T) {
+	w := NewWorld()
+	a := w.Create(With(position{1, 2}), With(health(3)))
+	b := w.Create(With(position{3, 4}))
+	c := w.Create(With(position{5, 6}))
+	positions := Components[position](w)
+	if positions != Components[position](w) {
+		t.Fatal("A type should have one storage")
+	}
+	if positions.Len() != 3 || !Has[health](w, a) || Has[health](w, b) {
+		t.Fatal("Components given on creation are missing")
+	}
+	p, ok := Get[position](w, b)
+	if !ok || *p != (position{3, 4}) {
+		t.Fatalf("expected b at 3,4, got %v", p)
+	}
+	p.X = 10
+	Set(w, c, position{7, 8})
+	//Removing moves the last component in place of the removed one
+	if !Remove[position](w, a) || Remove[position](w, a) {
+		t.Fatal("Removing once should work, twice not")
+	}
+	if positions.Len() != 2 || positions.Entities()[0] != c || positions.Values()[0] != (position{7, 8}) {
+		t.Errorf("unexpected storage %v %v", positions.Entities(), positions.Values())
+	}
+	if p, _ := Get[position](w, b); p.X != 10 {
+		t.Error("Changing a component through Get should stick")
+	}
+	w.Destroy(b)
+	if positions.Len() != 1 || Has[position](w, b) {
+		t.Error("Destroying an entity should drop its components")
+	}
+	//A stale handle doesn't see the components of the entity reusing its slot
+	d := w.Create(With(position{}))
+	if d.Index() != b.Index() || Has[position](w, b) {
+		t.Error("Stale handles shouldn't have components")
+	}
+	if !panics(func() { Set(w, b, position{}) }) {
+		t.Error("Setting a component on a dead entity should panic")
+	}
+}
+
+func TestStructuralChanges(t *testing.T) {
+	w := NewWorld()
+	e := w.Create(With(position{}))
+	var inside []bool
+	NewQuery1[position](w).Each(func(Entity, *position) {
+		inside = append(inside,
+			panics(func() { w.Create() }),
+			panics(func() { w.Destroy(e) }),
+			panics(func() { Set(w, e, health(1)) }),
+			panics(func() { Remove[position](w, e) }),
+			panics(func() { Set(w, e, position{1, 1}) }))
+	})
+	if len(inside) != 5 || !inside[0] || !inside[1] || !inside[2] || !inside[3] || inside[4] {
+		t.Errorf("only changing a value should be allowed while iterating, panics %v", inside)
+	}
+	//The lock is released once the query is done, even after panics
+	w.Create()
+	if w.Len() != 2 {
+		t.Error("Structural changes should work after iterating")
+	}
+}
+
+func TestCommands(t *testing.T) {
+	w := NewWorld()
+	a := w.Create(With(position{}))
+	b := w.Create()
+	c := w.Commands()
+	c.Create(With(position{1, 1}), With(velocity{1, 0}))
+	c.Destroy(a)
+	c.Set(b, With(health(5)))
+	RemoveLater[health](c, b)
+	c.Set(a, With(health(1))) //Dropped, a is destroyed first
+	c.Do(func(w *World) {
+		//Commands made while applying run in the same flush
+		w.Commands().Create(With(frozen{}))
+	})
+	if c.Len() != 6 || w.Len() != 2 {
+		t.Fatal("Commands should wait for Flush")
+	}
+	w.Flush()
+	if c.Len() != 0 || w.Len() != 3 || w.Alive(a) || Has[health](w, b) || Has[health](w, a) {
+		t.Fatalf("unexpected world after flush, %d entities", w.Len())
+	}
+	if NewQuery2[position, velocity](w).Count() != 1 || Components[frozen](w).Len() != 1 {
+		t.Error("Entities created by commands are missing")
+	}
+}
+
+func benchmarkWorld(n int) *World {
+	w := NewWorld()
+	for i := 0; i < n; i++ {
+		e := w.Create(With(position{float32(i), 0}))
+		if i%2 == 0 {
+			Set(w, e, velocity{1, 1})
+		}
+		if i%3 == 0 {
+			Set(w, e, health(100))
+		}
+	}
+	return w
+}
+
+func BenchmarkCreateDestroy(b *testing.B) {
+	w := NewWorld()
+	entities := make([]Entity, 50000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for j := range entities {
+			entities[j] = w.Create(With(position{}), With(velocity{}))
+		}
+		for _, e := range entities {
+			w.Destroy(e)
+		}
+	}
+}
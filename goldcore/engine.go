@@ -1,21 +1,315 @@
 package goldcore
 
+import (
+	"sync"
+	"time"
+)
+
+const (
+	//GameStopped : Game hasn't been played yet or was stopped
+	GameStopped = 0
+	//GameSuspended : Game is paused and can be resumed with Play
+	GameSuspended = 1
+	//GameRunning : Game is being played
+	GameRunning = 2
+)
+
+//Updater : Something advanced by the game tick. dt is in seconds
+type Updater interface {
+	Update(dt float32)
+}
+
+//updateFunc : Updater calling a plain function. A pointer, so it can be
+//compared by RemoveUpdater
+type updateFunc struct {
+	f func(dt float32)
+}
+
+func (u *updateFunc) Update(dt float32) {
+	u.f(dt)
+}
+
+//GameObserver : Implementations of this interface get the messages sent by
+//the game and its systems (tweens...). Called on the update thread
+type GameObserver interface {
+	OnGameNotify(*GameMessage)
+}
+
 type Game struct {
-	window GameWindow
+	window    *GameWindow
+	state     int
+	changes   uint64 //Number of state changes, tells who changed it last
+	mutex     sync.Mutex
+	seed      uint64
+	random    *RandomStreams
+	time      float64
+	frame     uint64
+	updaters  []Updater
+	observers []GameObserver
+	tweens    *TweenManager
+	scheduler *Scheduler
+	resources *ResourceManager
+	timeScale float32
 }
 
+//NewGame : Creates a stopped game seeded from the clock. Call SetSeed before
+//Play to replay a run
 func NewGame() *Game {
-	return &Game{}
+	seed := uint64(time.Now().UnixNano())
+	game := &Game{seed: seed, random: NewRandomStreams(seed), timeScale: 1}
+	game.tweens = NewTweenManager(game.notify)
+	game.scheduler = NewScheduler()
+	game.resources = NewResourceManager(game.notify, 0)
+	game.AddUpdater(game.tweens)
+	game.AddUpdater(game.scheduler)
+	game.AddUpdater(game.resources)
+	return game
 }
 
-func (game *Game) Play() {
+//SetWindow : Makes gW the window of this game
+func (game *Game) SetWindow(gW *GameWindow) {
+	game.window = gW
+	gW.game = game
+}
+
+//Window : Returns the window of this game, nil if none was set
+func (game *Game) Window() *GameWindow {
+	return game.window
+}
+
+//State : Returns GameStopped, GameSuspended or GameRunning
+func (game *Game) State() int {
+	game.mutex.Lock()
+	defer game.mutex.Unlock()
+	return game.state
+}
 
+//IsRunning : Checks whether the game is being played
+func (game *Game) IsRunning() bool {
+	return game.State() == GameRunning
 }
 
+//IsSuspended : Checks whether the game is paused
+func (game *Game) IsSuspended() bool {
+	return game.State() == GameSuspended
+}
+
+//Play : Starts the game, or resumes it after Suspend. Starting a stopped game
+//resets Time and reseeds every random stream with Seed, so a run played again
+//with the same seed and the same inputs draws the same numbers
+func (game *Game) Play() {
+	game.mutex.Lock()
+	if game.state == GameStopped {
+		game.random.Reseed(game.seed)
+		game.time, game.frame = 0, 0
+	}
+	game.setState(GameRunning)
+	game.mutex.Unlock()
+}
+
+//Suspend : Pauses a running game
 func (game *Game) Suspend() {
+	game.mutex.Lock()
+	if game.state == GameRunning {
+		game.setState(GameSuspended)
+	}
+	game.mutex.Unlock()
+}
+
+//suspendFor : Suspends a running game on behalf of the window. Returns the
+//state change it made, 0 if the game wasn't running
+func (game *Game) suspendFor() uint64 {
+	game.mutex.Lock()
+	defer game.mutex.Unlock()
+	if game.state != GameRunning {
+		return 0
+	}
+	game.setState(GameSuspended)
+	return game.changes
+}
 
+//resumeAfter : Resumes the game suspended by change, unless something else
+//changed its state since (stopped, played or suspended it again)
+func (game *Game) resumeAfter(change uint64) bool {
+	game.mutex.Lock()
+	defer game.mutex.Unlock()
+	if game.changes != change || game.state != GameSuspended {
+		return false
+	}
+	game.setState(GameRunning)
+	return true
 }
 
+//setState : Changes the state, called with the mutex held
+func (game *Game) setState(state int) {
+	if game.state != state {
+		game.state = state
+		game.changes++
+	}
+}
+
+//Stop : Stops the game and cancels everything scheduled and every tween, so
+//playing again starts from a clean slate
 func (game *Game) Stop() {
+	game.mutex.Lock()
+	game.setState(GameStopped)
+	game.mutex.Unlock()
+	game.scheduler.Clear()
+	game.tweens.StopAll()
+}
+
+//Seed : Seed the random streams restart from when the game starts
+func (game *Game) Seed() uint64 {
+	game.mutex.Lock()
+	defer game.mutex.Unlock()
+	return game.seed
+}
+
+//SetSeed : Changes the seed and restarts every random stream from it
+func (game *Game) SetSeed(seed uint64) {
+	game.mutex.Lock()
+	game.seed = seed
+	game.random.Reseed(seed)
+	game.mutex.Unlock()
+}
+
+//Random : The game's random stream named stream. Use one stream per system
+//(loot, AI, particles...) so they don't disturb each other
+func (game *Game) Random(stream string) *Random {
+	return game.random.Stream(stream)
+}
+
+//RandomStreams : Every random stream of the game, to save and restore them
+//along with the rest of the game state
+func (game *Game) RandomStreams() *RandomStreams {
+	return game.random
+}
+
+//AddUpdater : Adds u to the things advanced by Update, after the ones
+//already there
+func (game *Game) AddUpdater(u Updater) {
+	game.mutex.Lock()
+	game.updaters = append(game.updaters, u)
+	game.mutex.Unlock()
+}
+
+//AddUpdateFunc : Adds f to the things advanced by Update. Keep the returned
+//Updater to remove it later
+func (game *Game) AddUpdateFunc(f func(dt float32)) Updater {
+	u := &updateFunc{f: f}
+	game.AddUpdater(u)
+	return u
+}
+
+//RemoveUpdater : Stops advancing u
+func (game *Game) RemoveUpdater(u Updater) {
+	game.mutex.Lock()
+	defer game.mutex.Unlock()
+	for i, o := range game.updaters {
+		if o == u {
+			game.updaters = append(game.updaters[:i:i], game.updaters[i+1:]...)
+			return
+		}
+	}
+}
+
+//Update : The game tick. Advances game time and every updater, in the order
+//they were added, by dt seconds times the time scale. Does nothing unless
+//the game is running, so a suspended game is frozen, except for hot reloading
+//which keeps checking files. Call it once per frame from the update loop
+//with the real time elapsed
+func (game *Game) Update(dt float32) {
+	game.mutex.Lock()
+	if game.state != GameRunning {
+		suspended := game.state == GameSuspended
+		game.mutex.Unlock()
+		if suspended {
+			game.resources.pollChanged()
+		}
+		return
+	}
+	dt *= game.timeScale
+	game.time += float64(dt)
+	game.frame++
+	//Updaters may add or remove updaters
+	updaters := game.updaters
+	game.mutex.Unlock()
+	for _, u := range updaters {
+		u.Update(dt)
+	}
+}
+
+//Time : Seconds of running time the game was advanced by since it started
+func (game *Game) Time() float64 {
+	game.mutex.Lock()
+	defer game.mutex.Unlock()
+	return game.time
+}
+
+//Frame : Number of ticks since the game started
+func (game *Game) Frame() uint64 {
+	game.mutex.Lock()
+	defer game.mutex.Unlock()
+	return game.frame
+}
+
+//TimeScale : How fast game time goes compared to real time, 1 by default
+func (game *Game) TimeScale() float32 {
+	game.mutex.Lock()
+	defer game.mutex.Unlock()
+	return game.timeScale
+}
+
+//SetTimeScale : Slows down (below 1) or speeds up (above 1) game time, and
+//everything driven by Update with it. 0 freezes time but keeps ticking
+//frames. Negative scales count as 0
+func (game *Game) SetTimeScale(scale float32) {
+	game.mutex.Lock()
+	game.timeScale = maxFloat(scale, 0)
+	game.mutex.Unlock()
+}
+
+//Tweens : The game's tweens, advanced by Update
+func (game *Game) Tweens() *TweenManager {
+	return game.tweens
+}
+
+//Scheduler : The game's timers and coroutines, advanced by Update after
+//the tweens
+func (game *Game) Scheduler() *Scheduler {
+	return game.scheduler
+}
+
+//Resources : The game's assets. Finished loads are announced by Update, after
+//the scheduler
+func (game *Game) Resources() *ResourceManager {
+	return game.resources
+}
+
+//AddObserver : Adds Game Observer to Observer list
+func (game *Game) AddObserver(gO GameObserver) {
+	game.mutex.Lock()
+	game.observers = append(game.observers, gO)
+	game.mutex.Unlock()
+}
+
+//RemoveObserver : Removes GameObserver from observer list
+func (game *Game) RemoveObserver(gO GameObserver) {
+	game.mutex.Lock()
+	defer game.mutex.Unlock()
+	for i, o := range game.observers {
+		if o == gO {
+			game.observers = append(game.observers[:i:i], game.observers[i+1:]...)
+			return
+		}
+	}
+}
 
+func (game *Game) notify(gM *GameMessage) {
+	game.mutex.Lock()
+	observers := game.observers
+	game.mutex.Unlock()
+	for _, o := range observers {
+		o.OnGameNotify(gM)
+	}
 }
@@ -0,0 +1,44 @@
+package goldcore
+
+import "testing"
+
+func TestGameUpdate(t *testing.T) {
+	game := NewGame()
+	var order []string
+	var total float32
+	game.AddUpdateFunc(func(dt float32) {
+		order = append(order, "first")
+		total += dt
+	})
+	var second Updater
+	second = game.AddUpdateFunc(func(dt float32) {
+		order = append(order, "second")
+		//Removing itself while being updated is fine
+		game.RemoveUpdater(second)
+	})
+
+	game.Update(1)
+	if len(order) != 0 || game.Time() != 0 {
+		t.Errorf("stopped game shouldn't tick: %v", order)
+	}
+	game.Play()
+	game.Update(0.25)
+	game.Update(0.25)
+	if len(order) != 3 || order[0] != "first" || order[1] != "second" || order[2] != "first" {
+		t.Errorf("updaters ran as %v", order)
+	}
+	if total != 0.5 || game.Time() != 0.5 || game.Frame() != 2 {
+		t.Errorf("expected 0.5s over 2 frames, got %v %v %v", total, game.Time(), game.Frame())
+	}
+
+	game.Suspend()
+	game.Update(1)
+	if game.Time() != 0.5 {
+		t.Errorf("suspended game shouldn't tick, time %v", game.Time())
+	}
+	game.Stop()
+	game.Play()
+	if game.Time() != 0 || game.Frame() != 0 {
+		t.Errorf("starting again should reset the clock, got %v %v", game.Time(), game.Frame())
+	}
+}
@@ -0,0 +1,151 @@
+package goldcore
+
+import (
+	"image"
+	"image/draw"
+	"sync"
+)
+
+//Font : Where Text gets its glyphs. TrueTypeFont draws any size from
+//outlines, BMFont scales pre-drawn bitmaps, FontStack falls back through
+//several fonts. Implementations are safe for concurrent use
+type Font interface {
+	//Glyph : Glyph of r at size pixels. False if the font doesn't have it
+	Glyph(r rune, size float32) (Glyph, bool)
+	//HasGlyph : Checks whether the font has a glyph for r
+	HasGlyph(r rune) bool
+	//Kerning : Extra advance between a and b, usually negative
+	Kerning(a, b rune, size float32) float32
+	//Metrics : Vertical measurements at size pixels
+	Metrics(size float32) FontMetrics
+}
+
+//Glyph : Image of a character and how it sits on the baseline
+type Glyph struct {
+	//Advance : How far the pen moves to the next character
+	Advance float32
+	//Bounds : Where the image goes, from the pen position on the baseline.
+	//Top is negative for parts above the baseline
+	Bounds RectF
+	//Texture : Texture holding the image, nil for blank glyphs like spaces
+	Texture *Texture
+	//TextureRect : Part of Texture holding the image
+	TextureRect RectI
+}
+
+//FontMetrics : Vertical measurements of a font at some size, in pixels
+type FontMetrics struct {
+	Ascent     float32 //Baseline to the top of the tallest glyphs
+	Descent    float32 //Baseline to the bottom of the lowest glyphs, positive
+	LineHeight float32 //Baseline to baseline
+}
+
+/////////////////////////////////////
+///		FONT STACK
+/////////////////////////////////////
+
+//FontStack : Font using the first of its fonts that has a glyph, so a
+//Latin font can be completed by CJK or emoji fonts. Metrics come from the
+//first font
+type FontStack []Font
+
+//Glyph : Glyph from the first font having it, or the first font's
+//placeholder if none does
+func (fs FontStack) Glyph(r rune, size float32) (Glyph, bool) {
+	if len(fs) == 0 {
+		return Glyph{}, false
+	}
+	return fs.pick(r).Glyph(r, size)
+}
+
+//HasGlyph : Checks whether any font has a glyph for r
+func (fs FontStack) HasGlyph(r rune) bool {
+	for _, f := range fs {
+		if f.HasGlyph(r) {
+			return true
+		}
+	}
+	return false
+}
+
+//Kerning : Kerning of the font drawing both runes, 0 if they come from
+//different fonts
+func (fs FontStack) Kerning(a, b rune, size float32) float32 {
+	if len(fs) == 0 {
+		return 0
+	}
+	f := fs.pick(a)
+	if f != fs.pick(b) {
+		return 0
+	}
+	return f.Kerning(a, b, size)
+}
+
+//Metrics : Metrics of the first font
+func (fs FontStack) Metrics(size float32) FontMetrics {
+	if len(fs) == 0 {
+		return FontMetrics{}
+	}
+	return fs[0].Metrics(size)
+}
+
+//pick : First font having r, the first font if none does
+func (fs FontStack) pick(r rune) Font {
+	for _, f := range fs {
+		if f.HasGlyph(r) {
+			return f
+		}
+	}
+	return fs[0]
+}
+
+/////////////////////////////////////
+///		GLYPH ATLAS
+/////////////////////////////////////
+
+//glyphAtlasPageSize : Size of the textures glyphs are cached in
+const glyphAtlasPageSize = 512
+
+//glyphAtlas : Textures glyphs are drawn into as they are first needed, so
+//a whole string is usually a single draw call
+type glyphAtlas struct {
+	mutex sync.Mutex
+	pages []glyphPage
+}
+
+type glyphPage struct {
+	texture *Texture
+	packer  *RectPacker
+}
+
+//add : Copies size pixels of the coverage mask, from maskp, into the atlas
+//as white with alpha coverage, with a pixel of padding. Returns where they
+//went
+func (atlas *glyphAtlas) add(mask image.Image, maskp, size image.Point) (*Texture, RectI) {
+	w, h := size.X, size.Y
+	pixels := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.DrawMask(pixels, pixels.Rect, image.White, image.Point{}, mask, maskp, draw.Src)
+
+	atlas.mutex.Lock()
+	defer atlas.mutex.Unlock()
+	var page *glyphPage
+	var cell RectI
+	for i := range atlas.pages {
+		if r, ok := atlas.pages[i].packer.Pack(w+2, h+2); ok {
+			page, cell = &atlas.pages[i], r
+			break
+		}
+	}
+	if page == nil {
+		side := maxInt(glyphAtlasPageSize, maxInt(w, h)+2)
+		atlas.pages = append(atlas.pages, glyphPage{
+			texture: NewEmptyTexture(uint(side), uint(side)),
+			packer:  NewRectPacker(side, side),
+		})
+		page = &atlas.pages[len(atlas.pages)-1]
+		cell, _ = page.packer.Pack(w+2, h+2)
+	}
+	rect := RectI{Left: cell.Left + 1, Top: cell.Top + 1, Width: w, Height: h}
+	page.texture.Update(pixels, rect.Position())
+	return page.texture, rect
+}
@@ -0,0 +1,288 @@
+package goldcore
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+var (
+	_ Font = (*TrueTypeFont)(nil)
+	_ Font = (*BMFont)(nil)
+	_ Font = FontStack(nil)
+)
+
+//testFNT : Font drawn at 10 pixels, 'A' is a white 4x8 box, '?' a red one
+const testFNT = `info face="Test Font" size=10 bold=0 italic=0
+common lineHeight=12 base=10 scaleW=8 scaleH=8 pages=1
+page id=0 file="test.png"
+chars count=3
+char id=65   x=0 y=0 width=4 height=8 xoffset=1 yoffset=2 xadvance=6 page=0 chnl=15
+char id=63   x=4 y=0 width=4 height=8 xoffset=0 yoffset=2 xadvance=5 page=0 chnl=15
+char id=32   x=0 y=0 width=0 height=0 xoffset=0 yoffset=0 xadvance=3 page=0 chnl=15
+kernings count=1
+kerning first=65 second=65 amount=-1
+`
+
+//testBMFont : testFNT with its page
+func testBMFont(t *testing.T) *BMFont {
+	t.Helper()
+	page := solid(8, 8, white)
+	for y := 0; y < 8; y++ {
+		for x := 4; x < 8; x++ {
+			page.SetRGBA(x, y, red)
+		}
+	}
+	bm, err := ParseBMFont(strings.NewReader(testFNT), func(name string) (*Texture, error) {
+		if name != "test.png" {
+			t.Errorf("Expected page test.png got %s", name)
+		}
+		return NewTexture(page), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bm
+}
+
+func testTrueTypeFont(t *testing.T) *TrueTypeFont {
+	t.Helper()
+	ttf, err := ParseTrueTypeFont(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ttf
+}
+
+func TestBMFont(t *testing.T) {
+	bm := testBMFont(t)
+	if bm.Size() != 10 {
+		t.Errorf("Expected size 10 got %v", bm.Size())
+	}
+	g, ok := bm.Glyph('A', 20)
+	expected := RectF{Left: 2, Top: -16, Width: 8, Height: 16}
+	if !ok || g.Advance != 12 || g.Bounds != expected || g.Texture == nil {
+		t.Errorf("Glyph A at twice the size: got %+v", g)
+	}
+	if g.TextureRect != (RectI{Width: 4, Height: 8}) {
+		t.Errorf("Expected texture rect of A got %v", g.TextureRect)
+	}
+	if g, ok := bm.Glyph(' ', 10); !ok || g.Texture != nil || g.Advance != 3 {
+		t.Errorf("Space should be blank with an advance of 3: %+v", g)
+	}
+	if g, ok := bm.Glyph('é', 10); ok || g.TextureRect.Left != 4 || g.Advance != 5 {
+		t.Errorf("Missing runes should get '?' and false: %+v %v", g, ok)
+	}
+	if bm.HasGlyph('é') || !bm.HasGlyph('A') {
+		t.Error("HasGlyph should check the chars")
+	}
+	if k := bm.Kerning('A', 'A', 20); k != -2 {
+		t.Errorf("Expected kerning -2 got %v", k)
+	}
+	if k := bm.Kerning('A', '?', 20); k != 0 {
+		t.Errorf("Expected no kerning got %v", k)
+	}
+	if m := bm.Metrics(20); m != (FontMetrics{Ascent: 20, Descent: 4, LineHeight: 24}) {
+		t.Errorf("Unexpected metrics %+v", m)
+	}
+
+	for _, fnt := range []string{
+		"info size=10\nchar id=65 x=0",
+		"common lineHeight=12 base=x",
+		"page id=300 file=\"a.png\"",
+		"chars count=0",
+	} {
+		if _, err := ParseBMFont(strings.NewReader(fnt), func(string) (*Texture, error) { return nil, nil }); err == nil {
+			t.Errorf("Expected an error parsing %q", fnt)
+		}
+	}
+}
+
+func TestParseBMFontLine(t *testing.T) {
+	tag, attrs := parseBMFontLine(`info face="Some Font" size=32  bold=0 charset=""`)
+	if tag != "info" || attrs["face"] != "Some Font" || attrs["size"] != "32" || attrs["bold"] != "0" {
+		t.Errorf("Unexpected %s %v", tag, attrs)
+	}
+	if v, ok := attrs["charset"]; !ok || v != "" {
+		t.Errorf("Empty quoted values should be kept: %v", attrs)
+	}
+	if tag, attrs := parseBMFontLine("chars"); tag != "chars" || len(attrs) != 0 {
+		t.Errorf("Unexpected %s %v", tag, attrs)
+	}
+}
+
+func TestTrueTypeFont(t *testing.T) {
+	ttf := testTrueTypeFont(t)
+	if name := ttf.Name(); name != "Go Regular" {
+		t.Errorf("Expected Go Regular got %q", name)
+	}
+	g, ok := ttf.Glyph('A', 16)
+	if !ok || g.Texture == nil || g.Advance <= 0 || g.Bounds.Top >= 0 || g.Bounds.Bottom() != 0 {
+		t.Fatalf("A should sit on the baseline: %+v", g)
+	}
+	if again, _ := ttf.Glyph('A', 16); again != g {
+		t.Error("Glyphs should be cached")
+	}
+	if g, ok := ttf.Glyph(' ', 16); !ok || g.Texture != nil || g.Advance <= 0 {
+		t.Errorf("Space should be blank: %+v", g)
+	}
+	if _, ok := ttf.Glyph('\U0001F600', 16); ok || ttf.HasGlyph('\U0001F600') {
+		t.Error("Go Regular has no emoji")
+	}
+	if !ttf.HasGlyph('é') || !ttf.HasGlyph('Ж') {
+		t.Error("Go Regular has Latin and Cyrillic")
+	}
+	big, _ := ttf.Glyph('A', 32)
+	if big.Bounds.Height <= g.Bounds.Height || big.Texture != g.Texture {
+		t.Errorf("Bigger glyphs should share the atlas: %+v", big)
+	}
+	if textures := ttf.Textures(); len(textures) != 1 {
+		t.Fatalf("Expected 1 atlas page got %d", len(textures))
+	}
+
+	//The atlas holds white coverage inside the glyph and nothing around it
+	page := g.Texture.Image()
+	r := g.TextureRect
+	covered := false
+	for y := r.Top; y < r.Bottom(); y++ {
+		for x := r.Left; x < r.Right(); x++ {
+			c := page.RGBAAt(x, y)
+			if c.R != c.A || c.G != c.A || c.B != c.A {
+				t.Fatalf("Glyph pixels should be white: %v", c)
+			}
+			covered = covered || c.A == 0xff
+		}
+	}
+	if !covered {
+		t.Error("A should cover some pixels")
+	}
+	if page.RGBAAt(r.Left-1, r.Top-1) != (color.RGBA{}) {
+		t.Error("Padding should be transparent")
+	}
+
+	m := ttf.Metrics(16)
+	if m.Ascent <= 0 || m.Descent <= 0 || m.LineHeight < m.Ascent+m.Descent-1 {
+		t.Errorf("Unexpected metrics %+v", m)
+	}
+	if _, err := ParseTrueTypeFont([]byte("not a font")); err == nil {
+		t.Error("Parsing garbage should fail")
+	}
+}
+
+//glyphPixels : Copy of the part of its texture g shows, at the origin
+func glyphPixels(g Glyph) *image.RGBA {
+	rect := g.TextureRect
+	pixels := image.NewRGBA(image.Rect(0, 0, rect.Width, rect.Height))
+	if g.Texture != nil {
+		draw.Draw(pixels, pixels.Rect, g.Texture.Image(), image.Pt(rect.Left, rect.Top), draw.Src)
+	}
+	return pixels
+}
+
+func TestBakeBMFont(t *testing.T) {
+	ttf := testTrueTypeFont(t)
+	bm, err := BakeBMFont(ttf, 16, []rune("AVa ?A\U0001F600"), 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bm.Pages()) != 1 || bm.Size() != 16 {
+		t.Fatalf("Expected one page at 16 pixels got %d at %v", len(bm.Pages()), bm.Size())
+	}
+	if bm.HasGlyph('\U0001F600') || bm.HasGlyph('b') || !bm.HasGlyph(' ') {
+		t.Error("Only the runes asked that the font has are baked")
+	}
+	//Glyphs land where the TrueType ones do
+	for _, r := range "AVa" {
+		expected, _ := ttf.Glyph(r, 16)
+		g, ok := bm.Glyph(r, 16)
+		if !ok || g.Bounds != expected.Bounds || g.Advance != roundFloat(expected.Advance) || g.Texture != bm.Pages()[0] {
+			t.Errorf("%c: expected %+v got %+v", r, expected, g)
+		}
+		//Every glyph is baked, not only those on the page when it was read
+		want := glyphPixels(expected)
+		if diff := compareImages(glyphPixels(g), want); diff != "" {
+			t.Errorf("%c: baked pixels differ, %s", r, diff)
+		}
+		if bytes.Count(want.Pix, []byte{0}) == len(want.Pix) {
+			t.Errorf("%c: expected a glyph with some ink", r)
+		}
+	}
+	if k := bm.Kerning('A', 'V', 16); k != roundFloat(ttf.Kerning('A', 'V', 16)) {
+		t.Errorf("Kerning should be kept, got %v", k)
+	}
+	if _, err := BakeBMFont(ttf, 0, []rune("A"), 0); err == nil {
+		t.Error("Fonts need a size")
+	}
+
+	//Written and parsed back
+	var buf bytes.Buffer
+	if err := EncodeBMFont(&buf, bm, []string{"ui.0.png"}); err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseBMFont(&buf, func(name string) (*Texture, error) {
+		if name != "ui.0.png" {
+			t.Errorf("Expected page ui.0.png got %s", name)
+		}
+		return bm.Pages()[0], nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range "AVa ?" {
+		expected, _ := bm.Glyph(r, 24)
+		if g, _ := parsed.Glyph(r, 24); g != expected {
+			t.Errorf("%c: expected %+v got %+v", r, expected, g)
+		}
+	}
+	if parsed.Metrics(16) != bm.Metrics(16) || parsed.Kerning('A', 'V', 16) != bm.Kerning('A', 'V', 16) {
+		t.Error("Metrics and kerning should survive")
+	}
+}
+
+func TestFontStack(t *testing.T) {
+	bm, ttf := testBMFont(t), testTrueTypeFont(t)
+	stack := FontStack{bm, ttf}
+	if g, ok := stack.Glyph('A', 10); !ok || g.Texture != bm.pages[0] {
+		t.Error("A should come from the first font")
+	}
+	if g, ok := stack.Glyph('é', 10); !ok || g.Texture == bm.pages[0] {
+		t.Error("é should come from the second font")
+	}
+	if g, ok := stack.Glyph('\U0001F600', 10); ok || g.TextureRect.Left != 4 {
+		t.Error("Missing runes should get the first font's placeholder")
+	}
+	if !stack.HasGlyph('é') || stack.HasGlyph('\U0001F600') {
+		t.Error("HasGlyph should check every font")
+	}
+	if stack.Kerning('A', 'A', 10) != -1 || stack.Kerning('A', 'é', 10) != 0 {
+		t.Error("Kerning only applies within a font")
+	}
+	if stack.Metrics(10) != bm.Metrics(10) {
+		t.Error("Metrics should come from the first font")
+	}
+	if _, ok := (FontStack{}).Glyph('A', 10); ok {
+		t.Error("An empty stack has no glyphs")
+	}
+}
+
+func TestGlyphAtlasPages(t *testing.T) {
+	var atlas glyphAtlas
+	mask := image.NewAlpha(image.Rect(0, 0, 300, 300))
+	tex1, r1 := atlas.add(mask, image.Point{}, mask.Rect.Size())
+	tex2, r2 := atlas.add(mask, image.Point{}, mask.Rect.Size())
+	if tex1 == tex2 || len(atlas.pages) != 2 {
+		t.Error("A glyph that doesn't fit should start a page")
+	}
+	if r1 != (RectI{Left: 1, Top: 1, Width: 300, Height: 300}) || r1 != r2 {
+		t.Errorf("Unexpected rects %v %v", r1, r2)
+	}
+	tex3, _ := atlas.add(mask, image.Point{}, image.Point{X: 600, Y: 10})
+	if size := tex3.Size(); size.X != 602 {
+		t.Errorf("Pages grow for huge glyphs, got %v", size)
+	}
+}
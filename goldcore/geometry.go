@@ -0,0 +1,381 @@
+package goldcore
+
+import "math"
+
+/////////////////////////////////////
+///		RAY
+/////////////////////////////////////
+
+//Ray : Half line starting at Origin going towards Direction. Direction
+//doesn't need to be normalized
+type Ray struct {
+	Origin, Direction Vector2f
+}
+
+//At : Point distance units along the ray
+func (r Ray) At(distance float32) Vector2f {
+	return r.Origin.Plus(r.Direction.Normalize().Scale(distance))
+}
+
+//RayHit : Where a ray hit a shape. Normal is the unit normal of the surface
+//hit, pointing out of the shape; it is zero when the ray starts inside.
+//Distance is measured from the ray origin
+type RayHit struct {
+	Point    Vector2f
+	Normal   Vector2f
+	Distance float32
+}
+
+/////////////////////////////////////
+///		CIRCLE
+/////////////////////////////////////
+
+//Circle : Disc around Center
+type Circle struct {
+	Center Vector2f
+	Radius float32
+}
+
+//Contains : True if p is inside the circle or on its edge
+func (c Circle) Contains(p Vector2f) bool {
+	return p.DistanceSquared(c.Center) <= c.Radius*c.Radius
+}
+
+//Bounds : Smallest rectangle containing the circle
+func (c Circle) Bounds() RectF {
+	return RectF{Left: c.Center.X - c.Radius, Top: c.Center.Y - c.Radius, Width: 2 * c.Radius, Height: 2 * c.Radius}
+}
+
+//ClosestPoint : Point of the disc closest to p. p itself if inside
+func (c Circle) ClosestPoint(p Vector2f) Vector2f {
+	if c.Contains(p) {
+		return p
+	}
+	return c.Center.Plus(p.Minus(c.Center).Normalize().Scale(c.Radius))
+}
+
+//IntersectsCircle : True if the discs overlap or touch
+func (c Circle) IntersectsCircle(other Circle) bool {
+	r := c.Radius + other.Radius
+	return c.Center.DistanceSquared(other.Center) <= r*r
+}
+
+//IntersectsRect : True if the disc overlaps the rectangle
+func (c Circle) IntersectsRect(r RectF) bool {
+	return c.Contains(r.ClosestPoint(c.Center))
+}
+
+//IntersectsSegment : True if the segment passes through the disc
+func (c Circle) IntersectsSegment(s Segment) bool {
+	return c.Contains(s.ClosestPoint(c.Center))
+}
+
+//IntersectsPolygon : True if the disc overlaps the polygon
+func (c Circle) IntersectsPolygon(p Polygon) bool {
+	return p.Contains(c.Center) || c.Contains(p.ClosestPoint(c.Center))
+}
+
+//RayCast : First point where ray enters the disc. A ray starting inside hits
+//at its origin
+func (c Circle) RayCast(ray Ray) (RayHit, bool) {
+	dir := ray.Direction.Normalize()
+	if dir == (Vector2f{}) {
+		return RayHit{}, false
+	}
+	if c.Contains(ray.Origin) {
+		return RayHit{Point: ray.Origin}, true
+	}
+	toOrigin := ray.Origin.Minus(c.Center)
+	b := toOrigin.Dot(dir)
+	disc := b*b - toOrigin.LengthSquared() + c.Radius*c.Radius
+	if disc < 0 {
+		return RayHit{}, false
+	}
+	t := -b - sqrtFloat(disc)
+	if t < 0 {
+		return RayHit{}, false
+	}
+	point := ray.Origin.Plus(dir.Scale(t))
+	return RayHit{Point: point, Normal: point.Minus(c.Center).Normalize(), Distance: t}, true
+}
+
+/////////////////////////////////////
+///		SEGMENT
+/////////////////////////////////////
+
+//Segment : Straight line between A and B
+type Segment struct {
+	A, B Vector2f
+}
+
+//Length : Distance between the ends
+func (s Segment) Length() float32 {
+	return s.A.Distance(s.B)
+}
+
+//Direction : Unit vector from A to B
+func (s Segment) Direction() Vector2f {
+	return s.B.Minus(s.A).Normalize()
+}
+
+//Bounds : Smallest rectangle containing the segment
+func (s Segment) Bounds() RectF {
+	return RectFromPoints(s.A, s.B)
+}
+
+//ClosestPoint : Point of the segment closest to p
+func (s Segment) ClosestPoint(p Vector2f) Vector2f {
+	ab := s.B.Minus(s.A)
+	lengthSquared := ab.LengthSquared()
+	if lengthSquared == 0 {
+		return s.A
+	}
+	t := Clamp(p.Minus(s.A).Dot(ab)/lengthSquared, 0, 1)
+	return s.A.Plus(ab.Scale(t))
+}
+
+//Distance : Distance from p to the closest point of the segment
+func (s Segment) Distance(p Vector2f) float32 {
+	return p.Distance(s.ClosestPoint(p))
+}
+
+//Intersection : Point where both segments cross. False if they don't, or if
+//they are parallel
+func (s Segment) Intersection(other Segment) (Vector2f, bool) {
+	r := s.B.Minus(s.A)
+	q := other.B.Minus(other.A)
+	denom := r.Cross(q)
+	if denom == 0 {
+		return Vector2f{}, false
+	}
+	ao := other.A.Minus(s.A)
+	t := ao.Cross(q) / denom
+	u := ao.Cross(r) / denom
+	if t < 0 || t > 1 || u < 0 || u > 1 {
+		return Vector2f{}, false
+	}
+	return s.A.Plus(r.Scale(t)), true
+}
+
+//Intersects : True if the segments cross. Overlapping collinear segments
+//count too
+func (s Segment) Intersects(other Segment) bool {
+	if _, ok := s.Intersection(other); ok {
+		return true
+	}
+	r := s.B.Minus(s.A)
+	if r.Cross(other.B.Minus(other.A)) != 0 || r.Cross(other.A.Minus(s.A)) != 0 {
+		return false
+	}
+	//Collinear, check the projections overlap
+	return s.Distance(other.A) == 0 || s.Distance(other.B) == 0 || other.Distance(s.A) == 0
+}
+
+//IntersectsRect : True if part of the segment is inside the rectangle
+func (s Segment) IntersectsRect(r RectF) bool {
+	if r.Contains(s.A) || r.Contains(s.B) {
+		return true
+	}
+	corners := r.Corners()
+	for i := range corners {
+		if s.Intersects(Segment{A: corners[i], B: corners[(i+1)%4]}) {
+			return true
+		}
+	}
+	return false
+}
+
+//RayCast : Point where ray crosses the segment. The normal faces the ray
+func (s Segment) RayCast(ray Ray) (RayHit, bool) {
+	dir := ray.Direction.Normalize()
+	if dir == (Vector2f{}) {
+		return RayHit{}, false
+	}
+	edge := s.B.Minus(s.A)
+	denom := dir.Cross(edge)
+	if denom == 0 {
+		return RayHit{}, false
+	}
+	ao := s.A.Minus(ray.Origin)
+	t := ao.Cross(edge) / denom
+	u := ao.Cross(dir) / denom
+	if t < 0 || u < 0 || u > 1 {
+		return RayHit{}, false
+	}
+	normal := edge.Perpendicular().Normalize()
+	if normal.Dot(dir) > 0 {
+		normal = normal.Neg()
+	}
+	return RayHit{Point: ray.Origin.Plus(dir.Scale(t)), Normal: normal, Distance: t}, true
+}
+
+/////////////////////////////////////
+///		POLYGON
+/////////////////////////////////////
+
+//Polygon : Closed shape through Points, in order. The last point connects
+//back to the first. Convex or not, but edges shouldn't cross each other
+type Polygon struct {
+	Points []Vector2f
+}
+
+//NewPolygon : Polygon through points
+func NewPolygon(points ...Vector2f) Polygon {
+	return Polygon{Points: points}
+}
+
+//RectPolygon : Polygon with the corners of r
+func RectPolygon(r RectF) Polygon {
+	corners := r.Corners()
+	return Polygon{Points: corners[:]}
+}
+
+//Edge : Edge i, from point i to the next one
+func (p Polygon) Edge(i int) Segment {
+	return Segment{A: p.Points[i], B: p.Points[(i+1)%len(p.Points)]}
+}
+
+//Bounds : Smallest rectangle containing the polygon
+func (p Polygon) Bounds() RectF {
+	return RectFromPoints(p.Points...)
+}
+
+//SignedArea : Area of the polygon, positive if the points go clockwise on
+//screen (y pointing down)
+func (p Polygon) SignedArea() float32 {
+	var area float32
+	for i := range p.Points {
+		e := p.Edge(i)
+		area += e.A.Cross(e.B)
+	}
+	return area / 2
+}
+
+//Area : Area of the polygon
+func (p Polygon) Area() float32 {
+	return float32(math.Abs(float64(p.SignedArea())))
+}
+
+//Centroid : Center of mass of the polygon
+func (p Polygon) Centroid() Vector2f {
+	area := p.SignedArea()
+	if area == 0 {
+		return p.Bounds().Center()
+	}
+	var c Vector2f
+	for i := range p.Points {
+		e := p.Edge(i)
+		c = c.Plus(e.A.Plus(e.B).Scale(e.A.Cross(e.B)))
+	}
+	return c.Div(6 * area)
+}
+
+//IsConvex : True if every corner turns the same way
+func (p Polygon) IsConvex() bool {
+	n := len(p.Points)
+	if n < 3 {
+		return false
+	}
+	var sign float32
+	for i := 0; i < n; i++ {
+		a, b, c := p.Points[i], p.Points[(i+1)%n], p.Points[(i+2)%n]
+		turn := b.Minus(a).Cross(c.Minus(b))
+		if turn == 0 {
+			continue
+		}
+		if sign == 0 {
+			sign = turn
+		} else if (turn > 0) != (sign > 0) {
+			return false
+		}
+	}
+	return sign != 0
+}
+
+//Contains : True if pt is inside the polygon, using the even-odd rule
+func (p Polygon) Contains(pt Vector2f) bool {
+	inside := false
+	n := len(p.Points)
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		a, b := p.Points[i], p.Points[j]
+		if (a.Y > pt.Y) != (b.Y > pt.Y) && pt.X < (b.X-a.X)*(pt.Y-a.Y)/(b.Y-a.Y)+a.X {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+//ClosestPoint : Point on the outline of the polygon closest to pt
+func (p Polygon) ClosestPoint(pt Vector2f) Vector2f {
+	if len(p.Points) == 0 {
+		return pt
+	}
+	best := p.Points[0]
+	bestDistance := float32(math.Inf(1))
+	for i := range p.Points {
+		c := p.Edge(i).ClosestPoint(pt)
+		if d := c.DistanceSquared(pt); d < bestDistance {
+			best, bestDistance = c, d
+		}
+	}
+	return best
+}
+
+//IntersectsSegment : True if part of the segment is inside the polygon
+func (p Polygon) IntersectsSegment(s Segment) bool {
+	if p.Contains(s.A) {
+		return true
+	}
+	for i := range p.Points {
+		if p.Edge(i).Intersects(s) {
+			return true
+		}
+	}
+	return false
+}
+
+//IntersectsPolygon : True if the polygons overlap
+func (p Polygon) IntersectsPolygon(other Polygon) bool {
+	if len(p.Points) == 0 || len(other.Points) == 0 {
+		return false
+	}
+	if p.Contains(other.Points[0]) || other.Contains(p.Points[0]) {
+		return true
+	}
+	for i := range p.Points {
+		if other.IntersectsSegment(p.Edge(i)) {
+			return true
+		}
+	}
+	return false
+}
+
+//IntersectsRect : True if the polygon overlaps the rectangle
+func (p Polygon) IntersectsRect(r RectF) bool {
+	return p.IntersectsPolygon(RectPolygon(r))
+}
+
+//RayCast : First point where ray enters the polygon. A ray starting inside
+//hits at its origin
+func (p Polygon) RayCast(ray Ray) (RayHit, bool) {
+	if p.Contains(ray.Origin) {
+		return RayHit{Point: ray.Origin}, true
+	}
+	var best RayHit
+	found := false
+	for i := range p.Points {
+		if hit, ok := p.Edge(i).RayCast(ray); ok && (!found || hit.Distance < best.Distance) {
+			best, found = hit, true
+		}
+	}
+	return best, found
+}
+
+//Transform : Polygon with every point moved by t
+func (p Polygon) Transform(t Transform2D) Polygon {
+	points := make([]Vector2f, len(p.Points))
+	for i, pt := range p.Points {
+		points[i] = t.TransformPoint(pt)
+	}
+	return Polygon{Points: points}
+}
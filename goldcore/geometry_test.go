@@ -0,0 +1,164 @@
+package goldcore
+
+import "testing"
+
+func TestRectI(t *testing.T) {
+	a := RectI{Left: 0, Top: 0, Width: 10, Height: 10}
+	b := RectI{Left: 5, Top: 8, Width: 10, Height: 10}
+	if !a.Contains(Vector2i{X: 0, Y: 9}) || a.Contains(Vector2i{X: 10, Y: 0}) {
+		t.Error("Contains should include the left/top edges and exclude right/bottom")
+	}
+	if got, ok := a.Intersection(b); !ok || got != (RectI{Left: 5, Top: 8, Width: 5, Height: 2}) {
+		t.Errorf("Intersection: got %v %v", got, ok)
+	}
+	if a.Intersects(RectI{Left: 10, Width: 5, Height: 5}) {
+		t.Error("rectangles sharing only an edge shouldn't intersect")
+	}
+	if got := a.Union(b); got != (RectI{Width: 15, Height: 18}) {
+		t.Errorf("Union: got %v", got)
+	}
+	if got := a.Union(RectI{Left: 100}); got != a {
+		t.Errorf("Union with an empty rect should ignore it, got %v", got)
+	}
+	if !a.ContainsRect(RectI{Left: 2, Top: 2, Width: 8, Height: 8}) || a.ContainsRect(b) {
+		t.Error("ContainsRect wrong")
+	}
+	if got := (RectF{Left: 0.5, Top: -0.5, Width: 1, Height: 1}).ToRectI(); got != (RectI{Top: -1, Width: 2, Height: 2}) {
+		t.Errorf("ToRectI should cover the float rect, got %v", got)
+	}
+}
+
+func TestRectF(t *testing.T) {
+	r := RectF{Left: 0, Top: 0, Width: 4, Height: 2}
+	if got := r.ClosestPoint(Vector2f{X: 10, Y: -3}); got != (Vector2f{X: 4}) {
+		t.Errorf("ClosestPoint: got %v", got)
+	}
+	if got, ok := r.Intersection(RectF{Left: 3, Top: 1, Width: 5, Height: 5}); !ok || got != (RectF{Left: 3, Top: 1, Width: 1, Height: 1}) {
+		t.Errorf("Intersection: got %v %v", got, ok)
+	}
+	if got := RectFromPoints(Vector2f{X: 3, Y: -1}, Vector2f{X: -2, Y: 4}); got != (RectF{Left: -2, Top: -1, Width: 5, Height: 5}) {
+		t.Errorf("RectFromPoints: got %v", got)
+	}
+
+	hit, ok := r.RayCast(Ray{Origin: Vector2f{X: -3, Y: 1}, Direction: Vector2f{X: 2}})
+	if !ok || hit.Point != (Vector2f{Y: 1}) || hit.Normal != (Vector2f{X: -1}) || hit.Distance != 3 {
+		t.Errorf("RayCast from the left: got %+v %v", hit, ok)
+	}
+	hit, ok = r.RayCast(Ray{Origin: Vector2f{X: 2, Y: 10}, Direction: Vector2f{Y: -1}})
+	if !ok || hit.Point != (Vector2f{X: 2, Y: 2}) || hit.Normal != (Vector2f{Y: 1}) {
+		t.Errorf("RayCast from below: got %+v %v", hit, ok)
+	}
+	if _, ok := r.RayCast(Ray{Origin: Vector2f{X: -3, Y: 1}, Direction: Vector2f{X: -1}}); ok {
+		t.Error("ray pointing away shouldn't hit")
+	}
+	if hit, ok := r.RayCast(Ray{Origin: Vector2f{X: 1, Y: 1}, Direction: Vector2f{X: 1}}); !ok || hit.Distance != 0 {
+		t.Errorf("ray starting inside should hit at its origin, got %+v %v", hit, ok)
+	}
+}
+
+func TestCircle(t *testing.T) {
+	c := Circle{Center: Vector2f{X: 5, Y: 5}, Radius: 2}
+	if !c.Contains(Vector2f{X: 7, Y: 5}) || c.Contains(Vector2f{X: 7, Y: 7}) {
+		t.Error("Contains wrong")
+	}
+	if !c.IntersectsCircle(Circle{Center: Vector2f{X: 9, Y: 5}, Radius: 2}) || c.IntersectsCircle(Circle{Center: Vector2f{X: 10, Y: 5}, Radius: 2}) {
+		t.Error("IntersectsCircle wrong")
+	}
+	if !c.IntersectsRect(RectF{Left: 6, Top: 0, Width: 10, Height: 10}) || c.IntersectsRect(RectF{Left: 7.5, Top: 7.5, Width: 1, Height: 1}) {
+		t.Error("IntersectsRect wrong")
+	}
+	if got := c.ClosestPoint(Vector2f{X: 5, Y: 0}); !got.ApproxEquals(Vector2f{X: 5, Y: 3}, Epsilon) {
+		t.Errorf("ClosestPoint: got %v", got)
+	}
+	hit, ok := c.RayCast(Ray{Origin: Vector2f{X: 0, Y: 5}, Direction: Vector2f{X: 1}})
+	if !ok || !hit.Point.ApproxEquals(Vector2f{X: 3, Y: 5}, Epsilon) || !hit.Normal.ApproxEquals(Vector2f{X: -1}, Epsilon) || !ApproxEqual(hit.Distance, 3, Epsilon) {
+		t.Errorf("RayCast: got %+v %v", hit, ok)
+	}
+	if _, ok := c.RayCast(Ray{Origin: Vector2f{X: 0, Y: 0}, Direction: Vector2f{X: 1}}); ok {
+		t.Error("ray missing the circle shouldn't hit")
+	}
+}
+
+func TestSegment(t *testing.T) {
+	s := Segment{A: Vector2f{}, B: Vector2f{X: 10}}
+	if got := s.ClosestPoint(Vector2f{X: 4, Y: 3}); got != (Vector2f{X: 4}) {
+		t.Errorf("ClosestPoint: got %v", got)
+	}
+	if got := s.Distance(Vector2f{X: 13, Y: 4}); !ApproxEqual(got, 5, Epsilon) {
+		t.Errorf("Distance past the end: got %v", got)
+	}
+	if got, ok := s.Intersection(Segment{A: Vector2f{X: 3, Y: -1}, B: Vector2f{X: 3, Y: 1}}); !ok || got != (Vector2f{X: 3}) {
+		t.Errorf("Intersection: got %v %v", got, ok)
+	}
+	if s.Intersects(Segment{A: Vector2f{X: 3, Y: 1}, B: Vector2f{X: 3, Y: 2}}) {
+		t.Error("segments that don't reach each other shouldn't intersect")
+	}
+	if !s.Intersects(Segment{A: Vector2f{X: 8}, B: Vector2f{X: 12}}) {
+		t.Error("overlapping collinear segments should intersect")
+	}
+	if s.Intersects(Segment{A: Vector2f{X: 11}, B: Vector2f{X: 12}}) {
+		t.Error("disjoint collinear segments shouldn't intersect")
+	}
+	if !s.IntersectsRect(RectF{Left: 2, Top: -1, Width: 1, Height: 2}) || s.IntersectsRect(RectF{Left: 2, Top: 1, Width: 1, Height: 2}) {
+		t.Error("IntersectsRect wrong")
+	}
+	hit, ok := s.RayCast(Ray{Origin: Vector2f{X: 5, Y: 5}, Direction: Vector2f{Y: -1}})
+	if !ok || hit.Point != (Vector2f{X: 5}) || hit.Normal != (Vector2f{Y: 1}) || hit.Distance != 5 {
+		t.Errorf("RayCast: got %+v %v", hit, ok)
+	}
+}
+
+func TestPolygon(t *testing.T) {
+	//L shaped, concave
+	l := NewPolygon(
+		Vector2f{X: 0, Y: 0}, Vector2f{X: 4, Y: 0}, Vector2f{X: 4, Y: 2},
+		Vector2f{X: 2, Y: 2}, Vector2f{X: 2, Y: 4}, Vector2f{X: 0, Y: 4},
+	)
+	if !l.Contains(Vector2f{X: 1, Y: 3}) || l.Contains(Vector2f{X: 3, Y: 3}) {
+		t.Error("Contains wrong for a concave polygon")
+	}
+	if l.IsConvex() || !RectPolygon(RectF{Width: 1, Height: 1}).IsConvex() {
+		t.Error("IsConvex wrong")
+	}
+	if got := l.Area(); got != 12 {
+		t.Errorf("Area: expected 12, got %v", got)
+	}
+	if got := RectPolygon(RectF{Left: 2, Top: 2, Width: 4, Height: 2}).Centroid(); !got.ApproxEquals(Vector2f{X: 4, Y: 3}, Epsilon) {
+		t.Errorf("Centroid: got %v", got)
+	}
+	if got := l.ClosestPoint(Vector2f{X: 3, Y: 3}); !got.ApproxEquals(Vector2f{X: 3, Y: 2}, Epsilon) && !got.ApproxEquals(Vector2f{X: 2, Y: 3}, Epsilon) {
+		t.Errorf("ClosestPoint: got %v", got)
+	}
+
+	tri := NewPolygon(Vector2f{X: 3, Y: 3}, Vector2f{X: 5, Y: 3}, Vector2f{X: 5, Y: 5})
+	if l.IntersectsPolygon(tri) {
+		t.Error("triangle in the notch of the L shouldn't intersect it")
+	}
+	if !l.IntersectsPolygon(tri.Transform(TranslationTransform(Vector2f{X: -1.5, Y: -1.5}))) {
+		t.Error("moved triangle should intersect")
+	}
+	inner := RectPolygon(RectF{Left: 0.5, Top: 0.5, Width: 0.5, Height: 0.5})
+	if !l.IntersectsPolygon(inner) || !inner.IntersectsPolygon(l) {
+		t.Error("polygon inside another should intersect it")
+	}
+	if !(Circle{Center: Vector2f{X: 3, Y: 3}, Radius: 1.5}).IntersectsPolygon(l) {
+		t.Error("circle reaching the corner of the notch should intersect")
+	}
+
+	hit, ok := l.RayCast(Ray{Origin: Vector2f{X: 6, Y: 3}, Direction: Vector2f{X: -1}})
+	if !ok || !hit.Point.ApproxEquals(Vector2f{X: 2, Y: 3}, Epsilon) || !hit.Normal.ApproxEquals(Vector2f{X: 1}, Epsilon) {
+		t.Errorf("RayCast: got %+v %v", hit, ok)
+	}
+}
+
+func TestCameraVisibleBounds(t *testing.T) {
+	cam := NewCamera2D(Vector2u{X: 800, Y: 600})
+	if got := cam.VisibleBounds(); got != (RectF{Width: 800, Height: 600}) {
+		t.Errorf("default camera should see the whole target, got %v", got)
+	}
+	cam.Rotation = 90
+	got := cam.VisibleBounds()
+	if !closeTo(got.Size(), Vector2f{X: 600, Y: 800}) || !closeTo(got.Center(), Vector2f{X: 400, Y: 300}) {
+		t.Errorf("rotated camera: got %v", got)
+	}
+}
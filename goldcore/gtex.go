@@ -0,0 +1,124 @@
+package goldcore
+
+import (
+	"bufio"
+	"compress/flate"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+)
+
+//gtexMagic : First bytes of a GTEX file
+const gtexMagic = "GTEX"
+
+//gtexVersion : Version written by EncodeGTEX
+const gtexVersion = 1
+
+//gtexMaxPixels : Biggest image DecodeGTEX allocates, 16384 x 16384
+const gtexMaxPixels = 1 << 28
+
+//gtexMaxSide : Widest or tallest image DecodeGTEX takes
+const gtexMaxSide = 1 << 16
+
+func init() {
+	image.RegisterFormat("gtex", gtexMagic, func(r io.Reader) (image.Image, error) {
+		return DecodeGTEX(r)
+	}, DecodeGTEXConfig)
+}
+
+//EncodeGTEX : Writes img to w in GTEX, the engine's texture format written
+//by goldpack. Pixels are stored as textures keep them, alpha premultiplied
+//RGBA, so loading converts nothing. Each byte is stored minus the byte of
+//the pixel to its left and the rows are DEFLATE compressed. The layout is
+//	"GTEX", version byte, 3 zero bytes, width and height as little endian
+//	uint32, compressed rows
+//The format is registered with the image package, so DecodeTexture,
+//LoadTexture and image.Decode read it
+func EncodeGTEX(w io.Writer, img image.Image) error {
+	bounds := img.Bounds()
+	rgba, ok := img.(*image.RGBA)
+	if !ok || rgba.Rect.Min != (image.Point{}) {
+		rgba = image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		draw.Draw(rgba, rgba.Rect, img, bounds.Min, draw.Src)
+	}
+	var header [16]byte
+	copy(header[:], gtexMagic)
+	header[4] = gtexVersion
+	binary.LittleEndian.PutUint32(header[8:], uint32(bounds.Dx()))
+	binary.LittleEndian.PutUint32(header[12:], uint32(bounds.Dy()))
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+	zw, err := flate.NewWriter(w, flate.BestCompression)
+	if err != nil {
+		return err
+	}
+	row := make([]byte, 4*bounds.Dx())
+	for y := 0; y < bounds.Dy(); y++ {
+		pix := rgba.Pix[y*rgba.Stride : y*rgba.Stride+len(row)]
+		for i := range row {
+			row[i] = pix[i]
+			if i >= 4 {
+				row[i] -= pix[i-4]
+			}
+		}
+		if _, err := zw.Write(row); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+//DecodeGTEXConfig : Size of a GTEX image without decoding its pixels
+func DecodeGTEXConfig(r io.Reader) (image.Config, error) {
+	w, h, err := readGTEXHeader(r)
+	if err != nil {
+		return image.Config{}, err
+	}
+	return image.Config{ColorModel: color.RGBAModel, Width: w, Height: h}, nil
+}
+
+//DecodeGTEX : Image from GTEX data
+func DecodeGTEX(r io.Reader) (*image.RGBA, error) {
+	br := bufio.NewReader(r)
+	w, h, err := readGTEXHeader(br)
+	if err != nil {
+		return nil, err
+	}
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	zr := flate.NewReader(br)
+	defer zr.Close()
+	if _, err := io.ReadFull(zr, img.Pix); err != nil {
+		return nil, fmt.Errorf("gtex: reading pixels: %v", err)
+	}
+	for y := 0; y < h; y++ {
+		row := img.Pix[y*img.Stride : (y+1)*img.Stride]
+		for i := 4; i < len(row); i++ {
+			row[i] += row[i-4]
+		}
+	}
+	return img, nil
+}
+
+//readGTEXHeader : Checks the header and gives the size
+func readGTEXHeader(r io.Reader) (int, int, error) {
+	var header [16]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, 0, fmt.Errorf("gtex: reading header: %v", err)
+	}
+	if string(header[:4]) != gtexMagic {
+		return 0, 0, fmt.Errorf("gtex: not a GTEX file")
+	}
+	if header[4] != gtexVersion {
+		return 0, 0, fmt.Errorf("gtex: unknown version %d", header[4])
+	}
+	w := binary.LittleEndian.Uint32(header[8:])
+	h := binary.LittleEndian.Uint32(header[12:])
+	if w > gtexMaxSide || h > gtexMaxSide || uint64(w)*uint64(h) > gtexMaxPixels {
+		return 0, 0, fmt.Errorf("gtex: %dx%d image is too big", w, h)
+	}
+	return int(w), int(h), nil
+}
@@ -0,0 +1,65 @@
+package goldcore
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestGTEX(t *testing.T) {
+	//Gradient with translucent pixels, NRGBA so encoding premultiplies
+	src := image.NewNRGBA(image.Rect(3, 5, 67, 37))
+	for y := src.Rect.Min.Y; y < src.Rect.Max.Y; y++ {
+		for x := src.Rect.Min.X; x < src.Rect.Max.X; x++ {
+			src.SetNRGBA(x, y, color.NRGBA{R: uint8(x * 4), G: uint8(y * 8), B: 200, A: uint8(255 - x)})
+		}
+	}
+	var buf bytes.Buffer
+	if err := EncodeGTEX(&buf, src); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	img, err := DecodeGTEX(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Rect != image.Rect(0, 0, 64, 32) {
+		t.Fatalf("Expected 64x32 from the origin got %v", img.Rect)
+	}
+	for y := 0; y < 32; y++ {
+		for x := 0; x < 64; x++ {
+			expected := color.RGBAModel.Convert(src.At(x+3, y+5)).(color.RGBA)
+			if got := img.RGBAAt(x, y); got != expected {
+				t.Fatalf("Pixel (%d, %d): expected %v got %v", x, y, expected, got)
+			}
+		}
+	}
+
+	//Registered with the image package
+	if _, format, err := image.Decode(bytes.NewReader(data)); err != nil || format != "gtex" {
+		t.Errorf("image.Decode should read GTEX, got %s %v", format, err)
+	}
+	if config, _, err := image.DecodeConfig(bytes.NewReader(data)); err != nil || config.Width != 64 || config.Height != 32 {
+		t.Errorf("Expected a 64x32 config got %v %v", config, err)
+	}
+	if tex, err := DecodeTexture(bytes.NewReader(data)); err != nil || tex.Size() != (Vector2u{X: 64, Y: 32}) {
+		t.Errorf("Textures load from GTEX: %v", err)
+	}
+
+	if len(data) >= len(img.Pix)/4 {
+		t.Errorf("Expected the %d bytes of pixels compressed got %d bytes", len(img.Pix), len(data))
+	}
+
+	for name, bad := range map[string][]byte{
+		"magic":     append([]byte("GTEZ"), data[4:]...),
+		"version":   append([]byte("GTEX\x09"), data[5:]...),
+		"truncated": data[:len(data)/2],
+		"huge":      append([]byte("GTEX\x01\x00\x00\x00\xff\xff\xff\x00\xff\xff\xff\x00"), data[16:]...),
+	} {
+		if _, err := DecodeGTEX(bytes.NewReader(bad)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
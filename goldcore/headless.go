@@ -0,0 +1,140 @@
+package goldcore
+
+import (
+	"sync"
+
+	sf "github.com/manyminds/gosfml"
+)
+
+//headlessWindow : windowBackend that never touches the OS or the GPU. Frames
+//live in a software framebuffer, so tests can render and compare pixels.
+type headlessWindow struct {
+	*softwareSurface
+	mutex    sync.Mutex
+	size     sf.Vector2u
+	position sf.Vector2i
+	title    string
+	open     bool
+	active   bool
+	events   []sf.Event
+}
+
+//newHeadlessWindow : Creates an open headless window with black buffers
+func newHeadlessWindow(width, height uint, name string) *headlessWindow {
+	return &headlessWindow{
+		softwareSurface: newSoftwareSurface(width, height),
+		size:            sf.Vector2u{X: width, Y: height},
+		title:           name,
+		open:            true,
+	}
+}
+
+//NewHeadlessGameWindow : Creates a GameWindow without a real window behind it.
+//Behaves like NewGameWindow but everything is rendered in software.
+func NewHeadlessGameWindow(width, height uint, name string) *GameWindow {
+	gW := &GameWindow{
+		renderWindow: newHeadlessWindow(width, height, name),
+		InputSystem:  NewInputSystem(),
+		observers:    make([]WindowObserver, 0),
+		capture:      &windowCapture{},
+		stats:        &frameStats{},
+		post:         NewPostChain(),
+		deferred:     &deferredMessages{},
+		wait:         make(chan int),
+		presented:    make(chan int),
+	}
+	gW.stopped = true
+	gW.size = gW.GetSize()
+	return gW
+}
+
+//PushEvent : Queues an event on a headless window as if the OS had sent it.
+//It is handled by the next PollEvent. Returns false, doing nothing, if the
+//window is a real one
+func (gW *GameWindow) PushEvent(event sf.Event) bool {
+	w, ok := gW.renderWindow.(*headlessWindow)
+	if ok {
+		w.pushEvent(event)
+	}
+	return ok
+}
+
+func (w *headlessWindow) pushEvent(event sf.Event) {
+	w.mutex.Lock()
+	w.events = append(w.events, event)
+	w.mutex.Unlock()
+}
+
+//PollEvent : Pops the oldest queued event, nil if there are none
+func (w *headlessWindow) PollEvent() sf.Event {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if len(w.events) == 0 {
+		return nil
+	}
+	event := w.events[0]
+	w.events = w.events[1:]
+	return event
+}
+
+//IsOpen : False once Close has been called
+func (w *headlessWindow) IsOpen() bool {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.open
+}
+
+//Close : Closes the window
+func (w *headlessWindow) Close() {
+	w.mutex.Lock()
+	w.open = false
+	w.mutex.Unlock()
+}
+
+//SetActive : There is no context to activate, just remembers the flag
+func (w *headlessWindow) SetActive(active bool) bool {
+	w.mutex.Lock()
+	w.active = active
+	w.mutex.Unlock()
+	return true
+}
+
+//GetSize : Size of the framebuffer
+func (w *headlessWindow) GetSize() sf.Vector2u {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.size
+}
+
+//SetSize : Reallocates the framebuffers, their contents are lost. Queues a
+//resize event like a real window would
+func (w *headlessWindow) SetSize(size sf.Vector2u) {
+	w.mutex.Lock()
+	if size != w.size {
+		w.size = size
+		w.resize(size.X, size.Y)
+		w.events = append(w.events, sf.EventResized{Width: size.X, Height: size.Y})
+	}
+	w.mutex.Unlock()
+}
+
+//GetPosition : Position last given to SetPosition
+func (w *headlessWindow) GetPosition() sf.Vector2i {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.position
+}
+
+//SetPosition : Remembers the position
+func (w *headlessWindow) SetPosition(pos sf.Vector2i) {
+	w.mutex.Lock()
+	w.position = pos
+	w.mutex.Unlock()
+}
+
+//SetTitle : Remembers the title
+func (w *headlessWindow) SetTitle(title string) {
+	w.mutex.Lock()
+	w.title = title
+	w.mutex.Unlock()
+}
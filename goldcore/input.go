@@ -286,6 +286,7 @@ type EventMouseButton struct {
 //EventMouseButtonWrapper : Wrapper around EventMouseButton for Messaging System
 type EventMouseButtonWrapper struct {
 	Pos              Vector2i
+	World            Vector2f //Pos seen through the window's camera
 	EventMouseButton EventMouseButton
 }
 
@@ -311,12 +312,12 @@ func SFMouseButtonReleasedToEventMouseButton(eM sf.EventMouseButtonReleased) Eve
 
 //SFMouseButtonPressedToEventMouseButtonWrapper sfml to Mouse buton
 func SFMouseButtonPressedToEventMouseButtonWrapper(eM sf.EventMouseButtonPressed) EventMouseButtonWrapper {
-	return EventMouseButtonWrapper{EventMouseButton: EventMouseButton{Button: MouseButton(eM.Button), Clicked: true}, Pos: Vector2i{eM.X, eM.Y}}
+	return EventMouseButtonWrapper{EventMouseButton: EventMouseButton{Button: MouseButton(eM.Button), Clicked: true}, Pos: Vector2i{eM.X, eM.Y}, World: Vector2f{float32(eM.X), float32(eM.Y)}}
 }
 
 //SFMouseButtonReleasedToEventMouseButtonWrapper sfml to mouse button
 func SFMouseButtonReleasedToEventMouseButtonWrapper(eM sf.EventMouseButtonReleased) EventMouseButtonWrapper {
-	return EventMouseButtonWrapper{EventMouseButton: EventMouseButton{Button: MouseButton(eM.Button), Clicked: false}, Pos: Vector2i{eM.X, eM.Y}}
+	return EventMouseButtonWrapper{EventMouseButton: EventMouseButton{Button: MouseButton(eM.Button), Clicked: false}, Pos: Vector2i{eM.X, eM.Y}, World: Vector2f{float32(eM.X), float32(eM.Y)}}
 }
 
 //EventMouseButtonToSFMouseButtonPressed mousebutton to sfml
@@ -515,8 +516,9 @@ func (mD *MouseButtonDispatcher) CheckMouseButtonSet(eM *EventMouseButton, x, y
 
 //EventMouseMoved : Called once when mouse is clicked, called again when mouse stops clicking
 type EventMouseMoved struct {
-	X int //< X position of the mouse pointer, relative to the left of the owner window
-	Y int //< Y position of the mouse pointer, relative to the top of the owner window
+	X     int      //< X position of the mouse pointer, relative to the left of the owner window
+	Y     int      //< Y position of the mouse pointer, relative to the top of the owner window
+	World Vector2f //< Position of the mouse pointer in the world, seen through the window's camera
 }
 
 //EventMouseMovedToSFML : Converts EventMouseMoved to the sfml version
@@ -526,7 +528,7 @@ func (eM EventMouseMoved) EventMouseMovedToSFML() sf.EventMouseMoved {
 
 //SFEventMouseMovedToEventMouseMoved sfml to Mouse buton
 func SFEventMouseMovedToEventMouseMoved(eM sf.EventMouseMoved) EventMouseMoved {
-	return EventMouseMoved{X: eM.X, Y: eM.Y}
+	return EventMouseMoved{X: eM.X, Y: eM.Y, World: Vector2f{float32(eM.X), float32(eM.Y)}}
 }
 
 //MouseMoveObserver : what is called on mouse move
@@ -573,8 +575,9 @@ func (mH *MouseMovedHandler) notify(eM EventMouseMoved) {
 //EventMouseWheelMoved : Called once when mouse is clicked, called again when mouse stops clicking
 type EventMouseWheelMoved struct {
 	Delta int
-	X     int //< X position of the mouse pointer, relative to the left of the owner window
-	Y     int //< Y position of the mouse pointer, relative to the top of the owner window
+	X     int      //< X position of the mouse pointer, relative to the left of the owner window
+	Y     int      //< Y position of the mouse pointer, relative to the top of the owner window
+	World Vector2f //< Position of the mouse pointer in the world, seen through the window's camera
 }
 
 //EventMouseWheelMovedToSFML : Converts EventMouseMoved to the sfml version
@@ -584,7 +587,7 @@ func (eM EventMouseWheelMoved) EventMouseWheelMovedToSFML() sf.EventMouseWheelMo
 
 //SFEventMouseWheelMovedToEventMouseMoved sfml to Mouse buton
 func SFEventMouseWheelMovedToEventMouseMoved(eM sf.EventMouseWheelMoved) EventMouseWheelMoved {
-	return EventMouseWheelMoved{X: eM.X, Y: eM.Y, Delta: eM.Delta}
+	return EventMouseWheelMoved{X: eM.X, Y: eM.Y, Delta: eM.Delta, World: Vector2f{float32(eM.X), float32(eM.Y)}}
 }
 
 //MouseWheelMoveObserver : what is called on mouse move
@@ -689,6 +692,7 @@ type InputSystem struct {
 	mouseWheelMovedHandler MouseWheelMovedHandler
 	mouseMovedHandler      MouseMovedHandler
 	textEnteredHandler     TextEnteredHandler
+	camera                 *Camera2D
 }
 
 //NewInputSystem : Creates a New Input System
@@ -752,12 +756,41 @@ func (iS *InputSystem) SetMouseButtonReleased(event sf.EventMouseButtonReleased)
 
 //SetMouseMove : Sets the Mouse Move
 func (iS *InputSystem) SetMouseMove(eM sf.EventMouseMoved) {
-	iS.mouseMovedHandler.notify(SFEventMouseMovedToEventMouseMoved(eM))
+	iS.mouseMovedHandler.notify(iS.withWorldMoved(SFEventMouseMovedToEventMouseMoved(eM)))
 }
 
 //SetMouseWheelMove : Sets the Mouse Move
 func (iS *InputSystem) SetMouseWheelMove(eM sf.EventMouseWheelMoved) {
-	iS.mouseWheelMovedHandler.notify(SFEventMouseWheelMovedToEventMouseMoved(eM))
+	iS.mouseWheelMovedHandler.notify(iS.withWorldWheel(SFEventMouseWheelMovedToEventMouseMoved(eM)))
+}
+
+//SetCamera : Camera used to fill in the World position of mouse events.
+//Without one the world position is the pixel position
+func (iS *InputSystem) SetCamera(cam *Camera2D) {
+	iS.camera = cam
+}
+
+//worldPosition : World position under pixel x, y
+func (iS *InputSystem) worldPosition(x, y int) Vector2f {
+	if iS.camera == nil {
+		return Vector2f{X: float32(x), Y: float32(y)}
+	}
+	return iS.camera.WindowToWorld(Vector2i{X: x, Y: y})
+}
+
+func (iS *InputSystem) withWorldMoved(eM EventMouseMoved) EventMouseMoved {
+	eM.World = iS.worldPosition(eM.X, eM.Y)
+	return eM
+}
+
+func (iS *InputSystem) withWorldWheel(eM EventMouseWheelMoved) EventMouseWheelMoved {
+	eM.World = iS.worldPosition(eM.X, eM.Y)
+	return eM
+}
+
+func (iS *InputSystem) withWorldButton(eM EventMouseButtonWrapper) EventMouseButtonWrapper {
+	eM.World = iS.worldPosition(eM.Pos.X, eM.Pos.Y)
+	return eM
 }
 
 //SetTextEntered : Sets text entered
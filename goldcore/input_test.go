@@ -615,7 +615,7 @@ func TestMouseMovedHandler(t *testing.T) {
 	}
 
 	wg.Add(10)
-	mH.notify(EventMouseMoved{5, 5})
+	mH.notify(EventMouseMoved{X: 5, Y: 5})
 	wg.Wait()
 	for _, c := range closures {
 		if !c.State.Equals(Vector2i{5, 5}) {
@@ -648,7 +648,7 @@ func TestMouseWheelMovedHandler(t *testing.T) {
 	}
 
 	wg.Add(10)
-	mH.notify(EventMouseWheelMoved{5, 5, 5})
+	mH.notify(EventMouseWheelMoved{Delta: 5, X: 5, Y: 5})
 	wg.Wait()
 	for _, c := range closures {
 		if !c.State.Equals(Vector2i{5, 5}) {
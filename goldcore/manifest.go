@@ -0,0 +1,102 @@
+package goldcore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"sort"
+)
+
+//ManifestVersion : Version of the manifest layout this engine reads and
+//writes
+const ManifestVersion = 1
+
+//Manifest : What an asset build produced, written by goldpack as
+//manifest.json at the root of its output. Assets are keyed by their source
+//path, or by name for atlases and baked fonts. Files are relative to the
+//root of the output. See ResourceManager.SetManifest
+type Manifest struct {
+	Version int                      `json:"version"`
+	Assets  map[string]ManifestAsset `json:"assets"`
+}
+
+//ManifestAsset : A built asset
+type ManifestAsset struct {
+	Kind ResourceKind `json:"kind"`
+	//File : What to load for the asset
+	File string `json:"file"`
+	//Hashes : Hex SHA-256 of every file built for the asset, File and its
+	//pages, by path
+	Hashes map[string]string `json:"hashes,omitempty"`
+	//Atlas : Name of the atlas an image was packed into, its region is
+	//named after the image's source path
+	Atlas string `json:"atlas,omitempty"`
+}
+
+//NewManifest : Empty manifest
+func NewManifest() *Manifest {
+	return &Manifest{Version: ManifestVersion, Assets: make(map[string]ManifestAsset)}
+}
+
+//ParseManifest : Manifest from JSON
+func ParseManifest(r io.Reader) (*Manifest, error) {
+	man := NewManifest()
+	if err := json.NewDecoder(r).Decode(man); err != nil {
+		return nil, fmt.Errorf("goldcore: parsing manifest: %v", err)
+	}
+	if man.Version != ManifestVersion {
+		return nil, fmt.Errorf("goldcore: manifest version %d, expected %d", man.Version, ManifestVersion)
+	}
+	if man.Assets == nil {
+		man.Assets = make(map[string]ManifestAsset)
+	}
+	return man, nil
+}
+
+//LoadManifestFS : Manifest from the file name of fsys
+func LoadManifestFS(fsys fs.FS, name string) (*Manifest, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ParseManifest(file)
+}
+
+//EncodeManifest : Writes man as indented JSON, keys sorted so builds of
+//the same assets give the same file
+func EncodeManifest(w io.Writer, man *Manifest) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(man)
+}
+
+//Verify : Checks that every file of the manifest is in fsys with the hash
+//it was built with. Lists every file that isn't
+func (man *Manifest) Verify(fsys fs.FS) error {
+	var bad []string
+	for _, asset := range man.Assets {
+		for name, hash := range asset.Hashes {
+			data, err := fs.ReadFile(fsys, name)
+			if err != nil {
+				bad = append(bad, fmt.Sprintf("%s: %v", name, err))
+			} else if HashBytes(data) != hash {
+				bad = append(bad, fmt.Sprintf("%s: hash mismatch", name))
+			}
+		}
+	}
+	if len(bad) == 0 {
+		return nil
+	}
+	sort.Strings(bad)
+	return fmt.Errorf("goldcore: manifest files don't match: %v", bad)
+}
+
+//HashBytes : Hex SHA-256 of data, as used by manifests
+func HashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,353 @@
+package goldcore
+
+import "math"
+
+/////////////////////////////////////
+///		MAT4
+/////////////////////////////////////
+
+//Mat4 : 4x4 matrix for 3D transforms, row major: element (row, col) is at
+//index row*4+col. Vectors are columns, so m.Mul(other) applies other first.
+//The zero value is not the identity, use IdentityMat4
+type Mat4 [16]float32
+
+//IdentityMat4 : Matrix that changes nothing
+func IdentityMat4() Mat4 {
+	return Mat4{
+		1, 0, 0, 0,
+		0, 1, 0, 0,
+		0, 0, 1, 0,
+		0, 0, 0, 1,
+	}
+}
+
+//TranslationMat4 : Moves points by offset
+func TranslationMat4(offset Vector3f) Mat4 {
+	return Mat4{
+		1, 0, 0, offset.X,
+		0, 1, 0, offset.Y,
+		0, 0, 1, offset.Z,
+		0, 0, 0, 1,
+	}
+}
+
+//ScaleMat4 : Scales points away from the origin
+func ScaleMat4(factors Vector3f) Mat4 {
+	return Mat4{
+		factors.X, 0, 0, 0,
+		0, factors.Y, 0, 0,
+		0, 0, factors.Z, 0,
+		0, 0, 0, 1,
+	}
+}
+
+//RotationMat4 : Turns points by radians around axis, counter clockwise when
+//looking down the axis towards the origin
+func RotationMat4(axis Vector3f, radians float32) Mat4 {
+	return QuaternionFromAxisAngle(axis, radians).ToMat4()
+}
+
+//PerspectiveMat4 : OpenGL style projection. fovY is the vertical field of
+//view in radians, aspect is width / height. The camera looks down -Z and
+//depth maps to -1 (near) .. 1 (far)
+func PerspectiveMat4(fovY, aspect, near, far float32) Mat4 {
+	f := float32(1 / math.Tan(float64(fovY)/2))
+	return Mat4{
+		f / aspect, 0, 0, 0,
+		0, f, 0, 0,
+		0, 0, (far + near) / (near - far), 2 * far * near / (near - far),
+		0, 0, -1, 0,
+	}
+}
+
+//OrthographicMat4 : OpenGL style projection mapping the box to -1 .. 1 on
+//every axis. The camera looks down -Z
+func OrthographicMat4(left, right, bottom, top, near, far float32) Mat4 {
+	return Mat4{
+		2 / (right - left), 0, 0, -(right + left) / (right - left),
+		0, 2 / (top - bottom), 0, -(top + bottom) / (top - bottom),
+		0, 0, -2 / (far - near), -(far + near) / (far - near),
+		0, 0, 0, 1,
+	}
+}
+
+//LookAtMat4 : View matrix of a camera at eye looking at target, with up
+//pointing roughly up
+func LookAtMat4(eye, target, up Vector3f) Mat4 {
+	forward := target.Minus(eye).Normalize()
+	side := forward.Cross(up).Normalize()
+	u := side.Cross(forward)
+	return Mat4{
+		side.X, side.Y, side.Z, -side.Dot(eye),
+		u.X, u.Y, u.Z, -u.Dot(eye),
+		-forward.X, -forward.Y, -forward.Z, forward.Dot(eye),
+		0, 0, 0, 1,
+	}
+}
+
+//At : Element at row, col
+func (m Mat4) At(row, col int) float32 {
+	return m[row*4+col]
+}
+
+//Mul : Matrix product m * other, applying other first
+func (m Mat4) Mul(other Mat4) Mat4 {
+	var out Mat4
+	for row := 0; row < 4; row++ {
+		for col := 0; col < 4; col++ {
+			var sum float32
+			for k := 0; k < 4; k++ {
+				sum += m[row*4+k] * other[k*4+col]
+			}
+			out[row*4+col] = sum
+		}
+	}
+	return out
+}
+
+//TransformPoint : Where m moves point p, divided by w so projections work
+func (m Mat4) TransformPoint(p Vector3f) Vector3f {
+	x := m[0]*p.X + m[1]*p.Y + m[2]*p.Z + m[3]
+	y := m[4]*p.X + m[5]*p.Y + m[6]*p.Z + m[7]
+	z := m[8]*p.X + m[9]*p.Y + m[10]*p.Z + m[11]
+	w := m[12]*p.X + m[13]*p.Y + m[14]*p.Z + m[15]
+	if w != 1 && w != 0 {
+		return Vector3f{X: x / w, Y: y / w, Z: z / w}
+	}
+	return Vector3f{X: x, Y: y, Z: z}
+}
+
+//TransformVector : Where m moves direction v. Translation doesn't apply
+func (m Mat4) TransformVector(v Vector3f) Vector3f {
+	return Vector3f{
+		X: m[0]*v.X + m[1]*v.Y + m[2]*v.Z,
+		Y: m[4]*v.X + m[5]*v.Y + m[6]*v.Z,
+		Z: m[8]*v.X + m[9]*v.Y + m[10]*v.Z,
+	}
+}
+
+//Transpose : Rows swapped with columns
+func (m Mat4) Transpose() Mat4 {
+	var out Mat4
+	for row := 0; row < 4; row++ {
+		for col := 0; col < 4; col++ {
+			out[col*4+row] = m[row*4+col]
+		}
+	}
+	return out
+}
+
+//ColumnMajor : Elements column by column, the layout OpenGL uniforms expect
+func (m Mat4) ColumnMajor() [16]float32 {
+	return [16]float32(m.Transpose())
+}
+
+//minors : 2x2 minors of the top two rows (s) and bottom two rows (c),
+//shared by Determinant and Inverse
+func (m Mat4) minors() (s [6]float32, c [6]float32) {
+	s[0] = m[0]*m[5] - m[4]*m[1]
+	s[1] = m[0]*m[6] - m[4]*m[2]
+	s[2] = m[0]*m[7] - m[4]*m[3]
+	s[3] = m[1]*m[6] - m[5]*m[2]
+	s[4] = m[1]*m[7] - m[5]*m[3]
+	s[5] = m[2]*m[7] - m[6]*m[3]
+
+	c[5] = m[10]*m[15] - m[14]*m[11]
+	c[4] = m[9]*m[15] - m[13]*m[11]
+	c[3] = m[9]*m[14] - m[13]*m[10]
+	c[2] = m[8]*m[15] - m[12]*m[11]
+	c[1] = m[8]*m[14] - m[12]*m[10]
+	c[0] = m[8]*m[13] - m[12]*m[9]
+	return s, c
+}
+
+//Determinant : How much volumes are scaled. Negative if m mirrors
+func (m Mat4) Determinant() float32 {
+	return determinant(m.minors())
+}
+
+func determinant(s, c [6]float32) float32 {
+	return s[0]*c[5] - s[1]*c[4] + s[2]*c[3] + s[3]*c[2] - s[4]*c[1] + s[5]*c[0]
+}
+
+//Inverse : Matrix undoing m. False if m can't be undone
+func (m Mat4) Inverse() (Mat4, bool) {
+	s, c := m.minors()
+	det := determinant(s, c)
+	if det == 0 {
+		return IdentityMat4(), false
+	}
+	inv := 1 / det
+	return Mat4{
+		(m[5]*c[5] - m[6]*c[4] + m[7]*c[3]) * inv,
+		(-m[1]*c[5] + m[2]*c[4] - m[3]*c[3]) * inv,
+		(m[13]*s[5] - m[14]*s[4] + m[15]*s[3]) * inv,
+		(-m[9]*s[5] + m[10]*s[4] - m[11]*s[3]) * inv,
+
+		(-m[4]*c[5] + m[6]*c[2] - m[7]*c[1]) * inv,
+		(m[0]*c[5] - m[2]*c[2] + m[3]*c[1]) * inv,
+		(-m[12]*s[5] + m[14]*s[2] - m[15]*s[1]) * inv,
+		(m[8]*s[5] - m[10]*s[2] + m[11]*s[1]) * inv,
+
+		(m[4]*c[4] - m[5]*c[2] + m[7]*c[0]) * inv,
+		(-m[0]*c[4] + m[1]*c[2] - m[3]*c[0]) * inv,
+		(m[12]*s[4] - m[13]*s[2] + m[15]*s[0]) * inv,
+		(-m[8]*s[4] + m[9]*s[2] - m[11]*s[0]) * inv,
+
+		(-m[4]*c[3] + m[5]*c[1] - m[6]*c[0]) * inv,
+		(m[0]*c[3] - m[1]*c[1] + m[2]*c[0]) * inv,
+		(-m[12]*s[3] + m[13]*s[1] - m[14]*s[0]) * inv,
+		(m[8]*s[3] - m[9]*s[1] + m[10]*s[0]) * inv,
+	}, true
+}
+
+//ApproxEquals : True if every element is within epsilon of the other's
+func (m Mat4) ApproxEquals(other Mat4, epsilon float32) bool {
+	for i := range m {
+		if !ApproxEqual(m[i], other[i], epsilon) {
+			return false
+		}
+	}
+	return true
+}
+
+/////////////////////////////////////
+///		QUATERNION
+/////////////////////////////////////
+
+//Quaternion : 3D rotation. Only unit quaternions are rotations, the
+//builders return unit quaternions and Mul keeps them that way give or take
+//rounding; Normalize now and then if you chain many
+type Quaternion struct {
+	X, Y, Z, W float32
+}
+
+//IdentityQuaternion : Rotation that changes nothing
+func IdentityQuaternion() Quaternion {
+	return Quaternion{W: 1}
+}
+
+//QuaternionFromAxisAngle : Rotation by radians around axis, counter
+//clockwise when looking down the axis towards the origin
+func QuaternionFromAxisAngle(axis Vector3f, radians float32) Quaternion {
+	axis = axis.Normalize()
+	sin, cos := math.Sincos(float64(radians) / 2)
+	s := float32(sin)
+	return Quaternion{X: axis.X * s, Y: axis.Y * s, Z: axis.Z * s, W: float32(cos)}
+}
+
+//QuaternionFromEuler : Rotation by pitch around X, then yaw around Y, then
+//roll around Z. Radians
+func QuaternionFromEuler(pitch, yaw, roll float32) Quaternion {
+	x := QuaternionFromAxisAngle(Vector3f{X: 1}, pitch)
+	y := QuaternionFromAxisAngle(Vector3f{Y: 1}, yaw)
+	z := QuaternionFromAxisAngle(Vector3f{Z: 1}, roll)
+	return z.Mul(y).Mul(x)
+}
+
+//Mul : Rotation applying other first, then q
+func (q Quaternion) Mul(other Quaternion) Quaternion {
+	return Quaternion{
+		X: q.W*other.X + q.X*other.W + q.Y*other.Z - q.Z*other.Y,
+		Y: q.W*other.Y - q.X*other.Z + q.Y*other.W + q.Z*other.X,
+		Z: q.W*other.Z + q.X*other.Y - q.Y*other.X + q.Z*other.W,
+		W: q.W*other.W - q.X*other.X - q.Y*other.Y - q.Z*other.Z,
+	}
+}
+
+//Conjugate : Opposite rotation, for unit quaternions
+func (q Quaternion) Conjugate() Quaternion {
+	return Quaternion{X: -q.X, Y: -q.Y, Z: -q.Z, W: q.W}
+}
+
+//Inverse : Quaternion undoing q, even if it isn't unit length. Identity if
+//q is zero
+func (q Quaternion) Inverse() Quaternion {
+	l := q.Dot(q)
+	if l == 0 {
+		return IdentityQuaternion()
+	}
+	c := q.Conjugate()
+	return Quaternion{X: c.X / l, Y: c.Y / l, Z: c.Z / l, W: c.W / l}
+}
+
+//Dot : Sum of the products of the components
+func (q Quaternion) Dot(other Quaternion) float32 {
+	return q.X*other.X + q.Y*other.Y + q.Z*other.Z + q.W*other.W
+}
+
+//Length : Norm of q. 1 for rotations
+func (q Quaternion) Length() float32 {
+	return float32(math.Sqrt(float64(q.Dot(q))))
+}
+
+//Normalize : q scaled to length 1. Identity if q is zero
+func (q Quaternion) Normalize() Quaternion {
+	l := q.Length()
+	if l == 0 {
+		return IdentityQuaternion()
+	}
+	return Quaternion{X: q.X / l, Y: q.Y / l, Z: q.Z / l, W: q.W / l}
+}
+
+//Rotate : v rotated by q
+func (q Quaternion) Rotate(v Vector3f) Vector3f {
+	u := Vector3f{X: q.X, Y: q.Y, Z: q.Z}
+	t := u.Cross(v).Scale(2)
+	return v.Plus(t.Scale(q.W)).Plus(u.Cross(t))
+}
+
+//Slerp : Rotation part way between q (t = 0) and other (t = 1), turning at a
+//constant speed and taking the short way around
+func (q Quaternion) Slerp(other Quaternion, t float32) Quaternion {
+	cos := q.Dot(other)
+	if cos < 0 {
+		other = Quaternion{X: -other.X, Y: -other.Y, Z: -other.Z, W: -other.W}
+		cos = -cos
+	}
+	var a, b float32
+	if cos > 1-Epsilon {
+		a, b = 1-t, t
+	} else {
+		theta := math.Acos(float64(cos))
+		sin := math.Sin(theta)
+		a = float32(math.Sin((1-float64(t))*theta) / sin)
+		b = float32(math.Sin(float64(t)*theta) / sin)
+	}
+	return Quaternion{
+		X: q.X*a + other.X*b,
+		Y: q.Y*a + other.Y*b,
+		Z: q.Z*a + other.Z*b,
+		W: q.W*a + other.W*b,
+	}.Normalize()
+}
+
+//AxisAngle : Axis and angle, in radians, of the rotation. The axis is X for
+//the identity
+func (q Quaternion) AxisAngle() (Vector3f, float32) {
+	q = q.Normalize()
+	s := float32(math.Sqrt(float64(1 - q.W*q.W)))
+	angle := 2 * float32(math.Acos(float64(Clamp(q.W, -1, 1))))
+	if s < Epsilon {
+		return Vector3f{X: 1}, angle
+	}
+	return Vector3f{X: q.X / s, Y: q.Y / s, Z: q.Z / s}, angle
+}
+
+//ToMat4 : Rotation matrix doing the same as q
+func (q Quaternion) ToMat4() Mat4 {
+	x, y, z, w := q.X, q.Y, q.Z, q.W
+	return Mat4{
+		1 - 2*(y*y+z*z), 2 * (x*y - z*w), 2 * (x*z + y*w), 0,
+		2 * (x*y + z*w), 1 - 2*(x*x+z*z), 2 * (y*z - x*w), 0,
+		2 * (x*z - y*w), 2 * (y*z + x*w), 1 - 2*(x*x+y*y), 0,
+		0, 0, 0, 1,
+	}
+}
+
+//ApproxEquals : True if both describe the same rotation within epsilon.
+//q and -q are the same rotation
+func (q Quaternion) ApproxEquals(other Quaternion, epsilon float32) bool {
+	d := q.Dot(other)
+	return ApproxEqual(d, 1, epsilon) || ApproxEqual(d, -1, epsilon)
+}
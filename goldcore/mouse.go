@@ -36,8 +36,10 @@ func IsMouseButtonPressed(button MouseButton) bool {
 // 	position:   New position of the mouse
 // 	relativeTo: Reference window
 func MouseSetPosition(position Vector2i, relativeTo GameWindow) {
-
-	sf.MouseSetPosition(position.ToSFML(), relativeTo.renderWindow)
+	//Headless windows have no cursor to move
+	if w, ok := relativeTo.renderWindow.(*sfmlWindow); ok {
+		sf.MouseSetPosition(position.ToSFML(), w.RenderWindow)
+	}
 }
 
 //MouseGetPosition : Get the current position of the mouse
@@ -47,6 +49,10 @@ func MouseSetPosition(position Vector2i, relativeTo GameWindow) {
 //
 // 	relativeTo: Reference window
 func MouseGetPosition(relativeTo GameWindow) Vector2i {
-	pos := sf.MouseGetPosition(relativeTo.renderWindow)
+	w, ok := relativeTo.renderWindow.(*sfmlWindow)
+	if !ok {
+		return Vector2i{}
+	}
+	pos := sf.MouseGetPosition(w.RenderWindow)
 	return Vector2i{X: pos.X, Y: pos.Y}
 }
@@ -0,0 +1,154 @@
+package noise
+
+import "github.com/Dacode45/OldGoldEngine/goldcore"
+
+//octaveOffset2 : Shift applied to octave i so octaves don't all line up at
+//the origin. Whole numbers, so tiled sources stay tiled
+func octaveOffset2(i int) goldcore.Vector2f {
+	return goldcore.Vector2f{X: float32(i * 17), Y: float32(i * 31)}
+}
+
+//octaveOffset3 : octaveOffset2 in 3D
+func octaveOffset3(i int) goldcore.Vector3f {
+	return goldcore.Vector3f{X: float32(i * 17), Y: float32(i * 31), Z: float32(i * 43)}
+}
+
+//FBM : Fractal Brownian motion. Adds octaves of Source, each Lacunarity
+//times finer and Gain times weaker than the previous one. Result stays in
+//[-1, 1]. Keep Lacunarity a whole number to preserve tiling
+type FBM struct {
+	Source     Source
+	Octaves    int
+	Frequency  float32
+	Lacunarity float32
+	Gain       float32
+}
+
+//NewFBM : octaves of src, starting at frequency 1, each twice as fine and
+//half as strong as the previous one
+func NewFBM(src Source, octaves int) *FBM {
+	return &FBM{Source: src, Octaves: octaves, Frequency: 1, Lacunarity: 2, Gain: 0.5}
+}
+
+//Sample2D : Noise at p, in [-1, 1]
+func (f *FBM) Sample2D(p goldcore.Vector2f) float32 {
+	var sum, norm float32
+	amplitude, frequency := float32(1), f.Frequency
+	for i := 0; i < f.Octaves; i++ {
+		sum += amplitude * f.Source.Sample2D(p.Scale(frequency).Plus(octaveOffset2(i)))
+		norm += amplitude
+		amplitude *= f.Gain
+		frequency *= f.Lacunarity
+	}
+	if norm == 0 {
+		return 0
+	}
+	return sum / norm
+}
+
+//Sample3D : Noise at p, in [-1, 1]
+func (f *FBM) Sample3D(p goldcore.Vector3f) float32 {
+	var sum, norm float32
+	amplitude, frequency := float32(1), f.Frequency
+	for i := 0; i < f.Octaves; i++ {
+		sum += amplitude * f.Source.Sample3D(p.Scale(frequency).Plus(octaveOffset3(i)))
+		norm += amplitude
+		amplitude *= f.Gain
+		frequency *= f.Lacunarity
+	}
+	if norm == 0 {
+		return 0
+	}
+	return sum / norm
+}
+
+//Ridged : Ridged multifractal. Like FBM but folds every octave around 0 so
+//the zero crossings of Source turn into sharp crests, good for mountain
+//ranges. Each octave is weighted by the previous one so detail piles up on
+//the crests. Result stays in [-1, 1]
+type Ridged struct {
+	Source     Source
+	Octaves    int
+	Frequency  float32
+	Lacunarity float32
+	Gain       float32
+}
+
+//NewRidged : Ridged octaves of src, with NewFBM's defaults
+func NewRidged(src Source, octaves int) *Ridged {
+	return &Ridged{Source: src, Octaves: octaves, Frequency: 1, Lacunarity: 2, Gain: 0.5}
+}
+
+//ridge : Folds v so 0 becomes the top of a crest
+func ridge(v, weight float32) (float32, float32) {
+	r := 1 - abs(v)
+	r *= r * weight
+	return r, goldcore.Clamp(r*2, 0, 1)
+}
+
+//Sample2D : Noise at p, in [-1, 1]
+func (r *Ridged) Sample2D(p goldcore.Vector2f) float32 {
+	var sum, norm, octave float32
+	amplitude, frequency, weight := float32(1), r.Frequency, float32(1)
+	for i := 0; i < r.Octaves; i++ {
+		octave, weight = ridge(r.Source.Sample2D(p.Scale(frequency).Plus(octaveOffset2(i))), weight)
+		sum += amplitude * octave
+		norm += amplitude
+		amplitude *= r.Gain
+		frequency *= r.Lacunarity
+	}
+	if norm == 0 {
+		return 0
+	}
+	return sum/norm*2 - 1
+}
+
+//Sample3D : Noise at p, in [-1, 1]
+func (r *Ridged) Sample3D(p goldcore.Vector3f) float32 {
+	var sum, norm, octave float32
+	amplitude, frequency, weight := float32(1), r.Frequency, float32(1)
+	for i := 0; i < r.Octaves; i++ {
+		octave, weight = ridge(r.Source.Sample3D(p.Scale(frequency).Plus(octaveOffset3(i))), weight)
+		sum += amplitude * octave
+		norm += amplitude
+		amplitude *= r.Gain
+		frequency *= r.Lacunarity
+	}
+	if norm == 0 {
+		return 0
+	}
+	return sum/norm*2 - 1
+}
+
+//DomainWarp : Samples Source at a position pushed around by Warp, Strength
+//units at most. Turns blobs into swirls and flows. If Source and Warp both
+//tile with the same period so does the result
+type DomainWarp struct {
+	Source   Source
+	Warp     Source
+	Strength float32
+}
+
+//NewDomainWarp : src warped by warp
+func NewDomainWarp(src, warp Source, strength float32) *DomainWarp {
+	return &DomainWarp{Source: src, Warp: warp, Strength: strength}
+}
+
+//Sample2D : Noise at p, in [-1, 1]
+func (d *DomainWarp) Sample2D(p goldcore.Vector2f) float32 {
+	offset := goldcore.Vector2f{
+		X: d.Warp.Sample2D(p),
+		Y: d.Warp.Sample2D(p.Plus(octaveOffset2(1))),
+	}
+	return d.Source.Sample2D(p.Plus(offset.Scale(d.Strength)))
+}
+
+//Sample3D : Noise at p, in [-1, 1]
+func (d *DomainWarp) Sample3D(p goldcore.Vector3f) float32 {
+	offset := goldcore.Vector3f{
+		X: d.Warp.Sample3D(p),
+		Y: d.Warp.Sample3D(p.Plus(octaveOffset3(1))),
+		Z: d.Warp.Sample3D(p.Plus(octaveOffset3(2))),
+	}
+	return d.Source.Sample3D(p.Plus(offset.Scale(d.Strength)))
+}
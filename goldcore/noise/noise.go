@@ -0,0 +1,128 @@
+//Package noise : Seeded procedural noise for terrain and textures. Every
+//generator is immutable once built, so one generator can be sampled from any
+//number of worker goroutines at once.
+//
+//Samples are in [-1, 1]. Build generators from a game random stream to keep
+//runs replayable:
+//	terrain := noise.NewFBM(noise.NewOpenSimplex(game.Random("terrain").Uint64()), 6)
+package noise
+
+import (
+	"math"
+
+	"github.com/Dacode45/OldGoldEngine/goldcore"
+)
+
+//Source2D : Noise that can be sampled in 2D
+type Source2D interface {
+	Sample2D(p goldcore.Vector2f) float32
+}
+
+//Source3D : Noise that can be sampled in 3D
+type Source3D interface {
+	Sample3D(p goldcore.Vector3f) float32
+}
+
+//Source : Noise that can be sampled in 2D and 3D. Every generator and
+//combinator of the package is one
+type Source interface {
+	Source2D
+	Source3D
+}
+
+//Fill2D : Samples src on a w by h grid, row by row, into dst. Cell (x, y)
+//is sampled at origin + (x, y) * step. dst must hold at least w*h values
+func Fill2D(src Source2D, dst []float32, w, h int, origin goldcore.Vector2f, step float32) {
+	for y := 0; y < h; y++ {
+		row := dst[y*w : (y+1)*w]
+		py := origin.Y + float32(y)*step
+		for x := range row {
+			row[x] = src.Sample2D(goldcore.Vector2f{X: origin.X + float32(x)*step, Y: py})
+		}
+	}
+}
+
+//Fill3D : Samples src on a w by h by d grid, slice by slice then row by row,
+//into dst. dst must hold at least w*h*d values
+func Fill3D(src Source3D, dst []float32, w, h, d int, origin goldcore.Vector3f, step float32) {
+	i := 0
+	for z := 0; z < d; z++ {
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				dst[i] = src.Sample3D(goldcore.Vector3f{
+					X: origin.X + float32(x)*step,
+					Y: origin.Y + float32(y)*step,
+					Z: origin.Z + float32(z)*step,
+				})
+				i++
+			}
+		}
+	}
+}
+
+/////////////////////////////////////
+///		HELPERS
+/////////////////////////////////////
+
+const (
+	primeX = 0x9e3779b97f4a7c15
+	primeY = 0xc2b2ae3d27d4eb4f
+	primeZ = 0x165667b19e3779f9
+)
+
+//mix : Scrambles h so nearby lattice points get unrelated hashes
+func mix(h uint64) uint64 {
+	h = (h ^ (h >> 30)) * 0xbf58476d1ce4e5b9
+	h = (h ^ (h >> 27)) * 0x94d049bb133111eb
+	return h ^ (h >> 31)
+}
+
+//hash2 : Hash of lattice point x, y for seed
+func hash2(seed uint64, x, y int) uint64 {
+	return mix(seed ^ uint64(x)*primeX ^ uint64(y)*primeY)
+}
+
+//hash3 : Hash of lattice point x, y, z for seed
+func hash3(seed uint64, x, y, z int) uint64 {
+	return mix(seed ^ uint64(x)*primeX ^ uint64(y)*primeY ^ uint64(z)*primeZ)
+}
+
+//seedHash : Spreads a user seed over all 64 bits
+func seedHash(seed uint64) uint64 {
+	return mix(seed + primeX)
+}
+
+//unitFloat : h mapped to [0, 1)
+func unitFloat(h uint64) float32 {
+	return float32(h>>40) / (1 << 24)
+}
+
+func floor(x float32) (int, float32) {
+	f := math.Floor(float64(x))
+	return int(f), x - float32(f)
+}
+
+//wrap : i modulo period, always positive. A period of 0 or less doesn't wrap
+func wrap(i, period int) int {
+	if period <= 0 {
+		return i
+	}
+	i %= period
+	if i < 0 {
+		i += period
+	}
+	return i
+}
+
+//fade : Perlin's quintic smoothstep, 6t^5 - 15t^4 + 10t^3
+func fade(t float32) float32 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+func clamp(x float32) float32 {
+	return goldcore.Clamp(x, -1, 1)
+}
+
+func sqrt(x float32) float32 {
+	return float32(math.Sqrt(float64(x)))
+}
@@ -0,0 +1,231 @@
+package noise
+
+import (
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/Dacode45/OldGoldEngine/goldcore"
+)
+
+func sources() map[string]Source {
+	return map[string]Source{
+		"Perlin":      NewPerlin(1),
+		"OpenSimplex": NewOpenSimplex(1),
+		"Worley":      NewWorley(1),
+		"FBM":         NewFBM(NewOpenSimplex(1), 5),
+		"Ridged":      NewRidged(NewPerlin(1), 5),
+		"DomainWarp":  NewDomainWarp(NewPerlin(1), NewOpenSimplex(2), 0.5),
+	}
+}
+
+func samplePoints(n int) ([]goldcore.Vector2f, []goldcore.Vector3f) {
+	r := goldcore.NewRandom(42)
+	p2 := make([]goldcore.Vector2f, n)
+	p3 := make([]goldcore.Vector3f, n)
+	for i := range p2 {
+		p2[i] = goldcore.Vector2f{X: r.FloatRange(-50, 50), Y: r.FloatRange(-50, 50)}
+		p3[i] = goldcore.Vector3f{X: r.FloatRange(-50, 50), Y: r.FloatRange(-50, 50), Z: r.FloatRange(-50, 50)}
+	}
+	return p2, p3
+}
+
+func TestRangeAndSpread(t *testing.T) {
+	p2, p3 := samplePoints(20000)
+	for name, src := range sources() {
+		var sum2, sum3 float64
+		for i := range p2 {
+			v2, v3 := src.Sample2D(p2[i]), src.Sample3D(p3[i])
+			if v2 < -1 || v2 > 1 || v3 < -1 || v3 > 1 {
+				t.Fatalf("%s: sample out of [-1, 1]: %v %v", name, v2, v3)
+			}
+			sum2 += float64(v2 * v2)
+			sum3 += float64(v3 * v3)
+		}
+		rms2, rms3 := math.Sqrt(sum2/float64(len(p2))), math.Sqrt(sum3/float64(len(p3)))
+		if rms2 < 0.1 || rms3 < 0.1 {
+			t.Errorf("%s: samples barely vary, rms %v %v", name, rms2, rms3)
+		}
+	}
+}
+
+func TestContinuity(t *testing.T) {
+	const step = 1e-3
+	p2, p3 := samplePoints(20000)
+	for name, src := range sources() {
+		var worst2, worst3 float32
+		for i := range p2 {
+			d2 := abs(src.Sample2D(p2[i]) - src.Sample2D(p2[i].Plus(goldcore.Vector2f{X: step})))
+			d3 := abs(src.Sample3D(p3[i]) - src.Sample3D(p3[i].Plus(goldcore.Vector3f{Z: step})))
+			if d2 > worst2 {
+				worst2 = d2
+			}
+			if d3 > worst3 {
+				worst3 = d3
+			}
+		}
+		//Steepest slopes are well under 20 per unit, a seam would show as a jump
+		if worst2 > 20*step || worst3 > 20*step {
+			t.Errorf("%s: jump of %v / %v over a %v step", name, worst2, worst3, step)
+		}
+	}
+}
+
+func TestDeterminism(t *testing.T) {
+	p := goldcore.Vector2f{X: 3.7, Y: -12.1}
+	q := goldcore.Vector3f{X: 3.7, Y: -12.1, Z: 0.4}
+	for name, build := range map[string]func(uint64) Source{
+		"Perlin":      func(s uint64) Source { return NewPerlin(s) },
+		"OpenSimplex": func(s uint64) Source { return NewOpenSimplex(s) },
+		"Worley":      func(s uint64) Source { return NewWorley(s) },
+	} {
+		a, b, c := build(9), build(9), build(10)
+		if a.Sample2D(p) != b.Sample2D(p) || a.Sample3D(q) != b.Sample3D(q) {
+			t.Errorf("%s: same seed should give the same noise", name)
+		}
+		if a.Sample2D(p) == c.Sample2D(p) && a.Sample3D(q) == c.Sample3D(q) {
+			t.Errorf("%s: different seeds should give different noise", name)
+		}
+	}
+}
+
+func TestTiling(t *testing.T) {
+	p2, p3 := samplePoints(1000)
+	tiled := map[string]Source{
+		"Perlin": NewPerlin(5).Tiled(8, 4, 2),
+		"Worley": NewWorley(5).Tiled(8, 4, 2),
+		"FBM":    NewFBM(NewPerlin(5).Tiled(8, 4, 2), 4),
+	}
+	period2 := goldcore.Vector2f{X: 8, Y: 4}
+	period3 := goldcore.Vector3f{X: 8, Y: 4, Z: 2}
+	for name, src := range tiled {
+		for i := range p2 {
+			if a, b := src.Sample2D(p2[i]), src.Sample2D(p2[i].Plus(period2)); !goldcore.ApproxEqual(a, b, 1e-3) {
+				t.Fatalf("%s: 2D doesn't tile at %v: %v vs %v", name, p2[i], a, b)
+			}
+			if a, b := src.Sample3D(p3[i]), src.Sample3D(p3[i].Plus(period3)); !goldcore.ApproxEqual(a, b, 1e-3) {
+				t.Fatalf("%s: 3D doesn't tile at %v: %v vs %v", name, p3[i], a, b)
+			}
+		}
+	}
+
+	generic := NewTiled2D(NewDomainWarp(NewOpenSimplex(5), NewOpenSimplex(6), 0.4), goldcore.Vector2f{X: 10, Y: 6})
+	for i := range p2 {
+		if a, b := generic.Sample2D(p2[i]), generic.Sample2D(p2[i].Plus(goldcore.Vector2f{X: 10, Y: -6})); !goldcore.ApproxEqual(a, b, 1e-3) {
+			t.Fatalf("Tiled2D doesn't tile at %v: %v vs %v", p2[i], a, b)
+		}
+	}
+	//No seam on the tile edge
+	for y := float32(0); y < 6; y += 0.01 {
+		a := generic.Sample2D(goldcore.Vector2f{X: 10 - 1e-3, Y: y})
+		b := generic.Sample2D(goldcore.Vector2f{X: 10, Y: y})
+		if abs(a-b) > 0.05 {
+			t.Fatalf("Tiled2D seam at y=%v: %v vs %v", y, a, b)
+		}
+	}
+}
+
+func TestWorley(t *testing.T) {
+	w := NewWorley(3)
+	p2, p3 := samplePoints(2000)
+	for i := range p2 {
+		if f1, f2 := w.Cells2D(p2[i]); f1 > f2 || f1 < 0 {
+			t.Fatalf("expected 0 <= f1 <= f2, got %v %v", f1, f2)
+		}
+		if f1, f2 := w.Cells3D(p3[i]); f1 > f2 || f1 < 0 {
+			t.Fatalf("expected 0 <= f1 <= f2, got %v %v", f1, f2)
+		}
+	}
+
+	grid := NewWorley(3)
+	grid.Jitter = 0
+	if f1, _ := grid.Cells2D(goldcore.Vector2f{X: 4.5, Y: -1.5}); f1 != 0 {
+		t.Errorf("without jitter feature points sit on cell centers, got f1 %v", f1)
+	}
+	grid.Metric = Chebyshev
+	if f1, f2 := grid.Cells2D(goldcore.Vector2f{X: 4.5, Y: -1.5}); f2 != 1 || f1 != 0 {
+		t.Errorf("Chebyshev on a grid: expected 0 1, got %v %v", f1, f2)
+	}
+	grid.Metric = Manhattan
+	grid.Return = WorleyF2MinusF1
+	if got := grid.Sample2D(goldcore.Vector2f{X: 5, Y: -1.5}); got != -1 {
+		t.Errorf("on a cell border F2-F1 is 0, expected -1, got %v", got)
+	}
+}
+
+func TestFill(t *testing.T) {
+	src := NewFBM(NewPerlin(8), 4)
+	const w, h = 32, 16
+	origin := goldcore.Vector2f{X: -3, Y: 7}
+	dst := make([]float32, w*h)
+	Fill2D(src, dst, w, h, origin, 0.25)
+	if got, e := dst[5*w+9], src.Sample2D(goldcore.Vector2f{X: -3 + 9*0.25, Y: 7 + 5*0.25}); got != e {
+		t.Errorf("Fill2D cell (9, 5): expected %v, got %v", e, got)
+	}
+
+	dst3 := make([]float32, 4*3*2)
+	Fill3D(src, dst3, 4, 3, 2, goldcore.Vector3f{}, 0.5)
+	if got, e := dst3[1*12+2*4+3], src.Sample3D(goldcore.Vector3f{X: 1.5, Y: 1, Z: 0.5}); got != e {
+		t.Errorf("Fill3D cell (3, 2, 1): expected %v, got %v", e, got)
+	}
+
+	//One generator shared by workers filling chunks, run with -race
+	chunks := make([][]float32, 8)
+	var wg sync.WaitGroup
+	for i := range chunks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			chunks[i] = make([]float32, w*h)
+			Fill2D(src, chunks[i], w, h, goldcore.Vector2f{X: float32(i * w)}, 1)
+		}(i)
+	}
+	wg.Wait()
+	for i, chunk := range chunks {
+		if e := src.Sample2D(goldcore.Vector2f{X: float32(i*w + 3), Y: 2}); chunk[2*w+3] != e {
+			t.Errorf("chunk %d: expected %v, got %v", i, e, chunk[2*w+3])
+		}
+	}
+}
+
+var sink float32
+
+func benchmark2D(b *testing.B, src Source2D) {
+	p := goldcore.Vector2f{X: 0.37, Y: 12.9}
+	for i := 0; i < b.N; i++ {
+		p.X += 0.013
+		sink += src.Sample2D(p)
+	}
+}
+
+func benchmark3D(b *testing.B, src Source3D) {
+	p := goldcore.Vector3f{X: 0.37, Y: 12.9, Z: -4.2}
+	for i := 0; i < b.N; i++ {
+		p.X += 0.013
+		sink += src.Sample3D(p)
+	}
+}
+
+func BenchmarkPerlin2D(b *testing.B)      { benchmark2D(b, NewPerlin(1)) }
+func BenchmarkPerlin3D(b *testing.B)      { benchmark3D(b, NewPerlin(1)) }
+func BenchmarkOpenSimplex2D(b *testing.B) { benchmark2D(b, NewOpenSimplex(1)) }
+func BenchmarkOpenSimplex3D(b *testing.B) { benchmark3D(b, NewOpenSimplex(1)) }
+func BenchmarkWorley2D(b *testing.B)      { benchmark2D(b, NewWorley(1)) }
+func BenchmarkWorley3D(b *testing.B)      { benchmark3D(b, NewWorley(1)) }
+func BenchmarkFBM2D(b *testing.B)         { benchmark2D(b, NewFBM(NewOpenSimplex(1), 6)) }
+func BenchmarkDomainWarp2D(b *testing.B) {
+	benchmark2D(b, NewDomainWarp(NewOpenSimplex(1), NewOpenSimplex(2), 0.5))
+}
+
+//BenchmarkFillChunkParallel : 64x64 terrain chunks generated by all cores
+func BenchmarkFillChunkParallel(b *testing.B) {
+	src := NewFBM(NewOpenSimplex(1), 6)
+	b.RunParallel(func(pb *testing.PB) {
+		chunk := make([]float32, 64*64)
+		x := float32(0)
+		for pb.Next() {
+			Fill2D(src, chunk, 64, 64, goldcore.Vector2f{X: x}, 1.0/16)
+			x += 4
+		}
+	})
+}
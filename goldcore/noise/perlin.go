@@ -0,0 +1,81 @@
+package noise
+
+import (
+	"math"
+
+	"github.com/Dacode45/OldGoldEngine/goldcore"
+)
+
+//Perlin : Ken Perlin's improved gradient noise. One feature per lattice
+//cell, so sample at a frequency around 1/cellSize
+type Perlin struct {
+	seed   uint64
+	period [3]int
+}
+
+//gradients2 : Unit gradients for 2D Perlin noise
+var gradients2 = [8]goldcore.Vector2f{
+	{X: 1}, {X: -1}, {Y: 1}, {Y: -1},
+	{X: math.Sqrt2 / 2, Y: math.Sqrt2 / 2}, {X: -math.Sqrt2 / 2, Y: math.Sqrt2 / 2},
+	{X: math.Sqrt2 / 2, Y: -math.Sqrt2 / 2}, {X: -math.Sqrt2 / 2, Y: -math.Sqrt2 / 2},
+}
+
+//gradients3 : Cube edge gradients from improved Perlin noise
+var gradients3 = [12]goldcore.Vector3f{
+	{X: 1, Y: 1}, {X: -1, Y: 1}, {X: 1, Y: -1}, {X: -1, Y: -1},
+	{X: 1, Z: 1}, {X: -1, Z: 1}, {X: 1, Z: -1}, {X: -1, Z: -1},
+	{Y: 1, Z: 1}, {Y: -1, Z: 1}, {Y: 1, Z: -1}, {Y: -1, Z: -1},
+}
+
+const (
+	//perlin2Scale : 2D Perlin with unit gradients peaks at sqrt(1/2)
+	perlin2Scale = math.Sqrt2
+	//perlin3Scale : 3D Perlin with edge gradients already peaks at about 1
+	perlin3Scale = 1
+)
+
+//NewPerlin : Perlin noise for seed
+func NewPerlin(seed uint64) *Perlin {
+	return &Perlin{seed: seedHash(seed)}
+}
+
+//Tiled : Copy of the noise repeating every x, y and z lattice cells. 0 means
+//no repeat on that axis
+func (n *Perlin) Tiled(x, y, z int) *Perlin {
+	return &Perlin{seed: n.seed, period: [3]int{x, y, z}}
+}
+
+func (n *Perlin) grad2(x, y int, dx, dy float32) float32 {
+	g := gradients2[hash2(n.seed, wrap(x, n.period[0]), wrap(y, n.period[1]))&7]
+	return g.X*dx + g.Y*dy
+}
+
+func (n *Perlin) grad3(x, y, z int, dx, dy, dz float32) float32 {
+	h := hash3(n.seed, wrap(x, n.period[0]), wrap(y, n.period[1]), wrap(z, n.period[2]))
+	g := gradients3[h%12]
+	return g.X*dx + g.Y*dy + g.Z*dz
+}
+
+//Sample2D : Noise at p, in [-1, 1]
+func (n *Perlin) Sample2D(p goldcore.Vector2f) float32 {
+	x, fx := floor(p.X)
+	y, fy := floor(p.Y)
+	u, v := fade(fx), fade(fy)
+	a := goldcore.Lerp(n.grad2(x, y, fx, fy), n.grad2(x+1, y, fx-1, fy), u)
+	b := goldcore.Lerp(n.grad2(x, y+1, fx, fy-1), n.grad2(x+1, y+1, fx-1, fy-1), u)
+	return clamp(goldcore.Lerp(a, b, v) * perlin2Scale)
+}
+
+//Sample3D : Noise at p, in [-1, 1]
+func (n *Perlin) Sample3D(p goldcore.Vector3f) float32 {
+	x, fx := floor(p.X)
+	y, fy := floor(p.Y)
+	z, fz := floor(p.Z)
+	u, v, w := fade(fx), fade(fy), fade(fz)
+	lerp := goldcore.Lerp
+	a := lerp(n.grad3(x, y, z, fx, fy, fz), n.grad3(x+1, y, z, fx-1, fy, fz), u)
+	b := lerp(n.grad3(x, y+1, z, fx, fy-1, fz), n.grad3(x+1, y+1, z, fx-1, fy-1, fz), u)
+	c := lerp(n.grad3(x, y, z+1, fx, fy, fz-1), n.grad3(x+1, y, z+1, fx-1, fy, fz-1), u)
+	d := lerp(n.grad3(x, y+1, z+1, fx, fy-1, fz-1), n.grad3(x+1, y+1, z+1, fx-1, fy-1, fz-1), u)
+	return clamp(lerp(lerp(a, b, v), lerp(c, d, v), w) * perlin3Scale)
+}
@@ -0,0 +1,173 @@
+package noise
+
+import (
+	"math"
+
+	"github.com/Dacode45/OldGoldEngine/goldcore"
+)
+
+//OpenSimplex : OpenSimplex2 noise. Smoother and less grid aligned than
+//Perlin, and cheaper in 3D. Features are about as big as Perlin's
+type OpenSimplex struct {
+	seed uint64
+}
+
+const (
+	skew2D   = 0.366025403784439    //(sqrt(3) - 1) / 2
+	unskew2D = -0.21132486540518713 //(1 / sqrt(3) - 1) / 2
+	radius2D = 0.5
+	radius3D = 0.5
+	//rotate3D : Turns the cubic lattice so a main diagonal points along Z,
+	//hiding its axis alignment
+	rotate3D = 2.0 / 3
+)
+
+//Normalizers bringing the peaks of each sum of kernels just under 1,
+//measured over millions of samples
+const (
+	simplex2Scale = 99.83685446303647
+	simplex3Scale = 107
+)
+
+//simplexGradients2 : 24 unit gradients, evenly spread
+var simplexGradients2 = func() (g [24]goldcore.Vector2f) {
+	for i := range g {
+		angle := (float64(i) + 0.5) * 2 * math.Pi / 24
+		g[i] = goldcore.Vector2f{X: float32(math.Cos(angle)), Y: float32(math.Sin(angle))}
+	}
+	return g
+}()
+
+//simplexGradients3 : Cube edge gradients, normalized
+var simplexGradients3 = func() (g [12]goldcore.Vector3f) {
+	for i, v := range gradients3 {
+		g[i] = v.Normalize()
+	}
+	return g
+}()
+
+//NewOpenSimplex : OpenSimplex2 noise for seed
+func NewOpenSimplex(seed uint64) *OpenSimplex {
+	return &OpenSimplex{seed: seedHash(seed)}
+}
+
+func (n *OpenSimplex) grad2(x, y int, dx, dy float32) float32 {
+	g := simplexGradients2[hash2(n.seed, x, y)%24]
+	return g.X*dx + g.Y*dy
+}
+
+func (n *OpenSimplex) grad3(seed uint64, x, y, z int, dx, dy, dz float32) float32 {
+	g := simplexGradients3[hash3(seed, x, y, z)%12]
+	return g.X*dx + g.Y*dy + g.Z*dz
+}
+
+//Sample2D : Noise at p, in [-1, 1]
+func (n *OpenSimplex) Sample2D(p goldcore.Vector2f) float32 {
+	//Skew onto the triangular lattice
+	s := skew2D * (p.X + p.Y)
+	xsb, xi := floor(p.X + s)
+	ysb, yi := floor(p.Y + s)
+
+	t := (xi + yi) * unskew2D
+	dx0, dy0 := xi+t, yi+t
+
+	var value float32
+	a0 := radius2D - dx0*dx0 - dy0*dy0
+	if a0 > 0 {
+		value = a0 * a0 * a0 * a0 * n.grad2(xsb, ysb, dx0, dy0)
+	}
+
+	//Opposite corner, a1 derived from a0 to save work
+	a1 := (2*(1+2*unskew2D)*(1/unskew2D+2))*t + (-2*(1+2*unskew2D)*(1+2*unskew2D) + a0)
+	if a1 > 0 {
+		dx1, dy1 := dx0-(1+2*unskew2D), dy0-(1+2*unskew2D)
+		value += a1 * a1 * a1 * a1 * n.grad2(xsb+1, ysb+1, dx1, dy1)
+	}
+
+	//Third corner depends on which triangle of the cell p is in
+	var dx2, dy2 float32
+	var x2, y2 int
+	if dy0 > dx0 {
+		dx2, dy2 = dx0-unskew2D, dy0-(unskew2D+1)
+		x2, y2 = xsb, ysb+1
+	} else {
+		dx2, dy2 = dx0-(unskew2D+1), dy0-unskew2D
+		x2, y2 = xsb+1, ysb
+	}
+	if a2 := radius2D - dx2*dx2 - dy2*dy2; a2 > 0 {
+		value += a2 * a2 * a2 * a2 * n.grad2(x2, y2, dx2, dy2)
+	}
+	return clamp(value * simplex2Scale)
+}
+
+//Sample3D : Noise at p, in [-1, 1]
+func (n *OpenSimplex) Sample3D(p goldcore.Vector3f) float32 {
+	r := rotate3D * (p.X + p.Y + p.Z)
+	xr, yr, zr := r-p.X, r-p.Y, r-p.Z
+
+	//Closest point of the first cubic lattice. The second lattice is the
+	//first shifted by half a cell, together they make a BCC lattice
+	xrb, yrb, zrb := round(xr), round(yr), round(zr)
+	xri, yri, zri := xr-float32(xrb), yr-float32(yrb), zr-float32(zrb)
+
+	//Direction of the nearest neighbor on each axis, -1 or 1
+	xSign, ySign, zSign := negSign(xri), negSign(yri), negSign(zri)
+	ax0, ay0, az0 := -float32(xSign)*xri, -float32(ySign)*yri, -float32(zSign)*zri
+
+	seed := n.seed
+	var value float32
+	a := radius3D - xri*xri - yri*yri - zri*zri
+	for lattice := 0; ; lattice++ {
+		if a > 0 {
+			value += a * a * a * a * n.grad3(seed, xrb, yrb, zrb, xri, yri, zri)
+		}
+
+		//Closest neighbor of that point on the same lattice
+		if ax0 >= ay0 && ax0 >= az0 {
+			if b := a + ax0 + ax0 - 1; b > 0 {
+				value += b * b * b * b * n.grad3(seed, xrb-xSign, yrb, zrb, xri+float32(xSign), yri, zri)
+			}
+		} else if ay0 > ax0 && ay0 >= az0 {
+			if b := a + ay0 + ay0 - 1; b > 0 {
+				value += b * b * b * b * n.grad3(seed, xrb, yrb-ySign, zrb, xri, yri+float32(ySign), zri)
+			}
+		} else {
+			if b := a + az0 + az0 - 1; b > 0 {
+				value += b * b * b * b * n.grad3(seed, xrb, yrb, zrb-zSign, xri, yri, zri+float32(zSign))
+			}
+		}
+
+		if lattice == 1 {
+			break
+		}
+
+		//Move to the closest point of the second lattice
+		ax0, ay0, az0 = 0.5-ax0, 0.5-ay0, 0.5-az0
+		xri, yri, zri = float32(xSign)*ax0, float32(ySign)*ay0, float32(zSign)*az0
+		a += (0.75 - ax0) - (ay0 + az0)
+		if xSign < 0 {
+			xrb++
+		}
+		if ySign < 0 {
+			yrb++
+		}
+		if zSign < 0 {
+			zrb++
+		}
+		xSign, ySign, zSign = -xSign, -ySign, -zSign
+		seed = ^seed
+	}
+	return clamp(value * simplex3Scale)
+}
+
+func round(x float32) int {
+	return int(math.Floor(float64(x) + 0.5))
+}
+
+//negSign : -1 if x is positive, 1 otherwise
+func negSign(x float32) int {
+	if x > 0 {
+		return -1
+	}
+	return 1
+}
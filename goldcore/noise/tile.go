@@ -0,0 +1,45 @@
+package noise
+
+import "github.com/Dacode45/OldGoldEngine/goldcore"
+
+//Tiled2D : Makes any 2D source repeat every Size units, by blending it with
+//copies of itself shifted by one period. Perlin and Worley have exact tiling
+//through their Tiled method; Tiled2D works with everything, OpenSimplex and
+//warped noise included, but costs four samples and flattens the contrast a
+//little in the middle of the tile
+type Tiled2D struct {
+	Source Source2D
+	Size   goldcore.Vector2f
+}
+
+//NewTiled2D : src repeating every size units
+func NewTiled2D(src Source2D, size goldcore.Vector2f) *Tiled2D {
+	return &Tiled2D{Source: src, Size: size}
+}
+
+//Sample2D : Noise at p, in [-1, 1]
+func (t *Tiled2D) Sample2D(p goldcore.Vector2f) float32 {
+	w, h := t.Size.X, t.Size.Y
+	//Position inside the tile, and how far along it
+	x := p.X - w*float32(floorInt(p.X/w))
+	y := p.Y - h*float32(floorInt(p.Y/h))
+	u, v := x/w, y/h
+
+	a := t.Source.Sample2D(goldcore.Vector2f{X: x, Y: y})
+	b := t.Source.Sample2D(goldcore.Vector2f{X: x - w, Y: y})
+	c := t.Source.Sample2D(goldcore.Vector2f{X: x, Y: y - h})
+	d := t.Source.Sample2D(goldcore.Vector2f{X: x - w, Y: y - h})
+	lerp := goldcore.Lerp
+	blended := lerp(lerp(a, b, u), lerp(c, d, u), v)
+
+	//Blending uncorrelated values shrinks them the most in the middle,
+	//undo it so the tile keeps an even contrast
+	wu, wv := 1-u, 1-v
+	spread := sqrt((wu*wu + u*u) * (wv*wv + v*v))
+	return clamp(blended / spread)
+}
+
+func floorInt(x float32) int {
+	i, _ := floor(x)
+	return i
+}
@@ -0,0 +1,158 @@
+package noise
+
+import (
+	"math"
+
+	"github.com/Dacode45/OldGoldEngine/goldcore"
+)
+
+//WorleyReturn : Which distance Worley noise samples return
+type WorleyReturn int
+
+const (
+	//WorleyF1 : Distance to the closest feature point. Round cells
+	WorleyF1 WorleyReturn = iota
+	//WorleyF2 : Distance to the second closest feature point
+	WorleyF2
+	//WorleyF2MinusF1 : Difference of both. Thin cracks along cell borders
+	WorleyF2MinusF1
+)
+
+//DistanceMetric : How Worley noise measures distances
+type DistanceMetric int
+
+const (
+	//Euclidean : Straight line distance
+	Euclidean DistanceMetric = iota
+	//Manhattan : Sum of the distances along each axis. Diamond cells
+	Manhattan
+	//Chebyshev : Largest distance along an axis. Square cells
+	Chebyshev
+)
+
+//Worley : Cellular noise. Every lattice cell holds one feature point and the
+//noise is the distance to the closest ones
+type Worley struct {
+	Return WorleyReturn
+	Metric DistanceMetric
+	//Jitter : How far feature points stray from the cell centers, 0 makes
+	//a regular grid and 1, the default, puts them anywhere in their cell
+	Jitter float32
+
+	seed   uint64
+	period [3]int
+}
+
+//NewWorley : F1 Euclidean Worley noise for seed
+func NewWorley(seed uint64) *Worley {
+	return &Worley{Return: WorleyF1, Metric: Euclidean, Jitter: 1, seed: seedHash(seed)}
+}
+
+//Tiled : Copy of the noise repeating every x, y and z cells. 0 means no
+//repeat on that axis
+func (n *Worley) Tiled(x, y, z int) *Worley {
+	tiled := *n
+	tiled.period = [3]int{x, y, z}
+	return &tiled
+}
+
+func (n *Worley) distance(dx, dy, dz float32) float32 {
+	switch n.Metric {
+	case Manhattan:
+		return abs(dx) + abs(dy) + abs(dz)
+	case Chebyshev:
+		d := abs(dx)
+		if ay := abs(dy); ay > d {
+			d = ay
+		}
+		if az := abs(dz); az > d {
+			d = az
+		}
+		return d
+	default:
+		return sqrt(dx*dx + dy*dy + dz*dz)
+	}
+}
+
+//Cells2D : Distances from p to the closest (f1) and second closest (f2)
+//feature points
+func (n *Worley) Cells2D(p goldcore.Vector2f) (f1, f2 float32) {
+	x, fx := floor(p.X)
+	y, fy := floor(p.Y)
+	jitter := goldcore.Clamp(n.Jitter, 0, 1)
+	f1, f2 = math.MaxFloat32, math.MaxFloat32
+	for cy := -1; cy <= 1; cy++ {
+		for cx := -1; cx <= 1; cx++ {
+			h := hash2(n.seed, wrap(x+cx, n.period[0]), wrap(y+cy, n.period[1]))
+			px := float32(cx) + 0.5 + jitter*(unitFloat(h)-0.5)
+			py := float32(cy) + 0.5 + jitter*(unitFloat(h<<24)-0.5)
+			f1, f2 = closest(f1, f2, n.distance(px-fx, py-fy, 0))
+		}
+	}
+	return f1, f2
+}
+
+//Cells3D : Distances from p to the closest (f1) and second closest (f2)
+//feature points
+func (n *Worley) Cells3D(p goldcore.Vector3f) (f1, f2 float32) {
+	x, fx := floor(p.X)
+	y, fy := floor(p.Y)
+	z, fz := floor(p.Z)
+	jitter := goldcore.Clamp(n.Jitter, 0, 1)
+	f1, f2 = math.MaxFloat32, math.MaxFloat32
+	for cz := -1; cz <= 1; cz++ {
+		for cy := -1; cy <= 1; cy++ {
+			for cx := -1; cx <= 1; cx++ {
+				h := hash3(n.seed, wrap(x+cx, n.period[0]), wrap(y+cy, n.period[1]), wrap(z+cz, n.period[2]))
+				px := float32(cx) + 0.5 + jitter*(unitFloat(h)-0.5)
+				py := float32(cy) + 0.5 + jitter*(unitFloat(h<<24)-0.5)
+				pz := float32(cz) + 0.5 + jitter*(unitFloat(mix(h))-0.5)
+				f1, f2 = closest(f1, f2, n.distance(px-fx, py-fy, pz-fz))
+			}
+		}
+	}
+	return f1, f2
+}
+
+//Sample2D : Selected distance mapped to [-1, 1]: 0 gives -1, a cell's
+//width or more gives 1
+func (n *Worley) Sample2D(p goldcore.Vector2f) float32 {
+	return n.pick(n.Cells2D(p))
+}
+
+//Sample3D : Selected distance mapped to [-1, 1]: 0 gives -1, a cell's
+//width or more gives 1
+func (n *Worley) Sample3D(p goldcore.Vector3f) float32 {
+	return n.pick(n.Cells3D(p))
+}
+
+func (n *Worley) pick(f1, f2 float32) float32 {
+	var d float32
+	switch n.Return {
+	case WorleyF2:
+		d = f2
+	case WorleyF2MinusF1:
+		d = f2 - f1
+	default:
+		d = f1
+	}
+	return clamp(d*2 - 1)
+}
+
+//closest : Keeps the two smallest distances
+func closest(f1, f2, d float32) (float32, float32) {
+	if d < f1 {
+		return d, f1
+	}
+	if d < f2 {
+		return f1, d
+	}
+	return f1, f2
+}
+
+func abs(x float32) float32 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,655 @@
+package goldcore
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"sync"
+
+	sf "github.com/manyminds/gosfml"
+)
+
+/////////////////////////////////////
+///		POST EFFECT
+/////////////////////////////////////
+
+//PostReference : CPU version of a post effect. Reads every pixel of src and
+//writes every pixel of dst, both the size of the frame. Used by headless
+//windows, so a chain can be checked without a GPU
+type PostReference func(dst, src *image.RGBA, effect *PostEffect)
+
+//PostEffect : Full screen pass over the frame. On the GPU its material's
+//fragment shader runs over a texture of the frame bound to the sampler2D
+//uniform "texture". If the shader declares a vec2 "textureSize" it gets the
+//frame's size in pixels. Headless windows run the reference instead
+type PostEffect struct {
+	name      string
+	material  *Material
+	reference PostReference
+
+	mutex   sync.Mutex
+	enabled bool
+}
+
+//NewPostEffect : Enabled effect drawing with shader on the GPU and with
+//reference on the CPU. A nil reference leaves headless frames untouched
+func NewPostEffect(name string, shader *Shader, reference PostReference) *PostEffect {
+	return &PostEffect{name: name, material: NewMaterial(shader), reference: reference, enabled: true}
+}
+
+//Name : Name of the effect in its chain
+func (effect *PostEffect) Name() string {
+	return effect.name
+}
+
+//Material : Uniform values of the effect
+func (effect *PostEffect) Material() *Material {
+	return effect.material
+}
+
+//Enabled : Checks whether the effect runs
+func (effect *PostEffect) Enabled() bool {
+	effect.mutex.Lock()
+	defer effect.mutex.Unlock()
+	return effect.enabled
+}
+
+//SetEnabled : Turns the effect on or off, from the next frame on
+func (effect *PostEffect) SetEnabled(enabled bool) {
+	effect.mutex.Lock()
+	effect.enabled = enabled
+	effect.mutex.Unlock()
+}
+
+//SetParameter : Sets a uniform of the effect. value is a float32, Vector2f,
+//Vector3f, Color or *Texture matching the uniform's type
+func (effect *PostEffect) SetParameter(name string, value interface{}) error {
+	return effect.material.set(name, value)
+}
+
+//SetFloat : Sets a float uniform
+func (effect *PostEffect) SetFloat(name string, x float32) error {
+	return effect.material.SetFloat(name, x)
+}
+
+//SetVector2 : Sets a vec2 uniform
+func (effect *PostEffect) SetVector2(name string, v Vector2f) error {
+	return effect.material.SetVector2(name, v)
+}
+
+//SetColor : Sets a vec4 uniform, or vec3 leaving alpha out
+func (effect *PostEffect) SetColor(name string, c Color) error {
+	return effect.material.SetColor(name, c)
+}
+
+//Float : Value of a float uniform, 0 if it isn't set
+func (effect *PostEffect) Float(name string) float32 {
+	v, _ := effect.material.Uniform(name)
+	x, _ := v.(float32)
+	return x
+}
+
+//Vector2 : Value of a vec2 uniform, zero if it isn't set
+func (effect *PostEffect) Vector2(name string) Vector2f {
+	v, _ := effect.material.Uniform(name)
+	vec, _ := v.(Vector2f)
+	return vec
+}
+
+//Color : Value of a vec3 or vec4 uniform, transparent if it isn't set
+func (effect *PostEffect) Color(name string) Color {
+	v, _ := effect.material.Uniform(name)
+	c, _ := v.(Color)
+	return c
+}
+
+//toSFML : The effect's shader reading the current texture, nil if shaders
+//can't be used. Must be called on the render thread
+func (effect *PostEffect) toSFML(size sf.Vector2u) *sf.Shader {
+	s := effect.material.toSFML()
+	if s == nil {
+		return nil
+	}
+	s.SetCurrentTextureParameter("texture")
+	if t, _ := effect.material.Shader.UniformType("textureSize"); t == UniformVec2 {
+		s.SetFloatParameter("textureSize", float32(size.X), float32(size.Y))
+	}
+	return s
+}
+
+/////////////////////////////////////
+///		POST CHAIN
+/////////////////////////////////////
+
+//PostChain : Ordered effects applied to every frame of a window, the first
+//one reading what was drawn and each next one the result of the previous.
+//Changes show from the next frame on
+type PostChain struct {
+	mutex   sync.Mutex
+	effects []*PostEffect
+}
+
+//NewPostChain : Chain without effects
+func NewPostChain() *PostChain {
+	return &PostChain{}
+}
+
+//Add : Appends effect to the end of the chain. Names must be unique
+func (chain *PostChain) Add(effect *PostEffect) error {
+	chain.mutex.Lock()
+	defer chain.mutex.Unlock()
+	if chain.index(effect.name) >= 0 {
+		return fmt.Errorf("goldcore: post effect %s already in the chain", effect.name)
+	}
+	chain.effects = append(chain.effects, effect)
+	return nil
+}
+
+//Remove : Takes the effect name out of the chain, nil if it isn't there
+func (chain *PostChain) Remove(name string) *PostEffect {
+	chain.mutex.Lock()
+	defer chain.mutex.Unlock()
+	i := chain.index(name)
+	if i < 0 {
+		return nil
+	}
+	effect := chain.effects[i]
+	chain.effects = append(chain.effects[:i], chain.effects[i+1:]...)
+	return effect
+}
+
+//Effect : The effect called name, nil if it isn't in the chain
+func (chain *PostChain) Effect(name string) *PostEffect {
+	chain.mutex.Lock()
+	defer chain.mutex.Unlock()
+	if i := chain.index(name); i >= 0 {
+		return chain.effects[i]
+	}
+	return nil
+}
+
+//Effects : Every effect, enabled or not, in the order they run
+func (chain *PostChain) Effects() []*PostEffect {
+	chain.mutex.Lock()
+	defer chain.mutex.Unlock()
+	return append([]*PostEffect(nil), chain.effects...)
+}
+
+//Move : Moves the effect name to position index, clamped to the chain
+func (chain *PostChain) Move(name string, index int) error {
+	chain.mutex.Lock()
+	defer chain.mutex.Unlock()
+	i := chain.index(name)
+	if i < 0 {
+		return fmt.Errorf("goldcore: no post effect %s", name)
+	}
+	effect := chain.effects[i]
+	chain.effects = append(chain.effects[:i], chain.effects[i+1:]...)
+	index = clampInt(index, 0, len(chain.effects))
+	chain.effects = append(chain.effects, nil)
+	copy(chain.effects[index+1:], chain.effects[index:])
+	chain.effects[index] = effect
+	return nil
+}
+
+//SetEnabled : Turns the effect name on or off
+func (chain *PostChain) SetEnabled(name string, enabled bool) error {
+	effect := chain.Effect(name)
+	if effect == nil {
+		return fmt.Errorf("goldcore: no post effect %s", name)
+	}
+	effect.SetEnabled(enabled)
+	return nil
+}
+
+//SetParameter : Sets a uniform of the effect name, see PostEffect.SetParameter
+func (chain *PostChain) SetParameter(name, uniform string, value interface{}) error {
+	effect := chain.Effect(name)
+	if effect == nil {
+		return fmt.Errorf("goldcore: no post effect %s", name)
+	}
+	return effect.SetParameter(uniform, value)
+}
+
+//Apply : Runs the enabled effects' CPU references over src. src is left
+//alone, the result is a new image, or src itself if no effect ran
+func (chain *PostChain) Apply(src *image.RGBA) *image.RGBA {
+	var buffers [2]*image.RGBA
+	out, n := src, 0
+	for _, effect := range chain.active() {
+		if effect.reference == nil {
+			continue
+		}
+		dst := buffers[n%2]
+		if dst == nil {
+			dst = image.NewRGBA(src.Rect)
+			buffers[n%2] = dst
+		}
+		effect.reference(dst, out, effect)
+		out = dst
+		n++
+	}
+	return out
+}
+
+//active : Enabled effects in order
+func (chain *PostChain) active() []*PostEffect {
+	chain.mutex.Lock()
+	defer chain.mutex.Unlock()
+	var effects []*PostEffect
+	for _, effect := range chain.effects {
+		if effect.Enabled() {
+			effects = append(effects, effect)
+		}
+	}
+	return effects
+}
+
+//index : Position of the effect name, -1 if it isn't there. Mutex held
+func (chain *PostChain) index(name string) int {
+	for i, effect := range chain.effects {
+		if effect.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+/////////////////////////////////////
+///		WINDOW
+/////////////////////////////////////
+
+//PostEffectToggle : Payload of WindowPostEffectToggled
+type PostEffectToggle struct {
+	Effect  string
+	Enabled bool
+}
+
+//PostEffectMove : Payload of WindowPostEffectMoved
+type PostEffectMove struct {
+	Effect string
+	Index  int //New position in the chain
+}
+
+//PostEffectParameter : Payload of WindowPostEffectParameter
+type PostEffectParameter struct {
+	Effect  string
+	Uniform string
+	Value   interface{} //float32, Vector2f, Vector3f, Color or *Texture
+}
+
+//PostEffects : The window's post processing chain
+func (gW *GameWindow) PostEffects() *PostChain {
+	return gW.post
+}
+
+//onPostEffectMessage : Applies a post processing message to the chain
+func (gW *GameWindow) onPostEffectMessage(gM *GameMessage) error {
+	switch p := gM.Payload.(type) {
+	case PostEffectToggle:
+		return gW.post.SetEnabled(p.Effect, p.Enabled)
+	case PostEffectMove:
+		return gW.post.Move(p.Effect, p.Index)
+	case PostEffectParameter:
+		return gW.post.SetParameter(p.Effect, p.Uniform, p.Value)
+	}
+	return fmt.Errorf("goldcore: unexpected post effect payload %T", gM.Payload)
+}
+
+//Present : Displays the back buffer through chain's CPU references
+func (w *headlessWindow) Present(chain *PostChain, beforeDisplay func()) {
+	s := w.softwareSurface
+	s.mutex.Lock()
+	copy(s.front.Pix, chain.Apply(s.back).Pix)
+	s.mutex.Unlock()
+	if beforeDisplay != nil {
+		beforeDisplay()
+	}
+}
+
+//Present : Displays the frame through chain. While effects are enabled
+//frames are drawn to an off screen texture and the passes ping-pong between
+//two more, the last one drawing to the window. Turning the first effect on
+//takes effect a frame late, as that frame was already drawn to the window
+func (w *sfmlWindow) Present(chain *PostChain, beforeDisplay func()) {
+	effects := chain.active()
+	if w.scene != nil {
+		w.postProcess(effects)
+	}
+	//The back buffer is undefined once swapped, read it before
+	if beforeDisplay != nil {
+		beforeDisplay()
+	}
+	w.RenderWindow.Display()
+	w.preparePost(len(effects) > 0)
+}
+
+//postProcess : Draws the scene to the window through effects
+func (w *sfmlWindow) postProcess(effects []*PostEffect) {
+	w.scene.Display()
+	size := w.scene.GetSize()
+	src := w.scene.GetTexture()
+	var last *PostEffect
+	for i, effect := range effects {
+		if i == len(effects)-1 {
+			last = effect
+			break
+		}
+		dst := w.passes[i%2]
+		dst.Clear(ColorTransparent.ToSFML())
+		drawPassSFML(dst, src, size, effect)
+		dst.Display()
+		src = dst.GetTexture()
+	}
+	w.RenderWindow.SetView(w.RenderWindow.GetDefaultView())
+	drawPassSFML(w.RenderWindow, src, size, last)
+	if w.view != nil {
+		w.RenderWindow.SetView(w.view)
+	}
+}
+
+//preparePost : Creates the off screen textures the next frame is drawn to,
+//or drops them when nothing is enabled. Without render texture support
+//frames are drawn straight to the window
+func (w *sfmlWindow) preparePost(on bool) {
+	if !on {
+		w.scene, w.passes = nil, [2]*sf.RenderTexture{}
+		return
+	}
+	size := w.RenderWindow.GetSize()
+	if w.scene != nil && w.scene.GetSize() == size {
+		return
+	}
+	var targets [3]*sf.RenderTexture
+	for i := range targets {
+		rt, err := sf.NewRenderTexture(size.X, size.Y, false)
+		if err != nil {
+			w.scene, w.passes = nil, [2]*sf.RenderTexture{}
+			return
+		}
+		targets[i] = rt
+	}
+	w.scene, w.passes = targets[0], [2]*sf.RenderTexture{targets[1], targets[2]}
+	if w.view != nil {
+		w.scene.SetView(w.view)
+	}
+}
+
+//drawPassSFML : Covers target with tex drawn through effect, replacing what
+//was there. A nil effect copies tex
+func drawPassSFML(target sf.RenderTarget, tex *sf.Texture, size sf.Vector2u, effect *PostEffect) {
+	width, height := float32(size.X), float32(size.Y)
+	white := ColorWhite.ToSFML()
+	quad := []sf.Vertex{
+		{Position: sf.Vector2f{}, Color: white, TexCoords: sf.Vector2f{}},
+		{Position: sf.Vector2f{X: width}, Color: white, TexCoords: sf.Vector2f{X: width}},
+		{Position: sf.Vector2f{Y: height}, Color: white, TexCoords: sf.Vector2f{Y: height}},
+		{Position: sf.Vector2f{X: width, Y: height}, Color: white, TexCoords: sf.Vector2f{X: width, Y: height}},
+	}
+	states := sf.DefaultRenderStates()
+	states.BlendMode = sf.BlendNone
+	states.Texture = tex
+	if effect != nil {
+		states.Shader = effect.toSFML(size)
+	}
+	target.DrawPrimitives(quad, sf.PrimitiveTrianglesStrip, states)
+}
+
+/////////////////////////////////////
+///		EFFECTS
+/////////////////////////////////////
+
+//vignetteShader : Darkens towards the corners. d is 1 in the corners
+const vignetteShader = `#version 120
+uniform sampler2D texture;
+uniform float strength;
+uniform float radius;
+uniform float softness;
+
+void main()
+{
+	vec2 uv = gl_TexCoord[0].xy;
+	vec4 pixel = texture2D(texture, uv);
+	float d = length(uv - vec2(0.5)) * 1.41421356;
+	float v = 1.0 - strength * smoothstep(radius - softness, radius, d);
+	gl_FragColor = vec4(pixel.rgb * v, pixel.a);
+}
+`
+
+//NewVignetteEffect : Darkens the edges of the frame. Uniforms:
+//strength (0.5) how dark the corners get, radius (1) distance from the
+//center where the darkening is full, the corners being at 1, and softness
+//(0.5) how far before radius it starts
+func NewVignetteEffect() *PostEffect {
+	shader := mustPostShader(vignetteShader)
+	shader.SetFloat("strength", 0.5)
+	shader.SetFloat("radius", 1)
+	shader.SetFloat("softness", 0.5)
+	return NewPostEffect("vignette", shader, vignetteReference)
+}
+
+func vignetteReference(dst, src *image.RGBA, effect *PostEffect) {
+	strength, radius, softness := effect.Float("strength"), effect.Float("radius"), effect.Float("softness")
+	mapPixels(dst, src, func(c ColorF, uv Vector2f) ColorF {
+		d := uv.Distance(Vector2f{X: 0.5, Y: 0.5}) * math.Sqrt2
+		v := 1 - strength*smoothstep(radius-softness, radius, d)
+		return ColorF{R: c.R * v, G: c.G * v, B: c.B * v, A: c.A}
+	})
+}
+
+//colorGradeShader : Brightness, contrast, saturation then tint
+const colorGradeShader = `#version 120
+uniform sampler2D texture;
+uniform float brightness;
+uniform float contrast;
+uniform float saturation;
+uniform vec3 tint;
+
+void main()
+{
+	vec4 pixel = texture2D(texture, gl_TexCoord[0].xy);
+	vec3 c = (pixel.rgb - 0.5) * contrast + 0.5 + brightness;
+	float gray = dot(c, vec3(0.2126, 0.7152, 0.0722));
+	c = mix(vec3(gray), c, saturation) * tint;
+	gl_FragColor = vec4(clamp(c, 0.0, 1.0), pixel.a);
+}
+`
+
+//NewColorGradeEffect : Adjusts the colors of the frame. Uniforms:
+//brightness (0) added to every channel, contrast (1) scaling channels away
+//from middle gray, saturation (1) with 0 making the frame gray, and tint
+//(white) multiplying the result
+func NewColorGradeEffect() *PostEffect {
+	shader := mustPostShader(colorGradeShader)
+	shader.SetFloat("brightness", 0)
+	shader.SetFloat("contrast", 1)
+	shader.SetFloat("saturation", 1)
+	shader.SetColor("tint", ColorWhite)
+	return NewPostEffect("color grade", shader, colorGradeReference)
+}
+
+func colorGradeReference(dst, src *image.RGBA, effect *PostEffect) {
+	brightness, contrast, saturation := effect.Float("brightness"), effect.Float("contrast"), effect.Float("saturation")
+	tint := effect.Color("tint").Float()
+	grade := func(x float32) float32 {
+		return (x-0.5)*contrast + 0.5 + brightness
+	}
+	mapPixels(dst, src, func(c ColorF, uv Vector2f) ColorF {
+		r, g, b := grade(c.R), grade(c.G), grade(c.B)
+		gray := 0.2126*r + 0.7152*g + 0.0722*b
+		return ColorF{
+			R: Lerp(gray, r, saturation) * tint.R,
+			G: Lerp(gray, g, saturation) * tint.G,
+			B: Lerp(gray, b, saturation) * tint.B,
+			A: c.A,
+		}
+	})
+}
+
+//bloomShader : Adds a blur of what is brighter than threshold. 9x9 taps
+//radius / 4 pixels apart, weighted by a gaussian
+const bloomShader = `#version 120
+uniform sampler2D texture;
+uniform vec2 textureSize;
+uniform float threshold;
+uniform float intensity;
+uniform float radius;
+
+void main()
+{
+	vec2 uv = gl_TexCoord[0].xy;
+	vec4 pixel = texture2D(texture, uv);
+	vec2 texel = radius / 4.0 / textureSize;
+	vec3 glow = vec3(0.0);
+	float total = 0.0;
+	for (int j = -4; j <= 4; j++) {
+		for (int i = -4; i <= 4; i++) {
+			float w = exp(-float(i * i + j * j) / 8.0);
+			vec3 c = texture2D(texture, uv + vec2(float(i), float(j)) * texel).rgb;
+			glow += max(c - vec3(threshold), 0.0) * w;
+			total += w;
+		}
+	}
+	gl_FragColor = vec4(min(pixel.rgb + glow / total * intensity, 1.0), pixel.a);
+}
+`
+
+//NewBloomEffect : Makes bright parts of the frame glow. Uniforms:
+//threshold (0.7) brightness above which a channel glows, intensity (1)
+//strength of the glow, and radius (4) its size in pixels
+func NewBloomEffect() *PostEffect {
+	shader := mustPostShader(bloomShader)
+	shader.SetFloat("threshold", 0.7)
+	shader.SetFloat("intensity", 1)
+	shader.SetFloat("radius", 4)
+	return NewPostEffect("bloom", shader, bloomReference)
+}
+
+func bloomReference(dst, src *image.RGBA, effect *PostEffect) {
+	threshold, intensity := effect.Float("threshold"), effect.Float("intensity")
+	texel := effect.Float("radius") / 4
+	var offsets [9]int
+	var weights [9][9]float32
+	var total float32
+	for i := range offsets {
+		offsets[i] = int(math.Floor(float64(float32(i-4)*texel) + 0.5))
+		for j := range weights[i] {
+			weights[i][j] = float32(math.Exp(-float64((i-4)*(i-4)+(j-4)*(j-4)) / 8))
+			total += weights[i][j]
+		}
+	}
+	bright := func(x float32) float32 {
+		return float32(math.Max(float64(x-threshold), 0))
+	}
+	b := src.Rect
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			var glow ColorF
+			for j, dy := range offsets {
+				for i, dx := range offsets {
+					c, w := pixelF(src, x+dx, y+dy), weights[j][i]
+					glow.R += bright(c.R) * w
+					glow.G += bright(c.G) * w
+					glow.B += bright(c.B) * w
+				}
+			}
+			c := pixelF(src, x, y)
+			k := intensity / total
+			setPixelF(dst, x, y, ColorF{R: c.R + glow.R*k, G: c.G + glow.G*k, B: c.B + glow.B*k, A: c.A})
+		}
+	}
+}
+
+//screenShakeShader : Moves the frame by amplitude pixels along a path
+//driven by time, repeating the edges
+const screenShakeShader = `#version 120
+uniform sampler2D texture;
+uniform vec2 textureSize;
+uniform float amplitude;
+uniform float time;
+
+void main()
+{
+	vec2 offset = amplitude * vec2(sin(time * 59.0), cos(time * 47.0));
+	gl_FragColor = texture2D(texture, gl_TexCoord[0].xy - offset / textureSize);
+}
+`
+
+//NewScreenShakeEffect : Shakes the frame. Uniforms: amplitude (0) in
+//pixels and time (0) in seconds. Advance time every frame and tween
+//amplitude down to 0 to shake. See ShakeOffset
+func NewScreenShakeEffect() *PostEffect {
+	shader := mustPostShader(screenShakeShader)
+	shader.SetFloat("amplitude", 0)
+	shader.SetFloat("time", 0)
+	return NewPostEffect("screen shake", shader, screenShakeReference)
+}
+
+//ShakeOffset : How far, in pixels, the screen shake effect moves the frame
+//for amplitude and time
+func ShakeOffset(amplitude, time float32) Vector2f {
+	return Vector2f{
+		X: amplitude * float32(math.Sin(float64(time)*59)),
+		Y: amplitude * float32(math.Cos(float64(time)*47)),
+	}
+}
+
+func screenShakeReference(dst, src *image.RGBA, effect *PostEffect) {
+	offset := ShakeOffset(effect.Float("amplitude"), effect.Float("time")).Round()
+	b := src.Rect
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			sx, sy := clampInt(x-offset.X, b.Min.X, b.Max.X-1), clampInt(y-offset.Y, b.Min.Y, b.Max.Y-1)
+			copy(dst.Pix[dst.PixOffset(x, y):dst.PixOffset(x, y)+4], src.Pix[src.PixOffset(sx, sy):])
+		}
+	}
+}
+
+//mustPostShader : Shader of a built in effect, whose source is known good
+func mustPostShader(fragment string) *Shader {
+	shader, err := NewShader("", fragment)
+	if err != nil {
+		panic(err)
+	}
+	return shader
+}
+
+//mapPixels : Sets every pixel of dst to f of the same pixel of src. Colors
+//are straight alpha and uv goes from 0, 0 to 1, 1 over pixel centers like
+//texture coordinates in a shader
+func mapPixels(dst, src *image.RGBA, f func(c ColorF, uv Vector2f) ColorF) {
+	b := src.Rect
+	size := Vector2f{X: float32(b.Dx()), Y: float32(b.Dy())}
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			uv := Vector2f{X: float32(x-b.Min.X) + 0.5, Y: float32(y-b.Min.Y) + 0.5}
+			setPixelF(dst, x, y, f(pixelF(src, x, y), Vector2f{X: uv.X / size.X, Y: uv.Y / size.Y}))
+		}
+	}
+}
+
+//pixelF : Straight alpha color of src at x, y, repeating the edges
+func pixelF(src *image.RGBA, x, y int) ColorF {
+	b := src.Rect
+	i := src.PixOffset(clampInt(x, b.Min.X, b.Max.X-1), clampInt(y, b.Min.Y, b.Max.Y-1))
+	return Color{R: src.Pix[i], G: src.Pix[i+1], B: src.Pix[i+2], A: src.Pix[i+3]}.Unpremultiply().Float()
+}
+
+//setPixelF : Stores a straight alpha color, clamped, in dst at x, y
+func setPixelF(dst *image.RGBA, x, y int, c ColorF) {
+	p := c.Color().Premultiply()
+	i := dst.PixOffset(x, y)
+	dst.Pix[i], dst.Pix[i+1], dst.Pix[i+2], dst.Pix[i+3] = p.R, p.G, p.B, p.A
+}
+
+//smoothstep : GLSL smoothstep, 0 below edge0 and 1 above edge1
+func smoothstep(edge0, edge1, x float32) float32 {
+	if edge1 <= edge0 {
+		if x < edge0 {
+			return 0
+		}
+		return 1
+	}
+	t := Clamp((x-edge0)/(edge1-edge0), 0, 1)
+	return t * t * (3 - 2*t)
+}
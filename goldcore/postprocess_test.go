@@ -0,0 +1,186 @@
+package goldcore
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+//invertEffect : Effect without a shader source that matters, inverting colors
+func invertEffect(t *testing.T, name string) *PostEffect {
+	t.Helper()
+	shader, err := NewShader("", "uniform sampler2D texture;\nvoid main() {}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewPostEffect(name, shader, func(dst, src *image.RGBA, effect *PostEffect) {
+		mapPixels(dst, src, func(c ColorF, uv Vector2f) ColorF {
+			return ColorF{R: 1 - c.R, G: 1 - c.G, B: 1 - c.B, A: c.A}
+		})
+	})
+}
+
+func TestPostChain(t *testing.T) {
+	chain := NewPostChain()
+	for _, effect := range []*PostEffect{NewVignetteEffect(), NewBloomEffect(), NewColorGradeEffect()} {
+		if err := chain.Add(effect); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if chain.Add(NewBloomEffect()) == nil {
+		t.Error("Names should be unique")
+	}
+	names := func() (s []string) {
+		for _, effect := range chain.Effects() {
+			s = append(s, effect.Name())
+		}
+		return s
+	}
+	chain.Move("color grade", 0)
+	chain.Move("vignette", 10)
+	if s := names(); len(s) != 3 || s[0] != "color grade" || s[1] != "bloom" || s[2] != "vignette" {
+		t.Errorf("Unexpected order %v", s)
+	}
+	if chain.Move("missing", 0) == nil || chain.SetEnabled("missing", false) == nil {
+		t.Error("Unknown effects should fail")
+	}
+	if chain.Remove("bloom") == nil || chain.Effect("bloom") != nil || len(chain.Effects()) != 2 {
+		t.Error("Remove should take the effect out")
+	}
+	if chain.SetParameter("vignette", "strength", "strong") == nil {
+		t.Error("Parameters are type checked")
+	}
+
+	src := solid(2, 2, white)
+	chain.SetEnabled("vignette", false)
+	chain.SetEnabled("color grade", false)
+	if chain.Apply(src) != src {
+		t.Error("Without enabled effects the frame is untouched")
+	}
+
+	//Effects run in order, each on the result of the previous one
+	chain = NewPostChain()
+	chain.Add(invertEffect(t, "invert"))
+	grade := NewColorGradeEffect()
+	grade.SetFloat("brightness", 0.25)
+	chain.Add(grade)
+	out := chain.Apply(src)
+	expectPixels(t, out, map[image.Point]color.RGBA{{1, 1}: {R: 64, G: 64, B: 64, A: 0xff}})
+	chain.Move("color grade", 0)
+	out = chain.Apply(src)
+	expectPixels(t, out, map[image.Point]color.RGBA{{1, 1}: black})
+	if src.RGBAAt(0, 0) != white {
+		t.Error("Apply should leave src alone")
+	}
+}
+
+func TestPostEffectReferences(t *testing.T) {
+	run := func(effect *PostEffect, src *image.RGBA) *image.RGBA {
+		dst := image.NewRGBA(src.Rect)
+		effect.reference(dst, src, effect)
+		return dst
+	}
+
+	row := image.NewRGBA(image.Rect(0, 0, 3, 1))
+	row.SetRGBA(0, 0, red)
+	row.SetRGBA(1, 0, white)
+	row.SetRGBA(2, 0, black)
+	grade := NewColorGradeEffect()
+	grade.SetFloat("saturation", 0)
+	expectPixels(t, run(grade, row), map[image.Point]color.RGBA{
+		{0, 0}: {R: 54, G: 54, B: 54, A: 0xff}, {1, 0}: white, {2, 0}: black,
+	})
+	grade = NewColorGradeEffect()
+	grade.SetFloat("brightness", 0.25)
+	grade.SetColor("tint", ColorGreen)
+	expectPixels(t, run(grade, row), map[image.Point]color.RGBA{
+		{0, 0}: {G: 64, A: 0xff}, {1, 0}: green, {2, 0}: {G: 64, A: 0xff},
+	})
+
+	//Corner pixel centers are 3/4 of the way to the corners
+	vignette := run(NewVignetteEffect(), solid(4, 4, white))
+	expectPixels(t, vignette, map[image.Point]color.RGBA{
+		{0, 0}: {R: 191, G: 191, B: 191, A: 0xff}, {3, 3}: {R: 191, G: 191, B: 191, A: 0xff}, {1, 1}: white, {2, 1}: white,
+	})
+
+	//A single white pixel glows up to 4 pixels around with a gaussian falloff
+	dot := solid(11, 11, black)
+	dot.SetRGBA(5, 5, white)
+	bloom := NewBloomEffect()
+	bloom.SetFloat("threshold", 0.5)
+	bloom.SetFloat("intensity", 10)
+	glow := func(v uint8) color.RGBA { return color.RGBA{R: v, G: v, B: v, A: 0xff} }
+	expectPixels(t, run(bloom, dot), map[image.Point]color.RGBA{
+		{5, 5}: white, {6, 5}: glow(47), {4, 4}: glow(41), {5, 7}: glow(32), {0, 0}: black,
+	})
+
+	//At time 0 the shake moves straight down, repeating the top row
+	shake := NewScreenShakeEffect()
+	shake.SetFloat("amplitude", 2)
+	if offset := ShakeOffset(2, 0); !offset.Equals(Vector2f{Y: 2}) {
+		t.Errorf("Expected a shake of 0, 2 got %v", offset)
+	}
+	marked := solid(3, 4, black)
+	marked.SetRGBA(1, 0, red)
+	expectPixels(t, run(shake, marked), map[image.Point]color.RGBA{
+		{1, 0}: red, {1, 1}: red, {1, 2}: red, {1, 3}: black, {0, 2}: black,
+	})
+}
+
+func TestPostProcessWindow(t *testing.T) {
+	gW := NewHeadlessGameWindow(4, 4, "Post")
+	recorder := &MessageRecorder{}
+	gW.AddObserver(recorder)
+	gW.PostEffects().Add(NewColorGradeEffect())
+	gW.PostEffects().Add(invertEffect(t, "invert"))
+	gW.OnInputGameMessage(NewGameMessage(WindowPostEffectParameter, PostEffectParameter{Effect: "color grade", Uniform: "tint", Value: ColorRed}))
+	gW.OnInputGameMessage(NewGameMessage(WindowPostEffectToggled, PostEffectToggle{Effect: "invert", Enabled: false}))
+
+	gW.Clear(ColorWhite)
+	gW.renderWindow.Present(gW.PostEffects(), nil)
+	expectPixels(t, gW.Capture(), map[image.Point]color.RGBA{{0, 0}: red, {3, 3}: red})
+
+	//Inverting first turns white black, which no tint changes
+	gW.OnInputGameMessage(NewGameMessage(WindowPostEffectToggled, PostEffectToggle{Effect: "invert", Enabled: true}))
+	gW.OnInputGameMessage(NewGameMessage(WindowPostEffectMoved, PostEffectMove{Effect: "invert", Index: 0}))
+	gW.renderWindow.Present(gW.PostEffects(), nil)
+	expectPixels(t, gW.Capture(), map[image.Point]color.RGBA{{0, 0}: black})
+
+	//What was drawn is kept for the next frame, only what is shown changes
+	gW.PostEffects().SetEnabled("invert", false)
+	gW.PostEffects().SetEnabled("color grade", false)
+	gW.renderWindow.Present(gW.PostEffects(), nil)
+	expectPixels(t, gW.Capture(), map[image.Point]color.RGBA{{0, 0}: white})
+
+	gW.OnInputGameMessage(NewGameMessage(WindowPostEffectParameter, PostEffectParameter{Effect: "invert", Uniform: "missing", Value: float32(1)}))
+	gW.OnInputGameMessage(NewGameMessage(WindowPostEffectMoved, PostEffectMove{Effect: "missing"}))
+	var echoed, failed int
+	for _, gM := range recorder.Messages {
+		switch gM.Message {
+		case WindowPostEffectParameter, WindowPostEffectToggled, WindowPostEffectMoved:
+			echoed++
+		case WindowPostEffectFailed:
+			failed++
+		}
+	}
+	if echoed != 4 || failed != 2 {
+		t.Errorf("Expected 4 messages echoed and 2 failures got %d and %d", echoed, failed)
+	}
+}
+
+func TestPostProcessRunningWindow(t *testing.T) {
+	gW := NewHeadlessGameWindow(4, 4, "Post")
+	gW.PostEffects().Add(NewColorGradeEffect())
+	gW.OnInputGameMessage(NewGameMessage(WindowPostEffectParameter, PostEffectParameter{Effect: "color grade", Uniform: "tint", Value: ColorRed}))
+	gW.Start()
+	defer gW.Stop()
+
+	gW.Clear(ColorWhite)
+	gW.NextFrame(0)
+	expectPixels(t, gW.Capture(), map[image.Point]color.RGBA{{0, 0}: red, {3, 3}: red})
+
+	//Effects toggled between frames apply from the next one
+	gW.PostEffects().SetEnabled("color grade", false)
+	gW.NextFrame(1)
+	expectPixels(t, gW.Capture(), map[image.Point]color.RGBA{{0, 0}: white})
+}
@@ -0,0 +1,342 @@
+package goldcore
+
+import (
+	"math"
+	"math/bits"
+	"sort"
+	"sync"
+)
+
+//Random : Deterministic random number generator (PCG32). The same seed
+//always gives the same numbers, on every platform, and the whole state can
+//be saved and restored. Not safe for concurrent use, give each goroutine its
+//own stream
+type Random struct {
+	state RandomState
+}
+
+//RandomState : Everything needed to resume a Random where it left off
+type RandomState struct {
+	State, Inc uint64
+}
+
+const pcgMultiplier = 6364136223846793005
+
+//NewRandom : Generator seeded with seed
+func NewRandom(seed uint64) *Random {
+	r := &Random{}
+	r.Seed(seed)
+	return r
+}
+
+//Seed : Restarts the sequence for seed
+func (r *Random) Seed(seed uint64) {
+	r.seedStream(seed, 0)
+}
+
+//seedStream : Restarts the sequence for seed on one of the 2^63 independent
+//PCG sequences
+func (r *Random) seedStream(seed, sequence uint64) {
+	r.state = RandomState{Inc: sequence<<1 | 1}
+	r.Uint32()
+	r.state.State += seed
+	r.Uint32()
+}
+
+//State : Copy of the state, to restore later with SetState
+func (r *Random) State() RandomState {
+	return r.state
+}
+
+//SetState : Continues from a state returned by State
+func (r *Random) SetState(state RandomState) {
+	state.Inc |= 1
+	r.state = state
+}
+
+//Uint32 : Uniform 32 bit number
+func (r *Random) Uint32() uint32 {
+	old := r.state.State
+	r.state.State = old*pcgMultiplier + r.state.Inc
+	xorShifted := uint32(((old >> 18) ^ old) >> 27)
+	return bits.RotateLeft32(xorShifted, -int(old>>59))
+}
+
+//Uint64 : Uniform 64 bit number
+func (r *Random) Uint64() uint64 {
+	return uint64(r.Uint32())<<32 | uint64(r.Uint32())
+}
+
+//Intn : Uniform int in [0, n). Panics if n <= 0
+func (r *Random) Intn(n int) int {
+	if n <= 0 {
+		panic("goldcore: Random.Intn called with n <= 0")
+	}
+	if uint64(n) <= math.MaxUint32 {
+		return int(r.uint32n(uint32(n)))
+	}
+	//Rejection sampling for big n
+	max := uint64(n)
+	limit := math.MaxUint64 - math.MaxUint64%max
+	for {
+		if v := r.Uint64(); v < limit {
+			return int(v % max)
+		}
+	}
+}
+
+//uint32n : Uniform number in [0, n), without modulo bias (Lemire's method)
+func (r *Random) uint32n(n uint32) uint32 {
+	m := uint64(r.Uint32()) * uint64(n)
+	if low := uint32(m); low < n {
+		threshold := -n % n
+		for low < threshold {
+			m = uint64(r.Uint32()) * uint64(n)
+			low = uint32(m)
+		}
+	}
+	return uint32(m >> 32)
+}
+
+//IntRange : Uniform int between min and max, both included
+func (r *Random) IntRange(min, max int) int {
+	if max < min {
+		min, max = max, min
+	}
+	return min + r.Intn(max-min+1)
+}
+
+//Float64 : Uniform float in [0, 1)
+func (r *Random) Float64() float64 {
+	return float64(r.Uint64()>>11) / (1 << 53)
+}
+
+//Float32 : Uniform float in [0, 1)
+func (r *Random) Float32() float32 {
+	return float32(r.Uint32()>>8) / (1 << 24)
+}
+
+//FloatRange : Uniform float in [min, max)
+func (r *Random) FloatRange(min, max float32) float32 {
+	return min + (max-min)*r.Float32()
+}
+
+//Bool : True half of the time
+func (r *Random) Bool() bool {
+	return r.Uint32()&1 == 1
+}
+
+//Chance : True with probability p, 0 never, 1 always
+func (r *Random) Chance(p float32) bool {
+	return r.Float32() < p
+}
+
+//Angle : Uniform angle in radians, in [0, 2π)
+func (r *Random) Angle() float32 {
+	return r.Float32() * 2 * math.Pi
+}
+
+//UnitVector : Vector of length 1 pointing anywhere
+func (r *Random) UnitVector() Vector2f {
+	return Vector2f{X: 1}.Rotate(r.Angle())
+}
+
+//PointInRect : Uniform point inside rect
+func (r *Random) PointInRect(rect RectF) Vector2f {
+	return Vector2f{X: rect.Left + rect.Width*r.Float32(), Y: rect.Top + rect.Height*r.Float32()}
+}
+
+//PointInCircle : Uniform point inside the disc
+func (r *Random) PointInCircle(c Circle) Vector2f {
+	//sqrt so points don't bunch up in the middle
+	distance := c.Radius * sqrtFloat(r.Float32())
+	return c.Center.Plus(r.UnitVector().Scale(distance))
+}
+
+//PointOnCircle : Uniform point on the edge of the disc
+func (r *Random) PointOnCircle(c Circle) Vector2f {
+	return c.Center.Plus(r.UnitVector().Scale(c.Radius))
+}
+
+//Gaussian : Normally distributed number (Box-Muller)
+func (r *Random) Gaussian(mean, stddev float32) float32 {
+	u1 := 1 - r.Float64() //(0, 1] so the log is finite
+	u2 := r.Float64()
+	z := math.Sqrt(-2*math.Log(u1)) * math.Cos(2*math.Pi*u2)
+	return mean + stddev*float32(z)
+}
+
+//WeightedChoice : Index i picked with probability weights[i] / sum of
+//weights. Negative weights count as 0. -1 if no weight is positive
+func (r *Random) WeightedChoice(weights []float32) int {
+	var total float64
+	for _, w := range weights {
+		if w > 0 {
+			total += float64(w)
+		}
+	}
+	if total <= 0 {
+		return -1
+	}
+	pick := r.Float64() * total
+	last := -1
+	for i, w := range weights {
+		if w <= 0 {
+			continue
+		}
+		last = i
+		pick -= float64(w)
+		if pick < 0 {
+			return i
+		}
+	}
+	//Rounding left a sliver past the end
+	return last
+}
+
+//Shuffle : Puts n items in random order, swap exchanges items i and j
+//(Fisher-Yates)
+func (r *Random) Shuffle(n int, swap func(i, j int)) {
+	for i := n - 1; i > 0; i-- {
+		swap(i, r.Intn(i+1))
+	}
+}
+
+//Perm : Numbers 0 to n-1 in random order
+func (r *Random) Perm(n int) []int {
+	perm := make([]int, n)
+	for i := range perm {
+		perm[i] = i
+	}
+	r.Shuffle(n, func(i, j int) { perm[i], perm[j] = perm[j], perm[i] })
+	return perm
+}
+
+/////////////////////////////////////
+///		STREAMS
+/////////////////////////////////////
+
+//DefaultRandomStream : Name of the stream for randomness that doesn't
+//deserve its own
+const DefaultRandomStream = "default"
+
+//RandomStreams : Independent named generators derived from one seed. Drawing
+//from "particles" never changes what "loot" gives, so cosmetic randomness
+//can't desync gameplay. Getting streams is safe for concurrent use, using
+//one stream from several goroutines is not
+type RandomStreams struct {
+	mutex   sync.Mutex
+	seed    uint64
+	streams map[string]*Random
+}
+
+//RandomSnapshot : Seed and state of every stream, see RandomStreams.Save
+type RandomSnapshot struct {
+	Seed    uint64
+	Streams map[string]RandomState
+}
+
+//NewRandomStreams : Streams derived from seed
+func NewRandomStreams(seed uint64) *RandomStreams {
+	return &RandomStreams{seed: seed, streams: make(map[string]*Random)}
+}
+
+//Seed : Seed every stream is derived from
+func (rs *RandomStreams) Seed() uint64 {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+	return rs.seed
+}
+
+//Reseed : Restarts every stream from seed. Streams already handed out keep
+//working, they are reseeded in place
+func (rs *RandomStreams) Reseed(seed uint64) {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+	rs.seed = seed
+	for name, stream := range rs.streams {
+		rs.seedStream(stream, name)
+	}
+}
+
+//Stream : Generator named name, created on first use. Its sequence only
+//depends on the seed and the name
+func (rs *RandomStreams) Stream(name string) *Random {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+	stream, ok := rs.streams[name]
+	if !ok {
+		stream = &Random{}
+		rs.seedStream(stream, name)
+		rs.streams[name] = stream
+	}
+	return stream
+}
+
+//Default : The DefaultRandomStream stream
+func (rs *RandomStreams) Default() *Random {
+	return rs.Stream(DefaultRandomStream)
+}
+
+//Names : Names of the streams created so far, sorted
+func (rs *RandomStreams) Names() []string {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+	names := make([]string, 0, len(rs.streams))
+	for name := range rs.streams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+//Save : Seed and state of every stream
+func (rs *RandomStreams) Save() RandomSnapshot {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+	snapshot := RandomSnapshot{Seed: rs.seed, Streams: make(map[string]RandomState, len(rs.streams))}
+	for name, stream := range rs.streams {
+		snapshot.Streams[name] = stream.State()
+	}
+	return snapshot
+}
+
+//Restore : Puts every stream back as it was when snapshot was saved. Streams
+//missing from the snapshot restart from the snapshot's seed
+func (rs *RandomStreams) Restore(snapshot RandomSnapshot) {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+	rs.seed = snapshot.Seed
+	for name, state := range snapshot.Streams {
+		stream, ok := rs.streams[name]
+		if !ok {
+			stream = &Random{}
+			rs.streams[name] = stream
+		}
+		stream.SetState(state)
+	}
+	for name, stream := range rs.streams {
+		if _, ok := snapshot.Streams[name]; !ok {
+			rs.seedStream(stream, name)
+		}
+	}
+}
+
+//seedStream : Seeds stream from the seed and its name. Must hold the mutex
+func (rs *RandomStreams) seedStream(stream *Random, name string) {
+	//FNV-1a picks the PCG sequence, so streams differ even for equal seeds
+	hash := uint64(14695981039346656037)
+	for i := 0; i < len(name); i++ {
+		hash ^= uint64(name[i])
+		hash *= 1099511628211
+	}
+	stream.seedStream(splitMix64(rs.seed^hash), hash)
+}
+
+//splitMix64 : Scrambles x so close seeds give unrelated states
+func splitMix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
@@ -0,0 +1,192 @@
+package goldcore
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRandomReference(t *testing.T) {
+	//Output of the reference pcg32 demo for seed 42, sequence 54
+	r := &Random{}
+	r.seedStream(42, 54)
+	expected := []uint32{0xa15c02b7, 0x7b47f409, 0xba1d3330, 0x83d2f293, 0xbfa4784b, 0xcbed606e}
+	for i, e := range expected {
+		if got := r.Uint32(); got != e {
+			t.Fatalf("output %d: expected %#x, got %#x", i, e, got)
+		}
+	}
+}
+
+func TestRandomDeterminism(t *testing.T) {
+	a, b := NewRandom(7), NewRandom(7)
+	for i := 0; i < 100; i++ {
+		if a.Uint64() != b.Uint64() {
+			t.Fatal("same seed should give the same sequence")
+		}
+	}
+	if NewRandom(7).Uint64() == NewRandom(8).Uint64() {
+		t.Error("different seeds should give different sequences")
+	}
+
+	state := a.State()
+	first := []int{a.Intn(1000), a.Intn(1000), a.Intn(1000)}
+	a.SetState(state)
+	for i, f := range first {
+		if got := a.Intn(1000); got != f {
+			t.Errorf("restored state, draw %d: expected %d, got %d", i, f, got)
+		}
+	}
+}
+
+func TestRandomRanges(t *testing.T) {
+	r := NewRandom(1)
+	seen := make(map[int]bool)
+	for i := 0; i < 1000; i++ {
+		v := r.IntRange(-2, 2)
+		if v < -2 || v > 2 {
+			t.Fatalf("IntRange out of range: %d", v)
+		}
+		seen[v] = true
+		if f := r.FloatRange(3, 4); f < 3 || f >= 4 {
+			t.Fatalf("FloatRange out of range: %v", f)
+		}
+		if f := r.Float64(); f < 0 || f >= 1 {
+			t.Fatalf("Float64 out of range: %v", f)
+		}
+	}
+	if len(seen) != 5 {
+		t.Errorf("IntRange should reach every value, saw %v", seen)
+	}
+	if got := r.Intn(math.MaxInt64); got < 0 {
+		t.Errorf("Intn with a big n: got %d", got)
+	}
+
+	rect := RectF{Left: 10, Top: 20, Width: 5, Height: 1}
+	circle := Circle{Center: Vector2f{X: -3, Y: 3}, Radius: 2}
+	for i := 0; i < 1000; i++ {
+		if p := r.PointInRect(rect); !rect.Contains(p) {
+			t.Fatalf("PointInRect outside: %v", p)
+		}
+		if p := r.PointInCircle(circle); !circle.Contains(p) {
+			t.Fatalf("PointInCircle outside: %v", p)
+		}
+		if p := r.PointOnCircle(circle); !ApproxEqual(p.Distance(circle.Center), 2, 1e-4) {
+			t.Fatalf("PointOnCircle off the edge: %v", p)
+		}
+	}
+}
+
+func TestRandomDistributions(t *testing.T) {
+	r := NewRandom(3)
+	const n = 20000
+	var sum, sumSquares float64
+	for i := 0; i < n; i++ {
+		g := float64(r.Gaussian(10, 2))
+		sum += g
+		sumSquares += g * g
+	}
+	mean := sum / n
+	stddev := math.Sqrt(sumSquares/n - mean*mean)
+	if math.Abs(mean-10) > 0.1 || math.Abs(stddev-2) > 0.1 {
+		t.Errorf("Gaussian: expected mean 10 stddev 2, got %v %v", mean, stddev)
+	}
+
+	counts := make([]int, 4)
+	weights := []float32{1, 0, 3, -5}
+	for i := 0; i < n; i++ {
+		counts[r.WeightedChoice(weights)]++
+	}
+	if counts[1] != 0 || counts[3] != 0 {
+		t.Errorf("zero and negative weights should never be picked: %v", counts)
+	}
+	if ratio := float64(counts[2]) / float64(counts[0]); math.Abs(ratio-3) > 0.3 {
+		t.Errorf("WeightedChoice: expected a 3:1 ratio, got %v", counts)
+	}
+	if got := r.WeightedChoice([]float32{0, -1}); got != -1 {
+		t.Errorf("WeightedChoice with no positive weight: expected -1, got %d", got)
+	}
+
+	perm := r.Perm(50)
+	seen := make(map[int]bool)
+	for _, p := range perm {
+		seen[p] = true
+	}
+	if len(seen) != 50 {
+		t.Errorf("Perm should hold every number once: %v", perm)
+	}
+}
+
+func TestRandomStreams(t *testing.T) {
+	rs := NewRandomStreams(99)
+	loot := rs.Stream("loot")
+	expected := []uint32{loot.Uint32(), loot.Uint32(), loot.Uint32()}
+
+	//Drawing from another stream doesn't change loot
+	rs2 := NewRandomStreams(99)
+	particles := rs2.Stream("particles")
+	for i := 0; i < 10; i++ {
+		particles.Uint32()
+	}
+	loot2 := rs2.Stream("loot")
+	for i, e := range expected {
+		if got := loot2.Uint32(); got != e {
+			t.Errorf("loot draw %d disturbed by particles: expected %d, got %d", i, e, got)
+		}
+	}
+	if rs.Stream("ai").Uint32() == NewRandomStreams(99).Stream("loot").Uint32() {
+		t.Error("streams with different names should differ")
+	}
+
+	snapshot := rs.Save()
+	next := loot.Uint32()
+	rs.Stream("late").Uint32()
+	rs.Restore(snapshot)
+	if got := loot.Uint32(); got != next {
+		t.Errorf("Restore: expected %d, got %d", next, got)
+	}
+	if got, e := rs.Stream("late").Uint32(), NewRandomStreams(99).Stream("late").Uint32(); got != e {
+		t.Errorf("stream missing from the snapshot should restart from the seed: expected %d, got %d", e, got)
+	}
+
+	rs.Reseed(5)
+	if got, e := loot.Uint32(), NewRandomStreams(5).Stream("loot").Uint32(); got != e {
+		t.Errorf("Reseed should reseed streams in place: expected %d, got %d", e, got)
+	}
+	if names := rs.Names(); len(names) != 3 || names[0] != "ai" {
+		t.Errorf("Names: got %v", names)
+	}
+}
+
+func TestGameRandomReplay(t *testing.T) {
+	run := func(game *Game) []int {
+		game.Play()
+		var draws []int
+		for i := 0; i < 5; i++ {
+			draws = append(draws, game.Random("loot").Intn(100), game.Random(DefaultRandomStream).Intn(100))
+		}
+		game.Stop()
+		return draws
+	}
+	game := NewGame()
+	game.SetSeed(1234)
+	first := run(game)
+	//Playing again from a stop replays the same numbers
+	second := run(game)
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("replay differs at draw %d: %v vs %v", i, first, second)
+		}
+	}
+
+	//Resuming after Suspend continues the sequence instead
+	game.Play()
+	a := game.Random("loot").Intn(1 << 30)
+	game.Suspend()
+	game.Play()
+	b := game.Random("loot").Intn(1 << 30)
+	game.Stop()
+	game.Play()
+	if game.Random("loot").Intn(1<<30) != a || a == b {
+		t.Error("Suspend shouldn't reseed, Stop then Play should")
+	}
+}
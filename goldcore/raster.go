@@ -0,0 +1,183 @@
+package goldcore
+
+import "image"
+
+//rasterVertex : Vertex once transformed, ready to be rasterized
+type rasterVertex struct {
+	x, y  float64
+	color [4]float32 //Not premultiplied, from 0 to 1
+	uv    Vector2f
+}
+
+//rasterize : Draws vertices on dst by software, only inside clip. Pixel x, y
+//covers [x, x+1) by [y, y+1) and is drawn if its center is inside a
+//triangle. Edges shared by two triangles are drawn once (top left rule), so
+//transparent quads don't show a seam along their diagonal
+func rasterize(dst *image.RGBA, clip image.Rectangle, vertices []Vertex, primitive PrimitiveType, states RenderStates) {
+	clip = clip.Intersect(dst.Rect)
+	if clip.Empty() || len(vertices) < 3 {
+		return
+	}
+	points := make([]rasterVertex, len(vertices))
+	for i, v := range vertices {
+		p := states.Transform.TransformPoint(v.Position)
+		points[i] = rasterVertex{
+			x: float64(p.X), y: float64(p.Y),
+			color: [4]float32{float32(v.Color.R) / 0xff, float32(v.Color.G) / 0xff, float32(v.Color.B) / 0xff, float32(v.Color.A) / 0xff},
+			uv:    v.TexCoords,
+		}
+	}
+	r := rasterizer{dst: dst, clip: clip, blend: states.BlendMode}
+	if states.Texture != nil {
+		s := states.Texture.sampler()
+		r.sampler = &s
+	}
+	switch primitive {
+	case TriangleStrip:
+		for i := 2; i < len(points); i++ {
+			r.triangle(&points[i-2], &points[i-1], &points[i])
+		}
+	case TriangleFan:
+		for i := 2; i < len(points); i++ {
+			r.triangle(&points[0], &points[i-1], &points[i])
+		}
+	default:
+		for i := 2; i < len(points); i += 3 {
+			r.triangle(&points[i-2], &points[i-1], &points[i])
+		}
+	}
+}
+
+//rasterizer : What every triangle of a draw call shares
+type rasterizer struct {
+	dst     *image.RGBA
+	clip    image.Rectangle
+	sampler *sampler
+	blend   BlendMode
+}
+
+//edge : Twice the signed area of a, b, p. Positive when p is on the inner
+//side of a to b for a triangle with positive area
+func edge(a, b *rasterVertex, px, py float64) float64 {
+	return (b.x-a.x)*(py-a.y) - (b.y-a.y)*(px-a.x)
+}
+
+//isTopLeft : Whether pixel centers exactly on the edge a to b belong to
+//the triangle. The neighbour sharing the edge walks it the other way round
+//and gets the opposite answer
+func isTopLeft(a, b *rasterVertex) bool {
+	dy := b.y - a.y
+	return dy < 0 || (dy == 0 && b.x > a.x)
+}
+
+//triangle : Fills the triangle a, b, c
+func (r *rasterizer) triangle(a, b, c *rasterVertex) {
+	area := edge(a, b, c.x, c.y)
+	if area == 0 {
+		return
+	}
+	if area < 0 {
+		b, c = c, b
+		area = -area
+	}
+	minX := maxInt(r.clip.Min.X, floorInt(float32(minFloat64(a.x, b.x, c.x))))
+	maxX := minInt(r.clip.Max.X-1, floorInt(float32(maxFloat64(a.x, b.x, c.x))))
+	minY := maxInt(r.clip.Min.Y, floorInt(float32(minFloat64(a.y, b.y, c.y))))
+	maxY := minInt(r.clip.Max.Y-1, floorInt(float32(maxFloat64(a.y, b.y, c.y))))
+	topLeftA, topLeftB, topLeftC := isTopLeft(b, c), isTopLeft(c, a), isTopLeft(a, b)
+
+	for y := minY; y <= maxY; y++ {
+		py := float64(y) + 0.5
+		for x := minX; x <= maxX; x++ {
+			px := float64(x) + 0.5
+			//Weights of a, b and c
+			wa, wb, wc := edge(b, c, px, py), edge(c, a, px, py), edge(a, b, px, py)
+			if !inside(wa, topLeftA) || !inside(wb, topLeftB) || !inside(wc, topLeftC) {
+				continue
+			}
+			la, lb, lc := float32(wa/area), float32(wb/area), float32(wc/area)
+			r.shade(x, y, a, b, c, la, lb, lc)
+		}
+	}
+}
+
+func inside(w float64, topLeft bool) bool {
+	return w > 0 || (w == 0 && topLeft)
+}
+
+//shade : Blends the color at barycentric coordinates la, lb, lc into
+//pixel x, y
+func (r *rasterizer) shade(x, y int, a, b, c *rasterVertex, la, lb, lc float32) {
+	var src [4]float32
+	for i := range src {
+		src[i] = a.color[i]*la + b.color[i]*lb + c.color[i]*lc
+	}
+	//Premultiply the vertex color
+	src[0], src[1], src[2] = src[0]*src[3], src[1]*src[3], src[2]*src[3]
+	if r.sampler != nil {
+		u := a.uv.X*la + b.uv.X*lb + c.uv.X*lc
+		v := a.uv.Y*la + b.uv.Y*lb + c.uv.Y*lc
+		texel := r.sampler.sample(u, v)
+		for i := range src {
+			src[i] *= texel[i]
+		}
+	}
+
+	i := r.dst.PixOffset(x, y)
+	p := r.dst.Pix[i : i+4 : i+4]
+	var dst [4]float32
+	for k := range dst {
+		dst[k] = float32(p[k]) / 0xff
+	}
+	switch r.blend {
+	case BlendAdd:
+		for k := range dst {
+			dst[k] += src[k]
+		}
+	case BlendMultiply:
+		//Transparent parts of src leave dst untouched
+		for k := 0; k < 3; k++ {
+			dst[k] *= src[k] + 1 - src[3]
+		}
+	case BlendNone:
+		dst = src
+	default:
+		for k := range dst {
+			dst[k] = src[k] + dst[k]*(1-src[3])
+		}
+	}
+	for k := range dst {
+		p[k] = unitToByte(dst[k])
+	}
+}
+
+//unitToByte : 0 to 1 into 0 to 255, rounded and clamped
+func unitToByte(f float32) uint8 {
+	if f <= 0 {
+		return 0
+	}
+	if f >= 1 {
+		return 0xff
+	}
+	return uint8(f*0xff + 0.5)
+}
+
+func minFloat64(a, b, c float64) float64 {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+func maxFloat64(a, b, c float64) float64 {
+	if b > a {
+		a = b
+	}
+	if c > a {
+		a = c
+	}
+	return a
+}
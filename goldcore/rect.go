@@ -0,0 +1,292 @@
+package goldcore
+
+import (
+	"math"
+
+	sf "github.com/manyminds/gosfml"
+)
+
+/////////////////////////////////////
+///		RECTI
+/////////////////////////////////////
+
+//RectI : Axis aligned rectangle with integer coordinates, like screen regions
+//in pixels. A rectangle with no width or height is empty
+type RectI struct {
+	Left, Top, Width, Height int
+}
+
+//NewRectI : Rectangle at pos with the given size
+func NewRectI(pos, size Vector2i) RectI {
+	return RectI{Left: pos.X, Top: pos.Y, Width: size.X, Height: size.Y}
+}
+
+//Right : X just past the right edge
+func (r RectI) Right() int {
+	return r.Left + r.Width
+}
+
+//Bottom : Y just past the bottom edge
+func (r RectI) Bottom() int {
+	return r.Top + r.Height
+}
+
+//Position : Top left corner
+func (r RectI) Position() Vector2i {
+	return Vector2i{X: r.Left, Y: r.Top}
+}
+
+//Size : Width and height
+func (r RectI) Size() Vector2i {
+	return Vector2i{X: r.Width, Y: r.Height}
+}
+
+//Center : Middle of the rectangle
+func (r RectI) Center() Vector2f {
+	return Vector2f{X: float32(r.Left) + float32(r.Width)/2, Y: float32(r.Top) + float32(r.Height)/2}
+}
+
+//IsEmpty : True if the rectangle covers no pixel
+func (r RectI) IsEmpty() bool {
+	return r.Width <= 0 || r.Height <= 0
+}
+
+//Contains : True if pixel p is inside the rectangle. Left and top edges are
+//inside, right and bottom edges are not
+func (r RectI) Contains(p Vector2i) bool {
+	return p.X >= r.Left && p.X < r.Right() && p.Y >= r.Top && p.Y < r.Bottom()
+}
+
+//ContainsRect : True if other is entirely inside r
+func (r RectI) ContainsRect(other RectI) bool {
+	return other.Left >= r.Left && other.Top >= r.Top && other.Right() <= r.Right() && other.Bottom() <= r.Bottom()
+}
+
+//Intersects : True if the rectangles share at least one pixel
+func (r RectI) Intersects(other RectI) bool {
+	_, ok := r.Intersection(other)
+	return ok
+}
+
+//Intersection : Overlap of both rectangles. False if they don't overlap
+func (r RectI) Intersection(other RectI) (RectI, bool) {
+	left, top := maxInt(r.Left, other.Left), maxInt(r.Top, other.Top)
+	right, bottom := minInt(r.Right(), other.Right()), minInt(r.Bottom(), other.Bottom())
+	if left >= right || top >= bottom {
+		return RectI{}, false
+	}
+	return RectI{Left: left, Top: top, Width: right - left, Height: bottom - top}, true
+}
+
+//Union : Smallest rectangle containing both. Empty rectangles are ignored
+func (r RectI) Union(other RectI) RectI {
+	if r.IsEmpty() {
+		return other
+	}
+	if other.IsEmpty() {
+		return r
+	}
+	left, top := minInt(r.Left, other.Left), minInt(r.Top, other.Top)
+	right, bottom := maxInt(r.Right(), other.Right()), maxInt(r.Bottom(), other.Bottom())
+	return RectI{Left: left, Top: top, Width: right - left, Height: bottom - top}
+}
+
+//Translate : Rectangle moved by offset
+func (r RectI) Translate(offset Vector2i) RectI {
+	return RectI{Left: r.Left + offset.X, Top: r.Top + offset.Y, Width: r.Width, Height: r.Height}
+}
+
+//Inflate : Rectangle grown by amount on every side. Negative shrinks it
+func (r RectI) Inflate(amount int) RectI {
+	return RectI{Left: r.Left - amount, Top: r.Top - amount, Width: r.Width + 2*amount, Height: r.Height + 2*amount}
+}
+
+//ToRectF : Same rectangle with float coordinates
+func (r RectI) ToRectF() RectF {
+	return RectF{Left: float32(r.Left), Top: float32(r.Top), Width: float32(r.Width), Height: float32(r.Height)}
+}
+
+//ToSFML : Allows for SFML compatability
+func (r RectI) ToSFML() sf.IntRect {
+	return sf.IntRect{Left: r.Left, Top: r.Top, Width: r.Width, Height: r.Height}
+}
+
+//SFIntRectToRectI : SFML IntRect to GoldEngine RectI
+func SFIntRectToRectI(other sf.IntRect) RectI {
+	return RectI{Left: other.Left, Top: other.Top, Width: other.Width, Height: other.Height}
+}
+
+/////////////////////////////////////
+///		RECTF
+/////////////////////////////////////
+
+//RectF : Axis aligned rectangle with float coordinates
+type RectF struct {
+	Left, Top, Width, Height float32
+}
+
+//NewRectF : Rectangle at pos with the given size
+func NewRectF(pos, size Vector2f) RectF {
+	return RectF{Left: pos.X, Top: pos.Y, Width: size.X, Height: size.Y}
+}
+
+//RectFromPoints : Smallest rectangle containing every point
+func RectFromPoints(points ...Vector2f) RectF {
+	if len(points) == 0 {
+		return RectF{}
+	}
+	min, max := points[0], points[0]
+	for _, p := range points[1:] {
+		min = min.Min(p)
+		max = max.Max(p)
+	}
+	return RectF{Left: min.X, Top: min.Y, Width: max.X - min.X, Height: max.Y - min.Y}
+}
+
+//Right : X of the right edge
+func (r RectF) Right() float32 {
+	return r.Left + r.Width
+}
+
+//Bottom : Y of the bottom edge
+func (r RectF) Bottom() float32 {
+	return r.Top + r.Height
+}
+
+//Position : Top left corner
+func (r RectF) Position() Vector2f {
+	return Vector2f{X: r.Left, Y: r.Top}
+}
+
+//Size : Width and height
+func (r RectF) Size() Vector2f {
+	return Vector2f{X: r.Width, Y: r.Height}
+}
+
+//Max : Bottom right corner
+func (r RectF) Max() Vector2f {
+	return Vector2f{X: r.Right(), Y: r.Bottom()}
+}
+
+//IsEmpty : True if the rectangle has no area
+func (r RectF) IsEmpty() bool {
+	return r.Width <= 0 || r.Height <= 0
+}
+
+//Contains : True if p is inside the rectangle. Left and top edges are inside,
+//right and bottom edges are not
+func (r RectF) Contains(p Vector2f) bool {
+	return p.X >= r.Left && p.X < r.Left+r.Width && p.Y >= r.Top && p.Y < r.Top+r.Height
+}
+
+//ContainsRect : True if other is entirely inside r
+func (r RectF) ContainsRect(other RectF) bool {
+	return other.Left >= r.Left && other.Top >= r.Top && other.Right() <= r.Right() && other.Bottom() <= r.Bottom()
+}
+
+//Center : Middle of the rectangle
+func (r RectF) Center() Vector2f {
+	return Vector2f{X: r.Left + r.Width/2, Y: r.Top + r.Height/2}
+}
+
+//Intersects : True if the rectangles overlap. Touching edges don't count
+func (r RectF) Intersects(other RectF) bool {
+	_, ok := r.Intersection(other)
+	return ok
+}
+
+//Intersection : Overlap of both rectangles. False if they don't overlap
+func (r RectF) Intersection(other RectF) (RectF, bool) {
+	left, top := maxFloat(r.Left, other.Left), maxFloat(r.Top, other.Top)
+	right, bottom := minFloat(r.Right(), other.Right()), minFloat(r.Bottom(), other.Bottom())
+	if left >= right || top >= bottom {
+		return RectF{}, false
+	}
+	return RectF{Left: left, Top: top, Width: right - left, Height: bottom - top}, true
+}
+
+//Union : Smallest rectangle containing both. Empty rectangles are ignored
+func (r RectF) Union(other RectF) RectF {
+	if r.IsEmpty() {
+		return other
+	}
+	if other.IsEmpty() {
+		return r
+	}
+	return RectFromPoints(r.Position(), r.Max(), other.Position(), other.Max())
+}
+
+//Translate : Rectangle moved by offset
+func (r RectF) Translate(offset Vector2f) RectF {
+	return RectF{Left: r.Left + offset.X, Top: r.Top + offset.Y, Width: r.Width, Height: r.Height}
+}
+
+//Inflate : Rectangle grown by amount on every side. Negative shrinks it
+func (r RectF) Inflate(amount float32) RectF {
+	return RectF{Left: r.Left - amount, Top: r.Top - amount, Width: r.Width + 2*amount, Height: r.Height + 2*amount}
+}
+
+//ClosestPoint : Point of the rectangle closest to p. p itself if inside
+func (r RectF) ClosestPoint(p Vector2f) Vector2f {
+	return p.Clamp(r.Position(), r.Max())
+}
+
+//Corners : Top left, top right, bottom right and bottom left corners
+func (r RectF) Corners() [4]Vector2f {
+	return [4]Vector2f{
+		{X: r.Left, Y: r.Top},
+		{X: r.Right(), Y: r.Top},
+		{X: r.Right(), Y: r.Bottom()},
+		{X: r.Left, Y: r.Bottom()},
+	}
+}
+
+//RayCast : First point where ray enters the rectangle. A ray starting inside
+//hits at its origin
+func (r RectF) RayCast(ray Ray) (RayHit, bool) {
+	dir := ray.Direction.Normalize()
+	if dir == (Vector2f{}) {
+		return RayHit{}, false
+	}
+	tMin, tMax := float32(0), float32(math.Inf(1))
+	var normal Vector2f
+	slab := func(origin, d, min, max float32, axis Vector2f) bool {
+		if d == 0 {
+			return origin >= min && origin <= max
+		}
+		t1, t2 := (min-origin)/d, (max-origin)/d
+		n := axis.Neg()
+		if t1 > t2 {
+			t1, t2 = t2, t1
+			n = axis
+		}
+		if t1 > tMin {
+			tMin = t1
+			normal = n
+		}
+		tMax = minFloat(tMax, t2)
+		return tMin <= tMax
+	}
+	if !slab(ray.Origin.X, dir.X, r.Left, r.Right(), Vector2f{X: 1}) ||
+		!slab(ray.Origin.Y, dir.Y, r.Top, r.Bottom(), Vector2f{Y: 1}) {
+		return RayHit{}, false
+	}
+	return RayHit{Point: ray.Origin.Plus(dir.Scale(tMin)), Normal: normal, Distance: tMin}, true
+}
+
+//ToRectI : Smallest integer rectangle covering r
+func (r RectF) ToRectI() RectI {
+	left, top := int(math.Floor(float64(r.Left))), int(math.Floor(float64(r.Top)))
+	right, bottom := int(math.Ceil(float64(r.Right()))), int(math.Ceil(float64(r.Bottom())))
+	return RectI{Left: left, Top: top, Width: right - left, Height: bottom - top}
+}
+
+//ToSFML : Allows for SFML compatability
+func (r RectF) ToSFML() sf.FloatRect {
+	return sf.FloatRect{Left: r.Left, Top: r.Top, Width: r.Width, Height: r.Height}
+}
+
+//SFFloatRectToRectF : SFML FloatRect to GoldEngine RectF
+func SFFloatRectToRectF(other sf.FloatRect) RectF {
+	return RectF{Left: other.Left, Top: other.Top, Width: other.Width, Height: other.Height}
+}
@@ -0,0 +1,337 @@
+package goldcore
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"sync"
+	"sync/atomic"
+
+	sf "github.com/manyminds/gosfml"
+)
+
+//RenderTarget : Anything that can be drawn to. GameWindow draws to the screen,
+//RenderTexture draws off screen so the result can be composed into another
+//target (minimaps, split screen, thumbnails, post processing...)
+type RenderTarget interface {
+	//GetSize : Size of the target in pixels
+	GetSize() Vector2u
+	//Clear : Fills the whole target with c
+	Clear(c Color)
+	//DrawImage : Draws img with its top left corner at pos, blending it over
+	//what is already there. pos goes through the view like vertices do, one
+	//image pixel per world unit. Fails if img can't be uploaded to the GPU
+	DrawImage(img image.Image, pos Vector2i) error
+	//DrawVertices : Draws triangles made of vertices, see PrimitiveType
+	DrawVertices(vertices []Vertex, primitive PrimitiveType, states RenderStates)
+	//Capture : Copy of the target's pixels. A headless target returns what its
+	//last Display showed, a GPU window reads back its buffer as it is now, the
+	//frame being drawn, and a GPU RenderTexture what its last Display showed
+	Capture() *image.RGBA
+}
+
+//Clear : Fills the window with c
+func (gW *GameWindow) Clear(c Color) {
+	gW.do(func() {
+		gW.renderWindow.Clear(c)
+	})
+}
+
+//DrawImage : Draws img with its top left corner at pos. Like every window
+//draw it is queued on the render thread, so it returns nil and an upload
+//failure is announced with WindowCommandFailed. Don't change img until the
+//frame is presented
+func (gW *GameWindow) DrawImage(img image.Image, pos Vector2i) error {
+	gW.stats.count(0)
+	gW.doErr(func() error {
+		return gW.renderWindow.DrawImage(img, pos)
+	})
+	return nil
+}
+
+//DrawVertices : Draws triangles through the window's camera
+func (gW *GameWindow) DrawVertices(vertices []Vertex, primitive PrimitiveType, states RenderStates) {
+	gW.stats.count(len(vertices))
+	gW.do(func() {
+		gW.renderWindow.DrawVertices(vertices, primitive, states)
+	})
+}
+
+//Draw : Draws d through the window's camera
+func (gW *GameWindow) Draw(d Drawable) {
+	d.Draw(gW, DefaultRenderStates())
+}
+
+//renderSurface : What backs a RenderTexture
+type renderSurface interface {
+	RenderTarget
+	Display()
+	//displayCount : Number of Display calls so far
+	displayCount() uint64
+}
+
+//RenderTexture : Off screen RenderTarget. Draw into it, call Display, then
+//compose it into another target by drawing its Texture with a Sprite
+type RenderTexture struct {
+	renderSurface
+	texture *Texture
+}
+
+//NewRenderTexture : Creates a RenderTexture on the GPU. Fails if the driver
+//can't create one, use NewSoftwareRenderTexture then
+func NewRenderTexture(width, height uint) (*RenderTexture, error) {
+	rt, err := sf.NewRenderTexture(width, height, false)
+	if err != nil {
+		return nil, err
+	}
+	return newRenderTexture(&sfmlRenderTexture{RenderTexture: rt}), nil
+}
+
+//NewSoftwareRenderTexture : Creates a RenderTexture that lives in memory and
+//is drawn by the CPU. Works without a GPU
+func NewSoftwareRenderTexture(width, height uint) *RenderTexture {
+	return newRenderTexture(newSoftwareSurface(width, height))
+}
+
+//newRenderTexture : RenderTexture drawing to surface
+func newRenderTexture(surface renderSurface) *RenderTexture {
+	return &RenderTexture{renderSurface: surface, texture: newSurfaceTexture(surface)}
+}
+
+//Texture : Texture showing what was last displayed. Draw it with a Sprite or
+//vertices. On the GPU the render texture is drawn as is, nothing is read
+//back or uploaded again. Its pixels follow the RenderTexture so don't Update
+//it, and don't draw it into its own RenderTexture
+func (rt *RenderTexture) Texture() *Texture {
+	return rt.texture
+}
+
+//Draw : Draws d on the texture
+func (rt *RenderTexture) Draw(d Drawable) {
+	d.Draw(rt, DefaultRenderStates())
+}
+
+//IsSoftware : Checks whether the texture is drawn by the CPU
+func (rt *RenderTexture) IsSoftware() bool {
+	_, ok := rt.renderSurface.(*softwareSurface)
+	return ok
+}
+
+/////////////////////////////////////
+// Software
+
+//softwareSurface : Double buffered image drawn by the CPU. Used by headless
+//windows and software RenderTextures
+type softwareSurface struct {
+	mutex    sync.Mutex
+	back     *image.RGBA //What is being drawn
+	front    *image.RGBA //What was last displayed
+	view     Transform2D //World to pixels, like an SFML view
+	viewport RectF       //Part of the surface drawn to, as fractions
+	displays uint64
+}
+
+//newSoftwareSurface : Surface with opaque black buffers
+func newSoftwareSurface(width, height uint) *softwareSurface {
+	return &softwareSurface{
+		back:     newFramebuffer(width, height),
+		front:    newFramebuffer(width, height),
+		view:     IdentityTransform(),
+		viewport: RectF{Width: 1, Height: 1},
+	}
+}
+
+//newFramebuffer : Opaque black image of the given size
+func newFramebuffer(width, height uint) *image.RGBA {
+	fb := image.NewRGBA(image.Rect(0, 0, int(width), int(height)))
+	for i := 3; i < len(fb.Pix); i += 4 {
+		fb.Pix[i] = 0xff
+	}
+	return fb
+}
+
+//GetSize : Size of the buffers
+func (s *softwareSurface) GetSize() Vector2u {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	size := s.back.Rect.Size()
+	return Vector2u{X: uint(size.X), Y: uint(size.Y)}
+}
+
+//resize : Reallocates the buffers, their contents are lost
+func (s *softwareSurface) resize(width, height uint) {
+	s.mutex.Lock()
+	s.back = newFramebuffer(width, height)
+	s.front = newFramebuffer(width, height)
+	s.mutex.Unlock()
+}
+
+//Clear : Fills the back buffer with c
+func (s *softwareSurface) Clear(c Color) {
+	s.mutex.Lock()
+	draw.Draw(s.back, s.back.Rect, image.NewUniform(c), image.Point{}, draw.Src)
+	s.mutex.Unlock()
+}
+
+//DrawImage : Blends img over the back buffer. pos goes through the view,
+//like on the GPU, so with a camera the image is drawn as a textured quad
+func (s *softwareSurface) DrawImage(img image.Image, pos Vector2i) error {
+	s.mutex.Lock()
+	if s.view.Equals(IdentityTransform()) && s.viewport == (RectF{Width: 1, Height: 1}) {
+		bounds := img.Bounds()
+		dst := bounds.Sub(bounds.Min).Add(image.Pt(pos.X, pos.Y))
+		draw.Draw(s.back, dst, img, bounds.Min, draw.Over)
+		s.mutex.Unlock()
+		return nil
+	}
+	s.mutex.Unlock()
+	sprite := NewSprite(NewTexture(img))
+	sprite.Position = pos.ToVector2f()
+	sprite.Draw(s, DefaultRenderStates())
+	return nil
+}
+
+//DrawVertices : Rasterizes vertices on the back buffer through the view
+func (s *softwareSurface) DrawVertices(vertices []Vertex, primitive PrimitiveType, states RenderStates) {
+	s.mutex.Lock()
+	size := s.back.Rect.Size()
+	scale := Vector2f{X: float32(size.X), Y: float32(size.Y)}
+	min, max := s.viewport.Position().Mul(scale).Round(), s.viewport.Max().Mul(scale).Round()
+	clip := image.Rect(min.X, min.Y, max.X, max.Y)
+	states.Transform = s.view.Compose(states.Transform)
+	rasterize(s.back, clip, vertices, primitive, states)
+	s.mutex.Unlock()
+}
+
+//setView : Draws through cam from now on, nil goes back to one unit per
+//pixel over the whole surface
+func (s *softwareSurface) setView(cam *Camera2D) {
+	s.mutex.Lock()
+	if cam == nil {
+		s.view, s.viewport = IdentityTransform(), RectF{Width: 1, Height: 1}
+	} else {
+		s.view, s.viewport = cam.Transform(), cam.Viewport
+	}
+	s.mutex.Unlock()
+}
+
+//Display : Presents the back buffer
+func (s *softwareSurface) Display() {
+	s.mutex.Lock()
+	copy(s.front.Pix, s.back.Pix)
+	s.displays++
+	s.mutex.Unlock()
+}
+
+//displayCount : Number of Display calls so far
+func (s *softwareSurface) displayCount() uint64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.displays
+}
+
+//Capture : Copy of the front buffer
+func (s *softwareSurface) Capture() *image.RGBA {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	frame := image.NewRGBA(s.front.Rect)
+	copy(frame.Pix, s.front.Pix)
+	return frame
+}
+
+/////////////////////////////////////
+// SFML
+
+//sfmlRenderTexture : renderSurface backed by an sf.RenderTexture
+type sfmlRenderTexture struct {
+	*sf.RenderTexture
+	images   imageUpload
+	displays uint64 //Accessed atomically
+}
+
+//GetSize : Size of the texture
+func (rt *sfmlRenderTexture) GetSize() Vector2u {
+	return SFVector2uToGEVector2u(rt.RenderTexture.GetSize())
+}
+
+//Clear : Fills the texture with c
+func (rt *sfmlRenderTexture) Clear(c Color) {
+	rt.RenderTexture.Clear(c.ToSFML())
+}
+
+//DrawImage : Uploads img and draws it at pos
+func (rt *sfmlRenderTexture) DrawImage(img image.Image, pos Vector2i) error {
+	return rt.images.draw(rt.RenderTexture, img, pos)
+}
+
+//DrawVertices : Draws vertices through SFML
+func (rt *sfmlRenderTexture) DrawVertices(vertices []Vertex, primitive PrimitiveType, states RenderStates) {
+	drawVerticesSFML(rt.RenderTexture, vertices, primitive, states)
+}
+
+//Display : Updates the texture with what was drawn
+func (rt *sfmlRenderTexture) Display() {
+	rt.RenderTexture.Display()
+	atomic.AddUint64(&rt.displays, 1)
+}
+
+//displayCount : Number of Display calls so far
+func (rt *sfmlRenderTexture) displayCount() uint64 {
+	return atomic.LoadUint64(&rt.displays)
+}
+
+//Capture : Reads the texture back from the GPU
+func (rt *sfmlRenderTexture) Capture() *image.RGBA {
+	return SFImageToRGBA(rt.RenderTexture.GetTexture().CopyToImage())
+}
+
+//ColorToSFML : Any color to sf.Color. SFML colors are not alpha premultiplied
+func ColorToSFML(c color.Color) sf.Color {
+	return ColorFromColor(c).ToSFML()
+}
+
+//ImageToSFML : Any image to an sf.Image
+func ImageToSFML(img image.Image) (*sf.Image, error) {
+	bounds := img.Bounds()
+	return sf.NewImageFromPixels(uint(bounds.Dx()), uint(bounds.Dy()), nrgbaPixels(img))
+}
+
+//nrgbaPixels : Rows of non premultiplied RGBA8, the layout SFML uploads
+func nrgbaPixels(img image.Image) []byte {
+	bounds := img.Bounds()
+	nrgba := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(nrgba, nrgba.Rect, img, bounds.Min, draw.Src)
+	return nrgba.Pix
+}
+
+//imageUpload : Texture and sprite DrawImage uploads images into. They are
+//kept between draws and only created again when the image size changes
+type imageUpload struct {
+	texture *sf.Texture
+	sprite  *sf.Sprite
+}
+
+//draw : Uploads img and draws it at pos on target
+func (u *imageUpload) draw(target sf.RenderTarget, img image.Image, pos Vector2i) error {
+	bounds := img.Bounds()
+	width, height := uint(bounds.Dx()), uint(bounds.Dy())
+	if width == 0 || height == 0 {
+		return nil
+	}
+	if u.texture == nil || u.texture.GetSize() != (sf.Vector2u{X: width, Y: height}) {
+		texture, err := sf.NewTexture(width, height)
+		if err != nil {
+			return fmt.Errorf("goldcore: creating a %dx%d texture for DrawImage: %v", width, height, err)
+		}
+		sprite, err := sf.NewSprite(texture)
+		if err != nil {
+			return fmt.Errorf("goldcore: creating the DrawImage sprite: %v", err)
+		}
+		u.texture, u.sprite = texture, sprite
+	}
+	u.texture.UpdateFromPixels(nrgbaPixels(img), width, height, 0, 0)
+	u.sprite.SetPosition(Vector2f{X: float32(pos.X), Y: float32(pos.Y)}.ToSFML())
+	target.Draw(u.sprite, sf.DefaultRenderStates())
+	return nil
+}
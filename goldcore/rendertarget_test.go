@@ -0,0 +1,109 @@
+package goldcore
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+var (
+	_ RenderTarget = (*GameWindow)(nil)
+	_ RenderTarget = (*RenderTexture)(nil)
+)
+
+func TestSoftwareRenderTexture(t *testing.T) {
+	rt := NewSoftwareRenderTexture(4, 2)
+	if !rt.IsSoftware() {
+		t.Errorf("Software RenderTexture reports it is not")
+	}
+	if size := rt.GetSize(); !size.Equals(Vector2u{4, 2}) {
+		t.Errorf("Expected size %v got %v", Vector2u{4, 2}, size)
+	}
+
+	rt.Clear(ColorRed)
+	//Nothing is visible until Display
+	if diff := compareImages(rt.Capture(), newFramebuffer(4, 2)); diff != "" {
+		t.Errorf("Capture before Display: %s", diff)
+	}
+	rt.Display()
+	want := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for i := 0; i < len(want.Pix); i += 4 {
+		want.Pix[i], want.Pix[i+3] = 0xff, 0xff
+	}
+	if diff := compareImages(rt.Capture(), want); diff != "" {
+		t.Errorf("Capture after Clear: %s", diff)
+	}
+}
+
+func TestComposeRenderTextures(t *testing.T) {
+	//Minimap drawn off screen, then composed into the corner of the window
+	minimap := NewSoftwareRenderTexture(2, 2)
+	minimap.Clear(Color{G: 0xff, A: 0xff})
+	minimap.Display()
+
+	//Half transparent overlay on top of it
+	overlay := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	overlay.SetRGBA(0, 0, color.RGBA{R: 0x80, A: 0x80})
+
+	var target RenderTarget = NewHeadlessGameWindow(4, 4, "Compose")
+	target.Clear(Color{B: 0xff, A: 0xff})
+	target.DrawImage(minimap.Capture(), Vector2i{2, 2})
+	target.DrawImage(overlay, Vector2i{3, 3})
+	target.(*GameWindow).renderWindow.Display()
+
+	frame := target.Capture()
+	expected := map[image.Point]color.RGBA{
+		{0, 0}: {B: 0xff, A: 0xff},
+		{1, 2}: {B: 0xff, A: 0xff},
+		{2, 2}: {G: 0xff, A: 0xff},
+		{3, 2}: {G: 0xff, A: 0xff},
+		{3, 3}: {R: 0x80, G: 0x7f, A: 0xff},
+	}
+	for p, c := range expected {
+		if got := frame.RGBAAt(p.X, p.Y); got != c {
+			t.Errorf("Pixel %v. Expected %v got %v", p, c, got)
+		}
+	}
+}
+
+func TestRenderTextureTexture(t *testing.T) {
+	minimap := NewSoftwareRenderTexture(2, 2)
+	tex := minimap.Texture()
+	if size := tex.Size(); !size.Equals(Vector2u{2, 2}) {
+		t.Errorf("Expected size %v got %v", Vector2u{2, 2}, size)
+	}
+	minimap.Clear(Color{G: 0xff, A: 0xff})
+	if got := tex.Image().RGBAAt(0, 0); got != black {
+		t.Errorf("The texture shows what was displayed, got %v", got)
+	}
+	minimap.Display()
+	if got := tex.Image().RGBAAt(0, 0); got != green {
+		t.Errorf("Expected the displayed contents got %v", got)
+	}
+
+	gW := NewHeadlessGameWindow(4, 4, "Compose")
+	gW.Clear(Color{B: 0xff, A: 0xff})
+	sprite := NewSprite(tex)
+	sprite.Position = Vector2f{X: 2, Y: 2}
+	gW.Draw(sprite)
+	gW.renderWindow.Display()
+	expectPixels(t, gW.Capture(), map[image.Point]color.RGBA{
+		{1, 1}: blue,
+		{2, 2}: green,
+		{3, 3}: green,
+	})
+
+	//Later frames are picked up
+	minimap.Clear(Color{R: 0xff, A: 0xff})
+	minimap.Display()
+	gW.Draw(sprite)
+	gW.renderWindow.Display()
+	expectPixels(t, gW.Capture(), map[image.Point]color.RGBA{{2, 2}: red})
+}
+
+func TestDrawImageEmpty(t *testing.T) {
+	gW := NewHeadlessGameWindow(2, 2, "Empty")
+	if err := gW.DrawImage(image.NewRGBA(image.Rect(0, 0, 0, 0)), Vector2i{}); err != nil {
+		t.Errorf("Drawing an empty image: %v", err)
+	}
+}
@@ -0,0 +1,772 @@
+package goldcore
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+//Define Resource Messages
+var (
+	//Payload ResourceEvent
+	//Out: A resource finished loading, its handles now give it
+	ResourceLoaded = RegisterGameMessage("resource loaded")
+	//Payload ResourceEvent
+	//Out: A resource could not be loaded, its handles give the placeholder
+	ResourceLoadFailed = RegisterGameMessage("resource load failed")
+	//Payload ResourceEvent
+	//Out: A resource's files changed and it was loaded again, see
+	//ResourceManager.SetHotReload. If Err is set the reload failed and the
+	//handles keep what they had
+	ResourceReloaded = RegisterGameMessage("resource reloaded")
+)
+
+//ErrResourceManagerClosed : The manager was closed before the load ran
+var ErrResourceManagerClosed = errors.New("goldcore: resource manager closed")
+
+//ResourceKind : What a resource decodes to
+type ResourceKind int
+
+const (
+	//ResourceTexture : PNG, JPEG or GTEX image, a *Texture
+	ResourceTexture ResourceKind = iota
+	//ResourceFont : TTF, OTF or BMFont .fnt file, a Font
+	ResourceFont
+	//ResourceSound : WAV, OGG or FLAC file, a *Sound
+	ResourceSound
+	//ResourceShader : GLSL, a *Shader. See ResourceManager.Shader
+	ResourceShader
+	//ResourceData : Raw bytes
+	ResourceData
+	//ResourceAtlas : Atlas file written by EncodeAtlas, an *Atlas
+	ResourceAtlas
+)
+
+func (k ResourceKind) String() string {
+	switch k {
+	case ResourceTexture:
+		return "texture"
+	case ResourceFont:
+		return "font"
+	case ResourceSound:
+		return "sound"
+	case ResourceShader:
+		return "shader"
+	case ResourceData:
+		return "data"
+	case ResourceAtlas:
+		return "atlas"
+	}
+	return fmt.Sprintf("ResourceKind(%d)", int(k))
+}
+
+//MarshalText : The kind's name, see String
+func (k ResourceKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+//UnmarshalText : Kind from its name
+func (k *ResourceKind) UnmarshalText(text []byte) error {
+	for kind := ResourceTexture; kind <= ResourceAtlas; kind++ {
+		if kind.String() == string(text) {
+			*k = kind
+			return nil
+		}
+	}
+	return fmt.Errorf("goldcore: unknown resource kind %q", text)
+}
+
+//ResourceState : Where a resource is in its loading
+type ResourceState int
+
+const (
+	//ResourceLoading : Queued or being decoded
+	ResourceLoading ResourceState = iota
+	//ResourceReady : Loaded
+	ResourceReady
+	//ResourceFailed : Could not be loaded
+	ResourceFailed
+)
+
+//ResourceEvent : Payload of ResourceLoaded, ResourceLoadFailed and
+//ResourceReloaded
+type ResourceEvent struct {
+	Kind ResourceKind
+	Path string
+	Err  error //Why it failed, nil if it loaded
+}
+
+/////////////////////////////////////
+///		MANAGER
+/////////////////////////////////////
+
+//ResourceManager : Loads assets once per path on worker goroutines and hands
+//out typed handles to them. Handles count references, resources nobody
+//holds stay cached until UnloadUnused. While a resource is loading, or if it
+//failed, its handles give the placeholder of its kind. Finished loads are
+//announced by Update, on the update thread. Safe for concurrent use
+type ResourceManager struct {
+	notify  func(*GameMessage)
+	workers int
+
+	mutex        sync.Mutex
+	cond         *sync.Cond //Signals workers a load is queued or the manager closed
+	resources    map[resourceKey]*resource
+	placeholders map[ResourceKind]interface{}
+	queue        []*resource
+	events       []resourceMessage //Finished loads waiting for Update
+	started      bool
+	closed       bool
+	wg           sync.WaitGroup
+	fsys         fs.FS
+	manifest     *Manifest
+	hotReload    float32 //Seconds between checks for changed files, 0 for never
+	lastPoll     time.Time
+	now          func() time.Time //The clock polls are timed with, real time
+}
+
+//resourceMessage : A message waiting for Update. Loads finish on worker
+//goroutines, the GameMessage is only made on the update thread
+type resourceMessage struct {
+	msg   GMessage
+	event ResourceEvent
+}
+
+//resourceKey : A path loaded as a kind
+type resourceKey struct {
+	kind ResourceKind
+	path string
+}
+
+//resource : A cached asset. Fields are guarded by the manager's mutex
+type resource struct {
+	key     resourceKey
+	manager *ResourceManager
+	done    chan struct{} //Closed once loaded or failed
+	refs    int
+	state   ResourceState
+	value   interface{}
+	err     error
+	files   map[string]fileStamp //Files the last load read, for hot reloading
+}
+
+//NewResourceManager : Manager loading on workers goroutines, one per CPU if
+//workers < 1, and sending load messages to notify, which may be nil. The
+//workers start with the first load
+func NewResourceManager(notify func(*GameMessage), workers int) *ResourceManager {
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	m := &ResourceManager{
+		notify:       notify,
+		workers:      workers,
+		resources:    make(map[resourceKey]*resource),
+		placeholders: map[ResourceKind]interface{}{ResourceTexture: newPlaceholderTexture()},
+		fsys:         osFS{},
+		now:          time.Now,
+	}
+	m.cond = sync.NewCond(&m.mutex)
+	return m
+}
+
+//newPlaceholderTexture : 8x8 magenta and black checkerboard, hard to miss
+func newPlaceholderTexture() *Texture {
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			c := ColorBlack
+			if (x/4+y/4)%2 == 0 {
+				c = ColorMagenta
+			}
+			img.Set(x, y, c)
+		}
+	}
+	return NewTexture(img)
+}
+
+//SetPlaceholder : What handles of kind give while loading or after failing.
+//value must be a *Texture, Font, *Sound, *Shader, []byte or *Atlas
+//matching kind, or nil for none. Textures default to a checkerboard, the
+//others to nil
+func (m *ResourceManager) SetPlaceholder(kind ResourceKind, value interface{}) error {
+	if value != nil {
+		var ok bool
+		switch kind {
+		case ResourceTexture:
+			_, ok = value.(*Texture)
+		case ResourceFont:
+			_, ok = value.(Font)
+		case ResourceSound:
+			_, ok = value.(*Sound)
+		case ResourceShader:
+			_, ok = value.(*Shader)
+		case ResourceData:
+			_, ok = value.([]byte)
+		case ResourceAtlas:
+			_, ok = value.(*Atlas)
+		}
+		if !ok {
+			return fmt.Errorf("goldcore: %T is not a %v placeholder", value, kind)
+		}
+	}
+	m.mutex.Lock()
+	m.placeholders[kind] = value
+	m.mutex.Unlock()
+	return nil
+}
+
+//SetHotReload : Development mode. Every interval seconds of real time, Update
+//checks the files read by cached resources and loads the changed ones
+//again on the update thread. Textures and shaders are changed in place, so
+//sprites and materials holding them see the new files, the other kinds are
+//swapped behind their handles. Each reload is announced with
+//ResourceReloaded. A resource that failed is retried once its files change.
+//The interval ignores the time scale, and a suspended Game keeps checking so
+//assets can be tweaked while it is paused. 0 turns it off, the default
+func (m *ResourceManager) SetHotReload(interval float32) {
+	if interval < 0 {
+		interval = 0
+	}
+	m.mutex.Lock()
+	m.hotReload, m.lastPoll = interval, m.now()
+	m.mutex.Unlock()
+}
+
+//SetFileSystem : Reads assets from fsys, a VFS for example, rather than
+//from OS paths. nil goes back to OS paths. Call it before loading anything,
+//resources already cached stay as they are
+func (m *ResourceManager) SetFileSystem(fsys fs.FS) {
+	if fsys == nil {
+		fsys = osFS{}
+	}
+	m.mutex.Lock()
+	m.fsys = fsys
+	m.mutex.Unlock()
+}
+
+//SetManifest : Loads through man, the manifest of an asset build whose
+//output is the file system. A path man lists for the kind asked loads the
+//file built for it, other paths load as they are. Images packed into an
+//atlas fail as textures, load their atlas instead. nil stops using a
+//manifest. Call it before loading anything
+func (m *ResourceManager) SetManifest(man *Manifest) {
+	m.mutex.Lock()
+	m.manifest = man
+	m.mutex.Unlock()
+}
+
+//FileSystem : Where assets are read from
+func (m *ResourceManager) FileSystem() fs.FS {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.fsys
+}
+
+//Texture : Handle to the texture at path, loading it if it isn't cached
+func (m *ResourceManager) Texture(path string) *TextureHandle {
+	return &TextureHandle{m.acquire(ResourceTexture, path)}
+}
+
+//Font : Handle to the font at path, loading it if it isn't cached. The
+//extension picks the format, BMFont pages are loaded next to the .fnt
+func (m *ResourceManager) Font(path string) *FontHandle {
+	return &FontHandle{m.acquire(ResourceFont, path)}
+}
+
+//Sound : Handle to the sound at path, loading it if it isn't cached
+func (m *ResourceManager) Sound(path string) *SoundHandle {
+	return &SoundHandle{m.acquire(ResourceSound, path)}
+}
+
+//Shader : Handle to the shader at path, loading it if it isn't cached.
+//A .vert or .frag path is a single stage, any other path loads path.vert
+//and path.frag, leaving out the one that doesn't exist
+func (m *ResourceManager) Shader(path string) *ShaderHandle {
+	return &ShaderHandle{m.acquire(ResourceShader, path)}
+}
+
+//Data : Handle to the bytes of the file at path, loading it if it isn't
+//cached
+func (m *ResourceManager) Data(path string) *DataHandle {
+	return &DataHandle{m.acquire(ResourceData, path)}
+}
+
+//Atlas : Handle to the atlas at path, loading it if it isn't cached
+func (m *ResourceManager) Atlas(path string) *AtlasHandle {
+	return &AtlasHandle{m.acquire(ResourceAtlas, path)}
+}
+
+//acquire : References the resource, queuing its load the first time
+func (m *ResourceManager) acquire(kind ResourceKind, p string) resourceHandle {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	//Different spellings of a path share the resource
+	name, err := NormalizePath(p)
+	if _, ok := m.fsys.(osFS); ok {
+		name, err = path.Clean(filepath.ToSlash(p)), nil
+	}
+	if err != nil {
+		name = p
+	}
+	key := resourceKey{kind: kind, path: name}
+	res, ok := m.resources[key]
+	if !ok {
+		res = &resource{key: key, manager: m, done: make(chan struct{})}
+		m.resources[key] = res
+		if err != nil || m.closed {
+			if err == nil {
+				err = ErrResourceManagerClosed
+			}
+			res.state, res.err = ResourceFailed, err
+			close(res.done)
+		} else {
+			m.queue = append(m.queue, res)
+			m.start()
+			m.cond.Signal()
+		}
+	}
+	res.refs++
+	return resourceHandle{res: res}
+}
+
+//start : Starts the workers once. Mutex held
+func (m *ResourceManager) start() {
+	if m.started {
+		return
+	}
+	m.started = true
+	m.wg.Add(m.workers)
+	for i := 0; i < m.workers; i++ {
+		go m.work()
+	}
+}
+
+//work : Loads queued resources until the manager closes
+func (m *ResourceManager) work() {
+	defer m.wg.Done()
+	m.mutex.Lock()
+	for {
+		for len(m.queue) == 0 && !m.closed {
+			m.cond.Wait()
+		}
+		if m.closed {
+			m.mutex.Unlock()
+			return
+		}
+		res := m.queue[0]
+		m.queue = m.queue[1:]
+		m.mutex.Unlock()
+		value, files, err := m.decode(res.key)
+		m.mutex.Lock()
+		res.files = files
+		m.finish(res, value, err)
+	}
+}
+
+//finish : Stores the result of a load and queues its message. Mutex held
+func (m *ResourceManager) finish(res *resource, value interface{}, err error) {
+	msg := ResourceLoaded
+	if err != nil {
+		res.state, res.err = ResourceFailed, err
+		msg = ResourceLoadFailed
+	} else {
+		res.state, res.value = ResourceReady, value
+	}
+	m.events = append(m.events, resourceMessage{msg: msg, event: ResourceEvent{Kind: res.key.kind, Path: res.key.path, Err: err}})
+	close(res.done)
+}
+
+//Update : Reloads changed resources if hot reloading is on, then sends the
+//messages of the loads finished since the last call. Called by the game on
+//the update thread
+func (m *ResourceManager) Update(dt float32) {
+	m.pollChanged()
+	m.mutex.Lock()
+	events := m.events
+	m.events = nil
+	m.mutex.Unlock()
+	if m.notify == nil {
+		return
+	}
+	for _, e := range events {
+		m.notify(NewGameMessage(e.msg, e.event))
+	}
+}
+
+//Progress : Number of resources loaded or failed, and of resources cached.
+//For loading screens
+func (m *ResourceManager) Progress() (done, total int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for _, res := range m.resources {
+		if res.state != ResourceLoading {
+			done++
+		}
+	}
+	return done, len(m.resources)
+}
+
+//UnloadUnused : Drops the loaded resources no handle references. The next
+//request for them loads them again. Returns how many were dropped
+func (m *ResourceManager) UnloadUnused() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	n := 0
+	for key, res := range m.resources {
+		if res.refs == 0 && res.state != ResourceLoading {
+			delete(m.resources, key)
+			n++
+		}
+	}
+	return n
+}
+
+//Close : Stops the workers. Loads still queued fail with
+//ErrResourceManagerClosed, cached resources stay usable
+func (m *ResourceManager) Close() {
+	m.mutex.Lock()
+	if m.closed {
+		m.mutex.Unlock()
+		return
+	}
+	m.closed = true
+	for _, res := range m.queue {
+		m.finish(res, nil, ErrResourceManagerClosed)
+	}
+	m.queue = nil
+	m.cond.Broadcast()
+	m.mutex.Unlock()
+	m.wg.Wait()
+}
+
+/////////////////////////////////////
+///		HOT RELOADING
+/////////////////////////////////////
+
+//fileStamp : Modification time and size of a file, zero if it is missing
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+//stampOf : Stamp of the file name of fsys
+func stampOf(fsys fs.FS, name string) fileStamp {
+	info, err := fs.Stat(fsys, name)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{modTime: info.ModTime(), size: info.Size()}
+}
+
+//recordingFS : fs.FS noting the stamps of the files opened through it. Used
+//by one load at a time
+type recordingFS struct {
+	fs.FS
+	files map[string]fileStamp
+}
+
+func (r *recordingFS) Open(name string) (fs.File, error) {
+	f, err := r.FS.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	if info, err := f.Stat(); err == nil && !info.IsDir() {
+		r.files[name] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+	}
+	return f, nil
+}
+
+//pollChanged : Reloads changed resources if hot reloading is on and an
+//interval went by since the last check. Reload messages wait for Update
+func (m *ResourceManager) pollChanged() {
+	m.mutex.Lock()
+	poll := false
+	if m.hotReload > 0 {
+		if now := m.now(); now.Sub(m.lastPoll).Seconds() >= float64(m.hotReload) {
+			poll, m.lastPoll = true, now
+		}
+	}
+	m.mutex.Unlock()
+	if poll {
+		m.reloadChanged()
+	}
+}
+
+//reloadChanged : Loads again the resources whose files changed, in path
+//order so messages come out the same way every run
+func (m *ResourceManager) reloadChanged() {
+	type watch struct {
+		res   *resource
+		files map[string]fileStamp
+	}
+	m.mutex.Lock()
+	fsys := m.fsys
+	var watched []watch
+	for _, res := range m.resources {
+		if res.state != ResourceLoading && len(res.files) > 0 {
+			watched = append(watched, watch{res: res, files: res.files})
+		}
+	}
+	m.mutex.Unlock()
+	sort.Slice(watched, func(i, j int) bool {
+		a, b := watched[i].res.key, watched[j].res.key
+		if a.path != b.path {
+			return a.path < b.path
+		}
+		return a.kind < b.kind
+	})
+	for _, w := range watched {
+		stamps := make(map[string]fileStamp, len(w.files))
+		changed := false
+		for name, stamp := range w.files {
+			stamps[name] = stampOf(fsys, name)
+			if !stamps[name].modTime.Equal(stamp.modTime) || stamps[name].size != stamp.size {
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		value, files, err := m.decode(w.res.key)
+		if err != nil {
+			//Keep watching what the last good load read, missing files included
+			for name, stamp := range files {
+				stamps[name] = stamp
+			}
+			files = stamps
+		}
+		m.mutex.Lock()
+		m.reload(w.res, value, files, err)
+		m.mutex.Unlock()
+	}
+}
+
+//reload : Stores the result of a reload and queues its message. A failed
+//reload keeps the old value. Mutex held
+func (m *ResourceManager) reload(res *resource, value interface{}, files map[string]fileStamp, err error) {
+	res.files, res.err = files, err
+	if err == nil {
+		if res.state == ResourceReady {
+			switch old := res.value.(type) {
+			case *Texture:
+				old.replace(value.(*Texture))
+				value = old
+			case *Shader:
+				old.replace(value.(*Shader))
+				value = old
+			}
+		}
+		res.state, res.value = ResourceReady, value
+	}
+	m.events = append(m.events, resourceMessage{msg: ResourceReloaded, event: ResourceEvent{Kind: res.key.kind, Path: res.key.path, Err: err}})
+}
+
+/////////////////////////////////////
+///		DECODING
+/////////////////////////////////////
+
+//decode : Reads and decodes the file behind key, and gives the stamps of
+//the files it read
+func (m *ResourceManager) decode(key resourceKey) (interface{}, map[string]fileStamp, error) {
+	m.mutex.Lock()
+	fsys := &recordingFS{FS: m.fsys, files: make(map[string]fileStamp)}
+	name, err := m.resolve(key)
+	m.mutex.Unlock()
+	var value interface{}
+	switch {
+	case err != nil:
+	case key.kind == ResourceTexture:
+		value, err = LoadTextureFS(fsys, name)
+	case key.kind == ResourceFont:
+		value, err = loadFontFS(fsys, name)
+	case key.kind == ResourceSound:
+		var data []byte
+		if data, err = fs.ReadFile(fsys, name); err == nil {
+			value, err = NewSound(data)
+		}
+	case key.kind == ResourceShader:
+		value, err = loadShaderPathFS(fsys, name)
+	case key.kind == ResourceData:
+		value, err = fs.ReadFile(fsys, name)
+	case key.kind == ResourceAtlas:
+		value, err = LoadAtlasFS(fsys, name)
+	}
+	if err != nil {
+		return nil, fsys.files, fmt.Errorf("goldcore: loading %v %s: %v", key.kind, key.path, err)
+	}
+	return value, fsys.files, nil
+}
+
+//resolve : File to load for key, from the manifest if there is one. Mutex
+//held
+func (m *ResourceManager) resolve(key resourceKey) (string, error) {
+	if m.manifest == nil {
+		return key.path, nil
+	}
+	asset, ok := m.manifest.Assets[key.path]
+	if !ok || asset.Kind != key.kind {
+		return key.path, nil
+	}
+	if asset.Atlas != "" {
+		return "", fmt.Errorf("packed into atlas %s", asset.Atlas)
+	}
+	return asset.File, nil
+}
+
+//loadFontFS : TrueType or BMFont by extension
+func loadFontFS(fsys fs.FS, name string) (Font, error) {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".ttf", ".otf":
+		return LoadTrueTypeFontFS(fsys, name)
+	case ".fnt":
+		return LoadBMFontFS(fsys, name)
+	}
+	return nil, fmt.Errorf("unknown font format %s", path.Ext(name))
+}
+
+//loadShaderPathFS : Shader from one stage, or from both stages next to name
+func loadShaderPathFS(fsys fs.FS, name string) (*Shader, error) {
+	switch path.Ext(name) {
+	case ".vert":
+		return LoadShaderFS(fsys, name, "")
+	case ".frag":
+		return LoadShaderFS(fsys, "", name)
+	}
+	var stages [2]string
+	for i, ext := range []string{".vert", ".frag"} {
+		if _, err := fs.Stat(fsys, name+ext); err == nil {
+			stages[i] = name + ext
+		}
+	}
+	if stages[0] == "" && stages[1] == "" {
+		return nil, fmt.Errorf("no %s.vert or %s.frag", name, name)
+	}
+	return LoadShaderFS(fsys, stages[0], stages[1])
+}
+
+/////////////////////////////////////
+///		HANDLES
+/////////////////////////////////////
+
+//resourceHandle : One reference to a resource
+type resourceHandle struct {
+	res      *resource
+	released bool //Guarded by the manager's mutex
+}
+
+//Path : Path of the resource, normalized
+func (h *resourceHandle) Path() string {
+	return h.res.key.path
+}
+
+//State : Whether the resource is loading, ready or failed
+func (h *resourceHandle) State() ResourceState {
+	h.res.manager.mutex.Lock()
+	defer h.res.manager.mutex.Unlock()
+	return h.res.state
+}
+
+//Ready : Checks whether the resource is loaded
+func (h *resourceHandle) Ready() bool {
+	return h.State() == ResourceReady
+}
+
+//Err : Why the resource failed, or why its last reload failed, nil if it
+//didn't
+func (h *resourceHandle) Err() error {
+	h.res.manager.mutex.Lock()
+	defer h.res.manager.mutex.Unlock()
+	return h.res.err
+}
+
+//Wait : Blocks until the resource is loaded or failed, and returns why it
+//failed
+func (h *resourceHandle) Wait() error {
+	<-h.res.done
+	return h.Err()
+}
+
+//Release : Drops this reference. The resource is freed by UnloadUnused once
+//nothing references it. Releasing twice does nothing
+func (h *resourceHandle) Release() {
+	m := h.res.manager
+	m.mutex.Lock()
+	if !h.released {
+		h.released = true
+		h.res.refs--
+	}
+	m.mutex.Unlock()
+}
+
+//value : The resource, or the placeholder of its kind
+func (h *resourceHandle) value() interface{} {
+	m := h.res.manager
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if h.res.state == ResourceReady {
+		return h.res.value
+	}
+	return m.placeholders[h.res.key.kind]
+}
+
+//TextureHandle : Reference to a texture of a ResourceManager
+type TextureHandle struct{ resourceHandle }
+
+//Texture : The texture, or the placeholder until it is ready
+func (h *TextureHandle) Texture() *Texture {
+	tex, _ := h.value().(*Texture)
+	return tex
+}
+
+//FontHandle : Reference to a font of a ResourceManager
+type FontHandle struct{ resourceHandle }
+
+//Font : The font, or the placeholder until it is ready
+func (h *FontHandle) Font() Font {
+	f, _ := h.value().(Font)
+	return f
+}
+
+//SoundHandle : Reference to a sound of a ResourceManager
+type SoundHandle struct{ resourceHandle }
+
+//Sound : The sound, or the placeholder until it is ready
+func (h *SoundHandle) Sound() *Sound {
+	s, _ := h.value().(*Sound)
+	return s
+}
+
+//ShaderHandle : Reference to a shader of a ResourceManager
+type ShaderHandle struct{ resourceHandle }
+
+//Shader : The shader, or the placeholder until it is ready
+func (h *ShaderHandle) Shader() *Shader {
+	s, _ := h.value().(*Shader)
+	return s
+}
+
+//DataHandle : Reference to a data file of a ResourceManager
+type DataHandle struct{ resourceHandle }
+
+//Data : The file's bytes, or the placeholder until they are ready
+func (h *DataHandle) Data() []byte {
+	data, _ := h.value().([]byte)
+	return data
+}
+
+//AtlasHandle : Reference to an atlas of a ResourceManager
+type AtlasHandle struct{ resourceHandle }
+
+//Atlas : The atlas, or the placeholder until it is ready
+func (h *AtlasHandle) Atlas() *Atlas {
+	atlas, _ := h.value().(*Atlas)
+	return atlas
+}
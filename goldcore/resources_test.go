@@ -0,0 +1,514 @@
+package goldcore
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+//writeFiles : Writes files under dir, by slash separated name
+func writeFiles(t *testing.T, dir string, files map[string][]byte) {
+	t.Helper()
+	for name, data := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+//pngBytes : PNG file of a red w x h image
+func pngBytes(t *testing.T, w, h int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, solid(w, h, red)); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+//testWAV : Header of an empty WAV file
+var testWAV = []byte("RIFF\x24\x00\x00\x00WAVEfmt ")
+
+func TestResourceManager(t *testing.T) {
+	dir := t.TempDir()
+	page := pngBytes(t, 8, 8)
+	writeFiles(t, dir, map[string][]byte{
+		"hero.png":        pngBytes(t, 3, 2),
+		"fonts/test.fnt":  []byte(testFNT),
+		"fonts/test.png":  page,
+		"fonts/go.ttf":    goregular.TTF,
+		"jump.wav":        testWAV,
+		"flash.frag":      []byte(flashShader),
+		"both.vert":       []byte("void main() { gl_Position = gl_Vertex; }"),
+		"both.frag":       []byte(flashShader),
+		"level.json":      []byte(`{"name": "one"}`),
+		"broken.png":      []byte("not a png"),
+		"fonts/wrong.xyz": []byte("?"),
+	})
+	var mutex sync.Mutex
+	var events []ResourceEvent
+	m := NewResourceManager(func(gM *GameMessage) {
+		mutex.Lock()
+		events = append(events, gM.Payload.(ResourceEvent))
+		mutex.Unlock()
+	}, 2)
+	defer m.Close()
+	p := func(name string) string { return filepath.ToSlash(filepath.Join(dir, name)) }
+
+	hero := m.Texture(p("hero.png"))
+	bm := m.Font(p("fonts/test.fnt"))
+	ttf := m.Font(p("fonts/go.ttf"))
+	jump := m.Sound(p("jump.wav"))
+	flash := m.Shader(p("flash.frag"))
+	both := m.Shader(p("both"))
+	level := m.Data(p("level.json"))
+	for _, h := range []interface{ Wait() error }{hero, bm, ttf, jump, flash, both, level} {
+		if err := h.Wait(); err != nil {
+			t.Error(err)
+		}
+	}
+	if size := hero.Texture().Size(); size != (Vector2u{X: 3, Y: 2}) {
+		t.Errorf("Expected a 3x2 texture got %v", size)
+	}
+	if _, ok := bm.Font().(*BMFont); !ok {
+		t.Errorf("Expected a BMFont got %T", bm.Font())
+	}
+	if _, ok := ttf.Font().(*TrueTypeFont); !ok {
+		t.Errorf("Expected a TrueTypeFont got %T", ttf.Font())
+	}
+	if jump.Sound().Format() != "wav" {
+		t.Errorf("Expected a wav got %s", jump.Sound().Format())
+	}
+	if v, _ := both.Shader().Source(); v == "" {
+		t.Error("Both stages should be loaded")
+	}
+	if v, f := flash.Shader().Source(); v != "" || f != flashShader {
+		t.Error("A .frag path is only the fragment stage")
+	}
+	if string(level.Data()) != `{"name": "one"}` {
+		t.Errorf("Unexpected data %q", level.Data())
+	}
+
+	//Cached by path and kind
+	again := m.Texture(p("hero.png"))
+	if !again.Ready() || again.Texture() != hero.Texture() {
+		t.Error("Textures should be cached")
+	}
+	if raw := m.Data(p("hero.png")); raw.Wait() != nil || len(raw.Data()) == 0 {
+		t.Error("The same file can be loaded as another kind")
+	}
+
+	//Failures give the placeholder
+	for _, name := range []string{"missing.png", "broken.png"} {
+		h := m.Texture(p(name))
+		if h.Wait() == nil || h.State() != ResourceFailed {
+			t.Errorf("%s should fail", name)
+		}
+		if h.Texture() == nil || h.Texture().Size() != (Vector2u{X: 8, Y: 8}) {
+			t.Errorf("%s should give the checkerboard", name)
+		}
+	}
+	if h := m.Font(p("fonts/wrong.xyz")); h.Wait() == nil || h.Font() != nil {
+		t.Error("Unknown font formats should fail without a placeholder")
+	}
+	if h := m.Shader(p("missing")); h.Wait() == nil {
+		t.Error("Shaders without stages should fail")
+	}
+	fallback := NewEmptyTexture(1, 1)
+	if err := m.SetPlaceholder(ResourceTexture, fallback); err != nil {
+		t.Fatal(err)
+	}
+	if m.Texture(p("missing.png")).Texture() != fallback {
+		t.Error("Placeholders can be replaced")
+	}
+	if m.SetPlaceholder(ResourceFont, fallback) == nil {
+		t.Error("Placeholders must match their kind")
+	}
+
+	//Messages are sent by Update
+	mutex.Lock()
+	if len(events) != 0 {
+		t.Error("Nothing is announced before Update")
+	}
+	mutex.Unlock()
+	m.Update(0)
+	failed := 0
+	for _, e := range events {
+		if e.Err != nil {
+			failed++
+		}
+	}
+	if len(events) != 12 || failed != 4 {
+		t.Errorf("Expected 12 loads with 4 failures got %d and %d", len(events), failed)
+	}
+	if done, total := m.Progress(); done != 12 || total != 12 {
+		t.Errorf("Expected 12 of 12 done got %d of %d", done, total)
+	}
+
+	//Unloading only drops what nobody holds, releasing twice counts once
+	hero.Release()
+	hero.Release()
+	if n := m.UnloadUnused(); n != 0 {
+		t.Errorf("hero.png is still held, got %d dropped", n)
+	}
+	again.Release()
+	if n := m.UnloadUnused(); n != 1 {
+		t.Errorf("Expected hero.png dropped got %d", n)
+	}
+	if reloaded := m.Texture(p("hero.png")); reloaded.Wait() != nil || reloaded.Texture() == hero.Texture() {
+		t.Error("Unloaded resources load again")
+	}
+
+	m.Close()
+	if h := m.Data(p("level2.json")); !errors.Is(h.Wait(), ErrResourceManagerClosed) {
+		t.Errorf("Loads after Close should fail, got %v", h.Err())
+	}
+}
+
+func TestResourceMessagesOnUpdateThread(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string][]byte{}
+	for i := 0; i < 32; i++ {
+		files[strconv.Itoa(i)+".txt"] = []byte("data")
+	}
+	writeFiles(t, dir, files)
+	loaded := 0
+	m := NewResourceManager(func(gM *GameMessage) {
+		if gM.Message == ResourceLoaded || gM.Message == ResourceLoadFailed {
+			loaded++
+		}
+	}, 4)
+	defer m.Close()
+	var handles []*DataHandle
+	for name := range files {
+		handles = append(handles, m.Data(filepath.Join(dir, name)))
+	}
+	//Workers finish loads while the update thread makes its own messages
+	for loaded < len(files) {
+		NewGameMessage(WindowRendered, nil)
+		m.Update(0)
+	}
+	for _, h := range handles {
+		if err := h.Wait(); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestGameResources(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string][]byte{"a.txt": []byte("a")})
+	game := NewGame()
+	defer game.Resources().Close()
+	recorder := &gameMessageRecorder{}
+	game.AddObserver(recorder)
+	h := game.Resources().Data(filepath.Join(dir, "a.txt"))
+	h.Wait()
+	game.Update(0)
+	if len(recorder.messages) != 0 {
+		t.Error("A stopped game announces nothing")
+	}
+	game.Play()
+	game.Update(0)
+	if len(recorder.messages) != 1 || recorder.messages[0].Message != ResourceLoaded {
+		t.Errorf("Expected the load announced got %v", recorder.messages)
+	}
+}
+
+func TestHotReload(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string][]byte{
+		"hero.png":   pngBytes(t, 2, 2),
+		"flash.frag": []byte(flashShader),
+		"level.json": []byte("one"),
+		"broken.png": []byte("not a png"),
+	})
+	var events []ResourceEvent
+	m := NewResourceManager(func(gM *GameMessage) {
+		if gM.Message == ResourceReloaded {
+			events = append(events, gM.Payload.(ResourceEvent))
+		}
+	}, 1)
+	defer m.Close()
+	//Polls are timed in real time, tick moves a fake clock and updates
+	clock := time.Now()
+	m.now = func() time.Time { return clock }
+	tick := func(seconds float64) {
+		clock = clock.Add(time.Duration(seconds * float64(time.Second)))
+		m.Update(0)
+	}
+	p := func(name string) string { return filepath.ToSlash(filepath.Join(dir, name)) }
+	//Rewrites name with a later modification time, so the change shows
+	//whatever the file system's time resolution
+	bump := time.Now()
+	change := func(name string, data []byte) {
+		writeFiles(t, dir, map[string][]byte{name: data})
+		bump = bump.Add(time.Minute)
+		if err := os.Chtimes(filepath.Join(dir, name), bump, bump); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hero := m.Texture(p("hero.png"))
+	flash := m.Shader(p("flash.frag"))
+	level := m.Data(p("level.json"))
+	broken := m.Texture(p("broken.png"))
+	for _, h := range []interface{ Wait() error }{hero, flash, level} {
+		if err := h.Wait(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	broken.Wait()
+	tex, shader := hero.Texture(), flash.Shader()
+	mat := NewMaterial(shader)
+
+	//Off by default
+	change("hero.png", pngBytes(t, 4, 4))
+	tick(10)
+	if tex.Size() != (Vector2u{X: 2, Y: 2}) || len(events) != 0 {
+		t.Fatal("Nothing reloads unless asked")
+	}
+
+	m.SetHotReload(1)
+	tick(0.5)
+	if tex.Size() != (Vector2u{X: 2, Y: 2}) {
+		t.Error("Files are only checked every interval")
+	}
+	change("flash.frag", []byte("uniform float glow;\nvoid main() { gl_FragColor = vec4(glow); }"))
+	change("level.json", []byte("two"))
+	tick(0.5)
+	if hero.Texture() != tex || tex.Size() != (Vector2u{X: 4, Y: 4}) {
+		t.Errorf("Textures reload in place, got %v", tex.Size())
+	}
+	if flash.Shader() != shader {
+		t.Error("Shaders reload in place")
+	}
+	if _, ok := shader.UniformType("glow"); !ok {
+		t.Error("The reloaded shader should declare glow")
+	}
+	if err := mat.SetFloat("glow", 1); err != nil {
+		t.Errorf("Materials see the new uniforms: %v", err)
+	}
+	if string(level.Data()) != "two" {
+		t.Errorf("Expected the new data got %q", level.Data())
+	}
+	if len(events) != 3 || events[0].Path != p("flash.frag") || events[1].Path != p("hero.png") || events[2].Path != p("level.json") {
+		t.Errorf("Expected 3 reloads in path order got %v", events)
+	}
+
+	//A broken file keeps what was there, once
+	events = nil
+	change("hero.png", []byte("not a png"))
+	tick(1)
+	tick(1)
+	if len(events) != 1 || events[0].Err == nil || hero.Err() == nil {
+		t.Errorf("Expected one failed reload got %v", events)
+	}
+	if !hero.Ready() || tex.Size() != (Vector2u{X: 4, Y: 4}) {
+		t.Error("A failed reload keeps the old texture")
+	}
+
+	//Fixing files clears the error, failed loads included
+	events = nil
+	change("hero.png", pngBytes(t, 1, 1))
+	change("broken.png", pngBytes(t, 3, 3))
+	tick(1)
+	if len(events) != 2 || hero.Err() != nil || tex.Size() != (Vector2u{X: 1, Y: 1}) {
+		t.Errorf("Expected hero.png fixed got %v", events)
+	}
+	if !broken.Ready() || broken.Texture().Size() != (Vector2u{X: 3, Y: 3}) {
+		t.Error("A failed load is retried once its file changes")
+	}
+
+	m.SetHotReload(0)
+	events = nil
+	change("hero.png", pngBytes(t, 2, 2))
+	tick(10)
+	if len(events) != 0 {
+		t.Error("Hot reloading can be turned off")
+	}
+}
+
+func TestHotReloadWhileLoading(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string][]byte{"level.json": []byte("one")})
+	var reloaded, loaded int
+	m := NewResourceManager(func(gM *GameMessage) {
+		switch gM.Message {
+		case ResourceReloaded:
+			reloaded++
+		case ResourceLoaded, ResourceLoadFailed:
+			loaded++
+		}
+	}, 4)
+	defer m.Close()
+	level := m.Data(filepath.Join(dir, "level.json"))
+	if err := level.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	m.Update(0)
+	loaded = 0
+
+	files := map[string][]byte{}
+	for i := 0; i < 32; i++ {
+		files[strconv.Itoa(i)+".txt"] = []byte("data")
+	}
+	writeFiles(t, dir, files)
+	later := time.Now().Add(time.Minute)
+	writeFiles(t, dir, map[string][]byte{"level.json": []byte("two")})
+	if err := os.Chtimes(filepath.Join(dir, "level.json"), later, later); err != nil {
+		t.Fatal(err)
+	}
+	clock := time.Now()
+	m.now = func() time.Time { return clock }
+	m.SetHotReload(1)
+	for name := range files {
+		m.Data(filepath.Join(dir, name))
+	}
+	//Reloads and loads finishing on workers share the queue Update empties
+	clock = clock.Add(time.Second)
+	m.Update(1)
+	for loaded < len(files) {
+		NewGameMessage(WindowRendered, nil)
+		m.Update(0)
+	}
+	if reloaded != 1 || string(level.Data()) != "two" {
+		t.Errorf("Expected level.json reloaded once got %d and %q", reloaded, level.Data())
+	}
+}
+
+func TestGameHotReloadWhilePaused(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string][]byte{"level.json": []byte("one")})
+	game := NewGame()
+	m := game.Resources()
+	defer m.Close()
+	clock := time.Now()
+	m.now = func() time.Time { return clock }
+	recorder := &gameMessageRecorder{}
+	game.AddObserver(recorder)
+	name := filepath.Join(dir, "level.json")
+	level := m.Data(name)
+	if err := level.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	bump := time.Now()
+	change := func(data string) {
+		writeFiles(t, dir, map[string][]byte{"level.json": []byte(data)})
+		bump = bump.Add(time.Minute)
+		if err := os.Chtimes(name, bump, bump); err != nil {
+			t.Fatal(err)
+		}
+	}
+	game.Play()
+	m.SetHotReload(1)
+
+	//Frozen time still polls every second of real time
+	game.SetTimeScale(0)
+	change("two")
+	clock = clock.Add(time.Second)
+	game.Update(1)
+	if string(level.Data()) != "two" {
+		t.Errorf("Expected a reload with a time scale of 0 got %q", level.Data())
+	}
+
+	//So does a suspended game, whose messages wait until it plays again
+	recorder.messages = nil
+	game.Suspend()
+	change("three")
+	clock = clock.Add(time.Second)
+	game.Update(1)
+	if string(level.Data()) != "three" || len(recorder.messages) != 0 {
+		t.Errorf("Expected a silent reload while suspended got %q and %v", level.Data(), recorder.messages)
+	}
+	game.Play()
+	game.Update(0)
+	if len(recorder.messages) != 1 || recorder.messages[0].Message != ResourceReloaded {
+		t.Errorf("Expected the reload announced once playing got %v", recorder.messages)
+	}
+}
+
+func TestManifest(t *testing.T) {
+	var gtex, atlasFile bytes.Buffer
+	if err := EncodeGTEX(&gtex, solid(5, 4, red)); err != nil {
+		t.Fatal(err)
+	}
+	b := NewAtlasBuilder()
+	b.Add("sprites/a.png", solid(2, 2, green))
+	atlas, _ := b.Build()
+	if err := EncodeAtlas(&atlasFile, atlas, []string{"sprites.0.png"}); err != nil {
+		t.Fatal(err)
+	}
+	fsys := fstest.MapFS{
+		"hero.gtex":     {Data: gtex.Bytes()},
+		"sprites.atlas": {Data: atlasFile.Bytes()},
+		"sprites.0.png": {Data: pngBytes(t, 4, 4)},
+		"level.json":    {Data: []byte("{}")},
+	}
+	man := NewManifest()
+	man.Assets["hero.png"] = ManifestAsset{Kind: ResourceTexture, File: "hero.gtex", Hashes: map[string]string{"hero.gtex": HashBytes(gtex.Bytes())}}
+	man.Assets["sprites"] = ManifestAsset{Kind: ResourceAtlas, File: "sprites.atlas", Hashes: map[string]string{"sprites.atlas": HashBytes(atlasFile.Bytes())}}
+	man.Assets["sprites/a.png"] = ManifestAsset{Kind: ResourceTexture, File: "sprites.atlas", Atlas: "sprites"}
+
+	var buf bytes.Buffer
+	if err := EncodeManifest(&buf, man); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"kind": "atlas"`) {
+		t.Errorf("Kinds are written by name:\n%s", buf.String())
+	}
+	fsys["manifest.json"] = &fstest.MapFile{Data: buf.Bytes()}
+	loaded, err := LoadManifestFS(fsys, "manifest.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, man) {
+		t.Errorf("Expected %+v got %+v", man, loaded)
+	}
+	if err := loaded.Verify(fsys); err != nil {
+		t.Error(err)
+	}
+	for _, bad := range []string{`{"version": 9}`, `{"version": 1, "assets": {"a": {"kind": "music"}}}`} {
+		if _, err := ParseManifest(strings.NewReader(bad)); err == nil {
+			t.Errorf("%s should fail", bad)
+		}
+	}
+
+	m := NewResourceManager(nil, 1)
+	defer m.Close()
+	m.SetFileSystem(fsys)
+	m.SetManifest(loaded)
+	if hero := m.Texture("hero.png"); hero.Wait() != nil || hero.Texture().Size() != (Vector2u{X: 5, Y: 4}) {
+		t.Errorf("Textures load from the file built for them: %v", hero.Err())
+	}
+	if h := m.Atlas("sprites"); h.Wait() != nil || h.Atlas().Sprite("sprites/a.png") == nil {
+		t.Errorf("Atlases load by name: %v", h.Err())
+	}
+	if h := m.Texture("sprites/a.png"); h.Wait() == nil || !strings.Contains(h.Err().Error(), "packed into atlas sprites") {
+		t.Errorf("Packed images should point at their atlas, got %v", h.Err())
+	}
+	if h := m.Data("level.json"); h.Wait() != nil {
+		t.Errorf("Paths the manifest doesn't list load as they are: %v", h.Err())
+	}
+
+	fsys["hero.gtex"] = &fstest.MapFile{Data: []byte("changed")}
+	delete(fsys, "sprites.atlas")
+	if err := loaded.Verify(fsys); err == nil || !strings.Contains(err.Error(), "hero.gtex: hash mismatch") || !strings.Contains(err.Error(), "sprites.atlas") {
+		t.Errorf("Expected both files reported got %v", err)
+	}
+}
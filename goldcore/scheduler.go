@@ -0,0 +1,374 @@
+package goldcore
+
+import (
+	"container/heap"
+	"math"
+	"runtime"
+	"sync"
+)
+
+//Scheduler : Runs functions after some game time, or every so often. Every
+//Game has one, see Game.Scheduler. Callbacks run on the update thread, from
+//Update, so they never race with gameplay and stop while the game is
+//suspended. Timers due in the same update fire by due time, then in the
+//order they were created, so a run always plays out the same way
+type Scheduler struct {
+	mutex       sync.Mutex
+	now         float64
+	seq         uint64
+	timers      timerHeap
+	pending     []*Timer //Scheduled while firing, join timers next update
+	updating    bool
+	frameTimers []*Timer
+	coroutines  map[*Coroutine]struct{}
+}
+
+//Timer : Handle of a scheduled function
+type Timer struct {
+	scheduler *Scheduler
+	due       float64
+	interval  float64
+	repeat    bool
+	everyTick bool
+	seq       uint64
+	index     int //Position in the heap, -1 when not in it
+	f         func()
+	active    bool
+}
+
+//NewScheduler : Scheduler with nothing scheduled
+func NewScheduler() *Scheduler {
+	return &Scheduler{coroutines: make(map[*Coroutine]struct{})}
+}
+
+//Now : Game time the scheduler reached, in seconds
+func (s *Scheduler) Now() float64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.now
+}
+
+//After : Calls f once, delay seconds of game time from now
+func (s *Scheduler) After(delay float32, f func()) *Timer {
+	return s.schedule(&Timer{interval: float64(delay), f: f}, float64(delay))
+}
+
+//Every : Calls f every interval seconds of game time, starting interval
+//seconds from now. If an update covers several intervals f is called once
+//for each. An interval of 0 or less calls f once per update instead
+func (s *Scheduler) Every(interval float32, f func()) *Timer {
+	if interval <= 0 {
+		return s.EveryTick(f)
+	}
+	return s.schedule(&Timer{interval: float64(interval), repeat: true, f: f}, float64(interval))
+}
+
+//EveryTick : Calls f once per update, after the timers that are due,
+//starting with the next update
+func (s *Scheduler) EveryTick(f func()) *Timer {
+	t := &Timer{scheduler: s, everyTick: true, index: -1, f: f, active: true}
+	s.mutex.Lock()
+	s.frameTimers = append(s.frameTimers, t)
+	s.mutex.Unlock()
+	return t
+}
+
+func (s *Scheduler) schedule(t *Timer, delay float64) *Timer {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	t.scheduler = s
+	t.active = true
+	t.due = s.now + math.Max(delay, 0)
+	t.seq = s.seq
+	s.seq++
+	if s.updating {
+		t.index = -1
+		s.pending = append(s.pending, t)
+	} else {
+		heap.Push(&s.timers, t)
+	}
+	return t
+}
+
+//Cancel : Stops the timer. False if it already fired for good or was
+//cancelled
+func (t *Timer) Cancel() bool {
+	s := t.scheduler
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.cancel(t)
+}
+
+//cancel : Cancel with the mutex held
+func (s *Scheduler) cancel(t *Timer) bool {
+	if !t.active {
+		return false
+	}
+	t.active = false
+	switch {
+	case t.everyTick:
+		s.frameTimers = removeTimer(s.frameTimers, t)
+	case t.index >= 0:
+		heap.Remove(&s.timers, t.index)
+	default:
+		s.pending = removeTimer(s.pending, t)
+	}
+	return true
+}
+
+//removeTimer : timers without t, copied so snapshots being iterated stay
+//intact
+func removeTimer(timers []*Timer, t *Timer) []*Timer {
+	for i, o := range timers {
+		if o == t {
+			return append(timers[:i:i], timers[i+1:]...)
+		}
+	}
+	return timers
+}
+
+//IsActive : Checks whether the timer will still fire
+func (t *Timer) IsActive() bool {
+	t.scheduler.mutex.Lock()
+	defer t.scheduler.mutex.Unlock()
+	return t.active
+}
+
+//Remaining : Game time left before the timer fires next, 0 for EveryTick
+//timers and inactive ones
+func (t *Timer) Remaining() float32 {
+	t.scheduler.mutex.Lock()
+	defer t.scheduler.mutex.Unlock()
+	if !t.active || t.everyTick {
+		return 0
+	}
+	return float32(t.due - t.scheduler.now)
+}
+
+//Update : Advances game time by dt seconds and fires what is due. Timers
+//scheduled by callbacks fire on a later update, even with no delay
+func (s *Scheduler) Update(dt float32) {
+	s.mutex.Lock()
+	for _, t := range s.pending {
+		heap.Push(&s.timers, t)
+	}
+	s.pending = nil
+	frameTimers := s.frameTimers
+	s.now += float64(dt)
+	s.updating = true
+	for s.timers.Len() > 0 && s.timers[0].due <= s.now {
+		s.fire(s.timers[0])
+	}
+	s.updating = false
+	s.mutex.Unlock()
+
+	for _, t := range frameTimers {
+		s.mutex.Lock()
+		active := t.active
+		s.mutex.Unlock()
+		if active {
+			t.f()
+		}
+	}
+}
+
+//fire : Takes t off the heap, or moves it to its next due time, and calls
+//it without the mutex. Must hold the mutex
+func (s *Scheduler) fire(t *Timer) {
+	if t.repeat {
+		t.due += t.interval
+		heap.Fix(&s.timers, t.index)
+	} else {
+		heap.Remove(&s.timers, t.index)
+		t.active = false
+	}
+	s.mutex.Unlock()
+	t.f()
+	s.mutex.Lock()
+}
+
+//Clear : Cancels every timer and coroutine and sets the time back to 0
+func (s *Scheduler) Clear() {
+	s.mutex.Lock()
+	coroutines := make([]*Coroutine, 0, len(s.coroutines))
+	for co := range s.coroutines {
+		coroutines = append(coroutines, co)
+	}
+	s.mutex.Unlock()
+	for _, co := range coroutines {
+		co.Cancel()
+	}
+
+	s.mutex.Lock()
+	for _, t := range s.timers {
+		t.active = false
+		t.index = -1
+	}
+	for _, t := range s.pending {
+		t.active = false
+	}
+	for _, t := range s.frameTimers {
+		t.active = false
+	}
+	s.timers, s.pending, s.frameTimers = nil, nil, nil
+	s.now = 0
+	s.mutex.Unlock()
+}
+
+//Len : Number of active timers, coroutine waits included
+func (s *Scheduler) Len() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.timers) + len(s.pending) + len(s.frameTimers)
+}
+
+//timerBefore : Ordering of due timers, by due time then creation
+func timerBefore(a, b *Timer) bool {
+	if a.due != b.due {
+		return a.due < b.due
+	}
+	return a.seq < b.seq
+}
+
+//timerHeap : container/heap of timers ordered by timerBefore
+type timerHeap []*Timer
+
+func (h timerHeap) Len() int           { return len(h) }
+func (h timerHeap) Less(i, j int) bool { return timerBefore(h[i], h[j]) }
+func (h timerHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *timerHeap) Push(x interface{}) {
+	t := x.(*Timer)
+	t.index = len(*h)
+	*h = append(*h, t)
+}
+
+func (h *timerHeap) Pop() interface{} {
+	old := *h
+	t := old[len(old)-1]
+	old[len(old)-1] = nil
+	t.index = -1
+	*h = old[:len(old)-1]
+	return t
+}
+
+/////////////////////////////////////
+///		COROUTINES
+/////////////////////////////////////
+
+//Coroutine : Function spread over several updates, written as straight
+//line code with waits in between. It runs on its own goroutine but only
+//while the update thread waits for it, so it never runs in parallel with
+//gameplay and is as deterministic as a timer callback
+type Coroutine struct {
+	scheduler *Scheduler
+	resume    chan bool
+	yield     chan struct{}
+	wait      *Timer
+	done      bool
+	mutex     sync.Mutex
+}
+
+//Go : Starts f as a coroutine on the next update
+func (s *Scheduler) Go(f func(co *Coroutine)) *Coroutine {
+	co := &Coroutine{scheduler: s, resume: make(chan bool), yield: make(chan struct{})}
+	s.mutex.Lock()
+	s.coroutines[co] = struct{}{}
+	s.mutex.Unlock()
+	go func() {
+		defer func() {
+			co.mutex.Lock()
+			co.done = true
+			co.mutex.Unlock()
+			s.mutex.Lock()
+			delete(s.coroutines, co)
+			s.mutex.Unlock()
+			co.yield <- struct{}{}
+		}()
+		if !<-co.resume {
+			return
+		}
+		f(co)
+	}()
+	wait := s.After(0, co.step)
+	co.mutex.Lock()
+	co.wait = wait
+	co.mutex.Unlock()
+	return co
+}
+
+//step : Lets the coroutine run until its next wait. Called on the update
+//thread
+func (co *Coroutine) step() {
+	co.resume <- true
+	<-co.yield
+}
+
+//suspend : Hands control back to the update thread until the wait w fires,
+//which calls step. Ends the coroutine if it is cancelled meanwhile
+func (co *Coroutine) suspend(w *Timer) {
+	co.mutex.Lock()
+	co.wait = w
+	co.mutex.Unlock()
+	co.yield <- struct{}{}
+	if !<-co.resume {
+		//Cancelled, unwind the coroutine's goroutine
+		runtime.Goexit()
+	}
+}
+
+//Wait : Pauses the coroutine for seconds of game time
+func (co *Coroutine) Wait(seconds float32) {
+	co.suspend(co.scheduler.After(seconds, co.step))
+}
+
+//WaitTicks : Pauses the coroutine for n updates
+func (co *Coroutine) WaitTicks(n int) {
+	co.WaitUntil(func() bool {
+		n--
+		return n <= 0
+	})
+}
+
+//WaitUntil : Pauses the coroutine until cond returns true. cond is checked
+//once per update, on the update thread
+func (co *Coroutine) WaitUntil(cond func() bool) {
+	var t *Timer
+	t = co.scheduler.EveryTick(func() {
+		if cond() {
+			t.Cancel()
+			co.step()
+		}
+	})
+	co.suspend(t)
+}
+
+//Cancel : Stops the coroutine at its current wait. Deferred calls of the
+//coroutine run before Cancel returns. Don't call it from the coroutine
+//itself, return instead
+func (co *Coroutine) Cancel() {
+	co.mutex.Lock()
+	if co.done {
+		co.mutex.Unlock()
+		return
+	}
+	wait := co.wait
+	co.mutex.Unlock()
+	if wait != nil && !wait.Cancel() {
+		//The wait already fired, the coroutine is running or finished
+		return
+	}
+	co.resume <- false
+	<-co.yield
+}
+
+//IsDone : Checks whether the coroutine returned or was cancelled
+func (co *Coroutine) IsDone() bool {
+	co.mutex.Lock()
+	defer co.mutex.Unlock()
+	return co.done
+}
@@ -0,0 +1,170 @@
+package goldcore
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSchedulerTimers(t *testing.T) {
+	s := NewScheduler()
+	var log []string
+	s.After(1, func() { log = append(log, "b") })
+	s.After(0.5, func() { log = append(log, "a") })
+	s.After(1, func() { log = append(log, "c") })
+	every := s.Every(0.4, func() { log = append(log, "tick") })
+	cancelled := s.After(0.2, func() { log = append(log, "cancelled") })
+	if !cancelled.Cancel() || cancelled.Cancel() || cancelled.IsActive() {
+		t.Error("a timer should cancel once")
+	}
+
+	s.Update(0.3)
+	if len(log) != 0 {
+		t.Errorf("nothing is due after 0.3s, got %v", log)
+	}
+	//Covers two ticks, at 0.4 and 0.8, around a at 0.5
+	s.Update(0.6)
+	expected := []string{"tick", "a", "tick"}
+	if !reflect.DeepEqual(log, expected) {
+		t.Errorf("expected %v, got %v", expected, log)
+	}
+	if r := every.Remaining(); !ApproxEqual(r, 0.3, 1e-5) {
+		t.Errorf("next tick in 0.3s, got %v", r)
+	}
+	log = nil
+	s.Update(0.1)
+	//Equal due times fire in the order they were scheduled
+	expected = []string{"b", "c"}
+	if !reflect.DeepEqual(log, expected) {
+		t.Errorf("expected %v, got %v", expected, log)
+	}
+	every.Cancel()
+	if s.Len() != 0 {
+		t.Errorf("every timer fired or was cancelled, %d left", s.Len())
+	}
+}
+
+func TestSchedulerScheduleFromCallback(t *testing.T) {
+	s := NewScheduler()
+	count := 0
+	var again func()
+	again = func() {
+		count++
+		s.After(0, again)
+	}
+	s.After(0, again)
+	for i := 1; i <= 3; i++ {
+		s.Update(0.016)
+		if count != i {
+			t.Fatalf("update %d: a timer scheduled while firing waits for the next update, fired %d times", i, count)
+		}
+	}
+
+	ticks := 0
+	tick := s.EveryTick(func() { ticks++ })
+	s.Update(0)
+	s.Update(0)
+	tick.Cancel()
+	s.Update(0)
+	if ticks != 2 {
+		t.Errorf("expected 2 ticks, got %d", ticks)
+	}
+}
+
+func TestCoroutine(t *testing.T) {
+	s := NewScheduler()
+	var log []string
+	co := s.Go(func(co *Coroutine) {
+		log = append(log, "start")
+		co.Wait(1)
+		log = append(log, "waited")
+		co.WaitTicks(2)
+		log = append(log, "ticked")
+		ready := false
+		s.After(0.5, func() { ready = true })
+		co.WaitUntil(func() bool { return ready })
+		log = append(log, "ready")
+	})
+	steps := []struct {
+		dt       float32
+		expected []string
+	}{
+		{0, []string{"start"}},
+		{0.5, []string{"start"}},
+		{0.5, []string{"start", "waited"}},
+		{0, []string{"start", "waited"}},
+		{0, []string{"start", "waited", "ticked"}},
+		{0.4, []string{"start", "waited", "ticked"}},
+		{0.1, []string{"start", "waited", "ticked", "ready"}},
+	}
+	for i, step := range steps {
+		s.Update(step.dt)
+		if !reflect.DeepEqual(log, step.expected) {
+			t.Fatalf("step %d: expected %v, got %v", i, step.expected, log)
+		}
+	}
+	if !co.IsDone() {
+		t.Error("the coroutine returned")
+	}
+}
+
+func TestCoroutineCancel(t *testing.T) {
+	s := NewScheduler()
+	reached, deferred := false, false
+	co := s.Go(func(co *Coroutine) {
+		defer func() { deferred = true }()
+		co.Wait(1)
+		reached = true
+	})
+	s.Update(0.5)
+	co.Cancel()
+	if !co.IsDone() || !deferred {
+		t.Error("Cancel should end the coroutine and run its deferred calls")
+	}
+	s.Update(1)
+	if reached || s.Len() != 0 {
+		t.Error("a cancelled coroutine doesn't resume")
+	}
+
+	started := false
+	s.Go(func(co *Coroutine) { started = true })
+	s.Go(func(co *Coroutine) { co.Wait(10) })
+	s.Clear()
+	s.Update(1)
+	if started || s.Len() != 0 || s.Now() != 1 {
+		t.Error("Clear cancels coroutines that haven't started yet and resets the time")
+	}
+}
+
+func TestGameScheduler(t *testing.T) {
+	game := NewGame()
+	fired := 0
+	game.Scheduler().Every(1, func() { fired++ })
+
+	game.Update(1)
+	if fired != 0 {
+		t.Error("a stopped game doesn't advance its scheduler")
+	}
+	game.Play()
+	game.SetTimeScale(0.5)
+	game.Update(1)
+	if fired != 0 || game.Time() != 0.5 {
+		t.Errorf("half speed: expected no tick at 0.5s, got %d at %v", fired, game.Time())
+	}
+	game.Update(1)
+	if fired != 1 {
+		t.Errorf("expected a tick at 1s of game time, got %d", fired)
+	}
+	game.Suspend()
+	game.Update(10)
+	if fired != 1 {
+		t.Error("a suspended game doesn't fire timers")
+	}
+	game.SetTimeScale(-1)
+	if game.TimeScale() != 0 {
+		t.Error("negative time scales count as 0")
+	}
+	game.Stop()
+	if game.Scheduler().Len() != 0 {
+		t.Error("stopping the game cancels its timers")
+	}
+}
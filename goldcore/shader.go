@@ -0,0 +1,585 @@
+package goldcore
+
+import (
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	sf "github.com/manyminds/gosfml"
+)
+
+//UniformType : GLSL type of a uniform
+type UniformType int
+
+const (
+	//UniformOther : A type without a setter, like int or mat3, or an array
+	UniformOther UniformType = iota
+	//UniformFloat : float, set with SetFloat
+	UniformFloat
+	//UniformVec2 : vec2, set with SetVector2
+	UniformVec2
+	//UniformVec3 : vec3, set with SetVector3 or SetColor
+	UniformVec3
+	//UniformVec4 : vec4, set with SetColor
+	UniformVec4
+	//UniformSampler2D : sampler2D, set with SetTexture
+	UniformSampler2D
+)
+
+//String : GLSL name of the type
+func (t UniformType) String() string {
+	switch t {
+	case UniformFloat:
+		return "float"
+	case UniformVec2:
+		return "vec2"
+	case UniformVec3:
+		return "vec3"
+	case UniformVec4:
+		return "vec4"
+	case UniformSampler2D:
+		return "sampler2D"
+	default:
+		return "other"
+	}
+}
+
+//glslTypes : Uniform types by GLSL name
+var glslTypes = map[string]UniformType{
+	"float": UniformFloat, "vec2": UniformVec2, "vec3": UniformVec3, "vec4": UniformVec4, "sampler2D": UniformSampler2D,
+}
+
+//UniformInfo : A uniform declared by a shader
+type UniformInfo struct {
+	Name string
+	Type UniformType
+}
+
+//ShaderError : Why a shader was rejected, by the checks of NewShader or by
+//the graphics driver
+type ShaderError struct {
+	Stage   string //"vertex" or "fragment", empty when linking
+	Line    int    //Starting at 1, 0 if unknown
+	Message string
+}
+
+//Error : Error interface
+func (err *ShaderError) Error() string {
+	where := "shader"
+	if err.Stage != "" {
+		where = err.Stage + " shader"
+	}
+	if err.Line > 0 {
+		where = fmt.Sprintf("%s line %d", where, err.Line)
+	}
+	return fmt.Sprintf("goldcore: %s: %s", where, err.Message)
+}
+
+/////////////////////////////////////
+///		SHADER
+/////////////////////////////////////
+
+//Shader : GLSL vertex and fragment program, used through a Material.
+//The source is checked and its uniforms listed in Go, so mistakes show up
+//without a GPU. Software targets can't run GLSL and draw as if there were
+//no shader
+type Shader struct {
+	vertex, fragment string
+	uniforms         map[string]UniformType
+
+	mutex    sync.Mutex
+	values   uniformValues //Defaults for every material
+	sfShader *sf.Shader
+	compiled bool
+	err      error
+}
+
+//NewShader : Shader from GLSL source. Either stage may be empty, SFML
+//then uses its own
+func NewShader(vertex, fragment string) (*Shader, error) {
+	if vertex == "" && fragment == "" {
+		return nil, &ShaderError{Message: "no source"}
+	}
+	shader := &Shader{vertex: vertex, fragment: fragment, uniforms: make(map[string]UniformType), values: make(uniformValues)}
+	stages := make(map[string]string)
+	for _, stage := range []struct{ name, source string }{{"vertex", vertex}, {"fragment", fragment}} {
+		if stage.source == "" {
+			continue
+		}
+		uniforms, err := checkGLSL(stage.name, stage.source)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range uniforms {
+			if t, ok := shader.uniforms[u.info.Name]; ok && t != u.info.Type {
+				return nil, &ShaderError{Stage: stage.name, Line: u.line, Message: fmt.Sprintf(
+					"uniform %s is %v here and %v in the %s shader", u.info.Name, u.info.Type, t, stages[u.info.Name])}
+			}
+			shader.uniforms[u.info.Name] = u.info.Type
+			stages[u.info.Name] = stage.name
+		}
+	}
+	return shader, nil
+}
+
+//LoadShader : Shader from GLSL files. An empty path leaves its stage out
+func LoadShader(vertexPath, fragmentPath string) (*Shader, error) {
+	return LoadShaderFS(osFS{}, vertexPath, fragmentPath)
+}
+
+//LoadShaderFS : Shader from GLSL files of fsys. An empty path leaves its
+//stage out
+func LoadShaderFS(fsys fs.FS, vertexPath, fragmentPath string) (*Shader, error) {
+	var sources [2]string
+	for i, path := range []string{vertexPath, fragmentPath} {
+		if path == "" {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return nil, err
+		}
+		sources[i] = string(data)
+	}
+	shader, err := NewShader(sources[0], sources[1])
+	if err != nil {
+		return nil, fmt.Errorf("goldcore: loading shader %s %s: %v", vertexPath, fragmentPath, err)
+	}
+	return shader, nil
+}
+
+//ShadersAvailable : Checks whether the graphics driver can run shaders
+func ShadersAvailable() bool {
+	return sf.ShaderIsAvailable()
+}
+
+//Source : GLSL source of both stages
+func (shader *Shader) Source() (vertex, fragment string) {
+	shader.mutex.Lock()
+	defer shader.mutex.Unlock()
+	return shader.vertex, shader.fragment
+}
+
+//Uniforms : Uniforms declared by the shader, sorted by name
+func (shader *Shader) Uniforms() []UniformInfo {
+	types := shader.uniformTypes()
+	uniforms := make([]UniformInfo, 0, len(types))
+	for name, t := range types {
+		uniforms = append(uniforms, UniformInfo{Name: name, Type: t})
+	}
+	sort.Slice(uniforms, func(i, j int) bool { return uniforms[i].Name < uniforms[j].Name })
+	return uniforms
+}
+
+//UniformType : Type of the uniform name, false if the shader doesn't
+//declare it
+func (shader *Shader) UniformType(name string) (UniformType, bool) {
+	t, ok := shader.uniformTypes()[name]
+	return t, ok
+}
+
+//uniformTypes : Types of the uniforms by name. Never changed, hot reloading
+//replaces the whole map
+func (shader *Shader) uniformTypes() map[string]UniformType {
+	shader.mutex.Lock()
+	defer shader.mutex.Unlock()
+	return shader.uniforms
+}
+
+//Uniform : Default value of the uniform name, false if it was never set
+func (shader *Shader) Uniform(name string) (interface{}, bool) {
+	shader.mutex.Lock()
+	defer shader.mutex.Unlock()
+	v, ok := shader.values[name]
+	return v, ok
+}
+
+//SetFloat : Sets the default of a float uniform
+func (shader *Shader) SetFloat(name string, x float32) error {
+	return shader.set(name, x)
+}
+
+//SetVector2 : Sets the default of a vec2 uniform
+func (shader *Shader) SetVector2(name string, v Vector2f) error {
+	return shader.set(name, v)
+}
+
+//SetVector3 : Sets the default of a vec3 uniform
+func (shader *Shader) SetVector3(name string, v Vector3f) error {
+	return shader.set(name, v)
+}
+
+//SetColor : Sets the default of a vec4 uniform, or vec3 leaving alpha out.
+//Channels go from 0 to 1 in GLSL
+func (shader *Shader) SetColor(name string, c Color) error {
+	return shader.set(name, c)
+}
+
+//SetTexture : Sets the default of a sampler2D uniform
+func (shader *Shader) SetTexture(name string, tex *Texture) error {
+	return shader.set(name, tex)
+}
+
+func (shader *Shader) set(name string, value interface{}) error {
+	shader.mutex.Lock()
+	defer shader.mutex.Unlock()
+	return shader.values.set(shader.uniforms, name, value)
+}
+
+//replace : Takes the source and uniforms of other, keeping the defaults
+//that still fit. Used by hot reloading so materials see the new files. The
+//shader is compiled again when next drawn
+func (shader *Shader) replace(other *Shader) {
+	other.mutex.Lock()
+	vertex, fragment, uniforms := other.vertex, other.fragment, other.uniforms
+	other.mutex.Unlock()
+	shader.mutex.Lock()
+	defer shader.mutex.Unlock()
+	values := make(uniformValues)
+	for name, value := range shader.values {
+		values.set(uniforms, name, value)
+	}
+	shader.vertex, shader.fragment, shader.uniforms, shader.values = vertex, fragment, uniforms, values
+	shader.sfShader, shader.compiled, shader.err = nil, false, nil
+}
+
+//Compile : Compiles the shader on the GPU now rather than when first drawn,
+//to get the driver's errors. Must be called on the render thread. Does
+//nothing if shaders aren't available
+func (shader *Shader) Compile() error {
+	shader.mutex.Lock()
+	defer shader.mutex.Unlock()
+	shader.compile()
+	return shader.err
+}
+
+//compile : Compiles once, with the mutex held
+func (shader *Shader) compile() {
+	if shader.compiled || !sf.ShaderIsAvailable() {
+		return
+	}
+	shader.compiled = true
+	sfShader, err := sf.NewShaderFromMemory(shader.vertex, shader.fragment)
+	if err != nil {
+		shader.err = &ShaderError{Message: err.Error()}
+		return
+	}
+	shader.sfShader = sfShader
+}
+
+//toSFML : The compiled shader with its defaults then overrides applied, nil
+//if it can't be used. Must be called on the render thread
+func (shader *Shader) toSFML(overrides uniformValues) *sf.Shader {
+	shader.mutex.Lock()
+	defer shader.mutex.Unlock()
+	shader.compile()
+	if shader.sfShader == nil {
+		return nil
+	}
+	for _, values := range []uniformValues{shader.values, overrides} {
+		for name, value := range values {
+			applyUniformSFML(shader.sfShader, name, shader.uniforms[name], value)
+		}
+	}
+	return shader.sfShader
+}
+
+//applyUniformSFML : Sets a uniform of an SFML shader
+func applyUniformSFML(s *sf.Shader, name string, t UniformType, value interface{}) {
+	switch v := value.(type) {
+	case float32:
+		s.SetFloatParameter(name, v)
+	case Vector2f:
+		s.SetFloatParameter(name, v.X, v.Y)
+	case Vector3f:
+		s.SetFloatParameter(name, v.X, v.Y, v.Z)
+	case Color:
+		if t == UniformVec3 {
+			f := v.Float()
+			s.SetFloatParameter(name, f.R, f.G, f.B)
+		} else {
+			s.SetColorParameter(name, v.ToSFML())
+		}
+	case *Texture:
+		if v != nil {
+			s.SetTextureParameter(name, v.ToSFML())
+		}
+	}
+}
+
+/////////////////////////////////////
+///		MATERIAL
+/////////////////////////////////////
+
+//Material : A shader with its own uniform values, so many looks can share
+//one shader. Attach it to a Sprite, Text or Shape, or to RenderStates.
+//Uniforms it doesn't set keep the shader's defaults
+type Material struct {
+	Shader *Shader
+
+	id     uint64 //Creation order, sorts batches the same way every run
+	mutex  sync.Mutex
+	values uniformValues
+}
+
+var materialCount uint64
+
+//NewMaterial : Material drawing with shader
+func NewMaterial(shader *Shader) *Material {
+	return &Material{Shader: shader, id: atomic.AddUint64(&materialCount, 1), values: make(uniformValues)}
+}
+
+//order : Sorts sprites drawn without a material first
+func (mat *Material) order() uint64 {
+	if mat == nil {
+		return 0
+	}
+	return mat.id
+}
+
+//Clone : Copy of the material that can be changed on its own, to flash a
+//single enemy of many sharing a material
+func (mat *Material) Clone() *Material {
+	mat.mutex.Lock()
+	defer mat.mutex.Unlock()
+	clone := NewMaterial(mat.Shader)
+	for name, value := range mat.values {
+		clone.values[name] = value
+	}
+	return clone
+}
+
+//Uniform : Value of the uniform name, the shader's default if the material
+//doesn't set it. False if neither does
+func (mat *Material) Uniform(name string) (interface{}, bool) {
+	mat.mutex.Lock()
+	v, ok := mat.values[name]
+	mat.mutex.Unlock()
+	if ok || mat.Shader == nil {
+		return v, ok
+	}
+	return mat.Shader.Uniform(name)
+}
+
+//Reset : Goes back to the shader's default for name
+func (mat *Material) Reset(name string) {
+	mat.mutex.Lock()
+	delete(mat.values, name)
+	mat.mutex.Unlock()
+}
+
+//SetFloat : Sets a float uniform
+func (mat *Material) SetFloat(name string, x float32) error {
+	return mat.set(name, x)
+}
+
+//SetVector2 : Sets a vec2 uniform
+func (mat *Material) SetVector2(name string, v Vector2f) error {
+	return mat.set(name, v)
+}
+
+//SetVector3 : Sets a vec3 uniform
+func (mat *Material) SetVector3(name string, v Vector3f) error {
+	return mat.set(name, v)
+}
+
+//SetColor : Sets a vec4 uniform, or vec3 leaving alpha out
+func (mat *Material) SetColor(name string, c Color) error {
+	return mat.set(name, c)
+}
+
+//SetTexture : Sets a sampler2D uniform
+func (mat *Material) SetTexture(name string, tex *Texture) error {
+	return mat.set(name, tex)
+}
+
+func (mat *Material) set(name string, value interface{}) error {
+	if mat.Shader == nil {
+		return fmt.Errorf("goldcore: material has no shader")
+	}
+	mat.mutex.Lock()
+	defer mat.mutex.Unlock()
+	if mat.values == nil {
+		mat.values = make(uniformValues)
+	}
+	return mat.values.set(mat.Shader.uniformTypes(), name, value)
+}
+
+//toSFML : The material's shader ready to draw with, nil if there is none.
+//Must be called on the render thread
+func (mat *Material) toSFML() *sf.Shader {
+	if mat.Shader == nil {
+		return nil
+	}
+	mat.mutex.Lock()
+	defer mat.mutex.Unlock()
+	return mat.Shader.toSFML(mat.values)
+}
+
+/////////////////////////////////////
+///		UNIFORMS
+/////////////////////////////////////
+
+//uniformValues : Uniform values by name
+type uniformValues map[string]interface{}
+
+//set : Sets name to value if the uniform is declared with a type value fits
+func (values uniformValues) set(uniforms map[string]UniformType, name string, value interface{}) error {
+	t, ok := uniforms[name]
+	if !ok {
+		return fmt.Errorf("goldcore: shader has no uniform %s", name)
+	}
+	var fits bool
+	switch value.(type) {
+	case float32:
+		fits = t == UniformFloat
+	case Vector2f:
+		fits = t == UniformVec2
+	case Vector3f:
+		fits = t == UniformVec3
+	case Color:
+		fits = t == UniformVec3 || t == UniformVec4
+	case *Texture:
+		fits = t == UniformSampler2D
+	}
+	if !fits {
+		return fmt.Errorf("goldcore: uniform %s is %v, can't set it to a %T", name, t, value)
+	}
+	values[name] = value
+	return nil
+}
+
+/////////////////////////////////////
+///		GLSL CHECKS
+/////////////////////////////////////
+
+//glslUniform : A uniform found in the source
+type glslUniform struct {
+	info UniformInfo
+	line int
+}
+
+var (
+	glslMain       = regexp.MustCompile(`\bvoid\s+main\s*\(\s*(void)?\s*\)`)
+	glslUniformKey = regexp.MustCompile(`\buniform\b`)
+	glslIdentifier = regexp.MustCompile(`^[A-Za-z_]\w*`)
+)
+
+//checkGLSL : Catches the mistakes that can be caught without a compiler,
+//unbalanced brackets, unended comments, a missing main or a malformed
+//uniform, and lists the uniforms
+func checkGLSL(stage, source string) ([]glslUniform, error) {
+	code, err := stripGLSLComments(stage, source)
+	if err != nil {
+		return nil, err
+	}
+	lineAt := func(i int) int {
+		return strings.Count(code[:i], "\n") + 1
+	}
+
+	type open struct {
+		bracket byte
+		line    int
+	}
+	closing := map[byte]byte{')': '(', ']': '[', '}': '{'}
+	var stack []open
+	line := 1
+	for i := 0; i < len(code); i++ {
+		switch c := code[i]; c {
+		case '\n':
+			line++
+		case '(', '[', '{':
+			stack = append(stack, open{bracket: c, line: line})
+		case ')', ']', '}':
+			if len(stack) == 0 || stack[len(stack)-1].bracket != closing[c] {
+				return nil, &ShaderError{Stage: stage, Line: line, Message: fmt.Sprintf("unexpected %c", c)}
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+	if len(stack) > 0 {
+		last := stack[len(stack)-1]
+		return nil, &ShaderError{Stage: stage, Line: last.line, Message: fmt.Sprintf("%c is never closed", last.bracket)}
+	}
+	if !glslMain.MatchString(code) {
+		return nil, &ShaderError{Stage: stage, Message: "no main function"}
+	}
+
+	var uniforms []glslUniform
+	for _, loc := range glslUniformKey.FindAllStringIndex(code, -1) {
+		line := lineAt(loc[0])
+		rest := code[loc[1]:]
+		end := strings.IndexAny(rest, ";{(")
+		if end < 0 || rest[end] != ';' {
+			return nil, &ShaderError{Stage: stage, Line: line, Message: "uniform declaration doesn't end with ;"}
+		}
+		fields := strings.Fields(strings.Replace(rest[:end], ",", " , ", -1))
+		for len(fields) > 0 && (fields[0] == "lowp" || fields[0] == "mediump" || fields[0] == "highp") {
+			fields = fields[1:]
+		}
+		if len(fields) < 2 {
+			return nil, &ShaderError{Stage: stage, Line: line, Message: "uniform declaration needs a type and a name"}
+		}
+		t := glslTypes[fields[0]]
+		//Names separated by commas, maybe arrays or with initializers
+		expectName := true
+		for _, field := range fields[1:] {
+			if field == "," {
+				expectName = true
+				continue
+			}
+			if !expectName {
+				continue
+			}
+			name := glslIdentifier.FindString(field)
+			if name == "" {
+				return nil, &ShaderError{Stage: stage, Line: line, Message: fmt.Sprintf("invalid uniform name %q", field)}
+			}
+			ut := t
+			if strings.Contains(field, "[") {
+				ut = UniformOther
+			}
+			uniforms = append(uniforms, glslUniform{info: UniformInfo{Name: name, Type: ut}, line: line})
+			expectName = false
+		}
+	}
+	return uniforms, nil
+}
+
+//stripGLSLComments : source with comments blanked out, lines kept where
+//they are
+func stripGLSLComments(stage, source string) (string, error) {
+	var code strings.Builder
+	line := 1
+	for i := 0; i < len(source); i++ {
+		switch {
+		case strings.HasPrefix(source[i:], "//"):
+			end := strings.IndexByte(source[i:], '\n')
+			if end < 0 {
+				return code.String(), nil
+			}
+			i += end - 1
+		case strings.HasPrefix(source[i:], "/*"):
+			end := strings.Index(source[i+2:], "*/")
+			if end < 0 {
+				return "", &ShaderError{Stage: stage, Line: line, Message: "comment is never closed"}
+			}
+			comment := source[i : i+2+end+2]
+			newlines := strings.Count(comment, "\n")
+			line += newlines
+			code.WriteByte(' ')
+			code.WriteString(strings.Repeat("\n", newlines))
+			i += len(comment) - 1
+		default:
+			if source[i] == '\n' {
+				line++
+			}
+			code.WriteByte(source[i])
+		}
+	}
+	return code.String(), nil
+}
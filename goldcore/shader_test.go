@@ -0,0 +1,169 @@
+package goldcore
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+)
+
+//flashShader : Fragment shader mixing the texture with a flash color
+const flashShader = `#version 120
+uniform sampler2D texture;
+uniform vec4 flashColor; // color flashed with
+uniform float flash, unused = 1.0;
+/* uniform float commented; */
+uniform highp vec2 offsets[4];
+
+void main()
+{
+	vec4 pixel = texture2D(texture, gl_TexCoord[0].xy);
+	gl_FragColor = mix(pixel, flashColor, flash) * gl_Color;
+}
+`
+
+func TestShaderUniforms(t *testing.T) {
+	shader, err := NewShader("", flashShader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "[{flash float} {flashColor vec4} {offsets other} {texture sampler2D} {unused float}]"
+	if uniforms := fmt.Sprint(shader.Uniforms()); uniforms != expected {
+		t.Errorf("Expected %s got %s", expected, uniforms)
+	}
+	if _, ok := shader.UniformType("commented"); ok {
+		t.Error("Uniforms in comments don't exist")
+	}
+
+	if err := shader.SetFloat("flash", 0.5); err != nil {
+		t.Error(err)
+	}
+	if err := shader.SetColor("flashColor", ColorWhite); err != nil {
+		t.Error(err)
+	}
+	if err := shader.SetTexture("texture", NewEmptyTexture(1, 1)); err != nil {
+		t.Error(err)
+	}
+	for name, set := range map[string]func() error{
+		"missing uniform":   func() error { return shader.SetFloat("missing", 1) },
+		"vec2 to a float":   func() error { return shader.SetVector2("flash", Vector2f{}) },
+		"float to a vec4":   func() error { return shader.SetFloat("flashColor", 1) },
+		"vec3 to a vec4":    func() error { return shader.SetVector3("flashColor", Vector3f{}) },
+		"array":             func() error { return shader.SetVector2("offsets", Vector2f{}) },
+		"texture to a vec4": func() error { return shader.SetTexture("flashColor", nil) },
+	} {
+		if err := set(); err == nil {
+			t.Errorf("Setting a %s should fail", name)
+		}
+	}
+	if v, ok := shader.Uniform("flash"); !ok || v != float32(0.5) {
+		t.Errorf("Expected flash 0.5 got %v", v)
+	}
+	if _, ok := shader.Uniform("unused"); ok {
+		t.Error("Unset uniforms have no value")
+	}
+
+	//Uniforms shared by both stages must agree
+	vertex := "uniform vec2 offset;\nvoid main() { gl_Position = gl_Vertex; }"
+	if _, err := NewShader(vertex, "uniform vec2 offset;\nvoid main(void) {}"); err != nil {
+		t.Error(err)
+	}
+	_, err = NewShader(vertex, "\nuniform vec3 offset;\nvoid main() {}")
+	if se, ok := err.(*ShaderError); !ok || se.Stage != "fragment" || se.Line != 2 {
+		t.Errorf("Expected a type clash on line 2 of the fragment shader got %v", err)
+	}
+	if shader.Compile() != nil {
+		t.Error("Compile does nothing without shader support")
+	}
+}
+
+func TestShaderErrors(t *testing.T) {
+	for _, test := range []struct {
+		source string
+		line   int
+	}{
+		{"void main() {\n\tgl_FragColor = vec4(1.0;\n}", 3},
+		{"void main() {\n\tif (true) {\n}", 1},
+		{"void main() {\n}\n}", 3},
+		{"void main() {\n\tfloat a[2;\n}", 3},
+		{"uniform float x;\nvoid mainly() {}", 0},
+		{"uniform float\nvoid main() {}", 1},
+		{"uniform float x\nvoid main() {}", 1},
+		{"uniform float 2x;\nvoid main() {}", 1},
+		{"uniform Lights { vec3 pos; };\nvoid main() {}", 1},
+		{"void main() {}\n/* never\nclosed", 2},
+	} {
+		_, err := NewShader("", test.source)
+		se, ok := err.(*ShaderError)
+		if !ok {
+			t.Errorf("%q: expected a ShaderError got %v", test.source, err)
+			continue
+		}
+		if se.Stage != "fragment" || se.Line != test.line {
+			t.Errorf("%q: expected an error on line %d got %v", test.source, test.line, se)
+		}
+	}
+	if _, err := NewShader("", ""); err == nil {
+		t.Error("A shader needs some source")
+	}
+	//Comments don't hide lines
+	_, err := NewShader("/* a\nb */ // c\n\nvoid main() { ) }", "")
+	if se, ok := err.(*ShaderError); !ok || se.Line != 4 || se.Stage != "vertex" {
+		t.Errorf("Expected an error on line 4 got %v", err)
+	}
+	expected := "goldcore: vertex shader line 4: unexpected )"
+	if err.Error() != expected {
+		t.Errorf("Expected %q got %q", expected, err.Error())
+	}
+}
+
+func TestMaterial(t *testing.T) {
+	shader, err := NewShader("", flashShader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shader.SetFloat("flash", 0)
+	hit := NewMaterial(shader)
+	if v, ok := hit.Uniform("flash"); !ok || v != float32(0) {
+		t.Errorf("Materials should fall back to the shader's defaults, got %v", v)
+	}
+	if err := hit.SetFloat("flash", 1); err != nil {
+		t.Error(err)
+	}
+	if err := hit.SetColor("flashColor", ColorRed); err != nil {
+		t.Error(err)
+	}
+	if hit.SetFloat("flashColor", 1) == nil || hit.SetFloat("missing", 1) == nil {
+		t.Error("Materials check uniforms like shaders")
+	}
+	clone := hit.Clone()
+	clone.SetColor("flashColor", ColorBlue)
+	if v, _ := hit.Uniform("flashColor"); v != ColorRed {
+		t.Errorf("Clones should be independent, got %v", v)
+	}
+	if v, _ := clone.Uniform("flash"); v != float32(1) {
+		t.Errorf("Clones should copy the values, got %v", v)
+	}
+	clone.Reset("flash")
+	if v, _ := clone.Uniform("flash"); v != float32(0) {
+		t.Errorf("Reset should go back to the default, got %v", v)
+	}
+	if v, _ := shader.Uniform("flash"); v != float32(0) {
+		t.Error("Materials don't change the shader's defaults")
+	}
+	if (&Material{}).SetFloat("flash", 1) == nil {
+		t.Error("A material without a shader can't be set")
+	}
+
+	//Software targets draw as if there were no shader
+	sprite := NewSprite(NewTexture(solid(2, 2, green)))
+	sprite.Material = hit
+	rt := NewSoftwareRenderTexture(2, 2)
+	rt.Clear(ColorBlack)
+	rt.Draw(sprite)
+	rt.Display()
+	expectPixels(t, rt.Capture(), map[image.Point]color.RGBA{{0, 0}: green, {1, 1}: green})
+	if states := (RenderStates{Material: hit}).ToSFML(); states.Shader != nil {
+		t.Error("Without shader support nothing is bound")
+	}
+}
@@ -0,0 +1,303 @@
+package goldcore
+
+import "math"
+
+//miterLimit : Longest a sharp corner of an outline can stick out, in
+//thicknesses, before it is cut off flat
+const miterLimit = 4
+
+//circleTolerance : Furthest a circle's edge strays from the true circle,
+//in pixels, when its number of points is picked automatically
+const circleTolerance = 0.25
+
+//Shape : Filled and outlined polygon, or an open line when it isn't Closed.
+//The geometry is made in Go, see Vertices, so it is the same on every
+//target. Draw it with GameWindow.Draw or RenderTexture.Draw
+type Shape struct {
+	Position Vector2f //Where the origin is drawn
+	Origin   Vector2f //Point of the shape, in local pixels, at Position
+	Rotation float32  //Degrees, clockwise
+	Scale    Vector2f
+	//Points : Corners in local pixels, in order. Closed shapes are filled
+	//correctly only when convex
+	Points []Vector2f
+	//Closed : Connects the last point back to the first and fills the
+	//inside. Open shapes are lines and only have an outline
+	Closed    bool
+	FillColor Color
+	//OutlineColor : Color of the outline, or of the line of open shapes
+	OutlineColor Color
+	//OutlineThickness : Width of the outline. It grows outwards from closed
+	//shapes and is centered on open ones
+	OutlineThickness float32
+	//Material : Shader to draw with, nil for none
+	Material *Material
+}
+
+//NewRectangleShape : White rectangle covering r
+func NewRectangleShape(r RectF) *Shape {
+	shape := NewPolygonShape(Vector2f{}, Vector2f{X: r.Width}, Vector2f{X: r.Width, Y: r.Height}, Vector2f{Y: r.Height})
+	shape.Position = r.Position()
+	return shape
+}
+
+//NewCircleShape : White circle around center. segments is its number of
+//points, below 3 it is picked from the radius so the edge looks round
+func NewCircleShape(center Vector2f, radius float32, segments int) *Shape {
+	if segments < 3 {
+		segments = circleSegments(radius)
+	}
+	points := make([]Vector2f, segments)
+	for i := range points {
+		angle := 2 * math.Pi * float64(i) / float64(segments)
+		points[i] = Vector2f{X: radius * float32(math.Cos(angle)), Y: radius * float32(math.Sin(angle))}
+	}
+	shape := NewPolygonShape(points...)
+	shape.Position = center
+	return shape
+}
+
+//NewPolygonShape : White convex polygon through points
+func NewPolygonShape(points ...Vector2f) *Shape {
+	return &Shape{Scale: Vector2f{X: 1, Y: 1}, Points: points, Closed: true, FillColor: ColorWhite, OutlineColor: ColorWhite}
+}
+
+//NewLineShape : White line from a to b
+func NewLineShape(a, b Vector2f, thickness float32) *Shape {
+	return NewPolylineShape(thickness, a, b)
+}
+
+//NewPolylineShape : White line through points
+func NewPolylineShape(thickness float32, points ...Vector2f) *Shape {
+	return &Shape{Scale: Vector2f{X: 1, Y: 1}, Points: points, OutlineColor: ColorWhite, OutlineThickness: thickness}
+}
+
+//circleSegments : Number of points keeping a circle of radius within
+//circleTolerance of round
+func circleSegments(radius float32) int {
+	if radius <= circleTolerance {
+		return 8
+	}
+	n := int(math.Ceil(math.Pi / math.Acos(1-circleTolerance/float64(radius))))
+	return minInt(maxInt(n, 8), 512)
+}
+
+//Move : Moves the shape by offset
+func (shape *Shape) Move(offset Vector2f) {
+	shape.Position = shape.Position.Plus(offset)
+}
+
+//Transform : Local pixels to world, like a Sprite
+func (shape *Shape) Transform() Transform2D {
+	return TranslationTransform(shape.Position).
+		Rotate(DegreesToRadians(shape.Rotation)).
+		Scale(shape.Scale).
+		Translate(shape.Origin.Neg())
+}
+
+//LocalBounds : Rectangle covering the shape and its outline before its
+//transform
+func (shape *Shape) LocalBounds() RectF {
+	vertices := shape.Vertices()
+	if len(vertices) == 0 {
+		return RectFromPoints(shape.Points...)
+	}
+	points := make([]Vector2f, len(vertices))
+	for i, v := range vertices {
+		points[i] = v.Position
+	}
+	return RectFromPoints(points...)
+}
+
+//GlobalBounds : Axis aligned rectangle covering the transformed shape
+func (shape *Shape) GlobalBounds() RectF {
+	return shape.Transform().TransformRect(shape.LocalBounds())
+}
+
+//Vertices : Triangles of the fill then of the outline, in local pixels
+func (shape *Shape) Vertices() []Vertex {
+	var vertices []Vertex
+	if shape.Closed && shape.FillColor.A > 0 {
+		vertices = FillConvex(vertices, shape.Points, shape.FillColor)
+	}
+	if shape.OutlineThickness > 0 && shape.OutlineColor.A > 0 {
+		if shape.Closed {
+			vertices = OutlinePolygon(vertices, shape.Points, shape.OutlineThickness, shape.OutlineColor)
+		} else {
+			vertices = StrokePolyline(vertices, shape.Points, shape.OutlineThickness, shape.OutlineColor)
+		}
+	}
+	return vertices
+}
+
+//Draw : Draws the shape on target with states applied on top of its own
+//transform
+func (shape *Shape) Draw(target RenderTarget, states RenderStates) {
+	vertices := shape.Vertices()
+	if len(vertices) == 0 {
+		return
+	}
+	states.Transform = states.Transform.Compose(shape.Transform())
+	states.Texture = nil
+	if shape.Material != nil {
+		states.Material = shape.Material
+	}
+	target.DrawVertices(vertices, Triangles, states)
+}
+
+/////////////////////////////////////
+///		TESSELLATION
+/////////////////////////////////////
+
+//FillConvex : Appends the triangles filling the convex polygon through
+//points to vertices
+func FillConvex(vertices []Vertex, points []Vector2f, c Color) []Vertex {
+	for i := 2; i < len(points); i++ {
+		vertices = append(vertices,
+			Vertex{Position: points[0], Color: c},
+			Vertex{Position: points[i-1], Color: c},
+			Vertex{Position: points[i], Color: c})
+	}
+	return vertices
+}
+
+//OutlinePolygon : Appends the triangles of a band thickness wide around
+//the outside of the polygon through points to vertices
+func OutlinePolygon(vertices []Vertex, points []Vector2f, thickness float32, c Color) []Vertex {
+	if len(points) < 2 {
+		return vertices
+	}
+	//Normals point outwards for polygons going clockwise on screen
+	if NewPolygon(points...).SignedArea() < 0 {
+		thickness = -thickness
+	}
+	return stroke(vertices, points, true, thickness, 0, c)
+}
+
+//StrokePolyline : Appends the triangles of a line thickness wide through
+//points to vertices. The ends are cut square at the first and last points
+func StrokePolyline(vertices []Vertex, points []Vector2f, thickness float32, c Color) []Vertex {
+	return stroke(vertices, points, false, thickness/2, -thickness/2, c)
+}
+
+//stroke : Appends the band between the offsets a and b along the normals of
+//the edges through points. Corners are mitered, or beveled past miterLimit
+func stroke(vertices []Vertex, points []Vector2f, closed bool, a, b float32, c Color) []Vertex {
+	points = dedupPoints(points, closed)
+	n := len(points)
+	if n < 2 {
+		return vertices
+	}
+	edges := n - 1
+	if closed {
+		edges = n
+	}
+	normals := make([]Vector2f, edges)
+	for i := range normals {
+		normals[i] = points[(i+1)%n].Minus(points[i]).Perpendicular().Normalize().Neg()
+	}
+
+	//Where the band ends at every point, for the edge coming in and the one
+	//going out. They differ when beveled
+	type corner struct{ inA, inB, outA, outB Vector2f }
+	corners := make([]corner, n)
+	for i, p := range points {
+		var in, out Vector2f
+		switch {
+		case closed:
+			in, out = normals[(i+edges-1)%edges], normals[i]
+		case i == 0:
+			in, out = normals[0], normals[0]
+		case i == n-1:
+			in, out = normals[edges-1], normals[edges-1]
+		default:
+			in, out = normals[i-1], normals[i]
+		}
+		miter := in.Plus(out).Normalize()
+		cos := miter.Dot(in)
+		if cos*miterLimit >= 1 {
+			m := miter.Scale(1 / cos)
+			corners[i] = corner{inA: p.Plus(m.Scale(a)), inB: p.Plus(m.Scale(b))}
+			corners[i].outA, corners[i].outB = corners[i].inA, corners[i].inB
+			continue
+		}
+		corners[i] = corner{
+			inA: p.Plus(in.Scale(a)), inB: p.Plus(in.Scale(b)),
+			outA: p.Plus(out.Scale(a)), outB: p.Plus(out.Scale(b)),
+		}
+		//Bevel, filling the gap on both sides of the band
+		vertices = appendTriangle(vertices, p, corners[i].inA, corners[i].outA, c)
+		vertices = appendTriangle(vertices, p, corners[i].inB, corners[i].outB, c)
+	}
+
+	for i := 0; i < edges; i++ {
+		from, to := corners[i], corners[(i+1)%n]
+		vertices = appendTriangle(vertices, from.outA, from.outB, to.inA, c)
+		vertices = appendTriangle(vertices, to.inA, from.outB, to.inB, c)
+	}
+	return vertices
+}
+
+//dedupPoints : points without repeats, which have no direction
+func dedupPoints(points []Vector2f, closed bool) []Vector2f {
+	unique := make([]Vector2f, 0, len(points))
+	for _, p := range points {
+		if len(unique) == 0 || !unique[len(unique)-1].Equals(p) {
+			unique = append(unique, p)
+		}
+	}
+	if closed && len(unique) > 1 && unique[0].Equals(unique[len(unique)-1]) {
+		unique = unique[:len(unique)-1]
+	}
+	return unique
+}
+
+//appendTriangle : Appends a triangle, unless it is flat
+func appendTriangle(vertices []Vertex, p0, p1, p2 Vector2f, c Color) []Vertex {
+	if p1.Minus(p0).Cross(p2.Minus(p0)) == 0 {
+		return vertices
+	}
+	return append(vertices, Vertex{Position: p0, Color: c}, Vertex{Position: p1, Color: c}, Vertex{Position: p2, Color: c})
+}
+
+/////////////////////////////////////
+///		DEBUG DRAWING
+/////////////////////////////////////
+
+//DrawRect : Draws r filled with fill and outlined thickness wide with
+//outline, through the window's camera
+func (gW *GameWindow) DrawRect(r RectF, fill, outline Color, thickness float32) {
+	shape := NewRectangleShape(r)
+	shape.FillColor, shape.OutlineColor, shape.OutlineThickness = fill, outline, thickness
+	gW.Draw(shape)
+}
+
+//DrawCircle : Draws a circle filled with fill and outlined thickness wide
+//with outline, through the window's camera
+func (gW *GameWindow) DrawCircle(center Vector2f, radius float32, fill, outline Color, thickness float32) {
+	shape := NewCircleShape(center, radius, 0)
+	shape.FillColor, shape.OutlineColor, shape.OutlineThickness = fill, outline, thickness
+	gW.Draw(shape)
+}
+
+//DrawPolygon : Draws the convex polygon through points filled with fill
+//and outlined thickness wide with outline, through the window's camera
+func (gW *GameWindow) DrawPolygon(points []Vector2f, fill, outline Color, thickness float32) {
+	shape := NewPolygonShape(points...)
+	shape.FillColor, shape.OutlineColor, shape.OutlineThickness = fill, outline, thickness
+	gW.Draw(shape)
+}
+
+//DrawLine : Draws a line thickness wide from a to b, through the window's
+//camera
+func (gW *GameWindow) DrawLine(a, b Vector2f, thickness float32, c Color) {
+	gW.DrawPolyline([]Vector2f{a, b}, thickness, c)
+}
+
+//DrawPolyline : Draws a line thickness wide through points, through the
+//window's camera
+func (gW *GameWindow) DrawPolyline(points []Vector2f, thickness float32, c Color) {
+	shape := NewPolylineShape(thickness, points...)
+	shape.OutlineColor = c
+	gW.Draw(shape)
+}
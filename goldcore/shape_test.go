@@ -0,0 +1,140 @@
+package goldcore
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+var _ Drawable = (*Shape)(nil)
+
+//trianglesArea : Total area of the triangles, overlaps counted twice
+func trianglesArea(vertices []Vertex) float32 {
+	var area float32
+	for i := 0; i+2 < len(vertices); i += 3 {
+		a, b, c := vertices[i].Position, vertices[i+1].Position, vertices[i+2].Position
+		area += absFloat(b.Minus(a).Cross(c.Minus(a))) / 2
+	}
+	return area
+}
+
+func TestShapeTessellation(t *testing.T) {
+	c := ColorWhite
+	square := []Vector2f{{0, 0}, {4, 0}, {4, 3}, {0, 3}}
+	fill := FillConvex(nil, square, c)
+	if len(fill) != 6 || trianglesArea(fill) != 12 {
+		t.Errorf("Expected 2 triangles covering 12 got %d vertices covering %v", len(fill), trianglesArea(fill))
+	}
+
+	//The outline goes outside whatever the winding
+	reversed := []Vector2f{{0, 3}, {4, 3}, {4, 0}, {0, 0}}
+	for _, points := range [][]Vector2f{square, reversed} {
+		outline := OutlinePolygon(nil, points, 1, c)
+		if len(outline) != 24 {
+			t.Errorf("Expected 8 triangles got %d vertices", len(outline))
+		}
+		if area := trianglesArea(outline); !ApproxEqual(area, 6*5-12, 1e-4) {
+			t.Errorf("The outline should cover the 6x5 rectangle minus the square, got %v", area)
+		}
+		bounds := (&Shape{Points: positions(outline)}).LocalBounds()
+		if !bounds.Position().ApproxEquals(Vector2f{-1, -1}, 1e-5) || !bounds.Size().ApproxEquals(Vector2f{6, 5}, 1e-5) {
+			t.Errorf("Unexpected outline bounds %v", bounds)
+		}
+	}
+
+	line := StrokePolyline(nil, []Vector2f{{0, 0}, {10, 0}}, 2, c)
+	if len(line) != 6 || trianglesArea(line) != 20 {
+		t.Errorf("Expected a 10x2 quad got %d vertices covering %v", len(line), trianglesArea(line))
+	}
+	//A right angle turn is mitered, the band stays 2 wide
+	elbow := StrokePolyline(nil, []Vector2f{{0, 0}, {10, 0}, {10, 10}}, 2, c)
+	if len(elbow) != 12 || !ApproxEqual(trianglesArea(elbow), 40, 1e-4) {
+		t.Errorf("Expected 2 mitered quads got %d vertices covering %v", len(elbow), trianglesArea(elbow))
+	}
+	//A hairpin would stick out far, it is beveled
+	hairpin := NewPolylineShape(2, Vector2f{0, 0}, Vector2f{10, 0}, Vector2f{0, 1})
+	if right := hairpin.LocalBounds().Right(); right > 11 {
+		t.Errorf("Sharp corners should be beveled, the line goes to %v", right)
+	}
+
+	//Repeated points have no direction and are skipped
+	if v := StrokePolyline(nil, []Vector2f{{0, 0}, {0, 0}, {5, 0}, {5, 0}}, 2, c); len(v) != 6 {
+		t.Errorf("Expected repeated points to be skipped, got %d vertices", len(v))
+	}
+	for _, points := range [][]Vector2f{nil, {{1, 1}}, {{1, 1}, {1, 1}}} {
+		if v := StrokePolyline(nil, points, 2, c); len(v) != 0 {
+			t.Errorf("%v has no line, got %d vertices", points, len(v))
+		}
+	}
+}
+
+//positions : Positions of the vertices
+func positions(vertices []Vertex) []Vector2f {
+	points := make([]Vector2f, len(vertices))
+	for i, v := range vertices {
+		points[i] = v.Position
+	}
+	return points
+}
+
+func TestShapes(t *testing.T) {
+	rect := NewRectangleShape(RectF{Left: 2, Top: 3, Width: 4, Height: 5})
+	if b := rect.GlobalBounds(); b != (RectF{Left: 2, Top: 3, Width: 4, Height: 5}) {
+		t.Errorf("Unexpected rectangle bounds %v", b)
+	}
+	rect.OutlineThickness = 1
+	if b := rect.LocalBounds(); !b.Position().ApproxEquals(Vector2f{-1, -1}, 1e-5) || !b.Size().ApproxEquals(Vector2f{6, 7}, 1e-5) {
+		t.Errorf("The outline should grow the bounds, got %v", b)
+	}
+	rect.FillColor, rect.OutlineColor = Color{}, Color{}
+	if v := rect.Vertices(); len(v) != 0 {
+		t.Errorf("Transparent parts aren't tessellated, got %d vertices", len(v))
+	}
+
+	circle := NewCircleShape(Vector2f{10, 10}, 50, 0)
+	if n := len(circle.Points); n < 32 || n > 128 {
+		t.Errorf("Expected a smooth circle, got %d points", n)
+	}
+	for _, p := range circle.Points {
+		if !ApproxEqual(p.Length(), 50, 1e-3) {
+			t.Fatalf("%v isn't on the circle", p)
+		}
+	}
+	area := trianglesArea(circle.Vertices())
+	if math.Abs(float64(area)-math.Pi*2500) > 2*math.Pi*50*circleTolerance {
+		t.Errorf("Circle area should be close to 7854, got %v", area)
+	}
+	if n := len(NewCircleShape(Vector2f{}, 50, 6).Points); n != 6 {
+		t.Errorf("Expected a hexagon got %d points", n)
+	}
+	if circleSegments(0.1) != 8 || circleSegments(1e9) != 512 {
+		t.Error("Circle points should stay between 8 and 512")
+	}
+
+	line := NewLineShape(Vector2f{0, 0}, Vector2f{10, 0}, 2)
+	line.Rotation = 90
+	if b := line.GlobalBounds(); !b.Position().ApproxEquals(Vector2f{-1, 0}, 1e-5) || !b.Size().ApproxEquals(Vector2f{2, 10}, 1e-5) {
+		t.Errorf("Unexpected rotated line bounds %v", b)
+	}
+}
+
+func TestShapeDraw(t *testing.T) {
+	gW := NewHeadlessGameWindow(16, 16, "Shapes")
+	gW.Clear(ColorBlack)
+	gW.DrawRect(RectF{Left: 4, Top: 4, Width: 6, Height: 6}, ColorRed, ColorGreen, 1)
+	gW.DrawLine(Vector2f{0, 13}, Vector2f{16, 13}, 2, ColorBlue)
+	gW.DrawPolyline([]Vector2f{{0, 0}, {1, 0}}, 0, ColorBlue)
+	gW.DrawCircle(Vector2f{14, 2}, 1.5, ColorGreen, ColorRed, 0)
+	gW.DrawPolygon([]Vector2f{{0, 3}, {2, 3}, {0, 5}}, ColorBlue, ColorRed, 0)
+	gW.renderWindow.Display()
+	gW.stats.endFrame()
+	if stats := gW.FrameStats(); stats.DrawCalls != 4 {
+		t.Errorf("Expected one call per visible shape, got %+v", stats)
+	}
+	expectPixels(t, gW.Capture(), map[image.Point]color.RGBA{
+		{4, 4}: red, {9, 9}: red, {3, 3}: green, {10, 6}: green, {2, 2}: black, {11, 6}: black,
+		{0, 12}: blue, {15, 13}: blue, {0, 11}: black, {0, 14}: black,
+		{0, 0}: black, {14, 2}: green, {0, 3}: blue, {1, 4}: black,
+	})
+}
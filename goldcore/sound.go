@@ -0,0 +1,60 @@
+package goldcore
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	sf "github.com/manyminds/gosfml"
+)
+
+//Sound : Encoded WAV, OGG or FLAC audio kept in memory. SFML decodes it the
+//first time it is needed. Safe for concurrent use
+type Sound struct {
+	data   []byte
+	format string
+
+	mutex    sync.Mutex
+	sfBuffer *sf.SoundBuffer
+	err      error
+}
+
+//NewSound : Sound from the contents of a WAV, OGG or FLAC file. Only the
+//header is checked, the rest is decoded by SFML
+func NewSound(data []byte) (*Sound, error) {
+	var format string
+	switch {
+	case len(data) >= 12 && bytes.Equal(data[:4], []byte("RIFF")) && bytes.Equal(data[8:12], []byte("WAVE")):
+		format = "wav"
+	case bytes.HasPrefix(data, []byte("OggS")):
+		format = "ogg"
+	case bytes.HasPrefix(data, []byte("fLaC")):
+		format = "flac"
+	default:
+		return nil, fmt.Errorf("goldcore: sound is not WAV, OGG or FLAC")
+	}
+	return &Sound{data: data, format: format}, nil
+}
+
+//Data : The encoded audio
+func (s *Sound) Data() []byte {
+	return s.data
+}
+
+//Format : wav, ogg or flac
+func (s *Sound) Format() string {
+	return s.format
+}
+
+//ToSFML : The decoded sound buffer, decoded once
+func (s *Sound) ToSFML() (*sf.SoundBuffer, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.sfBuffer == nil && s.err == nil {
+		s.sfBuffer, s.err = sf.NewSoundBufferFromMemory(s.data)
+		if s.err != nil {
+			s.err = fmt.Errorf("goldcore: decoding %s sound: %v", s.format, s.err)
+		}
+	}
+	return s.sfBuffer, s.err
+}
@@ -0,0 +1,96 @@
+package goldcore
+
+//Sprite : Textured rectangle that can be moved, rotated and scaled. Draw it
+//with GameWindow.Draw or RenderTexture.Draw
+type Sprite struct {
+	Position Vector2f //Where the origin is drawn
+	//Origin : Point of the sprite, in local pixels, that sits at Position
+	//and that rotation and scale are around. The top left corner by default
+	Origin   Vector2f
+	Rotation float32  //Degrees, clockwise
+	Scale    Vector2f //Negative factors flip the sprite
+	//Color : Multiplies the texture. White leaves it untouched, a lower
+	//alpha fades the sprite
+	Color Color
+	//TextureRect : Part of the texture shown, in texture pixels. A negative
+	//width or height flips the texture
+	TextureRect RectI
+	//Material : Shader to draw with, nil for none
+	Material *Material
+
+	texture *Texture
+}
+
+//NewSprite : Sprite showing the whole of tex at the origin
+func NewSprite(tex *Texture) *Sprite {
+	sprite := &Sprite{Scale: Vector2f{X: 1, Y: 1}, Color: ColorWhite}
+	sprite.SetTexture(tex, true)
+	return sprite
+}
+
+//Texture : Texture shown by the sprite, may be nil
+func (sprite *Sprite) Texture() *Texture {
+	return sprite.texture
+}
+
+//SetTexture : Shows tex. resetRect makes TextureRect the whole texture,
+//otherwise the current one is kept, for atlases of same sized frames
+func (sprite *Sprite) SetTexture(tex *Texture, resetRect bool) {
+	sprite.texture = tex
+	if resetRect && tex != nil {
+		size := tex.Size()
+		sprite.TextureRect = RectI{Width: int(size.X), Height: int(size.Y)}
+	}
+}
+
+//Move : Moves the sprite by offset
+func (sprite *Sprite) Move(offset Vector2f) {
+	sprite.Position = sprite.Position.Plus(offset)
+}
+
+//Transform : Local pixels to world, origin, scale, rotation then position
+func (sprite *Sprite) Transform() Transform2D {
+	return TranslationTransform(sprite.Position).
+		Rotate(DegreesToRadians(sprite.Rotation)).
+		Scale(sprite.Scale).
+		Translate(sprite.Origin.Neg())
+}
+
+//LocalBounds : Rectangle of the sprite before its transform
+func (sprite *Sprite) LocalBounds() RectF {
+	return RectF{Width: absFloat(float32(sprite.TextureRect.Width)), Height: absFloat(float32(sprite.TextureRect.Height))}
+}
+
+//GlobalBounds : Axis aligned rectangle covering the transformed sprite
+func (sprite *Sprite) GlobalBounds() RectF {
+	return sprite.Transform().TransformRect(sprite.LocalBounds())
+}
+
+//Vertices : The sprite's corners in local pixels, as a TriangleStrip
+func (sprite *Sprite) Vertices() [4]Vertex {
+	bounds := sprite.LocalBounds()
+	rect := sprite.TextureRect
+	left, top := float32(rect.Left), float32(rect.Top)
+	right, bottom := float32(rect.Left+rect.Width), float32(rect.Top+rect.Height)
+	return [4]Vertex{
+		{Position: Vector2f{}, Color: sprite.Color, TexCoords: Vector2f{X: left, Y: top}},
+		{Position: Vector2f{Y: bounds.Height}, Color: sprite.Color, TexCoords: Vector2f{X: left, Y: bottom}},
+		{Position: Vector2f{X: bounds.Width}, Color: sprite.Color, TexCoords: Vector2f{X: right, Y: top}},
+		{Position: Vector2f{X: bounds.Width, Y: bounds.Height}, Color: sprite.Color, TexCoords: Vector2f{X: right, Y: bottom}},
+	}
+}
+
+//Draw : Draws the sprite on target with states applied on top of its own
+//transform. Draws nothing without a texture
+func (sprite *Sprite) Draw(target RenderTarget, states RenderStates) {
+	if sprite.texture == nil {
+		return
+	}
+	vertices := sprite.Vertices()
+	states.Transform = states.Transform.Compose(sprite.Transform())
+	states.Texture = sprite.texture
+	if sprite.Material != nil {
+		states.Material = sprite.Material
+	}
+	target.DrawVertices(vertices[:], TriangleStrip, states)
+}
@@ -0,0 +1,233 @@
+package goldcore
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var (
+	_ Drawable = (*Sprite)(nil)
+
+	red   = color.RGBA{R: 0xff, A: 0xff}
+	green = color.RGBA{G: 0xff, A: 0xff}
+	blue  = color.RGBA{B: 0xff, A: 0xff}
+	white = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	black = color.RGBA{A: 0xff}
+)
+
+//quadrants : 2x2 image, red green on top, blue white below
+func quadrants() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	img.SetRGBA(0, 0, red)
+	img.SetRGBA(1, 0, green)
+	img.SetRGBA(0, 1, blue)
+	img.SetRGBA(1, 1, white)
+	return img
+}
+
+//expectPixels : Checks the colors of some pixels of frame
+func expectPixels(t *testing.T, frame *image.RGBA, expected map[image.Point]color.RGBA) {
+	t.Helper()
+	for p, c := range expected {
+		if got := frame.RGBAAt(p.X, p.Y); got != c {
+			t.Errorf("Pixel %v. Expected %v got %v", p, c, got)
+		}
+	}
+}
+
+func TestDecodeTexture(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, quadrants()); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "quadrants.png")
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tex, err := LoadTexture(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := compareImages(tex.Image(), quadrants()); diff != "" {
+		t.Errorf("PNG texture: %s", diff)
+	}
+
+	buf.Reset()
+	if err := jpeg.Encode(&buf, &image.Gray{Pix: bytes.Repeat([]byte{0x80}, 64), Stride: 8, Rect: image.Rect(0, 0, 8, 8)}, nil); err != nil {
+		t.Fatal(err)
+	}
+	tex, err = DecodeTexture(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := tex.Size(); !size.Equals(Vector2u{8, 8}) {
+		t.Errorf("JPEG texture: expected size 8x8 got %v", size)
+	}
+	if r := tex.Image().RGBAAt(3, 3).R; r < 0x7e || r > 0x82 {
+		t.Errorf("JPEG texture: expected gray got %v", tex.Image().At(3, 3))
+	}
+
+	if _, err := DecodeTexture(strings.NewReader("not an image")); err == nil {
+		t.Error("Decoding garbage should fail")
+	}
+	if _, err := LoadTexture(filepath.Join(t.TempDir(), "missing.png")); err == nil {
+		t.Error("Loading a missing file should fail")
+	}
+}
+
+func TestTextureUploadFlags(t *testing.T) {
+	tex := NewTexture(quadrants())
+	//As if ToSFML had just uploaded it
+	tex.dirty, tex.filtering = false, false
+	tex.SetSmooth(true)
+	tex.SetRepeated(true)
+	if tex.dirty || !tex.filtering {
+		t.Error("Filtering changes shouldn't upload the pixels again")
+	}
+	tex.Update(solid(1, 1, red), Vector2i{})
+	if !tex.dirty {
+		t.Error("Update should upload the pixels again")
+	}
+}
+
+func TestSpriteSoftware(t *testing.T) {
+	rt := NewSoftwareRenderTexture(8, 8)
+	rt.Clear(ColorBlack)
+	sprite := NewSprite(NewTexture(quadrants()))
+	sprite.Position = Vector2f{X: 2, Y: 3}
+	sprite.Scale = Vector2f{X: 2, Y: 2}
+	if bounds := sprite.GlobalBounds(); bounds != (RectF{Left: 2, Top: 3, Width: 4, Height: 4}) {
+		t.Errorf("Expected bounds {2 3 4 4} got %v", bounds)
+	}
+	rt.Draw(sprite)
+	rt.Display()
+	expectPixels(t, rt.Capture(), map[image.Point]color.RGBA{
+		{2, 3}: red, {3, 4}: red,
+		{4, 3}: green, {5, 4}: green,
+		{2, 5}: blue, {3, 6}: blue,
+		{4, 5}: white, {5, 6}: white,
+		{1, 3}: black, {6, 3}: black, {2, 2}: black, {2, 7}: black,
+	})
+}
+
+func TestSpriteRectTintRotation(t *testing.T) {
+	rt := NewSoftwareRenderTexture(6, 3)
+	rt.Clear(ColorBlue)
+	sprite := NewSprite(NewTexture(quadrants()))
+	//Right column, green over white, turned on its side
+	sprite.TextureRect = RectI{Left: 1, Width: 1, Height: 2}
+	sprite.Position = Vector2f{X: 4}
+	sprite.Rotation = 90
+	sprite.Color = Color{R: 0xff, A: 0xff}
+	if bounds := sprite.GlobalBounds(); !bounds.Position().ApproxEquals(Vector2f{X: 2}, 1e-5) ||
+		!bounds.Size().ApproxEquals(Vector2f{X: 2, Y: 1}, 1e-5) {
+		t.Errorf("Expected bounds {2 0 2 1} got %v", bounds)
+	}
+	rt.Draw(sprite)
+	rt.Display()
+	expectPixels(t, rt.Capture(), map[image.Point]color.RGBA{
+		{3, 0}: black, //Green tinted red
+		{2, 0}: red,   //White tinted red
+		{4, 0}: blue, {1, 0}: blue, {2, 1}: blue,
+	})
+}
+
+func TestRasterizerNoSeam(t *testing.T) {
+	pixel := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	pixel.SetRGBA(0, 0, white)
+	rt := NewSoftwareRenderTexture(4, 4)
+	rt.Clear(ColorTransparent)
+	sprite := NewSprite(NewTexture(pixel))
+	sprite.Scale = Vector2f{X: 4, Y: 4}
+	sprite.Color = Color{R: 0xff, G: 0xff, B: 0xff, A: 0x80}
+	rt.Draw(sprite)
+	rt.Display()
+	frame := rt.Capture()
+	want := color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0x80}
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			if got := frame.RGBAAt(x, y); got != want {
+				t.Errorf("Pixel (%d, %d) drawn once should be %v got %v", x, y, want, got)
+			}
+		}
+	}
+}
+
+func TestRasterizerSmoothAndBlend(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	img.SetRGBA(0, 0, black)
+	img.SetRGBA(1, 0, white)
+	tex := NewTexture(img)
+	tex.SetSmooth(true)
+	rt := NewSoftwareRenderTexture(8, 1)
+	sprite := NewSprite(tex)
+	sprite.Scale = Vector2f{X: 4, Y: 1}
+	rt.Draw(sprite)
+	rt.Display()
+	frame := rt.Capture()
+	if first, last := frame.RGBAAt(0, 0).R, frame.RGBAAt(7, 0).R; first != 0 || last != 0xff {
+		t.Errorf("Smooth ends should be black and white got %d and %d", first, last)
+	}
+	for x := 1; x < 8; x++ {
+		if frame.RGBAAt(x, 0).R < frame.RGBAAt(x-1, 0).R {
+			t.Errorf("Smooth texture should get lighter to the right: %v", frame.Pix)
+			break
+		}
+	}
+	if middle := frame.RGBAAt(3, 0).R; middle == 0 || middle == 0xff {
+		t.Errorf("Smooth texture should blend in the middle got %d", middle)
+	}
+
+	pixel := NewTexture(quadrants().SubImage(image.Rect(1, 1, 2, 2)))
+	rt = NewSoftwareRenderTexture(1, 1)
+	rt.Clear(Color{R: 100, A: 0xff})
+	add := NewSprite(pixel)
+	add.Color = Color{R: 100, G: 50, A: 0xff}
+	states := DefaultRenderStates()
+	states.BlendMode = BlendAdd
+	add.Draw(rt, states)
+	rt.Display()
+	if got := rt.Capture().RGBAAt(0, 0); got != (color.RGBA{R: 200, G: 50, A: 0xff}) {
+		t.Errorf("Additive blending: expected {200 50 0 255} got %v", got)
+	}
+}
+
+func TestHeadlessWindowDrawThroughCamera(t *testing.T) {
+	gW := NewHeadlessGameWindow(8, 8, "Sprites")
+	cam := NewCamera2D(gW.GetSize())
+	cam.Center = Vector2f{X: 8, Y: 8}
+	cam.Viewport = RectF{Width: 0.5, Height: 1}
+	gW.SetCamera(cam)
+	gW.Clear(ColorBlack)
+
+	sprite := NewSprite(NewTexture(quadrants()))
+	//World 6, 4 is the top left corner of the window
+	sprite.Position = Vector2f{X: 6, Y: 4}
+	gW.Draw(sprite)
+	//Half outside of the viewport, clipped
+	sprite.Position = Vector2f{X: 9, Y: 8}
+	gW.Draw(sprite)
+	//Images go through the camera too, as on the GPU
+	gW.DrawImage(quadrants(), Vector2i{X: 6, Y: 6})
+	gW.DrawImage(quadrants(), Vector2i{X: 9, Y: 10})
+	gW.renderWindow.Display()
+	expectPixels(t, gW.Capture(), map[image.Point]color.RGBA{
+		{0, 0}: red, {1, 0}: green, {0, 1}: blue, {1, 1}: white,
+		{3, 4}: red, {4, 4}: black, {3, 5}: blue,
+		{0, 2}: red, {1, 3}: white, {3, 6}: red, {4, 6}: black, {3, 7}: blue,
+	})
+
+	gW.SetCamera(nil)
+	gW.Clear(ColorBlack)
+	sprite.Position = Vector2f{X: 6, Y: 6}
+	gW.Draw(sprite)
+	gW.renderWindow.Display()
+	expectPixels(t, gW.Capture(), map[image.Point]color.RGBA{{6, 6}: red, {7, 7}: white, {0, 0}: black})
+}
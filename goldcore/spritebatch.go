@@ -0,0 +1,153 @@
+package goldcore
+
+import (
+	"sort"
+	"sync"
+)
+
+//DrawStats : How much was sent to a RenderTarget
+type DrawStats struct {
+	DrawCalls int //DrawVertices and DrawImage calls
+	Vertices  int
+}
+
+//Add : Sum of both stats
+func (stats DrawStats) Add(other DrawStats) DrawStats {
+	return DrawStats{DrawCalls: stats.DrawCalls + other.DrawCalls, Vertices: stats.Vertices + other.Vertices}
+}
+
+//frameStats : Counts draws of the frame being drawn and keeps the totals
+//of the last displayed one
+type frameStats struct {
+	mutex   sync.Mutex
+	current DrawStats
+	last    DrawStats
+}
+
+func (fs *frameStats) count(vertices int) {
+	fs.mutex.Lock()
+	fs.current.DrawCalls++
+	fs.current.Vertices += vertices
+	fs.mutex.Unlock()
+}
+
+//endFrame : Called when the frame is displayed
+func (fs *frameStats) endFrame() {
+	fs.mutex.Lock()
+	fs.last, fs.current = fs.current, DrawStats{}
+	fs.mutex.Unlock()
+}
+
+//FrameStats : Draw calls and vertices of the last displayed frame
+func (gW *GameWindow) FrameStats() DrawStats {
+	gW.stats.mutex.Lock()
+	defer gW.stats.mutex.Unlock()
+	return gW.stats.last
+}
+
+/////////////////////////////////////
+///		SPRITE BATCH
+/////////////////////////////////////
+
+//SpriteBatch : Collects sprites and draws them in as few draw calls as
+//possible. Sprites are sorted by layer, lowest first, then by texture and
+//material, so every run of sprites sharing a texture and a material is a
+//single call. Within a layer sprites of the same texture and material keep
+//the order they were added in, but others may be reordered: put sprites
+//that must overlap in a given order on different layers. Use an Atlas so
+//most sprites share a texture. The blend mode is the one of the states
+//given to Draw, for every sprite. Not safe for concurrent use
+type SpriteBatch struct {
+	items    []batchItem
+	vertices []Vertex
+	order    []int
+	stats    DrawStats
+}
+
+//batchItem : Sprite as it was when added
+type batchItem struct {
+	layer    int
+	texture  *Texture
+	material *Material
+	corners [4]Vertex //Transformed, in TriangleStrip order
+}
+
+//sameDraw : Whether both sprites can be drawn by the same call
+func (item *batchItem) sameDraw(other *batchItem) bool {
+	return item.texture == other.texture && item.material == other.material
+}
+
+//NewSpriteBatch : Empty batch
+func NewSpriteBatch() *SpriteBatch {
+	return &SpriteBatch{}
+}
+
+//Add : Queues sprite on layer. The sprite is copied, it can be changed and
+//added again right away, which is how to draw many bullets with one Sprite.
+//Sprites without a texture are skipped
+func (batch *SpriteBatch) Add(sprite *Sprite, layer int) {
+	if sprite.texture == nil {
+		return
+	}
+	item := batchItem{layer: layer, texture: sprite.texture, material: sprite.Material, corners: sprite.Vertices()}
+	transform := sprite.Transform()
+	for i := range item.corners {
+		item.corners[i].Position = transform.TransformPoint(item.corners[i].Position)
+	}
+	batch.items = append(batch.items, item)
+}
+
+//Len : Number of sprites queued
+func (batch *SpriteBatch) Len() int {
+	return len(batch.items)
+}
+
+//Clear : Empties the batch for the next frame, keeping its memory
+func (batch *SpriteBatch) Clear() {
+	batch.items = batch.items[:0]
+}
+
+//Stats : Draw calls and vertices sent by the last Draw
+func (batch *SpriteBatch) Stats() DrawStats {
+	return batch.stats
+}
+
+//Draw : Draws every queued sprite on target, with states applied on top of
+//their own transforms. The sprites stay queued until Clear
+func (batch *SpriteBatch) Draw(target RenderTarget, states RenderStates) {
+	batch.stats = DrawStats{}
+	batch.order = batch.order[:0]
+	for i := range batch.items {
+		batch.order = append(batch.order, i)
+	}
+	items, material := batch.items, states.Material
+	sort.SliceStable(batch.order, func(i, j int) bool {
+		a, b := &items[batch.order[i]], &items[batch.order[j]]
+		if a.layer != b.layer {
+			return a.layer < b.layer
+		}
+		if a.texture != b.texture {
+			return a.texture.id < b.texture.id
+		}
+		return a.material.order() < b.material.order()
+	})
+
+	for start := 0; start < len(batch.order); {
+		first := &items[batch.order[start]]
+		batch.vertices = batch.vertices[:0]
+		end := start
+		for ; end < len(batch.order) && items[batch.order[end]].sameDraw(first); end++ {
+			c := &items[batch.order[end]].corners
+			//Two triangles per sprite, strips can't be joined
+			batch.vertices = append(batch.vertices, c[0], c[1], c[2], c[2], c[1], c[3])
+		}
+		states.Texture, states.Material = first.texture, material
+		if first.material != nil {
+			states.Material = first.material
+		}
+		target.DrawVertices(batch.vertices, Triangles, states)
+		batch.stats.DrawCalls++
+		batch.stats.Vertices += len(batch.vertices)
+		start = end
+	}
+}
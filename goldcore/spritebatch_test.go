@@ -0,0 +1,288 @@
+package goldcore
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+var _ Drawable = (*SpriteBatch)(nil)
+
+//solid : w by h image of one color
+func solid(w, h int, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestRectPacker(t *testing.T) {
+	p := NewRectPacker(64, 64)
+	for i := 0; i < 4; i++ {
+		if _, ok := p.Pack(32, 32); !ok {
+			t.Fatalf("Quarter %d should fit", i)
+		}
+	}
+	if _, ok := p.Pack(1, 1); ok {
+		t.Error("A full bin should reject everything")
+	}
+
+	p = NewRectPacker(100, 100)
+	var placed []RectI
+	for i := 0; i < 60; i++ {
+		w, h := 5+(i*7)%13, 4+(i*11)%9
+		r, ok := p.Pack(w, h)
+		if !ok {
+			continue
+		}
+		if r.Width != w || r.Height != h || !(RectI{Width: 100, Height: 100}).ContainsRect(r) {
+			t.Fatalf("Rect %d: asked %dx%d got %v", i, w, h, r)
+		}
+		for _, o := range placed {
+			if r.Intersects(o) {
+				t.Fatalf("%v overlaps %v", r, o)
+			}
+		}
+		placed = append(placed, r)
+	}
+	if len(placed) != 60 {
+		t.Errorf("Every rectangle fits in 100x100, placed %d of 60", len(placed))
+	}
+	if _, ok := p.Pack(0, 5); ok {
+		t.Error("Empty rectangles shouldn't be packed")
+	}
+}
+
+func TestAtlasBuilder(t *testing.T) {
+	b := NewAtlasBuilder()
+	b.Add("quadrants", quadrants())
+	b.Add("bar", solid(3, 1, red))
+	b.Add("dot", solid(1, 1, green))
+	atlas, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(atlas.Pages) != 1 {
+		t.Fatalf("Expected 1 page got %d", len(atlas.Pages))
+	}
+	if names := fmt.Sprint(atlas.Names()); names != "[bar dot quadrants]" {
+		t.Errorf("Expected names [bar dot quadrants] got %s", names)
+	}
+	page := atlas.Pages[0].Image()
+	for name, img := range map[string]*image.RGBA{"quadrants": quadrants(), "bar": solid(3, 1, red), "dot": solid(1, 1, green)} {
+		region, ok := atlas.Region(name)
+		if !ok || region.Texture != atlas.Pages[0] {
+			t.Fatalf("Region %s missing", name)
+		}
+		r := region.Rect
+		if r.Width != img.Rect.Dx() || r.Height != img.Rect.Dy() {
+			t.Errorf("Region %s: expected size %v got %v", name, img.Rect.Size(), r)
+		}
+		got := page.SubImage(image.Rect(r.Left, r.Top, r.Right(), r.Bottom())).(*image.RGBA)
+		for y := 0; y < r.Height; y++ {
+			for x := 0; x < r.Width; x++ {
+				if got.RGBAAt(r.Left+x, r.Top+y) != img.RGBAAt(x, y) {
+					t.Errorf("Region %s pixel (%d, %d): expected %v got %v", name, x, y, img.RGBAAt(x, y), got.RGBAAt(r.Left+x, r.Top+y))
+				}
+			}
+		}
+		//Padding repeats the edges
+		if page.RGBAAt(r.Left-1, r.Top-1) != img.RGBAAt(0, 0) || page.RGBAAt(r.Right(), r.Top) != img.RGBAAt(r.Width-1, 0) {
+			t.Errorf("Region %s padding should repeat its edges", name)
+		}
+	}
+	if atlas.Sprite("missing") != nil {
+		t.Error("Sprite of a missing region should be nil")
+	}
+
+	b = NewAtlasBuilder()
+	b.PageSize = 8
+	for i := 0; i < 4; i++ {
+		b.Add(fmt.Sprint(i), solid(5, 5, blue))
+	}
+	if atlas, err = b.Build(); err != nil || len(atlas.Pages) != 4 {
+		t.Errorf("7x7 padded images should take a page each: %v", err)
+	} else if size := atlas.Pages[0].Size(); !size.Equals(Vector2u{7, 7}) {
+		t.Errorf("Pages should be cropped to 7x7 got %v", size)
+	}
+	b.Add("huge", solid(9, 1, blue))
+	if _, err := b.Build(); err == nil {
+		t.Error("An image bigger than a page should fail")
+	}
+}
+
+func TestAtlasFile(t *testing.T) {
+	b := NewAtlasBuilder()
+	b.PageSize = 8
+	b.Add("a", solid(5, 5, red))
+	b.Add("b", solid(2, 3, green))
+	b.Add("c", solid(6, 6, blue))
+	atlas, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if EncodeAtlas(&buf, atlas, []string{"one.png"}) == nil {
+		t.Error("Every page needs a name")
+	}
+	names := make([]string, len(atlas.Pages))
+	for i := range names {
+		names[i] = fmt.Sprintf("page%d.png", i)
+	}
+	if err := EncodeAtlas(&buf, atlas, names); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := ParseAtlas(&buf, func(name string) (*Texture, error) {
+		var i int
+		fmt.Sscanf(name, "page%d.png", &i)
+		return atlas.Pages[i], nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(loaded.Names()) != "[a b c]" || len(loaded.Pages) != len(atlas.Pages) {
+		t.Fatalf("Expected regions [a b c] got %v", loaded.Names())
+	}
+	for _, name := range atlas.Names() {
+		expected, _ := atlas.Region(name)
+		if got, _ := loaded.Region(name); got != expected {
+			t.Errorf("Region %s: expected %v got %v", name, expected, got)
+		}
+	}
+	if _, err := ParseAtlas(strings.NewReader(`{"pages": [], "regions": {"a": {"page": 0}}}`), nil); err == nil {
+		t.Error("Regions must be on a page")
+	}
+}
+
+func TestSpriteBatch(t *testing.T) {
+	b := NewAtlasBuilder()
+	b.Add("red", solid(1, 1, red))
+	b.Add("blue", solid(1, 1, blue))
+	atlas, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := NewTexture(solid(1, 1, green))
+
+	gW := NewHeadlessGameWindow(16, 16, "Batch")
+	gW.Clear(ColorBlack)
+	batch := NewSpriteBatch()
+	//A bullet pattern: one sprite moved around, two textures interleaved
+	bullet, spark := atlas.Sprite("red"), NewSprite(other)
+	for i := 0; i < 16; i++ {
+		bullet.Position = Vector2f{X: float32(i), Y: 0}
+		batch.Add(bullet, 0)
+		spark.Position = Vector2f{X: float32(i), Y: 1}
+		batch.Add(spark, 0)
+	}
+	//Added first but on top, layers win over order
+	top := atlas.Sprite("blue")
+	top.Scale = Vector2f{X: 2, Y: 2}
+	batch.Add(top, 1)
+	bullet.Position = Vector2f{}
+	batch.Add(bullet, 0)
+	batch.Add(&Sprite{}, 0)
+
+	if batch.Len() != 34 {
+		t.Errorf("Expected 34 sprites got %d", batch.Len())
+	}
+	gW.Draw(batch)
+	//Atlas sprites of layer 0 and 1 share a texture but the green sprites
+	//sit between them
+	expected := DrawStats{DrawCalls: 3, Vertices: 34 * 6}
+	if stats := batch.Stats(); stats != expected {
+		t.Errorf("Expected %+v got %+v", expected, stats)
+	}
+	gW.renderWindow.Display()
+	gW.stats.endFrame()
+	if stats := gW.FrameStats(); stats != expected {
+		t.Errorf("Window frame stats: expected %+v got %+v", expected, stats)
+	}
+	expectPixels(t, gW.Capture(), map[image.Point]color.RGBA{
+		{0, 0}: blue, {1, 1}: blue, {2, 0}: red, {15, 0}: red, {2, 1}: green, {0, 2}: black,
+	})
+
+	gW.Draw(batch)
+	gW.DrawImage(solid(1, 1, red), Vector2i{})
+	gW.stats.endFrame()
+	if stats := gW.FrameStats(); stats.DrawCalls != 4 {
+		t.Errorf("Frame stats restart every frame, expected 4 calls got %d", stats.DrawCalls)
+	}
+	batch.Clear()
+	gW.Draw(batch)
+	if batch.Len() != 0 || batch.Stats() != (DrawStats{}) {
+		t.Error("An empty batch draws nothing")
+	}
+}
+
+//drawRecorder : RenderTarget keeping the states of every draw
+type drawRecorder struct {
+	*RenderTexture
+	draws []RenderStates
+}
+
+func (r *drawRecorder) DrawVertices(vertices []Vertex, primitive PrimitiveType, states RenderStates) {
+	r.draws = append(r.draws, states)
+	r.RenderTexture.DrawVertices(vertices, primitive, states)
+}
+
+func TestSpriteBatchMaterials(t *testing.T) {
+	shader, err := NewShader("", flashShader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hit, base := NewMaterial(shader), NewMaterial(shader)
+	tex := NewTexture(solid(1, 1, red))
+	plain, flashing := NewSprite(tex), NewSprite(tex)
+	flashing.Material = hit
+
+	batch := NewSpriteBatch()
+	for i := 0; i < 4; i++ {
+		plain.Position = Vector2f{X: float32(i)}
+		batch.Add(plain, 0)
+		flashing.Position = Vector2f{X: float32(i), Y: 1}
+		batch.Add(flashing, 0)
+	}
+	target := &drawRecorder{RenderTexture: NewSoftwareRenderTexture(4, 2)}
+	states := DefaultRenderStates()
+	states.Material = base
+	batch.Draw(target, states)
+
+	//Same texture, but the flashing sprites keep their material
+	if len(target.draws) != 2 {
+		t.Fatalf("Expected 2 draw calls got %d", len(target.draws))
+	}
+	if target.draws[0].Material != base || target.draws[1].Material != hit {
+		t.Errorf("Expected materials %p then %p got %p and %p", base, hit, target.draws[0].Material, target.draws[1].Material)
+	}
+	if target.draws[0].Texture != tex || target.draws[1].Texture != tex {
+		t.Error("Both calls draw the sprites' texture")
+	}
+}
+
+func BenchmarkSpriteBatch(b *testing.B) {
+	builder := NewAtlasBuilder()
+	builder.Add("bullet", solid(4, 4, red))
+	atlas, err := builder.Build()
+	if err != nil {
+		b.Fatal(err)
+	}
+	bullet := atlas.Sprite("bullet")
+	rt := NewSoftwareRenderTexture(256, 256)
+	batch := NewSpriteBatch()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		batch.Clear()
+		for i := 0; i < 5000; i++ {
+			bullet.Position = Vector2f{X: float32(i % 256), Y: float32(i / 256 * 4)}
+			batch.Add(bullet, i%3)
+		}
+		rt.Draw(batch)
+	}
+}
@@ -0,0 +1,394 @@
+package goldcore
+
+import (
+	"math"
+	"unicode"
+	"unicode/utf8"
+)
+
+//TextAlign : How lines are placed in the text's box
+type TextAlign int
+
+const (
+	//AlignLeft : Lines start at the left edge
+	AlignLeft TextAlign = iota
+	//AlignCenter : Lines are centered
+	AlignCenter
+	//AlignRight : Lines end at the right edge
+	AlignRight
+)
+
+//tabSpaces : Tab stops are this many spaces apart
+const tabSpaces = 4
+
+//Text : Drawable string. Lines break at '\n' and, with a wrap width, between
+//words that would go past it. Any rune the font has is drawn, use a
+//FontStack to cover more scripts. Local coordinates start at the top left
+//of the first line
+type Text struct {
+	Position Vector2f //Where the origin is drawn
+	Origin   Vector2f //Point of the text, in local pixels, at Position
+	Rotation float32  //Degrees, clockwise
+	Scale    Vector2f
+	Color    Color
+	Material *Material //Shader to draw with, nil for none
+
+	font      Font
+	str       string
+	size      float32
+	align     TextAlign
+	wrapWidth float32
+	layout    textLayout
+	dirty     bool
+}
+
+//textLayout : Where every rune of the string goes
+type textLayout struct {
+	quads  []textQuad
+	carets []Vector2f //Top of the line left of every rune, and past the last
+	bounds RectF
+	lines  int
+}
+
+//textQuad : A glyph placed in local coordinates
+type textQuad struct {
+	rect        RectF
+	texture     *Texture
+	textureRect RectI
+}
+
+//NewText : White text showing str with font at size pixels
+func NewText(font Font, str string, size float32) *Text {
+	return &Text{Scale: Vector2f{X: 1, Y: 1}, Color: ColorWhite, font: font, str: str, size: size, dirty: true}
+}
+
+//String : The string shown
+func (text *Text) String() string {
+	return text.str
+}
+
+//SetString : Shows str
+func (text *Text) SetString(str string) {
+	text.str = str
+	text.dirty = true
+}
+
+//Font : Font the text is drawn with
+func (text *Text) Font() Font {
+	return text.font
+}
+
+//SetFont : Draws the text with font
+func (text *Text) SetFont(font Font) {
+	text.font = font
+	text.dirty = true
+}
+
+//Size : Character size in pixels
+func (text *Text) Size() float32 {
+	return text.size
+}
+
+//SetSize : Changes the character size, in pixels
+func (text *Text) SetSize(size float32) {
+	text.size = size
+	text.dirty = true
+}
+
+//Align : How lines are aligned
+func (text *Text) Align() TextAlign {
+	return text.align
+}
+
+//SetAlign : Aligns lines left, center or right in the text's box, which is
+//as wide as the wrap width, or as the longest line without one
+func (text *Text) SetAlign(align TextAlign) {
+	text.align = align
+	text.dirty = true
+}
+
+//WrapWidth : Width lines are wrapped at, 0 if they aren't
+func (text *Text) WrapWidth() float32 {
+	return text.wrapWidth
+}
+
+//SetWrapWidth : Breaks lines between words so they fit in width pixels.
+//Words longer than that are broken anywhere. 0 turns wrapping off
+func (text *Text) SetWrapWidth(width float32) {
+	text.wrapWidth = width
+	text.dirty = true
+}
+
+//AppendRune : Types r at the end, as delivered by EventTextEntered.
+//Backspace erases the last rune, return starts a new line, other control
+//characters are ignored
+func (text *Text) AppendRune(r rune) {
+	switch {
+	case r == '\b':
+		if len(text.str) > 0 {
+			_, n := utf8.DecodeLastRuneInString(text.str)
+			text.SetString(text.str[:len(text.str)-n])
+		}
+	case r == '\r' || r == '\n':
+		text.SetString(text.str + "\n")
+	case r == '\t' || !unicode.IsControl(r):
+		text.SetString(text.str + string(r))
+	}
+}
+
+//OnTextEntered : Types what is entered, register the text with
+//TextEnteredHandler.AddTextEnteredObserver to make it editable
+func (text *Text) OnTextEntered(e EventTextEntered) {
+	text.AppendRune(e.Char)
+}
+
+//LineCount : Number of lines after wrapping
+func (text *Text) LineCount() int {
+	return text.update().lines
+}
+
+//LocalBounds : The text's box before its transform, line height times the
+//number of lines high
+func (text *Text) LocalBounds() RectF {
+	return text.update().bounds
+}
+
+//GlobalBounds : Axis aligned rectangle covering the transformed box
+func (text *Text) GlobalBounds() RectF {
+	return text.Transform().TransformRect(text.LocalBounds())
+}
+
+//CharacterPosition : Local position of the top left of the i-th rune, where
+//a caret before it goes. Past the end it is after the last rune
+func (text *Text) CharacterPosition(i int) Vector2f {
+	carets := text.update().carets
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(carets) {
+		i = len(carets) - 1
+	}
+	return carets[i]
+}
+
+//Transform : Local pixels to world, like a Sprite
+func (text *Text) Transform() Transform2D {
+	return TranslationTransform(text.Position).
+		Rotate(DegreesToRadians(text.Rotation)).
+		Scale(text.Scale).
+		Translate(text.Origin.Neg())
+}
+
+//Draw : Draws the text on target, one draw call per glyph texture
+func (text *Text) Draw(target RenderTarget, states RenderStates) {
+	quads := text.update().quads
+	states.Transform = states.Transform.Compose(text.Transform())
+	if text.Material != nil {
+		states.Material = text.Material
+	}
+	var vertices []Vertex
+	drawn := make([]bool, len(quads))
+	for i := range quads {
+		if drawn[i] {
+			continue
+		}
+		//Every quad sharing this texture
+		vertices = vertices[:0]
+		texture := quads[i].texture
+		for j := i; j < len(quads); j++ {
+			if drawn[j] || quads[j].texture != texture {
+				continue
+			}
+			drawn[j] = true
+			vertices = appendQuad(vertices, quads[j].rect, quads[j].textureRect, text.Color)
+		}
+		states.Texture = texture
+		target.DrawVertices(vertices, Triangles, states)
+	}
+}
+
+//appendQuad : Adds the 2 triangles showing rect of the texture over quad
+func appendQuad(vertices []Vertex, quad RectF, rect RectI, c Color) []Vertex {
+	left, top := float32(rect.Left), float32(rect.Top)
+	right, bottom := float32(rect.Right()), float32(rect.Bottom())
+	tl := Vertex{Position: quad.Position(), Color: c, TexCoords: Vector2f{X: left, Y: top}}
+	tr := Vertex{Position: Vector2f{X: quad.Right(), Y: quad.Top}, Color: c, TexCoords: Vector2f{X: right, Y: top}}
+	bl := Vertex{Position: Vector2f{X: quad.Left, Y: quad.Bottom()}, Color: c, TexCoords: Vector2f{X: left, Y: bottom}}
+	br := Vertex{Position: quad.Max(), Color: c, TexCoords: Vector2f{X: right, Y: bottom}}
+	return append(vertices, tl, bl, tr, tr, bl, br)
+}
+
+//MeasureText : Size of the box str takes with font at size pixels, wrapped
+//at wrapWidth if it isn't 0
+func MeasureText(font Font, str string, size, wrapWidth float32) Vector2f {
+	text := NewText(font, str, size)
+	text.SetWrapWidth(wrapWidth)
+	return text.LocalBounds().Size()
+}
+
+/////////////////////////////////////
+///		LAYOUT
+/////////////////////////////////////
+
+//textItem : A rune on a line, x from the start of the line
+type textItem struct {
+	r       rune
+	line    int
+	x       float32
+	advance float32
+	glyph   Glyph
+	visible bool
+}
+
+//update : Lays the text out again if something changed
+func (text *Text) update() *textLayout {
+	if !text.dirty {
+		return &text.layout
+	}
+	text.dirty = false
+	text.layout = textLayout{carets: []Vector2f{{}}, lines: 1}
+	if text.font == nil {
+		return &text.layout
+	}
+
+	items, lineWidths := text.breakLines()
+	metrics := text.font.Metrics(text.size)
+	box := text.wrapWidth
+	if box <= 0 {
+		for _, w := range lineWidths {
+			box = maxFloat(box, w)
+		}
+	}
+	offsets := make([]float32, len(lineWidths))
+	for i, w := range lineWidths {
+		switch text.align {
+		case AlignCenter:
+			offsets[i] = roundFloat((box - w) / 2)
+		case AlignRight:
+			offsets[i] = roundFloat(box - w)
+		}
+	}
+
+	layout := textLayout{carets: make([]Vector2f, 0, len(items)+1), lines: len(lineWidths)}
+	for _, item := range items {
+		top := float32(item.line) * metrics.LineHeight
+		x := offsets[item.line] + roundFloat(item.x)
+		layout.carets = append(layout.carets, Vector2f{X: x, Y: top})
+		if !item.visible || item.glyph.Texture == nil {
+			continue
+		}
+		baseline := top + roundFloat(metrics.Ascent)
+		layout.quads = append(layout.quads, textQuad{
+			rect:        item.glyph.Bounds.Translate(Vector2f{X: x, Y: baseline}),
+			texture:     item.glyph.Texture,
+			textureRect: item.glyph.TextureRect,
+		})
+	}
+	//Caret past the last rune
+	end := Vector2f{X: offsets[0]}
+	if n := len(items); n > 0 {
+		last := items[n-1]
+		if last.r == '\n' {
+			line := last.line + 1
+			end = Vector2f{X: offsets[line], Y: float32(line) * metrics.LineHeight}
+		} else {
+			end = Vector2f{X: offsets[last.line] + roundFloat(last.x+last.advance), Y: float32(last.line) * metrics.LineHeight}
+		}
+	}
+	layout.carets = append(layout.carets, end)
+	layout.bounds = RectF{Width: box, Height: float32(layout.lines) * metrics.LineHeight}
+	text.layout = layout
+	return &text.layout
+}
+
+//breakLines : Every rune with its line and position on it, and the width of
+//every line without its trailing spaces
+func (text *Text) breakLines() ([]textItem, []float32) {
+	space, _ := text.font.Glyph(' ', text.size)
+	tab := space.Advance * tabSpaces
+	items := make([]textItem, 0, len(text.str))
+	line, lineStart := 0, 0
+	pen := float32(0)
+	lastBreak := -1 //Index of the last space of the line, -1 if none
+	prev := rune(-1)
+	for _, r := range text.str {
+		item := textItem{r: r, line: line}
+		switch {
+		case r == '\n':
+			item.x = pen
+			items = append(items, item)
+			line, lineStart, pen, lastBreak, prev = line+1, len(items), 0, -1, -1
+			continue
+		case r == '\t':
+			if tab > 0 {
+				item.advance = float32(math.Floor(float64(pen/tab))+1)*tab - pen
+			}
+		case unicode.IsControl(r):
+			//Zero width, not drawn
+		default:
+			item.glyph, _ = text.font.Glyph(r, text.size)
+			item.advance = item.glyph.Advance
+			item.visible = true
+			if prev >= 0 {
+				pen += text.font.Kerning(prev, r, text.size)
+			}
+		}
+
+		if text.wrapWidth > 0 && pen+item.advance > text.wrapWidth && len(items) > lineStart && !unicode.IsSpace(r) {
+			if lastBreak >= lineStart {
+				//Move the word after the last space to a new line
+				start := lastBreak + 1
+				shift := pen
+				if start < len(items) {
+					shift = items[start].x
+				}
+				for i := start; i < len(items); i++ {
+					items[i].line++
+					items[i].x -= shift
+				}
+				lineStart = start
+			} else {
+				//A word longer than a line, break it here
+				lineStart = len(items)
+			}
+			line++
+			lastBreak = -1
+			item.line = line
+			//Kerning was added against the rune before, which may be on the
+			//other line now
+			pen = 0
+			if len(items) > lineStart {
+				last := items[len(items)-1]
+				pen = last.x + last.advance
+				if item.visible && last.visible {
+					pen += text.font.Kerning(last.r, r, text.size)
+				}
+			}
+		}
+		item.x = pen
+		pen += item.advance
+		if unicode.IsSpace(r) {
+			lastBreak = len(items)
+		}
+		if item.visible {
+			prev = r
+		} else {
+			prev = -1
+		}
+		items = append(items, item)
+	}
+
+	widths := make([]float32, line+1)
+	for _, item := range items {
+		if item.visible && !unicode.IsSpace(item.r) {
+			widths[item.line] = maxFloat(widths[item.line], item.x+item.advance)
+		}
+	}
+	return items, widths
+}
+
+//roundFloat : f rounded to the nearest whole number, so glyphs land on
+//pixels
+func roundFloat(f float32) float32 {
+	return float32(math.Floor(float64(f) + 0.5))
+}
@@ -0,0 +1,171 @@
+package goldcore
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+var (
+	_ Drawable            = (*Text)(nil)
+	_ TextEnteredObserver = (*Text)(nil)
+)
+
+func TestTextLayout(t *testing.T) {
+	bm := testBMFont(t)
+	text := NewText(bm, "AA\nA", 10)
+	if text.LineCount() != 2 {
+		t.Errorf("Expected 2 lines got %d", text.LineCount())
+	}
+	//A is 6 wide, kerned by -1 against another A
+	if b := text.LocalBounds(); b != (RectF{Width: 11, Height: 24}) {
+		t.Errorf("Unexpected bounds %v", b)
+	}
+	for i, expected := range []Vector2f{{0, 0}, {5, 0}, {11, 0}, {0, 12}, {6, 12}, {6, 12}} {
+		if pos := text.CharacterPosition(i); !pos.Equals(expected) {
+			t.Errorf("Caret %d: expected %v got %v", i, expected, pos)
+		}
+	}
+	if pos := text.CharacterPosition(-1); !pos.Equals(Vector2f{}) {
+		t.Errorf("Carets before the start should be at the start, got %v", pos)
+	}
+
+	text.SetString("\tA")
+	if pos := text.CharacterPosition(1); pos.X != 12 {
+		t.Errorf("Tab stops are 4 spaces apart, expected 12 got %v", pos.X)
+	}
+	text.SetString("AA\n")
+	if pos := text.CharacterPosition(3); !pos.Equals(Vector2f{0, 12}) || text.LineCount() != 2 {
+		t.Errorf("A trailing newline starts a line, caret at %v", pos)
+	}
+	text.SetSize(20)
+	if b := text.LocalBounds(); b != (RectF{Width: 22, Height: 48}) {
+		t.Errorf("Expected bounds scaled with the size got %v", b)
+	}
+
+	text = NewText(bm, "A\nAA", 10)
+	for align, expected := range map[TextAlign]float32{AlignLeft: 0, AlignCenter: 3, AlignRight: 5} {
+		text.SetAlign(align)
+		if pos := text.CharacterPosition(0); pos.X != expected {
+			t.Errorf("Align %d: expected the short line at %v got %v", align, expected, pos.X)
+		}
+		if pos := text.CharacterPosition(2); pos.X != 0 {
+			t.Errorf("Align %d: the longest line fills the box, got %v", align, pos.X)
+		}
+	}
+
+	if NewText(nil, "A", 10).LocalBounds() != (RectF{}) {
+		t.Error("Text without a font is empty")
+	}
+}
+
+func TestTextWrap(t *testing.T) {
+	bm := testBMFont(t)
+	text := NewText(bm, "AA AA", 10)
+	text.SetWrapWidth(12)
+	if text.LineCount() != 2 {
+		t.Fatalf("Expected 2 lines got %d", text.LineCount())
+	}
+	//The space stays at the end of the first line
+	for i, expected := range []Vector2f{{0, 0}, {5, 0}, {11, 0}, {0, 12}, {5, 12}, {11, 12}} {
+		if pos := text.CharacterPosition(i); !pos.Equals(expected) {
+			t.Errorf("Caret %d: expected %v got %v", i, expected, pos)
+		}
+	}
+	if b := text.LocalBounds(); b.Width != 12 {
+		t.Errorf("The box is as wide as the wrap width, got %v", b.Width)
+	}
+	text.SetAlign(AlignRight)
+	if pos := text.CharacterPosition(3); pos.X != 1 {
+		t.Errorf("Trailing spaces don't count when aligning, expected 1 got %v", pos.X)
+	}
+
+	//Words longer than a line break anywhere
+	text = NewText(bm, "AAAAA", 10)
+	text.SetWrapWidth(12)
+	if text.LineCount() != 3 {
+		t.Errorf("Expected 3 lines got %d", text.LineCount())
+	}
+	if pos := text.CharacterPosition(2); !pos.Equals(Vector2f{0, 12}) {
+		t.Errorf("Expected the third A to start a line got %v", pos)
+	}
+
+	size := MeasureText(bm, "AA AA", 10, 12)
+	if !size.Equals(Vector2f{12, 24}) {
+		t.Errorf("Expected 12x24 got %v", size)
+	}
+	if size := MeasureText(bm, "AA AA", 10, 0); !size.Equals(Vector2f{25, 12}) {
+		t.Errorf("Expected 25x12 without wrapping got %v", size)
+	}
+}
+
+func TestTextEditing(t *testing.T) {
+	text := NewText(testBMFont(t), "", 10)
+	for _, r := range "hé" {
+		text.OnTextEntered(EventTextEntered{Char: r})
+	}
+	text.AppendRune('\b')
+	text.AppendRune('😀')
+	if text.String() != "h😀" {
+		t.Errorf("Expected h😀 got %q", text.String())
+	}
+	text.AppendRune('\b')
+	text.AppendRune('\r')
+	text.AppendRune('\x01')
+	text.AppendRune('\t')
+	if text.String() != "h\n\t" {
+		t.Errorf("Expected h\\n\\t got %q", text.String())
+	}
+	if text.LineCount() != 2 {
+		t.Errorf("Return should start a line, got %d lines", text.LineCount())
+	}
+	text.SetString("")
+	text.AppendRune('\b')
+	if text.String() != "" {
+		t.Error("Backspace on nothing should do nothing")
+	}
+}
+
+func TestTextDraw(t *testing.T) {
+	bm := testBMFont(t)
+	gW := NewHeadlessGameWindow(16, 16, "Text")
+	gW.Clear(ColorBlack)
+	text := NewText(bm, "A ?", 10)
+	text.Position = Vector2f{X: 1, Y: 1}
+	text.Color = Color{G: 0xff, A: 0xff}
+	gW.Draw(text)
+	gW.renderWindow.Display()
+	gW.stats.endFrame()
+	//Both glyphs are on one page
+	if stats := gW.FrameStats(); stats != (DrawStats{DrawCalls: 1, Vertices: 12}) {
+		t.Errorf("Expected one call for 2 glyphs got %+v", stats)
+	}
+	//A at (2, 3) to (6, 11), tinted green. '?' at 9, red tinted green is black
+	expectPixels(t, gW.Capture(), map[image.Point]color.RGBA{
+		{2, 3}: green, {5, 10}: green, {1, 3}: black, {6, 3}: black, {2, 11}: black, {2, 2}: black, {9, 3}: black,
+	})
+	if b := text.GlobalBounds(); b != (RectF{Left: 1, Top: 1, Width: 14, Height: 12}) {
+		t.Errorf("Unexpected global bounds %v", b)
+	}
+
+	//Text made of both fonts draws once per texture
+	ttf := testTrueTypeFont(t)
+	gW.Clear(ColorBlack)
+	gW.Draw(NewText(FontStack{bm, ttf}, "AéAé", 10))
+	gW.stats.endFrame()
+	if stats := gW.FrameStats(); stats.DrawCalls != 2 {
+		t.Errorf("Expected 2 calls got %+v", stats)
+	}
+	lit := 0
+	frame := gW.Capture()
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 16; x++ {
+			if frame.RGBAAt(x, y) != black {
+				lit++
+			}
+		}
+	}
+	if lit == 0 {
+		t.Error("Text should draw something")
+	}
+}
@@ -0,0 +1,287 @@
+package goldcore
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/jpeg" //Registers JPEG for DecodeTexture
+	_ "image/png"  //Registers PNG for DecodeTexture
+	"io"
+	"io/fs"
+	"sync"
+	"sync/atomic"
+
+	sf "github.com/manyminds/gosfml"
+)
+
+//Texture : Image that can be drawn by sprites and vertices. The pixels are
+//kept in memory for the software rasterizer and uploaded to the GPU the
+//first time an SFML target draws it. Safe for concurrent use
+type Texture struct {
+	id        uint64 //Creation order, sorts batches the same way every run
+	mutex     sync.Mutex
+	pixels    *image.RGBA //Alpha premultiplied, top left at 0, 0
+	smooth    bool
+	repeated  bool
+	sfTexture *sf.Texture
+	dirty     bool          //pixels changed since the upload
+	filtering bool          //smooth or repeated changed since the upload
+	surface   renderSurface //RenderTexture shown, nil for plain textures
+	synced    uint64        //Display count of surface when pixels were read
+}
+
+//textureCount : Number of textures created so far
+var textureCount uint64
+
+//NewTexture : Texture holding a copy of img
+func NewTexture(img image.Image) *Texture {
+	bounds := img.Bounds()
+	pixels := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(pixels, pixels.Rect, img, bounds.Min, draw.Src)
+	return &Texture{id: atomic.AddUint64(&textureCount, 1), pixels: pixels, dirty: true}
+}
+
+//newSurfaceTexture : Texture showing what surface last displayed
+func newSurfaceTexture(surface renderSurface) *Texture {
+	return &Texture{id: atomic.AddUint64(&textureCount, 1), pixels: surface.Capture(), surface: surface, synced: surface.displayCount(), dirty: true, filtering: true}
+}
+
+//NewEmptyTexture : Fully transparent texture of the given size, to fill
+//with Update
+func NewEmptyTexture(width, height uint) *Texture {
+	return NewTexture(image.NewRGBA(image.Rect(0, 0, int(width), int(height))))
+}
+
+//DecodeTexture : Texture from PNG, JPEG or GTEX data
+func DecodeTexture(r io.Reader) (*Texture, error) {
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("goldcore: decoding texture: %v", err)
+	}
+	return NewTexture(img), nil
+}
+
+//LoadTexture : Texture from a PNG, JPEG or GTEX file
+func LoadTexture(path string) (*Texture, error) {
+	return LoadTextureFS(osFS{}, path)
+}
+
+//LoadTextureFS : Texture from a PNG, JPEG or GTEX file of fsys, a VFS for example
+func LoadTextureFS(fsys fs.FS, name string) (*Texture, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	tex, err := DecodeTexture(file)
+	if err != nil {
+		return nil, fmt.Errorf("goldcore: loading texture %s: %v", name, err)
+	}
+	return tex, nil
+}
+
+//Size : Size in pixels
+func (tex *Texture) Size() Vector2u {
+	if tex.surface != nil {
+		return tex.surface.GetSize()
+	}
+	tex.mutex.Lock()
+	defer tex.mutex.Unlock()
+	size := tex.pixels.Rect.Size()
+	return Vector2u{X: uint(size.X), Y: uint(size.Y)}
+}
+
+//Image : Copy of the pixels
+func (tex *Texture) Image() *image.RGBA {
+	tex.mutex.Lock()
+	defer tex.mutex.Unlock()
+	tex.syncSurface()
+	img := image.NewRGBA(tex.pixels.Rect)
+	copy(img.Pix, tex.pixels.Pix)
+	return img
+}
+
+//Update : Replaces the pixels under img, with its top left corner at pos.
+//What falls outside the texture is dropped
+func (tex *Texture) Update(img image.Image, pos Vector2i) {
+	tex.mutex.Lock()
+	bounds := img.Bounds()
+	dst := bounds.Sub(bounds.Min).Add(image.Pt(pos.X, pos.Y))
+	draw.Draw(tex.pixels, dst, img, bounds.Min, draw.Src)
+	tex.dirty = true
+	tex.mutex.Unlock()
+}
+
+//replace : Takes the pixels of other, which may be another size. Used by
+//hot reloading so whatever holds the texture sees the new file
+func (tex *Texture) replace(other *Texture) {
+	pixels := other.Image()
+	tex.mutex.Lock()
+	tex.pixels = pixels
+	tex.dirty = true
+	tex.mutex.Unlock()
+}
+
+//SetSmooth : Filters the texture bilinearly when it is scaled or rotated.
+//Off by default, which keeps pixel art crisp
+func (tex *Texture) SetSmooth(smooth bool) {
+	tex.mutex.Lock()
+	tex.smooth = smooth
+	tex.filtering = true
+	tex.mutex.Unlock()
+}
+
+//IsSmooth : Checks whether the texture is filtered
+func (tex *Texture) IsSmooth() bool {
+	tex.mutex.Lock()
+	defer tex.mutex.Unlock()
+	return tex.smooth
+}
+
+//SetRepeated : Tiles the texture when texture coordinates go past its
+//edges, instead of stretching the edge pixels
+func (tex *Texture) SetRepeated(repeated bool) {
+	tex.mutex.Lock()
+	tex.repeated = repeated
+	tex.filtering = true
+	tex.mutex.Unlock()
+}
+
+//IsRepeated : Checks whether the texture tiles
+func (tex *Texture) IsRepeated() bool {
+	tex.mutex.Lock()
+	defer tex.mutex.Unlock()
+	return tex.repeated
+}
+
+//ToSFML : GPU copy of the texture. Changed pixels are uploaded into the
+//same GPU texture, a new one is only created when the size changed. Must be
+//called on the render thread. Nil if SFML can't create it
+func (tex *Texture) ToSFML() *sf.Texture {
+	tex.mutex.Lock()
+	defer tex.mutex.Unlock()
+	if rt, ok := tex.surface.(*sfmlRenderTexture); ok {
+		//Drawn straight from the GPU
+		sfTexture := rt.RenderTexture.GetTexture()
+		if tex.filtering {
+			sfTexture.SetSmooth(tex.smooth)
+			sfTexture.SetRepeated(tex.repeated)
+			tex.filtering = false
+		}
+		return sfTexture
+	}
+	tex.syncSurface()
+	size := tex.pixels.Rect.Size()
+	width, height := uint(size.X), uint(size.Y)
+	if tex.sfTexture == nil || tex.sfTexture.GetSize() != (sf.Vector2u{X: width, Y: height}) {
+		sfTexture, err := sf.NewTexture(width, height)
+		if err != nil {
+			return nil
+		}
+		tex.sfTexture, tex.dirty, tex.filtering = sfTexture, true, true
+	}
+	if tex.dirty {
+		tex.sfTexture.UpdateFromPixels(nrgbaPixels(tex.pixels), width, height, 0, 0)
+		tex.dirty = false
+	}
+	if tex.filtering {
+		tex.sfTexture.SetSmooth(tex.smooth)
+		tex.sfTexture.SetRepeated(tex.repeated)
+		tex.filtering = false
+	}
+	return tex.sfTexture
+}
+
+//syncSurface : Reads the pixels of the RenderTexture shown again if it was
+//displayed since the last read. Mutex held
+func (tex *Texture) syncSurface() {
+	if tex.surface == nil {
+		return
+	}
+	if displays := tex.surface.displayCount(); displays != tex.synced {
+		tex.pixels, tex.synced, tex.dirty = tex.surface.Capture(), displays, true
+	}
+}
+
+//sampler : Snapshot of what the rasterizer needs to read a texture
+type sampler struct {
+	pixels   *image.RGBA
+	smooth   bool
+	repeated bool
+}
+
+//sampler : Reads the texture without holding the mutex for every pixel.
+//The pixels are shared, Update replaces them in place so don't update a
+//texture while it is being drawn
+func (tex *Texture) sampler() sampler {
+	tex.mutex.Lock()
+	defer tex.mutex.Unlock()
+	tex.syncSurface()
+	return sampler{pixels: tex.pixels, smooth: tex.smooth, repeated: tex.repeated}
+}
+
+//sample : Premultiplied color, from 0 to 1, at u, v in texture pixels
+func (s sampler) sample(u, v float32) [4]float32 {
+	if !s.smooth {
+		return s.texel(floorInt(u), floorInt(v))
+	}
+	//Bilinear between the 4 nearest texel centers
+	u, v = u-0.5, v-0.5
+	x, y := floorInt(u), floorInt(v)
+	fx, fy := u-float32(x), v-float32(y)
+	a, b := s.texel(x, y), s.texel(x+1, y)
+	c, d := s.texel(x, y+1), s.texel(x+1, y+1)
+	var out [4]float32
+	for i := range out {
+		top := a[i] + (b[i]-a[i])*fx
+		bottom := c[i] + (d[i]-c[i])*fx
+		out[i] = top + (bottom-top)*fy
+	}
+	return out
+}
+
+//texel : Premultiplied color of texel x, y. Outside the texture it wraps
+//if repeated, otherwise the nearest edge texel is used
+func (s sampler) texel(x, y int) [4]float32 {
+	w, h := s.pixels.Rect.Dx(), s.pixels.Rect.Dy()
+	if w == 0 || h == 0 {
+		return [4]float32{}
+	}
+	if s.repeated {
+		x, y = wrapInt(x, w), wrapInt(y, h)
+	} else {
+		x, y = clampInt(x, 0, w-1), clampInt(y, 0, h-1)
+	}
+	i := s.pixels.PixOffset(x, y)
+	p := s.pixels.Pix[i : i+4 : i+4]
+	return [4]float32{float32(p[0]) / 0xff, float32(p[1]) / 0xff, float32(p[2]) / 0xff, float32(p[3]) / 0xff}
+}
+
+//floorInt : Largest int not above f
+func floorInt(f float32) int {
+	i := int(f)
+	if float32(i) > f {
+		i--
+	}
+	return i
+}
+
+//wrapInt : i modulo n, always in [0, n)
+func wrapInt(i, n int) int {
+	i %= n
+	if i < 0 {
+		i += n
+	}
+	return i
+}
+
+//clampInt : i kept between min and max
+func clampInt(i, min, max int) int {
+	if i < min {
+		return min
+	}
+	if i > max {
+		return max
+	}
+	return i
+}
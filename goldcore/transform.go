@@ -0,0 +1,164 @@
+package goldcore
+
+import (
+	"math"
+
+	sf "github.com/manyminds/gosfml"
+)
+
+//Transform2D : 3x3 affine transform, row major. The last row is always
+//0 0 1 so it isn't stored.
+//	x' = M00*x + M01*y + M02
+//	y' = M10*x + M11*y + M12
+//The zero value is not the identity, use IdentityTransform
+type Transform2D struct {
+	M00, M01, M02 float32
+	M10, M11, M12 float32
+}
+
+/////////////////////////////////////
+// Builders
+
+//IdentityTransform : Transform that changes nothing
+func IdentityTransform() Transform2D {
+	return Transform2D{M00: 1, M11: 1}
+}
+
+//TranslationTransform : Moves points by offset
+func TranslationTransform(offset Vector2f) Transform2D {
+	return Transform2D{M00: 1, M02: offset.X, M11: 1, M12: offset.Y}
+}
+
+//RotationTransform : Turns points around the origin by radians, clockwise
+//on screen (y pointing down)
+func RotationTransform(radians float32) Transform2D {
+	sin, cos := math.Sincos(float64(radians))
+	s, c := float32(sin), float32(cos)
+	return Transform2D{M00: c, M01: -s, M10: s, M11: c}
+}
+
+//ScaleTransform : Scales points away from the origin
+func ScaleTransform(factors Vector2f) Transform2D {
+	return Transform2D{M00: factors.X, M11: factors.Y}
+}
+
+//ShearTransform : Slides x by shear.X times y, and y by shear.Y times x
+func ShearTransform(shear Vector2f) Transform2D {
+	return Transform2D{M00: 1, M01: shear.X, M10: shear.Y, M11: 1}
+}
+
+/////////////////////////////////////
+// Composition
+
+//Compose : Transform applying other first, then t. Same as the matrix
+//product t * other
+func (t Transform2D) Compose(other Transform2D) Transform2D {
+	return Transform2D{
+		M00: t.M00*other.M00 + t.M01*other.M10,
+		M01: t.M00*other.M01 + t.M01*other.M11,
+		M02: t.M00*other.M02 + t.M01*other.M12 + t.M02,
+		M10: t.M10*other.M00 + t.M11*other.M10,
+		M11: t.M10*other.M01 + t.M11*other.M11,
+		M12: t.M10*other.M02 + t.M11*other.M12 + t.M12,
+	}
+}
+
+//Translate : t with a translation applied before it, like SFML's
+//Transform.Translate. Chain Translate, Rotate and Scale in the order you
+//would write them for a scene graph node
+func (t Transform2D) Translate(offset Vector2f) Transform2D {
+	return t.Compose(TranslationTransform(offset))
+}
+
+//Rotate : t with a rotation around the origin applied before it
+func (t Transform2D) Rotate(radians float32) Transform2D {
+	return t.Compose(RotationTransform(radians))
+}
+
+//RotateAround : t with a rotation around center applied before it
+func (t Transform2D) RotateAround(radians float32, center Vector2f) Transform2D {
+	return t.Translate(center).Rotate(radians).Translate(center.Neg())
+}
+
+//Scale : t with a scale applied before it
+func (t Transform2D) Scale(factors Vector2f) Transform2D {
+	return t.Compose(ScaleTransform(factors))
+}
+
+//Shear : t with a shear applied before it
+func (t Transform2D) Shear(shear Vector2f) Transform2D {
+	return t.Compose(ShearTransform(shear))
+}
+
+//Determinant : How much areas are scaled. Negative if the transform mirrors
+func (t Transform2D) Determinant() float32 {
+	return t.M00*t.M11 - t.M01*t.M10
+}
+
+//Inverse : Transform undoing t. False if t squashes everything onto a line
+//and can't be undone
+func (t Transform2D) Inverse() (Transform2D, bool) {
+	det := t.Determinant()
+	if det == 0 {
+		return IdentityTransform(), false
+	}
+	inv := Transform2D{
+		M00: t.M11 / det,
+		M01: -t.M01 / det,
+		M10: -t.M10 / det,
+		M11: t.M00 / det,
+	}
+	inv.M02 = -(inv.M00*t.M02 + inv.M01*t.M12)
+	inv.M12 = -(inv.M10*t.M02 + inv.M11*t.M12)
+	return inv, true
+}
+
+/////////////////////////////////////
+// Application
+
+//TransformPoint : Where t moves point p
+func (t Transform2D) TransformPoint(p Vector2f) Vector2f {
+	return Vector2f{X: t.M00*p.X + t.M01*p.Y + t.M02, Y: t.M10*p.X + t.M11*p.Y + t.M12}
+}
+
+//TransformVector : Where t moves direction v. Translation doesn't apply
+func (t Transform2D) TransformVector(v Vector2f) Vector2f {
+	return Vector2f{X: t.M00*v.X + t.M01*v.Y, Y: t.M10*v.X + t.M11*v.Y}
+}
+
+//TransformRect : Axis aligned bounding box of rect once transformed
+func (t Transform2D) TransformRect(rect RectF) RectF {
+	corners := rect.Corners()
+	for i := range corners {
+		corners[i] = t.TransformPoint(corners[i])
+	}
+	return RectFromPoints(corners[:]...)
+}
+
+//Equals : True if every coefficient is equal
+func (t Transform2D) Equals(other Transform2D) bool {
+	return t == other
+}
+
+//ApproxEquals : True if every coefficient is within epsilon of the other's
+func (t Transform2D) ApproxEquals(other Transform2D, epsilon float32) bool {
+	return ApproxEqual(t.M00, other.M00, epsilon) && ApproxEqual(t.M01, other.M01, epsilon) &&
+		ApproxEqual(t.M02, other.M02, epsilon) && ApproxEqual(t.M10, other.M10, epsilon) &&
+		ApproxEqual(t.M11, other.M11, epsilon) && ApproxEqual(t.M12, other.M12, epsilon)
+}
+
+//ToSFML : Allows for SFML compatability
+func (t Transform2D) ToSFML() sf.Transform {
+	return sf.Transform{Matrix: [9]float32{
+		t.M00, t.M01, t.M02,
+		t.M10, t.M11, t.M12,
+		0, 0, 1,
+	}}
+}
+
+//SFTransformToTransform2D : SFML Transform to GoldEngine Transform2D. The
+//projective part of the SFML matrix is dropped
+func SFTransformToTransform2D(other sf.Transform) Transform2D {
+	m := other.Matrix
+	return Transform2D{M00: m[0], M01: m[1], M02: m[2], M10: m[3], M11: m[4], M12: m[5]}
+}
@@ -0,0 +1,183 @@
+package goldcore
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTransform2DBuilders(t *testing.T) {
+	p := Vector2f{X: 2, Y: 3}
+	if got := IdentityTransform().TransformPoint(p); got != p {
+		t.Errorf("Identity moved %v to %v", p, got)
+	}
+	if got := TranslationTransform(Vector2f{X: 10, Y: -1}).TransformPoint(p); got != (Vector2f{X: 12, Y: 2}) {
+		t.Errorf("Translation: got %v", got)
+	}
+	if got := ScaleTransform(Vector2f{X: 2, Y: 3}).TransformPoint(p); got != (Vector2f{X: 4, Y: 9}) {
+		t.Errorf("Scale: got %v", got)
+	}
+	if got := ShearTransform(Vector2f{X: 1}).TransformPoint(p); got != (Vector2f{X: 5, Y: 3}) {
+		t.Errorf("Shear: got %v", got)
+	}
+	got := RotationTransform(math.Pi / 2).TransformPoint(Vector2f{X: 1})
+	if !got.ApproxEquals(Vector2f{Y: 1}, Epsilon) {
+		t.Errorf("Rotation: expected {0 1}, got %v", got)
+	}
+	if got := RotationTransform(1).TransformPoint(p); !got.ApproxEquals(p.Rotate(1), Epsilon) {
+		t.Errorf("Rotation disagrees with Vector2f.Rotate: %v vs %v", got, p.Rotate(1))
+	}
+}
+
+func TestTransform2DCompose(t *testing.T) {
+	//Scene graph style: scale first, then rotate, then move
+	tr := IdentityTransform().Translate(Vector2f{X: 100, Y: 50}).Rotate(math.Pi / 2).Scale(Vector2f{X: 2, Y: 2})
+	got := tr.TransformPoint(Vector2f{X: 1})
+	if !got.ApproxEquals(Vector2f{X: 100, Y: 52}, Epsilon) {
+		t.Errorf("expected {100 52}, got %v", got)
+	}
+	if v := tr.TransformVector(Vector2f{X: 1}); !v.ApproxEquals(Vector2f{Y: 2}, Epsilon) {
+		t.Errorf("TransformVector must ignore translation, got %v", v)
+	}
+
+	around := IdentityTransform().RotateAround(math.Pi, Vector2f{X: 5, Y: 5})
+	if got := around.TransformPoint(Vector2f{X: 5, Y: 5}); !got.ApproxEquals(Vector2f{X: 5, Y: 5}, Epsilon) {
+		t.Errorf("RotateAround moved its center to %v", got)
+	}
+	if got := around.TransformPoint(Vector2f{X: 6, Y: 5}); !got.ApproxEquals(Vector2f{X: 4, Y: 5}, Epsilon) {
+		t.Errorf("RotateAround: expected {4 5}, got %v", got)
+	}
+
+	if det := ScaleTransform(Vector2f{X: -2, Y: 3}).Determinant(); det != -6 {
+		t.Errorf("Determinant: expected -6, got %v", det)
+	}
+}
+
+func TestTransform2DInverse(t *testing.T) {
+	tr := IdentityTransform().Translate(Vector2f{X: 3, Y: -7}).Rotate(0.7).Shear(Vector2f{X: 0.3}).Scale(Vector2f{X: 2, Y: 0.5})
+	inv, ok := tr.Inverse()
+	if !ok {
+		t.Fatal("transform should be invertible")
+	}
+	if !tr.Compose(inv).ApproxEquals(IdentityTransform(), 1e-4) {
+		t.Errorf("t * inverse should be the identity, got %v", tr.Compose(inv))
+	}
+	p := Vector2f{X: 12, Y: -4}
+	if got := inv.TransformPoint(tr.TransformPoint(p)); !got.ApproxEquals(p, 1e-4) {
+		t.Errorf("round trip: expected %v, got %v", p, got)
+	}
+	if _, ok := ScaleTransform(Vector2f{X: 1}).Inverse(); ok {
+		t.Error("flattening transform shouldn't be invertible")
+	}
+}
+
+func TestTransform2DRect(t *testing.T) {
+	r := RotationTransform(math.Pi / 2).TransformRect(RectF{Width: 4, Height: 2})
+	expected := RectF{Left: -2, Width: 2, Height: 4}
+	if !closeTo(Vector2f{X: r.Left, Y: r.Top}, Vector2f{X: expected.Left, Y: expected.Top}) ||
+		!closeTo(Vector2f{X: r.Width, Y: r.Height}, Vector2f{X: expected.Width, Y: expected.Height}) {
+		t.Errorf("expected %v, got %v", expected, r)
+	}
+}
+
+func TestTransform2DSFML(t *testing.T) {
+	tr := IdentityTransform().Translate(Vector2f{X: 1, Y: 2}).Rotate(0.3).Scale(Vector2f{X: 4, Y: 5})
+	sfT := tr.ToSFML()
+	if sfT.Matrix[6] != 0 || sfT.Matrix[7] != 0 || sfT.Matrix[8] != 1 {
+		t.Errorf("last row should be 0 0 1, got %v", sfT.Matrix[6:])
+	}
+	if sfT.Matrix[2] != 1 || sfT.Matrix[5] != 2 {
+		t.Errorf("translation should be in the last column, got %v", sfT.Matrix)
+	}
+	if back := SFTransformToTransform2D(sfT); back != tr {
+		t.Errorf("round trip: expected %v, got %v", tr, back)
+	}
+}
+
+func TestMat4(t *testing.T) {
+	p := Vector3f{X: 1, Y: 2, Z: 3}
+	m := TranslationMat4(Vector3f{X: 10}).Mul(RotationMat4(Vector3f{Z: 1}, math.Pi/2)).Mul(ScaleMat4(Vector3f{X: 2, Y: 2, Z: 2}))
+	if got := m.TransformPoint(p); !got.ApproxEquals(Vector3f{X: 6, Y: 2, Z: 6}, 1e-4) {
+		t.Errorf("expected {6 2 6}, got %v", got)
+	}
+	if got := m.TransformVector(Vector3f{X: 1}); !got.ApproxEquals(Vector3f{Y: 2}, 1e-4) {
+		t.Errorf("TransformVector must ignore translation, got %v", got)
+	}
+
+	inv, ok := m.Inverse()
+	if !ok {
+		t.Fatal("matrix should be invertible")
+	}
+	if !m.Mul(inv).ApproxEquals(IdentityMat4(), 1e-4) {
+		t.Errorf("m * inverse should be the identity, got %v", m.Mul(inv))
+	}
+	if det := m.Determinant(); !ApproxEqual(det, 8, 1e-4) {
+		t.Errorf("Determinant: expected 8, got %v", det)
+	}
+	if _, ok := ScaleMat4(Vector3f{X: 1, Y: 1}).Inverse(); ok {
+		t.Error("flattening matrix shouldn't be invertible")
+	}
+
+	if cm := m.ColumnMajor(); cm[12] != m.At(0, 3) || cm[1] != m.At(1, 0) {
+		t.Errorf("ColumnMajor layout wrong: %v", cm)
+	}
+}
+
+func TestMat4Projections(t *testing.T) {
+	view := LookAtMat4(Vector3f{Z: 5}, Vector3f{}, Vector3f{Y: 1})
+	if got := view.TransformPoint(Vector3f{}); !got.ApproxEquals(Vector3f{Z: -5}, 1e-4) {
+		t.Errorf("LookAt: target should be 5 units down -Z, got %v", got)
+	}
+
+	proj := PerspectiveMat4(math.Pi/2, 1, 1, 100)
+	if got := proj.TransformPoint(Vector3f{Z: -1}); !ApproxEqual(got.Z, -1, 1e-4) {
+		t.Errorf("near plane should map to -1, got %v", got.Z)
+	}
+	if got := proj.TransformPoint(Vector3f{Z: -100}); !ApproxEqual(got.Z, 1, 1e-4) {
+		t.Errorf("far plane should map to 1, got %v", got.Z)
+	}
+	if got := proj.TransformPoint(Vector3f{X: 1, Z: -1}); !ApproxEqual(got.X, 1, 1e-4) {
+		t.Errorf("edge of a 90 degree fov should map to 1, got %v", got.X)
+	}
+
+	ortho := OrthographicMat4(0, 800, 600, 0, -1, 1)
+	if got := ortho.TransformPoint(Vector3f{X: 800, Y: 600}); !got.ApproxEquals(Vector3f{X: 1, Y: -1}, 1e-4) {
+		t.Errorf("Orthographic: expected {1 -1 0}, got %v", got)
+	}
+}
+
+func TestQuaternion(t *testing.T) {
+	q := QuaternionFromAxisAngle(Vector3f{Z: 1}, math.Pi/2)
+	if got := q.Rotate(Vector3f{X: 1}); !got.ApproxEquals(Vector3f{Y: 1}, Epsilon) {
+		t.Errorf("expected {0 1 0}, got %v", got)
+	}
+	if got := q.Conjugate().Rotate(q.Rotate(Vector3f{X: 1, Y: 2, Z: 3})); !got.ApproxEquals(Vector3f{X: 1, Y: 2, Z: 3}, 1e-4) {
+		t.Errorf("conjugate should undo the rotation, got %v", got)
+	}
+
+	a := QuaternionFromEuler(0.3, -1.1, 2)
+	v := Vector3f{X: -2, Y: 0.5, Z: 4}
+	if got, expected := a.Rotate(v), a.ToMat4().TransformPoint(v); !got.ApproxEquals(expected, 1e-4) {
+		t.Errorf("Rotate and ToMat4 disagree: %v vs %v", got, expected)
+	}
+	b := QuaternionFromAxisAngle(Vector3f{X: 1, Y: 1}, 0.8)
+	if got, expected := a.Mul(b).Rotate(v), a.Rotate(b.Rotate(v)); !got.ApproxEquals(expected, 1e-4) {
+		t.Errorf("Mul should apply other first: %v vs %v", got, expected)
+	}
+	if !a.Mul(a.Inverse()).ApproxEquals(IdentityQuaternion(), 1e-4) {
+		t.Errorf("q * inverse should be the identity, got %v", a.Mul(a.Inverse()))
+	}
+
+	axis, angle := b.AxisAngle()
+	if !axis.ApproxEquals(Vector3f{X: 1, Y: 1}.Normalize(), 1e-4) || !ApproxEqual(angle, 0.8, 1e-4) {
+		t.Errorf("AxisAngle: got %v %v", axis, angle)
+	}
+
+	from := IdentityQuaternion()
+	to := QuaternionFromAxisAngle(Vector3f{Y: 1}, 2)
+	if half := from.Slerp(to, 0.5); !half.ApproxEquals(QuaternionFromAxisAngle(Vector3f{Y: 1}, 1), 1e-4) {
+		t.Errorf("Slerp halfway: got %v", half)
+	}
+	if end := from.Slerp(to, 1); !end.ApproxEquals(to, 1e-4) {
+		t.Errorf("Slerp end: got %v", end)
+	}
+}
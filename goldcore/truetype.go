@@ -0,0 +1,156 @@
+package goldcore
+
+import (
+	"fmt"
+	"io/fs"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+//TrueTypeFont : TrueType or OpenType font, drawn at any size by a pure Go
+//rasterizer. Glyphs are drawn once per size, the first time they are needed,
+//into a glyph atlas
+type TrueTypeFont struct {
+	mutex  sync.Mutex
+	font   *sfnt.Font
+	buf    sfnt.Buffer
+	faces  map[float32]font.Face
+	glyphs map[glyphKey]Glyph
+	atlas  glyphAtlas
+}
+
+//glyphKey : A rune at a size
+type glyphKey struct {
+	r    rune
+	size float32
+}
+
+//ParseTrueTypeFont : Font from TTF or OTF data
+func ParseTrueTypeFont(data []byte) (*TrueTypeFont, error) {
+	f, err := opentype.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("goldcore: parsing font: %v", err)
+	}
+	return &TrueTypeFont{font: f, faces: make(map[float32]font.Face), glyphs: make(map[glyphKey]Glyph)}, nil
+}
+
+//LoadTrueTypeFont : Font from a TTF or OTF file
+func LoadTrueTypeFont(path string) (*TrueTypeFont, error) {
+	return LoadTrueTypeFontFS(osFS{}, path)
+}
+
+//LoadTrueTypeFontFS : Font from a TTF or OTF file of fsys
+func LoadTrueTypeFontFS(fsys fs.FS, name string) (*TrueTypeFont, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	f, err := ParseTrueTypeFont(data)
+	if err != nil {
+		return nil, fmt.Errorf("goldcore: loading font %s: %v", name, err)
+	}
+	return f, nil
+}
+
+//Name : Full name of the font, empty if it has none
+func (ttf *TrueTypeFont) Name() string {
+	ttf.mutex.Lock()
+	defer ttf.mutex.Unlock()
+	name, err := ttf.font.Name(&ttf.buf, sfnt.NameIDFull)
+	if err != nil {
+		return ""
+	}
+	return name
+}
+
+//Glyph : Glyph of r at size pixels. Runes the font lacks get its
+//placeholder glyph, usually a box, and false
+func (ttf *TrueTypeFont) Glyph(r rune, size float32) (Glyph, bool) {
+	ttf.mutex.Lock()
+	defer ttf.mutex.Unlock()
+	key := glyphKey{r: r, size: size}
+	if g, ok := ttf.glyphs[key]; ok {
+		return g, ttf.hasGlyph(r)
+	}
+	face, ok := ttf.faces[size]
+	if !ok {
+		face, _ = opentype.NewFace(ttf.font, &opentype.FaceOptions{Size: float64(size), DPI: 72, Hinting: font.HintingFull})
+		ttf.faces[size] = face
+	}
+	dr, mask, maskp, advance, _ := face.Glyph(fixed.Point26_6{}, r)
+	g := Glyph{
+		Advance: fixedToFloat(advance),
+		Bounds:  RectF{Left: float32(dr.Min.X), Top: float32(dr.Min.Y), Width: float32(dr.Dx()), Height: float32(dr.Dy())},
+	}
+	if mask != nil && !dr.Empty() {
+		g.Texture, g.TextureRect = ttf.atlas.add(mask, maskp, dr.Size())
+	}
+	ttf.glyphs[key] = g
+	return g, ttf.hasGlyph(r)
+}
+
+//HasGlyph : Checks whether the font has a glyph for r
+func (ttf *TrueTypeFont) HasGlyph(r rune) bool {
+	ttf.mutex.Lock()
+	defer ttf.mutex.Unlock()
+	return ttf.hasGlyph(r)
+}
+
+//hasGlyph : HasGlyph with the mutex held
+func (ttf *TrueTypeFont) hasGlyph(r rune) bool {
+	index, err := ttf.font.GlyphIndex(&ttf.buf, r)
+	return err == nil && index != 0
+}
+
+//Kerning : Kerning of the pair from the font's kern table, 0 if it has none
+func (ttf *TrueTypeFont) Kerning(a, b rune, size float32) float32 {
+	ttf.mutex.Lock()
+	defer ttf.mutex.Unlock()
+	ia, err := ttf.font.GlyphIndex(&ttf.buf, a)
+	if err != nil {
+		return 0
+	}
+	ib, err := ttf.font.GlyphIndex(&ttf.buf, b)
+	if err != nil {
+		return 0
+	}
+	kern, err := ttf.font.Kern(&ttf.buf, ia, ib, floatToFixed(size), font.HintingNone)
+	if err != nil {
+		return 0
+	}
+	return fixedToFloat(kern)
+}
+
+//Metrics : Ascent, descent and line height at size pixels
+func (ttf *TrueTypeFont) Metrics(size float32) FontMetrics {
+	ttf.mutex.Lock()
+	defer ttf.mutex.Unlock()
+	m, err := ttf.font.Metrics(&ttf.buf, floatToFixed(size), font.HintingFull)
+	if err != nil {
+		return FontMetrics{Ascent: size, LineHeight: size}
+	}
+	return FontMetrics{Ascent: fixedToFloat(m.Ascent), Descent: fixedToFloat(m.Descent), LineHeight: fixedToFloat(m.Height)}
+}
+
+//Textures : Glyph atlas pages drawn so far
+func (ttf *TrueTypeFont) Textures() []*Texture {
+	ttf.atlas.mutex.Lock()
+	defer ttf.atlas.mutex.Unlock()
+	textures := make([]*Texture, len(ttf.atlas.pages))
+	for i, page := range ttf.atlas.pages {
+		textures[i] = page.texture
+	}
+	return textures
+}
+
+func fixedToFloat(f fixed.Int26_6) float32 {
+	return float32(f) / 64
+}
+
+func floatToFixed(f float32) fixed.Int26_6 {
+	return fixed.Int26_6(f*64 + 0.5)
+}
@@ -0,0 +1,503 @@
+package goldcore
+
+import (
+	"reflect"
+	"sync"
+)
+
+//Animation : Something played over time by a TweenManager: a Tween, a
+//Sequence, a Parallel group, or your own
+type Animation interface {
+	//Update : Advances by dt seconds. Once the animation is done it returns
+	//true along with the part of dt it didn't need, so whatever plays next
+	//starts on time. notify sends completion messages
+	Update(dt float32, notify func(*GameMessage)) (rest float32, done bool)
+	//Reset : Rewinds to the start so the animation can play again
+	Reset()
+}
+
+//completion : What to do when an animation finishes. Embedded by the
+//animations of this file
+type completion struct {
+	callbacks []func()
+	messages  []GMessage
+}
+
+func (c *completion) complete(a Animation, notify func(*GameMessage)) {
+	for _, f := range c.callbacks {
+		f()
+	}
+	if notify == nil {
+		return
+	}
+	for _, msg := range c.messages {
+		notify(NewGameMessage(msg, a))
+	}
+}
+
+/////////////////////////////////////
+///		TWEEN
+/////////////////////////////////////
+
+//Tween : Moves a value from where it is to a target over time. The start
+//value is read when the tween starts, after its delay, so tweens can be
+//chained on the same value
+type Tween struct {
+	completion
+	duration float32
+	delay    float32
+	ease     EasingFunc
+	repeat   int
+	yoyo     bool
+	begin    func()
+	apply    func(eased float32)
+
+	elapsed   float32
+	waited    float32
+	repeated  int
+	backwards bool
+	started   bool
+	done      bool
+}
+
+//TweenFunc : Tween calling apply every update with the eased progress,
+//0 at the start and 1 at the end. Everything else tweens through it
+func TweenFunc(duration float32, apply func(progress float32)) *Tween {
+	return &Tween{duration: duration, ease: EaseLinear, apply: apply}
+}
+
+//TweenFloat32 : Tween moving *target to to
+func TweenFloat32(target *float32, to, duration float32) *Tween {
+	var from float32
+	tw := TweenFunc(duration, func(p float32) { *target = Lerp(from, to, p) })
+	tw.begin = func() { from = *target }
+	return tw
+}
+
+//TweenVector2f : Tween moving *target to to in a straight line
+func TweenVector2f(target *Vector2f, to Vector2f, duration float32) *Tween {
+	var from Vector2f
+	tw := TweenFunc(duration, func(p float32) { *target = from.Lerp(to, p) })
+	tw.begin = func() { from = *target }
+	return tw
+}
+
+//Wait : Animation doing nothing for duration seconds, to space out the
+//steps of a Sequence
+func Wait(duration float32) *Tween {
+	return &Tween{duration: duration, ease: EaseLinear}
+}
+
+//SetEase : Shapes the progress with ease. Linear by default
+func (tw *Tween) SetEase(ease EasingFunc) *Tween {
+	tw.ease = ease
+	return tw
+}
+
+//SetDelay : Waits delay seconds before starting. The delay isn't repeated
+func (tw *Tween) SetDelay(delay float32) *Tween {
+	tw.delay = delay
+	return tw
+}
+
+//SetRepeat : Plays times more times after the first. -1 repeats forever
+func (tw *Tween) SetRepeat(times int) *Tween {
+	tw.repeat = times
+	return tw
+}
+
+//SetYoyo : Every repetition plays backwards from the previous one, going
+//back and forth instead of jumping back to the start
+func (tw *Tween) SetYoyo(yoyo bool) *Tween {
+	tw.yoyo = yoyo
+	return tw
+}
+
+//OnComplete : Calls f when the tween is done, repetitions included
+func (tw *Tween) OnComplete(f func()) *Tween {
+	tw.callbacks = append(tw.callbacks, f)
+	return tw
+}
+
+//NotifyOnComplete : Sends msg to the game observers when the tween is done.
+//The payload is the tween
+func (tw *Tween) NotifyOnComplete(msg GMessage) *Tween {
+	tw.messages = append(tw.messages, msg)
+	return tw
+}
+
+//IsDone : Checks whether the tween finished
+func (tw *Tween) IsDone() bool {
+	return tw.done
+}
+
+//Reset : Rewinds to before the delay
+func (tw *Tween) Reset() {
+	tw.elapsed, tw.waited, tw.repeated = 0, 0, 0
+	tw.backwards, tw.started, tw.done = false, false, false
+}
+
+//Update : Advances by dt seconds
+func (tw *Tween) Update(dt float32, notify func(*GameMessage)) (float32, bool) {
+	if tw.done {
+		return dt, true
+	}
+	if !tw.started {
+		wait := tw.delay - tw.waited
+		if dt < wait {
+			tw.waited += dt
+			return 0, false
+		}
+		if wait > 0 {
+			dt -= wait
+			tw.waited = tw.delay
+		}
+		tw.started = true
+		if tw.begin != nil {
+			tw.begin()
+		}
+	}
+	tw.elapsed += dt
+	for tw.elapsed >= tw.duration {
+		if tw.repeat >= 0 && tw.repeated >= tw.repeat {
+			rest := tw.elapsed - tw.duration
+			tw.elapsed = tw.duration
+			tw.show(1)
+			tw.done = true
+			tw.complete(tw, notify)
+			return rest, true
+		}
+		if tw.duration <= 0 {
+			//Repeating an instant tween forever, once per update is plenty
+			tw.show(1)
+			tw.elapsed = 0
+			return 0, false
+		}
+		tw.elapsed -= tw.duration
+		tw.repeated++
+		if tw.yoyo {
+			tw.backwards = !tw.backwards
+		}
+	}
+	tw.show(tw.elapsed / tw.duration)
+	return 0, false
+}
+
+//show : Applies linear progress p of the current repetition
+func (tw *Tween) show(p float32) {
+	if tw.apply == nil {
+		return
+	}
+	if tw.backwards {
+		p = 1 - p
+	}
+	tw.apply(tw.ease(p))
+}
+
+/////////////////////////////////////
+///		GROUPS
+/////////////////////////////////////
+
+//Sequence : Plays animations one after the other
+type Sequence struct {
+	completion
+	animations []Animation
+	current    int
+	repeat     int
+	repeated   int
+	done       bool
+}
+
+//NewSequence : Sequence playing animations in order
+func NewSequence(animations ...Animation) *Sequence {
+	return &Sequence{animations: animations}
+}
+
+//Append : Adds animations at the end
+func (s *Sequence) Append(animations ...Animation) *Sequence {
+	s.animations = append(s.animations, animations...)
+	return s
+}
+
+//Call : Adds a call to f at the end
+func (s *Sequence) Call(f func()) *Sequence {
+	return s.Append(TweenFunc(0, nil).OnComplete(f))
+}
+
+//SetRepeat : Plays the whole sequence times more times. -1 repeats forever
+func (s *Sequence) SetRepeat(times int) *Sequence {
+	s.repeat = times
+	return s
+}
+
+//OnComplete : Calls f when the sequence is done
+func (s *Sequence) OnComplete(f func()) *Sequence {
+	s.callbacks = append(s.callbacks, f)
+	return s
+}
+
+//NotifyOnComplete : Sends msg to the game observers when the sequence is
+//done. The payload is the sequence
+func (s *Sequence) NotifyOnComplete(msg GMessage) *Sequence {
+	s.messages = append(s.messages, msg)
+	return s
+}
+
+//Reset : Rewinds every animation
+func (s *Sequence) Reset() {
+	for _, a := range s.animations {
+		a.Reset()
+	}
+	s.current, s.repeated, s.done = 0, 0, false
+}
+
+//Update : Advances by dt seconds, moving on to the next animation with
+//whatever time the previous one didn't use
+func (s *Sequence) Update(dt float32, notify func(*GameMessage)) (float32, bool) {
+	if s.done {
+		return dt, true
+	}
+	for {
+		passStart, fromStart := dt, s.current == 0
+		for s.current < len(s.animations) {
+			rest, done := s.animations[s.current].Update(dt, notify)
+			if !done {
+				return 0, false
+			}
+			dt = rest
+			s.current++
+		}
+		if s.repeat >= 0 && s.repeated >= s.repeat {
+			break
+		}
+		s.repeated++
+		s.current = 0
+		for _, a := range s.animations {
+			a.Reset()
+		}
+		if fromStart && dt == passStart {
+			//Nothing in the sequence takes time, don't loop forever
+			return 0, false
+		}
+	}
+	s.done = true
+	s.complete(s, notify)
+	return dt, true
+}
+
+//Parallel : Plays animations together. Done once they all are
+type Parallel struct {
+	completion
+	animations []Animation
+	finished   []bool
+	done       bool
+}
+
+//NewParallel : Group playing animations at the same time
+func NewParallel(animations ...Animation) *Parallel {
+	return &Parallel{animations: animations, finished: make([]bool, len(animations))}
+}
+
+//Add : Adds animations to the group
+func (p *Parallel) Add(animations ...Animation) *Parallel {
+	p.animations = append(p.animations, animations...)
+	p.finished = append(p.finished, make([]bool, len(animations))...)
+	return p
+}
+
+//OnComplete : Calls f when every animation is done
+func (p *Parallel) OnComplete(f func()) *Parallel {
+	p.callbacks = append(p.callbacks, f)
+	return p
+}
+
+//NotifyOnComplete : Sends msg to the game observers when every animation is
+//done. The payload is the group
+func (p *Parallel) NotifyOnComplete(msg GMessage) *Parallel {
+	p.messages = append(p.messages, msg)
+	return p
+}
+
+//Reset : Rewinds every animation
+func (p *Parallel) Reset() {
+	for i, a := range p.animations {
+		a.Reset()
+		p.finished[i] = false
+	}
+	p.done = false
+}
+
+//Update : Advances every animation still playing by dt seconds
+func (p *Parallel) Update(dt float32, notify func(*GameMessage)) (float32, bool) {
+	if p.done {
+		return dt, true
+	}
+	rest := dt
+	for i, a := range p.animations {
+		if p.finished[i] {
+			continue
+		}
+		r, done := a.Update(dt, notify)
+		if !done {
+			rest = 0
+			continue
+		}
+		p.finished[i] = true
+		//The last one to finish decides what is left
+		rest = minFloat(rest, r)
+	}
+	for _, f := range p.finished {
+		if !f {
+			return 0, false
+		}
+	}
+	p.done = true
+	p.complete(p, notify)
+	return rest, true
+}
+
+/////////////////////////////////////
+///		MANAGER
+/////////////////////////////////////
+
+//TweenManager : Plays animations, advanced by the game tick. Every Game has
+//one, see Game.Tweens
+type TweenManager struct {
+	mutex   sync.Mutex
+	entries []*tweenEntry
+	notify  func(*GameMessage)
+}
+
+//tweenEntry : An animation being played. Stopping it sets stopped, so an
+//Update already going through the entries skips it
+type tweenEntry struct {
+	animation Animation
+	stopped   bool
+}
+
+//NewTweenManager : Manager sending completion messages to notify, which may
+//be nil
+func NewTweenManager(notify func(*GameMessage)) *TweenManager {
+	return &TweenManager{notify: notify}
+}
+
+//Start : Plays a from the next update
+func (m *TweenManager) Start(a Animation) Animation {
+	m.mutex.Lock()
+	m.entries = append(m.entries, &tweenEntry{animation: a})
+	m.mutex.Unlock()
+	return a
+}
+
+//Stop : Stops playing a, leaving values where they are. False if a wasn't
+//playing. Animations are told apart by pointer, so stopping a value that
+//isn't a pointer stops the first one equal to it
+func (m *TweenManager) Stop(a Animation) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for i, e := range m.entries {
+		if !e.stopped && sameAnimation(e.animation, a) {
+			e.stopped = true
+			m.entries = append(m.entries[:i:i], m.entries[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+//StopAll : Stops every animation
+func (m *TweenManager) StopAll() {
+	m.mutex.Lock()
+	for _, e := range m.entries {
+		e.stopped = true
+	}
+	m.entries = nil
+	m.mutex.Unlock()
+}
+
+//IsPlaying : Checks whether a is being played
+func (m *TweenManager) IsPlaying(a Animation) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for _, e := range m.entries {
+		if !e.stopped && sameAnimation(e.animation, a) {
+			return true
+		}
+	}
+	return false
+}
+
+//Len : Number of animations being played
+func (m *TweenManager) Len() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	n := 0
+	for _, e := range m.entries {
+		if !e.stopped {
+			n++
+		}
+	}
+	return n
+}
+
+//Update : Advances every animation by dt seconds and drops the finished
+//ones. Callbacks may start and stop animations
+func (m *TweenManager) Update(dt float32) {
+	m.mutex.Lock()
+	entries := m.entries
+	m.mutex.Unlock()
+	finished := false
+	for _, e := range entries {
+		//A callback may have stopped it this update
+		m.mutex.Lock()
+		stopped := e.stopped
+		m.mutex.Unlock()
+		if stopped {
+			continue
+		}
+		if _, done := e.animation.Update(dt, m.notify); done {
+			m.mutex.Lock()
+			e.stopped = true
+			m.mutex.Unlock()
+			finished = true
+		}
+	}
+	if !finished {
+		return
+	}
+	m.mutex.Lock()
+	playing := m.entries[:0:0]
+	for _, e := range m.entries {
+		if !e.stopped {
+			playing = append(playing, e)
+		}
+	}
+	m.entries = playing
+	m.mutex.Unlock()
+}
+
+//sameAnimation : Whether a and b are the same animation. Pointers are
+//compared by address, other values with == if their type allows it, so
+//animations that can't be compared never panic
+func sameAnimation(a, b Animation) (same bool) {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.Type() != vb.Type() {
+		return false
+	}
+	if va.Kind() == reflect.Ptr {
+		return va.Pointer() == vb.Pointer()
+	}
+	if !va.Type().Comparable() {
+		return false
+	}
+	//Comparable structs may still hold uncomparable values in interfaces
+	defer func() {
+		if recover() != nil {
+			same = false
+		}
+	}()
+	return a == b
+}
@@ -0,0 +1,262 @@
+package goldcore
+
+import "testing"
+
+func TestEasingEndpoints(t *testing.T) {
+	curves := map[string]EasingFunc{
+		"Linear": EaseLinear, "InQuad": EaseInQuad, "OutQuad": EaseOutQuad, "InOutQuad": EaseInOutQuad,
+		"InCubic": EaseInCubic, "OutCubic": EaseOutCubic, "InOutCubic": EaseInOutCubic,
+		"InSine": EaseInSine, "OutSine": EaseOutSine, "InOutSine": EaseInOutSine,
+		"InBack": EaseInBack, "OutBack": EaseOutBack, "InOutBack": EaseInOutBack,
+		"InElastic": EaseInElastic, "OutElastic": EaseOutElastic, "InOutElastic": EaseInOutElastic,
+		"InBounce": EaseInBounce, "OutBounce": EaseOutBounce, "InOutBounce": EaseInOutBounce,
+	}
+	for name, ease := range curves {
+		if got := ease(0); !ApproxEqual(got, 0, 1e-4) {
+			t.Errorf("%s(0): expected 0, got %v", name, got)
+		}
+		if got := ease(1); !ApproxEqual(got, 1, 1e-4) {
+			t.Errorf("%s(1): expected 1, got %v", name, got)
+		}
+	}
+	if EaseInBack(0.2) >= 0 {
+		t.Error("InBack should pull back below 0")
+	}
+	if EaseOutBack(0.8) <= 1 {
+		t.Error("OutBack should overshoot above 1")
+	}
+	if got := EaseInOutQuad(0.5); got != 0.5 {
+		t.Errorf("InOutQuad(0.5): expected 0.5, got %v", got)
+	}
+	if EaseInQuad(0.25) >= 0.25 || EaseOutQuad(0.25) <= 0.25 {
+		t.Error("in curves start slow, out curves start fast")
+	}
+}
+
+func TestTweenFloat32(t *testing.T) {
+	x := float32(10)
+	tw := TweenFloat32(&x, 20, 1)
+	if _, done := tw.Update(0.25, nil); done || x != 12.5 {
+		t.Errorf("after 0.25s: expected 12.5, got %v", x)
+	}
+	rest, done := tw.Update(1, nil)
+	if !done || x != 20 || rest != 0.25 {
+		t.Errorf("past the end: expected 20 with 0.25s left, got %v %v %v", x, rest, done)
+	}
+
+	//Start value is read when the tween starts, after the delay
+	x = 0
+	tw = TweenFloat32(&x, 1, 1).SetDelay(0.5).SetEase(EaseInQuad)
+	tw.Update(0.25, nil)
+	x = 5
+	tw.Update(0.25, nil)
+	tw.Update(0.5, nil)
+	if x != Lerp(5, 1, 0.25) {
+		t.Errorf("delayed tween: expected %v, got %v", Lerp(5, 1, 0.25), x)
+	}
+}
+
+func TestTweenRepeatYoyo(t *testing.T) {
+	v := Vector2f{}
+	completed := 0
+	tw := TweenVector2f(&v, Vector2f{X: 10, Y: 20}, 1).SetRepeat(2).SetYoyo(true).OnComplete(func() { completed++ })
+	tw.Update(1.5, nil)
+	if v != (Vector2f{X: 5, Y: 10}) {
+		t.Errorf("halfway back: got %v", v)
+	}
+	tw.Update(0.75, nil)
+	if v != (Vector2f{X: 2.5, Y: 5}) {
+		t.Errorf("third run, going forward again: got %v", v)
+	}
+	rest, done := tw.Update(1, nil)
+	if !done || v != (Vector2f{X: 10, Y: 20}) || !ApproxEqual(rest, 0.25, Epsilon) || completed != 1 {
+		t.Errorf("done after 3 runs: got %v %v %v, %d callbacks", v, rest, done, completed)
+	}
+
+	forever := TweenFunc(1, nil).SetRepeat(-1)
+	for i := 0; i < 100; i++ {
+		if _, done := forever.Update(0.7, nil); done {
+			t.Fatal("tween repeating forever finished")
+		}
+	}
+}
+
+func TestSequenceAndParallel(t *testing.T) {
+	var x, y float32
+	var calls []string
+	seq := NewSequence(
+		TweenFloat32(&x, 10, 1),
+		Wait(0.5),
+		NewParallel(TweenFloat32(&x, 0, 1), TweenFloat32(&y, 4, 2)).OnComplete(func() { calls = append(calls, "parallel") }),
+	).Call(func() { calls = append(calls, "call") }).OnComplete(func() { calls = append(calls, "sequence") })
+
+	seq.Update(1.25, nil)
+	if x != 10 {
+		t.Errorf("first tween done, waiting: expected 10, got %v", x)
+	}
+	//Leftover time of each step carries into the next
+	seq.Update(0.75, nil)
+	if x != 5 || y != 1 {
+		t.Errorf("0.5s into the parallel group: got %v %v", x, y)
+	}
+	seq.Update(1, nil)
+	if x != 0 || y != 3 || len(calls) != 0 {
+		t.Errorf("x done, y still going: got %v %v %v", x, y, calls)
+	}
+	rest, done := seq.Update(1, nil)
+	if !done || y != 4 || rest != 0.5 {
+		t.Errorf("sequence done: got %v %v %v", y, rest, done)
+	}
+	if len(calls) != 3 || calls[0] != "parallel" || calls[1] != "call" || calls[2] != "sequence" {
+		t.Errorf("callbacks out of order: %v", calls)
+	}
+
+	//Repeating a sequence replays it from the current values
+	x = 0
+	bounce := NewSequence(TweenFloat32(&x, 1, 1), TweenFloat32(&x, 0, 1)).SetRepeat(1)
+	bounce.Update(2.5, nil)
+	if x != 0.5 {
+		t.Errorf("second run of the sequence: expected 0.5, got %v", x)
+	}
+	if _, done := bounce.Update(1.5, nil); !done || x != 0 {
+		t.Errorf("repeated sequence done: got %v %v", x, done)
+	}
+
+	//A sequence of instant steps repeating forever doesn't hang
+	count := 0
+	instant := NewSequence().Call(func() { count++ }).SetRepeat(-1)
+	instant.Update(1, nil)
+	instant.Update(1, nil)
+	if count == 0 {
+		t.Error("instant sequence never ran")
+	}
+}
+
+var tweenTestDone = RegisterGameMessage("tween test done")
+
+type gameMessageRecorder struct {
+	messages []*GameMessage
+}
+
+func (r *gameMessageRecorder) OnGameNotify(gM *GameMessage) {
+	r.messages = append(r.messages, gM)
+}
+
+func TestGameTweens(t *testing.T) {
+	game := NewGame()
+	recorder := &gameMessageRecorder{}
+	game.AddObserver(recorder)
+
+	x := float32(0)
+	tw := TweenFloat32(&x, 8, 1).NotifyOnComplete(tweenTestDone)
+	game.Tweens().Start(tw)
+
+	game.Update(0.5)
+	if x != 0 {
+		t.Errorf("stopped game shouldn't advance tweens, got %v", x)
+	}
+	game.Play()
+	game.Update(0.5)
+	game.Suspend()
+	game.Update(10)
+	if x != 4 {
+		t.Errorf("suspended game should freeze tweens at 4, got %v", x)
+	}
+	game.Play()
+	game.Update(0.5)
+	if x != 8 || game.Tweens().IsPlaying(tw) || game.Tweens().Len() != 0 {
+		t.Errorf("tween should be done and dropped: x=%v playing=%v", x, game.Tweens().IsPlaying(tw))
+	}
+	if len(recorder.messages) != 1 || recorder.messages[0].Message != tweenTestDone || recorder.messages[0].Payload != tw {
+		t.Errorf("expected one completion message with the tween, got %v", recorder.messages)
+	}
+
+	//Stopping from a callback takes effect right away
+	var a, b float32
+	tb := TweenFloat32(&b, 1, 1)
+	ta := TweenFloat32(&a, 1, 0.1).OnComplete(func() { game.Tweens().Stop(tb) })
+	game.Tweens().Start(ta)
+	game.Tweens().Start(tb)
+	game.Update(0.5)
+	if b != 0 {
+		t.Errorf("tween stopped by a callback was still advanced: %v", b)
+	}
+}
+
+//frames : Animation that can't be compared with ==, playing one frame of
+//steps per update
+type frames struct {
+	steps  []float32
+	played *int
+}
+
+func (f frames) Update(dt float32, notify func(*GameMessage)) (float32, bool) {
+	*f.played++
+	return dt, *f.played >= len(f.steps)
+}
+
+func (f frames) Reset() {
+	*f.played = 0
+}
+
+func TestTweenManagerUncomparable(t *testing.T) {
+	m := NewTweenManager(nil)
+	played, other := 0, 0
+	a := frames{steps: make([]float32, 2), played: &played}
+	m.Start(a)
+	m.Start(frames{steps: make([]float32, 5), played: &other})
+	if m.IsPlaying(a) || m.Stop(a) {
+		t.Error("Values that can't be compared are never found")
+	}
+	m.Update(1)
+	m.Update(1)
+	if played != 2 || m.Len() != 1 {
+		t.Errorf("Expected 2 frames played and 1 animation left got %d and %d", played, m.Len())
+	}
+
+	//Pointers are found by address
+	x := float32(0)
+	tw := TweenFloat32(&x, 1, 1)
+	m.Start(tw)
+	if !m.IsPlaying(tw) || m.IsPlaying(TweenFloat32(&x, 1, 1)) || !m.Stop(tw) || m.IsPlaying(tw) {
+		t.Error("Tweens are told apart by pointer")
+	}
+	m.StopAll()
+	m.Update(1)
+	if other != 2 || m.Len() != 0 {
+		t.Errorf("StopAll should stop everything, played %d with %d left", other, m.Len())
+	}
+}
+
+func TestGameTweensReplay(t *testing.T) {
+	run := func(game *Game) []int {
+		game.Play()
+		var draws []int
+		draw := func() { draws = append(draws, game.Random("tween").Intn(1000)) }
+		var short, long float32
+		game.Tweens().Start(TweenFloat32(&short, 1, 0.5).OnComplete(draw))
+		//Still running when the game stops
+		game.Tweens().Start(TweenFloat32(&long, 1, 2).OnComplete(draw))
+		for i := 0; i < 4; i++ {
+			game.Update(0.25)
+			draw()
+		}
+		game.Stop()
+		return draws
+	}
+	game := NewGame()
+	game.SetSeed(99)
+	first := run(game)
+	if game.Tweens().Len() != 0 {
+		t.Errorf("Stop should drop every tween, %d left", game.Tweens().Len())
+	}
+	second := run(game)
+	if len(first) != len(second) {
+		t.Fatalf("replay differs: %v vs %v", first, second)
+	}
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("replay differs at draw %d: %v vs %v", i, first, second)
+		}
+	}
+}
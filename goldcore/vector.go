@@ -1,6 +1,8 @@
 package goldcore
 
 import (
+	"math"
+
 	sf "github.com/manyminds/gosfml"
 )
 
@@ -28,10 +30,99 @@ type Vector3f struct {
 	X, Y, Z float32
 }
 
+/////////////////////////////////////
+///		CONSTS
+/////////////////////////////////////
+
+//Epsilon : Default tolerance for ApproxEquals
+const Epsilon float32 = 1e-5
+
 /////////////////////////////////////
 ///		FUNCS
 /////////////////////////////////////
 
+//DegreesToRadians : Converts an angle. Vector functions take radians, SFML
+//and Camera2D take degrees
+func DegreesToRadians(degrees float32) float32 {
+	return degrees * math.Pi / 180
+}
+
+//RadiansToDegrees : Converts an angle
+func RadiansToDegrees(radians float32) float32 {
+	return radians * 180 / math.Pi
+}
+
+//ApproxEqual : True if a and b are within epsilon of each other
+func ApproxEqual(a, b, epsilon float32) bool {
+	return float32(math.Abs(float64(a-b))) <= epsilon
+}
+
+//Lerp : a when t is 0, b when t is 1
+func Lerp(a, b, t float32) float32 {
+	return a + (b-a)*t
+}
+
+//Clamp : x kept between min and max
+func Clamp(x, min, max float32) float32 {
+	if x < min {
+		return min
+	}
+	if x > max {
+		return max
+	}
+	return x
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minUint(a, b uint) uint {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxUint(a, b uint) uint {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minFloat(a, b float32) float32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxFloat(a, b float32) float32 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func absFloat(a float32) float32 {
+	return float32(math.Abs(float64(a)))
+}
+
+func sqrtFloat(a float32) float32 {
+	return float32(math.Sqrt(float64(a)))
+}
+
 /////////////////////////////////////
 // Vector2i
 
@@ -50,6 +141,84 @@ func (vec Vector2i) Equals(other Vector2i) bool {
 	return vec.X == other.X && vec.Y == other.Y
 }
 
+//Scale : Returns the vector multiplied by s
+func (vec Vector2i) Scale(s int) Vector2i {
+	return Vector2i{X: vec.X * s, Y: vec.Y * s}
+}
+
+//Mul : Returns the component wise product of two vectors
+func (vec Vector2i) Mul(other Vector2i) Vector2i {
+	return Vector2i{X: vec.X * other.X, Y: vec.Y * other.Y}
+}
+
+//Neg : Returns the vector pointing the other way
+func (vec Vector2i) Neg() Vector2i {
+	return Vector2i{X: -vec.X, Y: -vec.Y}
+}
+
+//Dot : Dot product
+func (vec Vector2i) Dot(other Vector2i) int {
+	return vec.X*other.X + vec.Y*other.Y
+}
+
+//Cross : Z component of the 3D cross product
+func (vec Vector2i) Cross(other Vector2i) int {
+	return vec.X*other.Y - vec.Y*other.X
+}
+
+//LengthSquared : Squared length, exact
+func (vec Vector2i) LengthSquared() int {
+	return vec.Dot(vec)
+}
+
+//Length : Euclidean length
+func (vec Vector2i) Length() float32 {
+	return float32(math.Hypot(float64(vec.X), float64(vec.Y)))
+}
+
+//ManhattanDistance : Number of horizontal and vertical steps to other
+func (vec Vector2i) ManhattanDistance(other Vector2i) int {
+	d := vec.Minus(other).Abs()
+	return d.X + d.Y
+}
+
+//Abs : Component wise absolute value
+func (vec Vector2i) Abs() Vector2i {
+	if vec.X < 0 {
+		vec.X = -vec.X
+	}
+	if vec.Y < 0 {
+		vec.Y = -vec.Y
+	}
+	return vec
+}
+
+//Min : Component wise minimum
+func (vec Vector2i) Min(other Vector2i) Vector2i {
+	return Vector2i{X: minInt(vec.X, other.X), Y: minInt(vec.Y, other.Y)}
+}
+
+//Max : Component wise maximum
+func (vec Vector2i) Max(other Vector2i) Vector2i {
+	return Vector2i{X: maxInt(vec.X, other.X), Y: maxInt(vec.Y, other.Y)}
+}
+
+//Clamp : Each component kept between the ones of min and max
+func (vec Vector2i) Clamp(min, max Vector2i) Vector2i {
+	return vec.Max(min).Min(max)
+}
+
+//ToVector2f : Converts to float
+func (vec Vector2i) ToVector2f() Vector2f {
+	return Vector2f{X: float32(vec.X), Y: float32(vec.Y)}
+}
+
+//ToVector2u : Converts to unsigned. Negative components become 0
+func (vec Vector2i) ToVector2u() Vector2u {
+	vec = vec.Max(Vector2i{})
+	return Vector2u{X: uint(vec.X), Y: uint(vec.Y)}
+}
+
 //SFVector2uToGEVector2i : SFML Vector2u to GoldEngine Vector2U
 //TODO Refactor name of these kinds of function to be SF_ToGE_
 func SFVector2uToGEVector2i(other sf.Vector2i) Vector2i {
@@ -74,6 +243,51 @@ func (vec Vector2u) Equals(other Vector2u) bool {
 	return vec.X == other.X && vec.Y == other.Y
 }
 
+//Scale : Returns the vector multiplied by s
+func (vec Vector2u) Scale(s uint) Vector2u {
+	return Vector2u{X: vec.X * s, Y: vec.Y * s}
+}
+
+//Mul : Returns the component wise product of two vectors
+func (vec Vector2u) Mul(other Vector2u) Vector2u {
+	return Vector2u{X: vec.X * other.X, Y: vec.Y * other.Y}
+}
+
+//Dot : Dot product
+func (vec Vector2u) Dot(other Vector2u) uint {
+	return vec.X*other.X + vec.Y*other.Y
+}
+
+//Length : Euclidean length
+func (vec Vector2u) Length() float32 {
+	return float32(math.Hypot(float64(vec.X), float64(vec.Y)))
+}
+
+//Min : Component wise minimum
+func (vec Vector2u) Min(other Vector2u) Vector2u {
+	return Vector2u{X: minUint(vec.X, other.X), Y: minUint(vec.Y, other.Y)}
+}
+
+//Max : Component wise maximum
+func (vec Vector2u) Max(other Vector2u) Vector2u {
+	return Vector2u{X: maxUint(vec.X, other.X), Y: maxUint(vec.Y, other.Y)}
+}
+
+//Clamp : Each component kept between the ones of min and max
+func (vec Vector2u) Clamp(min, max Vector2u) Vector2u {
+	return vec.Max(min).Min(max)
+}
+
+//ToVector2i : Converts to signed
+func (vec Vector2u) ToVector2i() Vector2i {
+	return Vector2i{X: int(vec.X), Y: int(vec.Y)}
+}
+
+//ToVector2f : Converts to float
+func (vec Vector2u) ToVector2f() Vector2f {
+	return Vector2f{X: float32(vec.X), Y: float32(vec.Y)}
+}
+
 //SFVector2uToGEVector2u : SFML Vector2u to GoldEngine Vector2U
 //TODO Refactor name of these kinds of function to be SF_ToGE_
 func SFVector2uToGEVector2u(other sf.Vector2u) Vector2u {
@@ -98,6 +312,312 @@ func (vec Vector2f) Equals(other Vector2f) bool {
 	return vec.X == other.X && vec.Y == other.Y
 }
 
+//ApproxEquals : True if x and y are within epsilon of the other's
+func (vec Vector2f) ApproxEquals(other Vector2f, epsilon float32) bool {
+	return ApproxEqual(vec.X, other.X, epsilon) && ApproxEqual(vec.Y, other.Y, epsilon)
+}
+
+//Scale : Returns the vector multiplied by s
+func (vec Vector2f) Scale(s float32) Vector2f {
+	return Vector2f{X: vec.X * s, Y: vec.Y * s}
+}
+
+//Div : Returns the vector divided by s
+func (vec Vector2f) Div(s float32) Vector2f {
+	return Vector2f{X: vec.X / s, Y: vec.Y / s}
+}
+
+//Mul : Returns the component wise product of two vectors
+func (vec Vector2f) Mul(other Vector2f) Vector2f {
+	return Vector2f{X: vec.X * other.X, Y: vec.Y * other.Y}
+}
+
+//Neg : Returns the vector pointing the other way
+func (vec Vector2f) Neg() Vector2f {
+	return Vector2f{X: -vec.X, Y: -vec.Y}
+}
+
+//Dot : Dot product
+func (vec Vector2f) Dot(other Vector2f) float32 {
+	return vec.X*other.X + vec.Y*other.Y
+}
+
+//Cross : Z component of the 3D cross product. Positive when other is
+//clockwise from vec on screen (y pointing down)
+func (vec Vector2f) Cross(other Vector2f) float32 {
+	return vec.X*other.Y - vec.Y*other.X
+}
+
+//LengthSquared : Squared length. Cheaper than Length for comparisons
+func (vec Vector2f) LengthSquared() float32 {
+	return vec.Dot(vec)
+}
+
+//Length : Euclidean length
+func (vec Vector2f) Length() float32 {
+	return sqrtFloat(vec.LengthSquared())
+}
+
+//Normalize : Vector with the same direction and a length of 1. The zero
+//vector stays zero
+func (vec Vector2f) Normalize() Vector2f {
+	length := vec.Length()
+	if length == 0 {
+		return vec
+	}
+	return vec.Div(length)
+}
+
+//Distance : Distance between two points
+func (vec Vector2f) Distance(other Vector2f) float32 {
+	return vec.Minus(other).Length()
+}
+
+//DistanceSquared : Squared distance between two points
+func (vec Vector2f) DistanceSquared(other Vector2f) float32 {
+	return vec.Minus(other).LengthSquared()
+}
+
+//Lerp : vec when t is 0, other when t is 1
+func (vec Vector2f) Lerp(other Vector2f, t float32) Vector2f {
+	return Vector2f{X: Lerp(vec.X, other.X, t), Y: Lerp(vec.Y, other.Y, t)}
+}
+
+//Abs : Component wise absolute value
+func (vec Vector2f) Abs() Vector2f {
+	return Vector2f{X: absFloat(vec.X), Y: absFloat(vec.Y)}
+}
+
+//Min : Component wise minimum
+func (vec Vector2f) Min(other Vector2f) Vector2f {
+	return Vector2f{X: minFloat(vec.X, other.X), Y: minFloat(vec.Y, other.Y)}
+}
+
+//Max : Component wise maximum
+func (vec Vector2f) Max(other Vector2f) Vector2f {
+	return Vector2f{X: maxFloat(vec.X, other.X), Y: maxFloat(vec.Y, other.Y)}
+}
+
+//Clamp : Each component kept between the ones of min and max
+func (vec Vector2f) Clamp(min, max Vector2f) Vector2f {
+	return Vector2f{X: Clamp(vec.X, min.X, max.X), Y: Clamp(vec.Y, min.Y, max.Y)}
+}
+
+//ClampLength : Vector shortened to max if it is longer
+func (vec Vector2f) ClampLength(max float32) Vector2f {
+	if lengthSquared := vec.LengthSquared(); lengthSquared > max*max {
+		return vec.Scale(max / sqrtFloat(lengthSquared))
+	}
+	return vec
+}
+
+//Rotate : Vector turned by radians. Positive angles turn clockwise on
+//screen (y pointing down)
+func (vec Vector2f) Rotate(radians float32) Vector2f {
+	sin, cos := math.Sincos(float64(radians))
+	s, c := float32(sin), float32(cos)
+	return Vector2f{X: vec.X*c - vec.Y*s, Y: vec.X*s + vec.Y*c}
+}
+
+//Angle : Angle from the x axis in radians, between -Pi and Pi
+func (vec Vector2f) Angle() float32 {
+	return float32(math.Atan2(float64(vec.Y), float64(vec.X)))
+}
+
+//AngleTo : Signed angle, in radians, to turn vec onto other's direction
+func (vec Vector2f) AngleTo(other Vector2f) float32 {
+	return float32(math.Atan2(float64(vec.Cross(other)), float64(vec.Dot(other))))
+}
+
+//Perpendicular : vec turned a quarter clockwise on screen
+func (vec Vector2f) Perpendicular() Vector2f {
+	return Vector2f{X: -vec.Y, Y: vec.X}
+}
+
+//Reflect : vec bounced off a surface with the given unit normal
+func (vec Vector2f) Reflect(normal Vector2f) Vector2f {
+	return vec.Minus(normal.Scale(2 * vec.Dot(normal)))
+}
+
+//Project : Part of vec going along onto. Zero if onto is zero
+func (vec Vector2f) Project(onto Vector2f) Vector2f {
+	lengthSquared := onto.LengthSquared()
+	if lengthSquared == 0 {
+		return Vector2f{}
+	}
+	return onto.Scale(vec.Dot(onto) / lengthSquared)
+}
+
+//Floor : Rounds both components down
+func (vec Vector2f) Floor() Vector2i {
+	return Vector2i{X: int(math.Floor(float64(vec.X))), Y: int(math.Floor(float64(vec.Y)))}
+}
+
+//Round : Rounds both components to the nearest integer
+func (vec Vector2f) Round() Vector2i {
+	return Vector2i{X: int(math.Floor(float64(vec.X) + 0.5)), Y: int(math.Floor(float64(vec.Y) + 0.5))}
+}
+
+//ToVector2i : Converts to int, truncating towards zero
+func (vec Vector2f) ToVector2i() Vector2i {
+	return Vector2i{X: int(vec.X), Y: int(vec.Y)}
+}
+
+//ToVector2u : Converts to unsigned, truncating. Negative components become 0
+func (vec Vector2f) ToVector2u() Vector2u {
+	vec = vec.Max(Vector2f{})
+	return Vector2u{X: uint(vec.X), Y: uint(vec.Y)}
+}
+
+//ToVector3f : Adds a z component
+func (vec Vector2f) ToVector3f(z float32) Vector3f {
+	return Vector3f{X: vec.X, Y: vec.Y, Z: z}
+}
+
+/////////////////////////////////////
+// Vector3f
+
+//Plus : Returns the sum of two vectors.
+func (vec Vector3f) Plus(other Vector3f) Vector3f {
+	return Vector3f{X: vec.X + other.X, Y: vec.Y + other.Y, Z: vec.Z + other.Z}
+}
+
+//Minus : Returns the difference of two vectors.
+func (vec Vector3f) Minus(other Vector3f) Vector3f {
+	return Vector3f{X: vec.X - other.X, Y: vec.Y - other.Y, Z: vec.Z - other.Z}
+}
+
+//Equals : True if x, y and z of vector are equal
+func (vec Vector3f) Equals(other Vector3f) bool {
+	return vec.X == other.X && vec.Y == other.Y && vec.Z == other.Z
+}
+
+//ApproxEquals : True if x, y and z are within epsilon of the other's
+func (vec Vector3f) ApproxEquals(other Vector3f, epsilon float32) bool {
+	return ApproxEqual(vec.X, other.X, epsilon) && ApproxEqual(vec.Y, other.Y, epsilon) && ApproxEqual(vec.Z, other.Z, epsilon)
+}
+
+//Scale : Returns the vector multiplied by s
+func (vec Vector3f) Scale(s float32) Vector3f {
+	return Vector3f{X: vec.X * s, Y: vec.Y * s, Z: vec.Z * s}
+}
+
+//Div : Returns the vector divided by s
+func (vec Vector3f) Div(s float32) Vector3f {
+	return Vector3f{X: vec.X / s, Y: vec.Y / s, Z: vec.Z / s}
+}
+
+//Mul : Returns the component wise product of two vectors
+func (vec Vector3f) Mul(other Vector3f) Vector3f {
+	return Vector3f{X: vec.X * other.X, Y: vec.Y * other.Y, Z: vec.Z * other.Z}
+}
+
+//Neg : Returns the vector pointing the other way
+func (vec Vector3f) Neg() Vector3f {
+	return Vector3f{X: -vec.X, Y: -vec.Y, Z: -vec.Z}
+}
+
+//Dot : Dot product
+func (vec Vector3f) Dot(other Vector3f) float32 {
+	return vec.X*other.X + vec.Y*other.Y + vec.Z*other.Z
+}
+
+//Cross : Cross product, perpendicular to both vectors (right handed)
+func (vec Vector3f) Cross(other Vector3f) Vector3f {
+	return Vector3f{
+		X: vec.Y*other.Z - vec.Z*other.Y,
+		Y: vec.Z*other.X - vec.X*other.Z,
+		Z: vec.X*other.Y - vec.Y*other.X,
+	}
+}
+
+//LengthSquared : Squared length. Cheaper than Length for comparisons
+func (vec Vector3f) LengthSquared() float32 {
+	return vec.Dot(vec)
+}
+
+//Length : Euclidean length
+func (vec Vector3f) Length() float32 {
+	return sqrtFloat(vec.LengthSquared())
+}
+
+//Normalize : Vector with the same direction and a length of 1. The zero
+//vector stays zero
+func (vec Vector3f) Normalize() Vector3f {
+	length := vec.Length()
+	if length == 0 {
+		return vec
+	}
+	return vec.Div(length)
+}
+
+//Distance : Distance between two points
+func (vec Vector3f) Distance(other Vector3f) float32 {
+	return vec.Minus(other).Length()
+}
+
+//DistanceSquared : Squared distance between two points
+func (vec Vector3f) DistanceSquared(other Vector3f) float32 {
+	return vec.Minus(other).LengthSquared()
+}
+
+//Lerp : vec when t is 0, other when t is 1
+func (vec Vector3f) Lerp(other Vector3f, t float32) Vector3f {
+	return Vector3f{X: Lerp(vec.X, other.X, t), Y: Lerp(vec.Y, other.Y, t), Z: Lerp(vec.Z, other.Z, t)}
+}
+
+//Abs : Component wise absolute value
+func (vec Vector3f) Abs() Vector3f {
+	return Vector3f{X: absFloat(vec.X), Y: absFloat(vec.Y), Z: absFloat(vec.Z)}
+}
+
+//Min : Component wise minimum
+func (vec Vector3f) Min(other Vector3f) Vector3f {
+	return Vector3f{X: minFloat(vec.X, other.X), Y: minFloat(vec.Y, other.Y), Z: minFloat(vec.Z, other.Z)}
+}
+
+//Max : Component wise maximum
+func (vec Vector3f) Max(other Vector3f) Vector3f {
+	return Vector3f{X: maxFloat(vec.X, other.X), Y: maxFloat(vec.Y, other.Y), Z: maxFloat(vec.Z, other.Z)}
+}
+
+//Clamp : Each component kept between the ones of min and max
+func (vec Vector3f) Clamp(min, max Vector3f) Vector3f {
+	return Vector3f{X: Clamp(vec.X, min.X, max.X), Y: Clamp(vec.Y, min.Y, max.Y), Z: Clamp(vec.Z, min.Z, max.Z)}
+}
+
+//ClampLength : Vector shortened to max if it is longer
+func (vec Vector3f) ClampLength(max float32) Vector3f {
+	if lengthSquared := vec.LengthSquared(); lengthSquared > max*max {
+		return vec.Scale(max / sqrtFloat(lengthSquared))
+	}
+	return vec
+}
+
+//AngleTo : Unsigned angle between the two vectors, in radians
+func (vec Vector3f) AngleTo(other Vector3f) float32 {
+	return float32(math.Atan2(float64(vec.Cross(other).Length()), float64(vec.Dot(other))))
+}
+
+//Reflect : vec bounced off a surface with the given unit normal
+func (vec Vector3f) Reflect(normal Vector3f) Vector3f {
+	return vec.Minus(normal.Scale(2 * vec.Dot(normal)))
+}
+
+//Project : Part of vec going along onto. Zero if onto is zero
+func (vec Vector3f) Project(onto Vector3f) Vector3f {
+	lengthSquared := onto.LengthSquared()
+	if lengthSquared == 0 {
+		return Vector3f{}
+	}
+	return onto.Scale(vec.Dot(onto) / lengthSquared)
+}
+
+//XY : Drops the z component
+func (vec Vector3f) XY() Vector2f {
+	return Vector2f{X: vec.X, Y: vec.Y}
+}
+
 //ToSFML : Allows for SFML compatability
 func (vec Vector2i) ToSFML() sf.Vector2i {
 	return sf.Vector2i{X: vec.X, Y: vec.Y}
@@ -115,5 +635,5 @@ func (vec Vector2f) ToSFML() sf.Vector2f {
 
 //ToSFML : Allows for SFML compatability
 func (vec Vector3f) ToSFML() sf.Vector3f {
-	return sf.Vector3f{X: vec.X, Y: vec.Y}
+	return sf.Vector3f{X: vec.X, Y: vec.Y, Z: vec.Z}
 }
@@ -0,0 +1,247 @@
+package goldcore
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVector2iMath(t *testing.T) {
+	a, b := Vector2i{3, -4}, Vector2i{2, 5}
+	cases := []struct {
+		name      string
+		got, want Vector2i
+	}{
+		{"Plus", a.Plus(b), Vector2i{5, 1}},
+		{"Minus", a.Minus(b), Vector2i{1, -9}},
+		{"Scale", a.Scale(2), Vector2i{6, -8}},
+		{"Mul", a.Mul(b), Vector2i{6, -20}},
+		{"Neg", a.Neg(), Vector2i{-3, 4}},
+		{"Abs", a.Abs(), Vector2i{3, 4}},
+		{"Min", a.Min(b), Vector2i{2, -4}},
+		{"Max", a.Max(b), Vector2i{3, 5}},
+		{"Clamp", Vector2i{-10, 10}.Clamp(Vector2i{0, 0}, Vector2i{5, 5}), Vector2i{0, 5}},
+		{"Round trip", a.ToVector2f().Round(), a},
+	}
+	for _, c := range cases {
+		if !c.got.Equals(c.want) {
+			t.Errorf("%s. Expected %v got %v", c.name, c.want, c.got)
+		}
+	}
+	if d := a.Dot(b); d != -14 {
+		t.Errorf("Dot. Expected -14 got %d", d)
+	}
+	if c := a.Cross(b); c != 23 {
+		t.Errorf("Cross. Expected 23 got %d", c)
+	}
+	if l := a.Length(); l != 5 || a.LengthSquared() != 25 {
+		t.Errorf("Length. Expected 5 got %f", l)
+	}
+	if d := a.ManhattanDistance(b); d != 10 {
+		t.Errorf("ManhattanDistance. Expected 10 got %d", d)
+	}
+	if u := a.ToVector2u(); !u.Equals(Vector2u{3, 0}) {
+		t.Errorf("ToVector2u should clamp negatives. Got %v", u)
+	}
+}
+
+func TestVector2uMath(t *testing.T) {
+	a, b := Vector2u{3, 4}, Vector2u{1, 6}
+	if got := a.Scale(3); !got.Equals(Vector2u{9, 12}) {
+		t.Errorf("Scale. Got %v", got)
+	}
+	if got := a.Mul(b); !got.Equals(Vector2u{3, 24}) {
+		t.Errorf("Mul. Got %v", got)
+	}
+	if d := a.Dot(b); d != 27 {
+		t.Errorf("Dot. Expected 27 got %d", d)
+	}
+	if l := a.Length(); l != 5 {
+		t.Errorf("Length. Expected 5 got %f", l)
+	}
+	if got := a.Clamp(Vector2u{2, 5}, Vector2u{2, 10}); !got.Equals(Vector2u{2, 5}) {
+		t.Errorf("Clamp. Got %v", got)
+	}
+	if got := a.ToVector2i(); !got.Equals(Vector2i{3, 4}) {
+		t.Errorf("ToVector2i. Got %v", got)
+	}
+	if got := a.ToVector2f(); !got.Equals(Vector2f{3, 4}) {
+		t.Errorf("ToVector2f. Got %v", got)
+	}
+}
+
+func TestVector2fMath(t *testing.T) {
+	a, b := Vector2f{3, 4}, Vector2f{-1, 2}
+	cases := []struct {
+		name      string
+		got, want Vector2f
+	}{
+		{"Plus", a.Plus(b), Vector2f{2, 6}},
+		{"Minus", a.Minus(b), Vector2f{4, 2}},
+		{"Scale", a.Scale(0.5), Vector2f{1.5, 2}},
+		{"Div", a.Div(2), Vector2f{1.5, 2}},
+		{"Mul", a.Mul(b), Vector2f{-3, 8}},
+		{"Neg", a.Neg(), Vector2f{-3, -4}},
+		{"Normalize", a.Normalize(), Vector2f{0.6, 0.8}},
+		{"Normalize zero", Vector2f{}.Normalize(), Vector2f{}},
+		{"Lerp", a.Lerp(b, 0.25), Vector2f{2, 3.5}},
+		{"Abs", b.Abs(), Vector2f{1, 2}},
+		{"Clamp", a.Clamp(Vector2f{0, 0}, Vector2f{2, 5}), Vector2f{2, 4}},
+		{"ClampLength", a.ClampLength(2.5), Vector2f{1.5, 2}},
+		{"ClampLength short", b.ClampLength(10), b},
+		{"Rotate", Vector2f{1, 0}.Rotate(math.Pi / 2), Vector2f{0, 1}},
+		{"Perpendicular", Vector2f{1, 0}.Perpendicular(), Vector2f{0, 1}},
+		{"Reflect", Vector2f{1, -1}.Reflect(Vector2f{0, 1}), Vector2f{1, 1}},
+		{"Project", a.Project(Vector2f{2, 0}), Vector2f{3, 0}},
+		{"Project zero", a.Project(Vector2f{}), Vector2f{}},
+	}
+	for _, c := range cases {
+		if !c.got.ApproxEquals(c.want, Epsilon) {
+			t.Errorf("%s. Expected %v got %v", c.name, c.want, c.got)
+		}
+	}
+	floats := []struct {
+		name      string
+		got, want float32
+	}{
+		{"Dot", a.Dot(b), 5},
+		{"Cross", a.Cross(b), 10},
+		{"Length", a.Length(), 5},
+		{"LengthSquared", a.LengthSquared(), 25},
+		{"Distance", a.Distance(b), float32(math.Sqrt(20))},
+		{"DistanceSquared", a.DistanceSquared(b), 20},
+		{"Angle", Vector2f{0, 1}.Angle(), math.Pi / 2},
+		{"AngleTo", Vector2f{1, 0}.AngleTo(Vector2f{0, -1}), -math.Pi / 2},
+		{"Degrees", RadiansToDegrees(DegreesToRadians(30)), 30},
+	}
+	for _, c := range floats {
+		if !ApproxEqual(c.got, c.want, Epsilon) {
+			t.Errorf("%s. Expected %f got %f", c.name, c.want, c.got)
+		}
+	}
+	if got := (Vector2f{-1.5, 2.5}).Floor(); !got.Equals(Vector2i{-2, 2}) {
+		t.Errorf("Floor. Got %v", got)
+	}
+	if got := (Vector2f{-1.5, 2.5}).ToVector2i(); !got.Equals(Vector2i{-1, 2}) {
+		t.Errorf("ToVector2i. Got %v", got)
+	}
+	if got := (Vector2f{-1.5, 2.5}).ToVector2u(); !got.Equals(Vector2u{0, 2}) {
+		t.Errorf("ToVector2u. Got %v", got)
+	}
+	if (Vector2f{1, 1}).ApproxEquals(Vector2f{1, 1.1}, Epsilon) {
+		t.Errorf("ApproxEquals too loose")
+	}
+}
+
+func TestVector3fMath(t *testing.T) {
+	x, y := Vector3f{1, 0, 0}, Vector3f{0, 1, 0}
+	a := Vector3f{1, 2, 2}
+	cases := []struct {
+		name      string
+		got, want Vector3f
+	}{
+		{"Plus", a.Plus(x), Vector3f{2, 2, 2}},
+		{"Minus", a.Minus(x), Vector3f{0, 2, 2}},
+		{"Scale", a.Scale(2), Vector3f{2, 4, 4}},
+		{"Div", a.Div(2), Vector3f{0.5, 1, 1}},
+		{"Mul", a.Mul(Vector3f{2, 0, -1}), Vector3f{2, 0, -2}},
+		{"Neg", a.Neg(), Vector3f{-1, -2, -2}},
+		{"Cross", x.Cross(y), Vector3f{0, 0, 1}},
+		{"Normalize", a.Normalize(), Vector3f{1.0 / 3, 2.0 / 3, 2.0 / 3}},
+		{"Lerp", x.Lerp(y, 0.5), Vector3f{0.5, 0.5, 0}},
+		{"Clamp", a.Clamp(Vector3f{}, Vector3f{1, 1, 1}), Vector3f{1, 1, 1}},
+		{"ClampLength", a.ClampLength(1.5), Vector3f{0.5, 1, 1}},
+		{"Reflect", Vector3f{1, -1, 0}.Reflect(y), Vector3f{1, 1, 0}},
+		{"Project", a.Project(Vector3f{0, 0, 5}), Vector3f{0, 0, 2}},
+		{"Min", a.Min(Vector3f{0, 3, 1}), Vector3f{0, 2, 1}},
+		{"Max", a.Max(Vector3f{0, 3, 1}), Vector3f{1, 3, 2}},
+		{"Abs", a.Neg().Abs(), a},
+	}
+	for _, c := range cases {
+		if !c.got.ApproxEquals(c.want, Epsilon) {
+			t.Errorf("%s. Expected %v got %v", c.name, c.want, c.got)
+		}
+	}
+	if l := a.Length(); !ApproxEqual(l, 3, Epsilon) {
+		t.Errorf("Length. Expected 3 got %f", l)
+	}
+	if d := a.Distance(x); !ApproxEqual(d, float32(math.Sqrt(8)), Epsilon) {
+		t.Errorf("Distance. Got %f", d)
+	}
+	if angle := x.AngleTo(y); !ApproxEqual(angle, math.Pi/2, Epsilon) {
+		t.Errorf("AngleTo. Got %f", angle)
+	}
+	if got := a.XY(); !got.Equals(Vector2f{1, 2}) {
+		t.Errorf("XY. Got %v", got)
+	}
+	if got := a.ToSFML(); got.Z != 2 {
+		t.Errorf("ToSFML dropped Z. Got %v", got)
+	}
+}
+
+func TestVectorOpsDoNotAllocate(t *testing.T) {
+	a, b := Vector2f{3, 4}, Vector2f{-1, 2}
+	c, d := Vector3f{1, 2, 3}, Vector3f{3, 2, 1}
+	var sink2 Vector2f
+	var sink3 Vector3f
+	allocs := testing.AllocsPerRun(100, func() {
+		sink2 = a.Plus(b).Scale(2).Normalize().Rotate(1).Reflect(b.Normalize()).Lerp(a, 0.5)
+		sink3 = c.Cross(d).Normalize().Project(d).Lerp(c, 0.5)
+	})
+	if allocs != 0 {
+		t.Errorf("Vector ops allocated %f times per run", allocs)
+	}
+	_, _ = sink2, sink3
+}
+
+var benchVector2f Vector2f
+var benchVector3f Vector3f
+var benchFloat float32
+
+func BenchmarkVector2fPlus(b *testing.B) {
+	b.ReportAllocs()
+	v, w := Vector2f{1, 2}, Vector2f{3, 4}
+	for i := 0; i < b.N; i++ {
+		v = v.Plus(w)
+	}
+	benchVector2f = v
+}
+
+func BenchmarkVector2fNormalize(b *testing.B) {
+	b.ReportAllocs()
+	v := Vector2f{3, 4}
+	for i := 0; i < b.N; i++ {
+		benchVector2f = v.Normalize()
+	}
+}
+
+func BenchmarkVector2fRotate(b *testing.B) {
+	b.ReportAllocs()
+	v := Vector2f{3, 4}
+	for i := 0; i < b.N; i++ {
+		benchVector2f = v.Rotate(0.5)
+	}
+}
+
+func BenchmarkVector2fDistance(b *testing.B) {
+	b.ReportAllocs()
+	v, w := Vector2f{1, 2}, Vector2f{3, 4}
+	for i := 0; i < b.N; i++ {
+		benchFloat = v.Distance(w)
+	}
+}
+
+func BenchmarkVector3fCross(b *testing.B) {
+	b.ReportAllocs()
+	v, w := Vector3f{1, 2, 3}, Vector3f{3, 2, 1}
+	for i := 0; i < b.N; i++ {
+		benchVector3f = v.Cross(w)
+	}
+}
+
+func BenchmarkVector3fNormalize(b *testing.B) {
+	b.ReportAllocs()
+	v := Vector3f{1, 2, 3}
+	for i := 0; i < b.N; i++ {
+		benchVector3f = v.Normalize()
+	}
+}
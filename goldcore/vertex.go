@@ -0,0 +1,119 @@
+package goldcore
+
+import sf "github.com/manyminds/gosfml"
+
+//Vertex : Corner of a drawn primitive
+type Vertex struct {
+	Position  Vector2f //Where, before the RenderStates transform
+	Color     Color    //Multiplies the texture, or the color if untextured
+	TexCoords Vector2f //Texture pixel shown at this corner
+}
+
+//PrimitiveType : How vertices are assembled into triangles
+type PrimitiveType int
+
+const (
+	//Triangles : Every 3 vertices make a triangle
+	Triangles PrimitiveType = iota
+	//TriangleStrip : Every vertex makes a triangle with the 2 before it
+	TriangleStrip
+	//TriangleFan : Every vertex makes a triangle with the one before it
+	//and the first
+	TriangleFan
+)
+
+//BlendMode : How drawn pixels are combined with what is already there
+type BlendMode int
+
+const (
+	//BlendAlpha : Draws over, letting through what transparency lets
+	//through. The default
+	BlendAlpha BlendMode = iota
+	//BlendAdd : Adds light, for glows and particles
+	BlendAdd
+	//BlendMultiply : Darkens, for shadows and tinting
+	BlendMultiply
+	//BlendNone : Replaces, transparency included
+	BlendNone
+)
+
+//RenderStates : How vertices are drawn
+type RenderStates struct {
+	Transform Transform2D //Applied to every vertex position
+	Texture   *Texture    //nil draws plain colors
+	BlendMode BlendMode
+	//Material : Shader to draw with, nil for none. Software targets can't
+	//run shaders and ignore it
+	Material *Material
+}
+
+//DefaultRenderStates : Identity transform, no texture, alpha blending. The
+//zero RenderStates has a zero transform and draws nothing visible
+func DefaultRenderStates() RenderStates {
+	return RenderStates{Transform: IdentityTransform()}
+}
+
+//Drawable : Anything that can draw itself on a RenderTarget. states is
+//what the caller wants applied on top of the drawable's own transform
+type Drawable interface {
+	Draw(target RenderTarget, states RenderStates)
+}
+
+//ToSFML : Allows for SFML compatability
+func (v Vertex) ToSFML() sf.Vertex {
+	return sf.Vertex{
+		Position:  v.Position.ToSFML(),
+		Color:     v.Color.ToSFML(),
+		TexCoords: v.TexCoords.ToSFML(),
+	}
+}
+
+//ToSFML : Allows for SFML compatability
+func (p PrimitiveType) ToSFML() sf.PrimitiveType {
+	switch p {
+	case TriangleStrip:
+		return sf.PrimitiveTrianglesStrip
+	case TriangleFan:
+		return sf.PrimitiveTrianglesFan
+	default:
+		return sf.PrimitiveTriangles
+	}
+}
+
+//ToSFML : Allows for SFML compatability
+func (b BlendMode) ToSFML() sf.BlendMode {
+	switch b {
+	case BlendAdd:
+		return sf.BlendAdd
+	case BlendMultiply:
+		return sf.BlendMultiply
+	case BlendNone:
+		return sf.BlendNone
+	default:
+		return sf.BlendAlpha
+	}
+}
+
+//ToSFML : Allows for SFML compatability. Uploads the texture, so it must be
+//called on the render thread
+func (states RenderStates) ToSFML() sf.RenderStates {
+	sfStates := sf.DefaultRenderStates()
+	sfStates.Transform = states.Transform.ToSFML()
+	sfStates.BlendMode = states.BlendMode.ToSFML()
+	if states.Texture != nil {
+		sfStates.Texture = states.Texture.ToSFML()
+	}
+	if states.Material != nil {
+		sfStates.Shader = states.Material.toSFML()
+	}
+	return sfStates
+}
+
+//drawVerticesSFML : Draws vertices on an SFML target
+func drawVerticesSFML(target sf.RenderTarget, vertices []Vertex, primitive PrimitiveType, states RenderStates) {
+	sfVertices := make([]sf.Vertex, len(vertices))
+	for i, v := range vertices {
+		sfVertices[i] = v.ToSFML()
+	}
+	target.DrawPrimitives(sfVertices, primitive.ToSFML(), states.ToSFML())
+}
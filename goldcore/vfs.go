@@ -0,0 +1,343 @@
+package goldcore
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+//VFS : Virtual filesystem made of file systems mounted at paths. A game can
+//mount its pack at the root and mod folders over it: when several mounts
+//have a file, the highest priority wins, then the latest mounted. Paths use
+//slashes and are relative to the root. Like any fs.FS it only takes clean
+//paths, run paths from users or data files through NormalizePath. A VFS is
+//an fs.FS, so fs.WalkDir and fs.Glob enumerate it. Safe for concurrent use
+type VFS struct {
+	mutex  sync.RWMutex
+	mounts []*vfsMount //Searched first to last
+	count  int
+}
+
+//vfsMount : A file system mounted at point
+type vfsMount struct {
+	point    string //Normalized, "." for the root
+	fsys     fs.FS
+	priority int
+	order    int       //Mount order, later wins ties
+	closer   io.Closer //Archive to close on Unmount, nil for none
+}
+
+//NewVFS : VFS with nothing mounted
+func NewVFS() *VFS {
+	return &VFS{}
+}
+
+//NormalizePath : name as a VFS path. Backslashes become slashes, leading
+//slashes, . and empty elements are dropped and .. is resolved. Paths
+//escaping the root fail. The root is "."
+func NormalizePath(name string) (string, error) {
+	p := strings.ReplaceAll(name, "\\", "/")
+	p = path.Clean("/" + p)[1:]
+	if p == "" {
+		p = "."
+	}
+	//Clean drops .. at the root, find out if there was one to drop
+	depth := 0
+	for _, elem := range strings.Split(strings.ReplaceAll(name, "\\", "/"), "/") {
+		switch elem {
+		case "", ".":
+		case "..":
+			depth--
+		default:
+			depth++
+		}
+		if depth < 0 {
+			return "", fmt.Errorf("goldcore: path %s leaves the root", name)
+		}
+	}
+	return p, nil
+}
+
+//Mount : Mounts fsys at point. fsys may be an os.DirFS, a zip.Reader, an
+//embed.FS or any other fs.FS. Use fs.Sub to mount part of one
+func (v *VFS) Mount(point string, fsys fs.FS, priority int) error {
+	return v.mount(point, fsys, priority, nil)
+}
+
+//MountDir : Mounts the OS directory dir at point
+func (v *VFS) MountDir(point, dir string, priority int) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("goldcore: mounting %s: %v", dir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("goldcore: mounting %s: not a directory", dir)
+	}
+	return v.mount(point, os.DirFS(dir), priority, nil)
+}
+
+//MountArchive : Mounts the zip file at archive at point. It stays open
+//until unmounted
+func (v *VFS) MountArchive(point, archive string, priority int) error {
+	r, err := zip.OpenReader(archive)
+	if err != nil {
+		return fmt.Errorf("goldcore: mounting %s: %v", archive, err)
+	}
+	if err := v.mount(point, r, priority, r); err != nil {
+		r.Close()
+		return err
+	}
+	return nil
+}
+
+func (v *VFS) mount(point string, fsys fs.FS, priority int, closer io.Closer) error {
+	point, err := NormalizePath(point)
+	if err != nil {
+		return err
+	}
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.count++
+	v.mounts = append(v.mounts, &vfsMount{point: point, fsys: fsys, priority: priority, order: v.count, closer: closer})
+	sort.SliceStable(v.mounts, func(i, j int) bool {
+		a, b := v.mounts[i], v.mounts[j]
+		if a.priority != b.priority {
+			return a.priority > b.priority
+		}
+		return a.order > b.order
+	})
+	return nil
+}
+
+//Unmount : Unmounts everything mounted at point, closing archives. Returns
+//how many mounts were removed
+func (v *VFS) Unmount(point string) int {
+	point, err := NormalizePath(point)
+	if err != nil {
+		return 0
+	}
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	kept, n := v.mounts[:0], 0
+	for _, m := range v.mounts {
+		if m.point != point {
+			kept = append(kept, m)
+			continue
+		}
+		if m.closer != nil {
+			m.closer.Close()
+		}
+		n++
+	}
+	v.mounts = kept
+	return n
+}
+
+//Close : Unmounts everything
+func (v *VFS) Close() error {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	var err error
+	for _, m := range v.mounts {
+		if m.closer != nil {
+			if e := m.closer.Close(); e != nil && err == nil {
+				err = e
+			}
+		}
+	}
+	v.mounts = nil
+	return err
+}
+
+//rel : name inside the mount, false if the mount doesn't cover it
+func (m *vfsMount) rel(name string) (string, bool) {
+	switch {
+	case m.point == ".":
+		return name, true
+	case name == m.point:
+		return ".", true
+	case strings.HasPrefix(name, m.point+"/"):
+		return name[len(m.point)+1:], true
+	}
+	return "", false
+}
+
+//Open : Opens the file name from the mount that wins it. name must be a
+//valid fs path, see fs.ValidPath. Directories list what every mount has
+func (v *VFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+	for _, m := range v.mounts {
+		rel, ok := m.rel(name)
+		if !ok {
+			continue
+		}
+		f, err := m.fsys.Open(rel)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		info, err := f.Stat()
+		if err != nil || !info.IsDir() {
+			return f, err
+		}
+		f.Close()
+		break
+	}
+	entries, err := v.readDir(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return &vfsDir{info: vfsDirInfo{name: path.Base(name)}, entries: entries}, nil
+}
+
+//ReadFile : Contents of the file name, see Open
+func (v *VFS) ReadFile(name string) ([]byte, error) {
+	f, err := v.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+//Stat : Information on the file name, see Open
+func (v *VFS) Stat(name string) (fs.FileInfo, error) {
+	f, err := v.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return f.Stat()
+}
+
+//ReadDir : Entries of the directory name from every mount, sorted by name.
+//Mount points show up as directories
+func (v *VFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+	entries, err := v.readDir(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+	return entries, nil
+}
+
+//readDir : Merged entries of the directory name. Mutex held
+func (v *VFS) readDir(name string) ([]fs.DirEntry, error) {
+	found := false
+	merged := make(map[string]fs.DirEntry)
+	for _, m := range v.mounts {
+		if rel, ok := m.rel(name); ok {
+			entries, err := fs.ReadDir(m.fsys, rel)
+			if err != nil {
+				//Not there, or a file hidden by a directory
+				continue
+			}
+			found = true
+			for _, e := range entries {
+				if _, ok := merged[e.Name()]; ok {
+					continue
+				}
+				//Directories can be merged from many mounts, they get made up info
+				if e.IsDir() {
+					e = fs.FileInfoToDirEntry(vfsDirInfo{name: e.Name()})
+				}
+				merged[e.Name()] = e
+			}
+			continue
+		}
+		//Mount points below name are directories of it
+		under := m.point
+		if name != "." {
+			if !strings.HasPrefix(m.point, name+"/") {
+				continue
+			}
+			under = m.point[len(name)+1:]
+		}
+		found = true
+		child := strings.SplitN(under, "/", 2)[0]
+		if _, ok := merged[child]; !ok {
+			merged[child] = fs.FileInfoToDirEntry(vfsDirInfo{name: child})
+		}
+	}
+	if !found {
+		if name == "." {
+			return nil, nil
+		}
+		return nil, fs.ErrNotExist
+	}
+	entries := make([]fs.DirEntry, 0, len(merged))
+	for _, e := range merged {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
+//vfsDir : Directory opened from a VFS, listing every mount
+type vfsDir struct {
+	info    vfsDirInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *vfsDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *vfsDir) Close() error               { return nil }
+
+func (d *vfsDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: errors.New("is a directory")}
+}
+
+//ReadDir : See fs.ReadDirFile
+func (d *vfsDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(rest) {
+		n = len(rest)
+	}
+	d.offset += n
+	return rest[:n], nil
+}
+
+//vfsDirInfo : fs.FileInfo of every directory of a VFS
+type vfsDirInfo struct {
+	name string
+}
+
+func (i vfsDirInfo) Name() string       { return i.name }
+func (i vfsDirInfo) Size() int64        { return 0 }
+func (i vfsDirInfo) Mode() fs.FileMode  { return fs.ModeDir | 0555 }
+func (i vfsDirInfo) ModTime() time.Time { return time.Time{} }
+func (i vfsDirInfo) IsDir() bool        { return true }
+func (i vfsDirInfo) Sys() interface{}   { return nil }
+
+//osFS : fs.FS opening OS paths as they are, relative to the working
+//directory or absolute. What asset loading reads from without a VFS
+type osFS struct{}
+
+func (osFS) Open(name string) (fs.File, error) {
+	return os.Open(name)
+}
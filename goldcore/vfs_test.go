@@ -0,0 +1,152 @@
+package goldcore
+
+import (
+	"archive/zip"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+//writeZip : Zip file at p holding files
+func writeZip(t *testing.T, p string, files map[string]string) {
+	t.Helper()
+	f, err := os.Create(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	w := zip.NewWriter(f)
+	for name, data := range files {
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fw.Write([]byte(data))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNormalizePath(t *testing.T) {
+	for name, expected := range map[string]string{
+		"a/b.png":        "a/b.png",
+		"/a//b.png":      "a/b.png",
+		"./a/./b.png":    "a/b.png",
+		`a\b\..\c.png`:   "a/c.png",
+		"":               ".",
+		"/":              ".",
+		"a/b/../../c/":   "c",
+		"textures/../..": "",
+	} {
+		p, err := NormalizePath(name)
+		if expected == "" {
+			if err == nil {
+				t.Errorf("%q leaves the root, got %q", name, p)
+			}
+			continue
+		}
+		if err != nil || p != expected {
+			t.Errorf("%q: expected %q got %q %v", name, expected, p, err)
+		}
+	}
+}
+
+func TestVFS(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, filepath.Join(dir, "game"), map[string][]byte{
+		"a.txt":             []byte("dir"),
+		"textures/hero.png": pngBytes(t, 2, 2),
+	})
+	pack := filepath.Join(dir, "game.pak")
+	writeZip(t, pack, map[string]string{"a.txt": "zip", "data/level.json": "{}"})
+
+	v := NewVFS()
+	defer v.Close()
+	if err := v.MountDir("", filepath.Join(dir, "game"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.MountArchive("/", pack, 0); err != nil {
+		t.Fatal(err)
+	}
+	mod := fstest.MapFS{
+		"readme.txt":        {Data: []byte("mod")},
+		"textures/hero.png": {Data: pngBytes(t, 4, 4)},
+	}
+	if err := v.Mount("mods/extra", mod, 10); err != nil {
+		t.Fatal(err)
+	}
+
+	//Later mounts win ties
+	for _, name := range []string{"a.txt", "/a.txt", `.\textures\..\a.txt`} {
+		p, err := NormalizePath(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if data, err := v.ReadFile(p); err != nil || string(data) != "zip" {
+			t.Errorf("%s: expected the pack's file got %q %v", name, data, err)
+		}
+	}
+	if _, err := v.ReadFile("/a.txt"); err == nil {
+		t.Error("Like any fs.FS, a VFS only takes valid paths")
+	}
+
+	entries, err := v.ReadDir(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	if s := strings.Join(names, " "); s != "a.txt data mods textures" {
+		t.Errorf("Unexpected root %s", s)
+	}
+	if err := fstest.TestFS(v, "a.txt", "data/level.json", "mods/extra/readme.txt", "textures/hero.png", "mods/extra/textures/hero.png"); err != nil {
+		t.Error(err)
+	}
+	var files []string
+	fs.WalkDir(v, ".", func(p string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			files = append(files, p)
+		}
+		return err
+	})
+	if s := strings.Join(files, " "); s != "a.txt data/level.json mods/extra/readme.txt mods/extra/textures/hero.png textures/hero.png" {
+		t.Errorf("Unexpected files %s", s)
+	}
+
+	//Mods override the game by mounting over it with a higher priority
+	if err := v.Mount("", mod, 10); err != nil {
+		t.Fatal(err)
+	}
+	m := NewResourceManager(nil, 1)
+	defer m.Close()
+	m.SetFileSystem(v)
+	hero := m.Texture("/textures/hero.png")
+	if hero.Wait() != nil || hero.Texture().Size() != (Vector2u{X: 4, Y: 4}) {
+		t.Errorf("Expected the mod's texture got %v", hero.Err())
+	}
+	if m.Texture("textures//./hero.png").Texture() != hero.Texture() {
+		t.Error("Resources are cached by normalized path")
+	}
+	if bad := m.Data("../outside"); bad.Wait() == nil {
+		t.Error("Paths leaving the root should fail")
+	}
+
+	if n := v.Unmount("/"); n != 3 {
+		t.Errorf("Expected the 3 mounts at the root unmounted got %d", n)
+	}
+	if _, err := v.Stat("a.txt"); err == nil {
+		t.Error("The root's files should be gone")
+	}
+	if info, err := v.Stat("mods"); err != nil || !info.IsDir() {
+		t.Error("Mount points are directories")
+	}
+	if v.MountDir("", filepath.Join(dir, "game", "a.txt"), 0) == nil || v.MountArchive("", filepath.Join(dir, "missing.pak"), 0) == nil {
+		t.Error("Mounting files that aren't there should fail")
+	}
+}
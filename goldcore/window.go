@@ -2,6 +2,8 @@ package goldcore
 
 import (
 	"runtime"
+	"sync"
+	"sync/atomic"
 
 	sf "github.com/manyminds/gosfml"
 	"github.com/trustmaster/goflow"
@@ -10,14 +12,25 @@ import (
 //Define Window Messages
 var (
 	//Messages for Output
+	//Payload Vector2u
+	//Out: Size of the window. Sent once, the first time the window is started or polled
 	WindowCreated = RegisterGameMessage("game window created")
 	WindowClosed  = RegisterGameMessage("game window closed")
-	//Payload Vector2u
-	//In: Resizes the Window
-	//Out: New Size of Window
+	//Payload Vector2u or EventWindowResized
+	//In: Resizes the Window. An EventWindowResized is resized to its Size, so
+	//the window's own messages can be fed back in
+	//Out: EventWindowResized with the new and old size of the Window
 	WindowResized     = RegisterGameMessage("game window resized")
 	WindowLostFocus   = RegisterGameMessage("game window lost focus")
 	WindowGainedFocus = RegisterGameMessage("game window gained focus")
+	//Payload EventWindowResized
+	//Out: Window was resized down to nothing. SFML 2 has no minimize event,
+	//only some platforms (Windows) report minimizing as a 0x0 resize. Where it
+	//doesn't this is never sent, WindowLostFocus is the closest thing there
+	WindowMinimized = RegisterGameMessage("game window minimized")
+	//Payload EventWindowResized
+	//Out: Window came back from being minimized
+	WindowRestored = RegisterGameMessage("game window restored")
 	//Payload EventTextEntered
 	//In: Notifies Text Entered Observers
 	//Out: EventTextEntered object
@@ -61,10 +74,42 @@ var (
 	WindowPaused    = RegisterGameMessage("game window paused")
 	WindowCantPause = RegisterGameMessage("game window paused WARNING: Can't pause a closed or stopped window")
 	WindowRunning   = RegisterGameMessage("game window running")
-	WindowSpinning  = RegisterGameMessage("game window spinning WARNING: Game window is allowed to render, but has not been given the render signal. You should Deactivate or Stop the Game window if you don't want to render")
+	//Not sent anymore, the render loop sleeps until it has work
+	WindowSpinning = RegisterGameMessage("game window spinning WARNING: Game window is allowed to render, but has not been given the render signal. You should Deactivate or Stop the Game window if you don't want to render")
 	//Payload : int. TODO figure out what to do with this int
 	WindowNextFrame = RegisterGameMessage("game window next frame")
 	WindowRendered  = RegisterGameMessage("game window rendered")
+
+	//Payload error
+	//Out: A window call (SetTitle, Clear, DrawImage...) failed on the render
+	//thread. Sent by the next PollEvent or NextFrame
+	WindowCommandFailed = RegisterGameMessage("game window command failed")
+
+	//Capture
+	//Payload string
+	//In: Saves a screenshot into the directory in the payload
+	//Out: Path of the saved PNG
+	WindowScreenshot = RegisterGameMessage("game window screenshot saved")
+	//Payload error
+	//Out: Why the screenshot could not be saved
+	WindowScreenshotFailed = RegisterGameMessage("game window screenshot failed")
+
+	//Post processing
+	//Payload PostEffectToggle
+	//In: Enables or disables an effect of the post processing chain
+	//Out: The same payload once done
+	WindowPostEffectToggled = RegisterGameMessage("game window post effect toggled")
+	//Payload PostEffectMove
+	//In: Moves an effect to another place in the chain
+	//Out: The same payload once done
+	WindowPostEffectMoved = RegisterGameMessage("game window post effect moved")
+	//Payload PostEffectParameter
+	//In: Sets a uniform of an effect
+	//Out: The same payload once done
+	WindowPostEffectParameter = RegisterGameMessage("game window post effect parameter")
+	//Payload error
+	//Out: Why a post processing message could not be applied
+	WindowPostEffectFailed = RegisterGameMessage("game window post effect failed")
 )
 
 //WindowObserver : Implementations of this interface get an Window event and the event
@@ -85,20 +130,37 @@ const (
 //GameWindow : Wrapper around SFML Window. Additional Functionality for Game
 //TODO change stopped param to running param
 type GameWindow struct {
-	renderWindow         *sf.RenderWindow
+	renderWindow         windowBackend
 	renderState          chan int
 	renderStateProcessed chan int
 	currentState         int
 	stopped              bool
-	wait                 chan int
+	wait                 chan int //Frames asked for by NextFrame
+	presented            chan int //Frames shown by the render loop
 	observers            []WindowObserver
 	InputSystem          InputSystem
 	game                 *Game
+	capture              *windowCapture
+	created              uint32   //Set once WindowCreated was sent. Accessed atomically
+	size                 Vector2u //Last size announced to observers
+	minimized            bool
+	pauseOnFocusLoss     bool
+	pausedGame           uint64 //Game state change made by a focus loss, 0 if none
+	camera               *Camera2D
+	stats                *frameStats
+	post                 *PostChain
+	deferred             *deferredMessages
 	flow.Component
 	InputGameMessage  <-chan *GameMessage
 	OutputGameMessage chan<- *GameMessage
 }
 
+//EventWindowResized : Payload of WindowResized, WindowMinimized and WindowRestored
+type EventWindowResized struct {
+	Size     Vector2u //Size of the window now
+	Previous Vector2u //Size of the window before the event
+}
+
 //GameWindowMessageBufferSize : Number of messages to keep in buffer
 const GameWindowMessageBufferSize = 5
 
@@ -110,6 +172,15 @@ func (gW *GameWindow) OnInputGameMessage(gM *GameMessage) {
 	case WindowClosed:
 		//Close Window
 		gW.CloseWindow()
+	case WindowResized:
+		switch size := gM.Payload.(type) {
+		case Vector2u:
+			gW.resizeTo(size)
+		case EventWindowResized:
+			gW.resizeTo(size.Size)
+		}
+		//The resize event coming back from the window announces the new size
+		return
 	case WindowKeyPressed:
 		gW.InputSystem.SetKeyPressed(EventKeyToSFEventKeyPressed(gM.Payload.(EventKey)))
 	case WindowKeyReleased:
@@ -134,6 +205,19 @@ func (gW *GameWindow) OnInputGameMessage(gM *GameMessage) {
 		gW.Start()
 	case WindowNextFrame:
 		gW.NextFrame(gM.Payload.(int))
+
+		//Capture
+	case WindowScreenshot:
+		gW.screenshot(gM.Payload.(string))
+		//The result is announced by screenshot, don't echo the request
+		return
+
+		//Post processing
+	case WindowPostEffectToggled, WindowPostEffectMoved, WindowPostEffectParameter:
+		if err := gW.onPostEffectMessage(gM); err != nil {
+			gW.notify(NewGameMessage(WindowPostEffectFailed, err))
+			return
+		}
 	}
 	gW.notify(gM)
 }
@@ -155,7 +239,10 @@ func (gW *GameWindow) RemoveObserver(wO WindowObserver) {
 
 func (gW *GameWindow) notify(gM *GameMessage) {
 	//fmt.Println(gM)
-	gW.OutputGameMessage <- gM
+	//Windows outside of a flow graph (headless tests) have no output port
+	if gW.OutputGameMessage != nil {
+		gW.OutputGameMessage <- gM
+	}
 	//fmt.Println("From Window", gM)
 	//fmt.Println(<-gW.OutputGameMessage)
 	for _, o := range gW.observers {
@@ -163,27 +250,141 @@ func (gW *GameWindow) notify(gM *GameMessage) {
 	}
 }
 
+//deferredMessages : Messages raised on the render thread. Observers get them
+//from PollEvent or NextFrame, on the caller's goroutine, so the render loop
+//never runs observer code that could wait for it. Shared by pointer since
+//GameWindows get passed around by value
+type deferredMessages struct {
+	mutex    sync.Mutex
+	messages []deferredMessage
+}
+
+//deferredMessage : A message waiting to be sent. The GameMessage is only
+//made when it is sent
+type deferredMessage struct {
+	msg     GMessage
+	payload interface{}
+}
+
+//push : Queues a message for the next flush
+func (d *deferredMessages) push(msg GMessage, payload interface{}) {
+	d.mutex.Lock()
+	d.messages = append(d.messages, deferredMessage{msg: msg, payload: payload})
+	d.mutex.Unlock()
+}
+
+//flushDeferred : Sends the messages raised on the render thread
+func (gW *GameWindow) flushDeferred() {
+	gW.deferred.mutex.Lock()
+	messages := gW.deferred.messages
+	gW.deferred.messages = nil
+	gW.deferred.mutex.Unlock()
+	for _, m := range messages {
+		gW.notify(NewGameMessage(m.msg, m.payload))
+	}
+}
+
 var activeGameWindow *GameWindow
 
 //NewGameWindow : Creates a new game window. Inactivates any GameWindow, and
 //activates the newly created one
-//TODO figure out how to maek the WindowCreated Message Work
-//Note, I can't create the windows.
+//WindowCreated can't be sent from here since nothing is listening yet. It is
+//sent the first time the window is started or polled instead
 func NewGameWindow(width, height uint, name string) *GameWindow {
 
 	gW := &GameWindow{
-		renderWindow: sf.NewRenderWindow(sf.VideoMode{Width: width, Height: height, BitsPerPixel: 32}, name, sf.StyleDefault, sf.DefaultContextSettings()),
+		renderWindow: newSFMLWindow(width, height, name),
 		InputSystem:  NewInputSystem(),
 		observers:    make([]WindowObserver, 0),
+		capture:      &windowCapture{},
+		stats:        &frameStats{},
+		post:         NewPostChain(),
+		deferred:     &deferredMessages{},
+		wait:         make(chan int),
+		presented:    make(chan int),
 	}
 	gW.renderWindow.SetActive(false)
 	gW.stopped = true
+	gW.size = gW.GetSize()
 	return gW
 }
 
+//announceCreated : Sends WindowCreated the first time it is called. Start
+//and PollEvent may race for it, only one of them sends it
+func (gW *GameWindow) announceCreated() {
+	if atomic.CompareAndSwapUint32(&gW.created, 0, 1) {
+		gW.notify(NewGameMessage(WindowCreated, gW.size))
+	}
+}
+
+//SetPauseOnFocusLoss : When enabled the Game owning this window is suspended
+//when the window loses focus, and resumed when focus comes back. A game that
+//was already suspended is left alone
+func (gW *GameWindow) SetPauseOnFocusLoss(pause bool) {
+	gW.pauseOnFocusLoss = pause
+}
+
+//PausesOnFocusLoss : Checks whether focus loss suspends the game
+func (gW *GameWindow) PausesOnFocusLoss() bool {
+	return gW.pauseOnFocusLoss
+}
+
+//onFocusChanged : Suspends or resumes the game if asked to
+func (gW *GameWindow) onFocusChanged(focused bool) {
+	if !gW.pauseOnFocusLoss || gW.game == nil {
+		return
+	}
+	if !focused {
+		if change := gW.game.suspendFor(); change != 0 {
+			gW.pausedGame = change
+		}
+	} else if gW.pausedGame != 0 {
+		//Only if the game is still suspended from the focus loss, not
+		//stopped or suspended by the player meanwhile
+		gW.game.resumeAfter(gW.pausedGame)
+		gW.pausedGame = 0
+	}
+}
+
+//onResized : Announces the new size. Going down to a zero sized window is how
+//minimizing shows up on the platforms that report it, so that is announced too
+func (gW *GameWindow) onResized(size Vector2u) {
+	resized := EventWindowResized{Size: size, Previous: gW.size}
+	gW.size = size
+	if gW.camera != nil {
+		gW.camera.SetTargetSize(size)
+		gW.ApplyCamera()
+	}
+	gW.notify(NewGameMessage(WindowResized, resized))
+	if minimized := size.X == 0 || size.Y == 0; minimized != gW.minimized {
+		gW.minimized = minimized
+		if minimized {
+			gW.notify(NewGameMessage(WindowMinimized, resized))
+		} else {
+			gW.notify(NewGameMessage(WindowRestored, resized))
+		}
+	}
+}
+
+//resizeTo : Resizes the window unless it already has that size, so feeding
+//a resize message back in doesn't start a loop of resize events
+func (gW *GameWindow) resizeTo(size Vector2u) {
+	if !gW.GetSize().Equals(size) {
+		gW.SetSize(size)
+	}
+}
+
+//IsMinimized : Checks whether the window was last resized to nothing. See
+//WindowMinimized for the platforms where this is never true
+func (gW *GameWindow) IsMinimized() bool {
+	return gW.minimized
+}
+
 //PollEvent : Calls handlers for every event since this was last called
 //TODO Make sure notify only called once
 func (gW *GameWindow) PollEvent() {
+	gW.announceCreated()
+	gW.flushDeferred()
 	for event := gW.renderWindow.PollEvent(); event != nil; event = gW.renderWindow.PollEvent() {
 		switch ev := event.(type) {
 		case sf.EventClosed:
@@ -191,10 +392,12 @@ func (gW *GameWindow) PollEvent() {
 			gW.notify(NewGameMessage(WindowClosed, nil))
 		case sf.EventLostFocus:
 			gW.notify(NewGameMessage(WindowLostFocus, nil))
+			gW.onFocusChanged(false)
 		case sf.EventGainedFocus:
-			gW.notify(NewGameMessage(WindowLostFocus, nil))
+			gW.notify(NewGameMessage(WindowGainedFocus, nil))
+			gW.onFocusChanged(true)
 		case sf.EventResized:
-			gW.notify(NewGameMessage(WindowResized, Vector2u{X: event.(sf.EventResized).Width, Y: event.(sf.EventResized).Height}))
+			gW.onResized(Vector2u{X: ev.Width, Y: ev.Height})
 		case sf.EventJoystickButtonPressed:
 			gW.notify(NewGameMessage(WindowJoystickButtonPressed, nil))
 		case sf.EventJoystickButtonReleased:
@@ -215,16 +418,16 @@ func (gW *GameWindow) PollEvent() {
 			gW.InputSystem.SetTextEntered(ev)
 			gW.notify(NewGameMessage(WindowTextEntered, SFEventTextEnteredToEventTextEntered(event.(sf.EventTextEntered))))
 		case sf.EventMouseButtonPressed:
-			gW.notify(NewGameMessage(WindowMouseButtonPressed, SFMouseButtonPressedToEventMouseButtonWrapper(event.(sf.EventMouseButtonPressed))))
+			gW.notify(NewGameMessage(WindowMouseButtonPressed, gW.InputSystem.withWorldButton(SFMouseButtonPressedToEventMouseButtonWrapper(ev))))
 			gW.InputSystem.SetMouseButtonPressed(ev)
 		case sf.EventMouseButtonReleased:
-			gW.notify(NewGameMessage(WindowMouseButtonReleased, SFMouseButtonReleasedToEventMouseButtonWrapper(event.(sf.EventMouseButtonReleased))))
+			gW.notify(NewGameMessage(WindowMouseButtonReleased, gW.InputSystem.withWorldButton(SFMouseButtonReleasedToEventMouseButtonWrapper(ev))))
 			gW.InputSystem.SetMouseButtonReleased(ev)
 		case sf.EventMouseMoved:
-			gW.notify(NewGameMessage(WindowMouseMoved, SFEventMouseMovedToEventMouseMoved(event.(sf.EventMouseMoved))))
+			gW.notify(NewGameMessage(WindowMouseMoved, gW.InputSystem.withWorldMoved(SFEventMouseMovedToEventMouseMoved(ev))))
 			gW.InputSystem.SetMouseMove(ev)
 		case sf.EventMouseWheelMoved:
-			gW.notify(NewGameMessage(WindowMouseWheelMoved, SFEventMouseWheelMovedToEventMouseMoved(event.(sf.EventMouseWheelMoved))))
+			gW.notify(NewGameMessage(WindowMouseWheelMoved, gW.InputSystem.withWorldWheel(SFEventMouseWheelMovedToEventMouseMoved(ev))))
 			gW.InputSystem.SetMouseWheelMove(ev)
 		case sf.EventMouseEntered:
 			gW.notify(NewGameMessage(WindowMouseEntered, nil))
@@ -291,6 +494,7 @@ func (gW *GameWindow) Activate() {
 
 //Start : Starts the window after it being stoped
 func (gW *GameWindow) Start() {
+	gW.announceCreated()
 	if gW.stopped {
 		gW.renderState = make(chan int)
 		gW.renderStateProcessed = make(chan int)
@@ -302,72 +506,132 @@ func (gW *GameWindow) Start() {
 	gW.Activate()
 }
 
-//NextFrame : Allows rendering of next frame. Don't know what to do with
-//data yet but its there for good measure
+//NextFrame : Presents everything drawn since the last frame and returns
+//once it is shown, then sends the messages raised on the render thread.
+//Only call it while the window is running, and not from the render thread.
+//Don't know what to do with data yet but its there for good measure
 func (gW *GameWindow) NextFrame(data int) {
 	gW.wait <- data
+	<-gW.presented
+	gW.flushDeferred()
 }
 
 //render : Called on Creation of game window.
 //All rendering is done in this thread wheile
 //you can pollEvents at anytime. Ensures only
 //one opengl context at a time
+//While running it owns the RenderThread queue and sleeps until commands are
+//queued or a frame is asked for. It never notifies observers itself, what it
+//has to say is sent by the next PollEvent or NextFrame
 func (gW *GameWindow) render() {
+	attached := false
+	detach := func() {
+		if attached {
+			RenderThread.Detach()
+			attached = false
+		}
+	}
+	defer detach()
 	gW.stopped = false
 	gW.currentState = RenderPaused //Begin in paused state
 	gW.renderWindow.SetActive(false)
-	//fmt.Println("\n\nIS WINDOW OPEN", gW.renderWindow.IsOpen())
-	//gW.renderState = make(chan int)
 	gW.renderStateProcessed <- RenderRunning
-	for gW.renderWindow.IsOpen() {
+	for {
+		var commands <-chan struct{}
+		var frames <-chan int
+		if attached {
+			commands = RenderThread.Ready()
+		}
+		if gW.currentState == RenderRunning {
+			frames = gW.wait
+		}
 		select {
 		case gW.currentState = <-gW.renderState:
-			//		fmt.Println("Recieved Render State", gW.currentState)
 			switch gW.currentState {
 			case RenderStopped:
+				detach()
 				gW.stopped = true
 				gW.renderWindow.SetActive(false)
 				runtime.UnlockOSThread()
 				gW.renderStateProcessed <- RenderStopped
-				//close(gW.renderState)
 				return
 			case RenderRunning:
 				runtime.LockOSThread()
 				gW.renderWindow.SetActive(true)
+				if !attached {
+					if err := RenderThread.Attach(); err != nil {
+						gW.deferred.push(WindowCommandFailed, err)
+					} else {
+						attached = true
+					}
+				}
 				gW.renderStateProcessed <- RenderRunning
 			case RenderPaused:
+				//Whoever runs the commands now won't have the GL context
+				detach()
 				gW.renderWindow.SetActive(false)
 				runtime.UnlockOSThread()
 				gW.renderStateProcessed <- RenderPaused
 			}
-		default:
-			//Don't Starve :) the scheduler
-			runtime.Gosched()
-			if gW.currentState == RenderPaused {
-				break
+		case <-commands:
+			RenderThread.Drain()
+		case data := <-frames:
+			if attached {
+				//Everything queued before NextFrame belongs to this frame
+				RenderThread.Drain()
 			}
-			select {
-			case <-gW.wait:
-				gW.renderWindow.Display()
-			default:
-				//TODO : Figue how to make this never happen
-				//GameWindow Spinning is bad. Address
-				gW.notify(NewGameMessage(WindowSpinning, nil))
-			}
-			//Actual work
+			gW.renderWindow.Present(gW.post, gW.recordFrame)
+			gW.stats.endFrame()
+			gW.presented <- data
 		}
 	}
 }
 
+//do : Runs f on the render thread. Window changes don't wait for it: they
+//are queued and take effect in order, before the next frame is presented, so
+//they can be called from anywhere, render thread commands included. A GPU
+//window that isn't rendering applies them once it starts. Headless windows
+//have no GL context to protect, while nothing renders f runs right away on
+//their synchronized backend. Failures are announced with WindowCommandFailed
+func (gW *GameWindow) do(f func()) {
+	gW.doErr(func() error {
+		f()
+		return nil
+	})
+}
+
+//doErr : do for calls that can fail
+func (gW *GameWindow) doErr(f func() error) {
+	command := func() {
+		var err error
+		if panicked := runCommand(func() { err = f() }); panicked != nil {
+			err = panicked
+		}
+		if err != nil {
+			gW.deferred.push(WindowCommandFailed, err)
+		}
+	}
+	if _, headless := gW.renderWindow.(*headlessWindow); headless && !RenderThread.IsAttached() {
+		command()
+		return
+	}
+	Do(command)
+}
+
 //CloseWindow : Inactivates window, sets active window as nil
 func (gW *GameWindow) CloseWindow() {
-	gW.Stop()
-	gW.renderWindow.Close()
+	//Queued first so the render loop closes it, with the context, as it stops
+	gW.do(gW.renderWindow.Close)
+	if !gW.IsStopped() {
+		gW.Stop()
+	}
 }
 
 //SetSize resizes window by width and height
 func (gW *GameWindow) SetSize(size Vector2u) {
-	gW.renderWindow.SetSize(size.ToSFML())
+	gW.do(func() {
+		gW.renderWindow.SetSize(size.ToSFML())
+	})
 }
 
 //GetSize : Returns size of GameWindow
@@ -377,7 +641,9 @@ func (gW *GameWindow) GetSize() (size Vector2u) {
 
 //SetTitle sets the name of the current window
 func (gW *GameWindow) SetTitle(newName string) {
-	gW.renderWindow.SetTitle(newName)
+	gW.do(func() {
+		gW.renderWindow.SetTitle(newName)
+	})
 }
 
 //GetPosition : Returns Position of Game Window
@@ -385,9 +651,16 @@ func (gW *GameWindow) GetPosition() (pos Vector2i) {
 	return SFVector2uToGEVector2i(gW.renderWindow.GetPosition())
 }
 
+//GetBounds : Screen region covered by the Game Window
+func (gW *GameWindow) GetBounds() RectI {
+	return NewRectI(gW.GetPosition(), gW.GetSize().ToVector2i())
+}
+
 //SetPosition : SetPosition of Game Window
 func (gW *GameWindow) SetPosition(pos Vector2i) {
-	gW.renderWindow.SetPosition(pos.ToSFML())
+	gW.do(func() {
+		gW.renderWindow.SetPosition(pos.ToSFML())
+	})
 }
 
 //IsOpen : Checks if game window is open
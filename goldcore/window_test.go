@@ -4,6 +4,7 @@ import (
 	"runtime"
 	"testing"
 
+	sf "github.com/manyminds/gosfml"
 	"github.com/trustmaster/goflow"
 )
 
@@ -62,3 +63,162 @@ func TestGameWindow(t *testing.T) {
 	close(in)
 	<-gT.Wait()
 }
+
+//messageNames : Readable list of the messages recorded
+func messageNames(mR *MessageRecorder) []string {
+	names := make([]string, len(mR.Messages))
+	for i := range mR.Messages {
+		names[i] = gameMessageStringRegistrar[mR.Messages[i].Message]
+	}
+	return names
+}
+
+func expectMessages(t *testing.T, mR *MessageRecorder, expected ...GMessage) {
+	if len(mR.Messages) != len(expected) {
+		t.Fatalf("Expected %d messages got %v", len(expected), messageNames(mR))
+	}
+	for i, msg := range expected {
+		if mR.Messages[i].Message != msg {
+			t.Errorf("Message %d. Expected %q got %q", i, gameMessageStringRegistrar[msg], messageNames(mR)[i])
+		}
+	}
+}
+
+func TestWindowLifecycleEvents(t *testing.T) {
+	gW := NewHeadlessGameWindow(640, 480, "Lifecycle")
+	recorder := &MessageRecorder{}
+	gW.AddObserver(recorder)
+
+	gW.PollEvent()
+	gW.PollEvent()
+	expectMessages(t, recorder, WindowCreated)
+	if size := recorder.Messages[0].Payload.(Vector2u); !size.Equals(Vector2u{640, 480}) {
+		t.Errorf("WindowCreated payload. Expected %v got %v", Vector2u{640, 480}, size)
+	}
+
+	recorder.Messages = nil
+	gW.PushEvent(sf.EventLostFocus{})
+	gW.PushEvent(sf.EventGainedFocus{})
+	gW.PollEvent()
+	expectMessages(t, recorder, WindowLostFocus, WindowGainedFocus)
+
+	recorder.Messages = nil
+	gW.PushEvent(sf.EventResized{Width: 800, Height: 600})
+	gW.PushEvent(sf.EventResized{Width: 0, Height: 0})
+	gW.PushEvent(sf.EventResized{Width: 800, Height: 600})
+	gW.PollEvent()
+	expectMessages(t, recorder, WindowResized, WindowResized, WindowMinimized, WindowResized, WindowRestored)
+	expected := []EventWindowResized{
+		{Size: Vector2u{800, 600}, Previous: Vector2u{640, 480}},
+		{Size: Vector2u{0, 0}, Previous: Vector2u{800, 600}},
+		{Size: Vector2u{0, 0}, Previous: Vector2u{800, 600}},
+		{Size: Vector2u{800, 600}, Previous: Vector2u{0, 0}},
+		{Size: Vector2u{800, 600}, Previous: Vector2u{0, 0}},
+	}
+	for i, e := range expected {
+		if got := recorder.Messages[i].Payload.(EventWindowResized); got != e {
+			t.Errorf("Resize payload %d. Expected %#v got %#v", i, e, got)
+		}
+	}
+	if gW.IsMinimized() {
+		t.Errorf("Window should be restored")
+	}
+}
+
+func TestWindowResizeFromInput(t *testing.T) {
+	gW := NewHeadlessGameWindow(640, 480, "Resize")
+	gW.PollEvent()
+	recorder := &MessageRecorder{}
+	gW.AddObserver(recorder)
+
+	gW.OnInputGameMessage(NewGameMessage(WindowResized, Vector2u{320, 240}))
+	if size := gW.GetSize(); !size.Equals(Vector2u{320, 240}) {
+		t.Errorf("Resize from input port. Expected %v got %v", Vector2u{320, 240}, size)
+	}
+	gW.PollEvent()
+	expectMessages(t, recorder, WindowResized)
+	resized := recorder.Messages[0].Payload.(EventWindowResized)
+	if !resized.Size.Equals(Vector2u{320, 240}) || !resized.Previous.Equals(Vector2u{640, 480}) {
+		t.Errorf("Resize payload. Got %#v", resized)
+	}
+}
+
+func TestWindowResizeFedBack(t *testing.T) {
+	gW := NewHeadlessGameWindow(640, 480, "Resize")
+	gW.PollEvent()
+	gW.OnInputGameMessage(NewGameMessage(WindowResized, Vector2u{320, 240}))
+	gW.PollEvent()
+	recorder := &MessageRecorder{}
+	gW.AddObserver(recorder)
+
+	//The window's own resize message goes back in without a panic or a new resize
+	gW.OnInputGameMessage(NewGameMessage(WindowResized, EventWindowResized{Size: Vector2u{320, 240}, Previous: Vector2u{640, 480}}))
+	gW.PollEvent()
+	expectMessages(t, recorder)
+	gW.OnInputGameMessage(NewGameMessage(WindowResized, EventWindowResized{Size: Vector2u{100, 50}}))
+	if size := gW.GetSize(); !size.Equals(Vector2u{100, 50}) {
+		t.Errorf("Expected %v got %v", Vector2u{100, 50}, size)
+	}
+	gW.OnInputGameMessage(NewGameMessage(WindowResized, "not a size"))
+	if size := gW.GetSize(); !size.Equals(Vector2u{100, 50}) {
+		t.Errorf("Unknown payloads are ignored, got %v", size)
+	}
+}
+
+func TestPauseOnFocusLoss(t *testing.T) {
+	game := NewGame()
+	gW := NewHeadlessGameWindow(64, 64, "Focus")
+	game.SetWindow(gW)
+	game.Play()
+
+	//Disabled by default
+	gW.PushEvent(sf.EventLostFocus{})
+	gW.PollEvent()
+	if !game.IsRunning() {
+		t.Fatalf("Game paused without PauseOnFocusLoss")
+	}
+
+	gW.SetPauseOnFocusLoss(true)
+	gW.PushEvent(sf.EventLostFocus{})
+	gW.PollEvent()
+	if !game.IsSuspended() {
+		t.Errorf("Game should be suspended after losing focus")
+	}
+	gW.PushEvent(sf.EventGainedFocus{})
+	gW.PollEvent()
+	if !game.IsRunning() {
+		t.Errorf("Game should resume after gaining focus")
+	}
+
+	//A game suspended on purpose stays suspended
+	game.Suspend()
+	gW.PushEvent(sf.EventLostFocus{})
+	gW.PushEvent(sf.EventGainedFocus{})
+	gW.PollEvent()
+	if !game.IsSuspended() {
+		t.Errorf("Focus gain resumed a game that was suspended by the player")
+	}
+
+	//A game stopped while the window was unfocused stays stopped
+	game.Play()
+	gW.PushEvent(sf.EventLostFocus{})
+	gW.PollEvent()
+	game.Stop()
+	gW.PushEvent(sf.EventGainedFocus{})
+	gW.PollEvent()
+	if game.State() != GameStopped {
+		t.Errorf("Focus gain restarted a stopped game")
+	}
+
+	//Resumed and suspended again by the player while unfocused
+	game.Play()
+	gW.PushEvent(sf.EventLostFocus{})
+	gW.PollEvent()
+	game.Play()
+	game.Suspend()
+	gW.PushEvent(sf.EventGainedFocus{})
+	gW.PollEvent()
+	if !game.IsSuspended() {
+		t.Errorf("Focus gain resumed a game the player suspended")
+	}
+}